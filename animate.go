@@ -11,7 +11,24 @@ type tween struct {
 	target   any
 	duration time.Duration
 	ease     func(float64) float64
-	from     any // initial value — if set, animation starts immediately
+	from     any     // initial value — if set, animation starts immediately
+	spring   *spring // moves by spring physics rather than duration and ease
+	keys     []any   // keyframes played in place of a target
+	delay    time.Duration
+	stagger  *stagger      // adds a delay by its place among the stagger's tweens
+	step     *timelineStep // waits for the timeline to reach its step
+}
+
+// stagger spaces out the tweens made by one Stagger call. each template
+// counts them as it compiles, see Template.staggerDelay.
+type stagger struct {
+	d time.Duration
+}
+
+// spring is a damped spring of unit mass pulling a value to its target.
+type spring struct {
+	stiffness float64
+	damping   float64
 }
 
 // AnimateFn configures and creates tweens. Methods return new AnimateFn values,
@@ -71,21 +88,362 @@ func (f AnimateFn) From(v any) AnimateFn {
 	}
 }
 
+// Spring moves values by spring physics instead of a duration and ease.
+// Changing the target mid-flight keeps the velocity, so motion bends
+// toward the new target rather than restarting. stiffness pulls toward
+// the target and damping slows it; 170 and 26 settle quickly with a
+// little overshoot, lower damping bounces more. Returns a new AnimateFn.
+//
+//	VBox.Height(Animate.Spring(170, 26)(&targetHeight))
+func (f AnimateFn) Spring(stiffness, damping float64) AnimateFn {
+	return func(target any) *tween {
+		tw := f(target)
+		tw.spring = &spring{stiffness: stiffness, damping: damping}
+		return tw
+	}
+}
+
+// Keyframes plays through values in turn over the duration, evenly spaced,
+// easing each step, then holds the last. It starts at once, or when a
+// screen effect it drives appears, or when its Timeline step does.
+//
+//	SEVignette().Strength(Animate.Duration(600 * time.Millisecond).Keyframes(0.0, 0.9, 0.6))
+func (f AnimateFn) Keyframes(values ...any) *tween {
+	tw := f(nil)
+	tw.keys = values
+	return tw
+}
+
+// Stagger delays the start of each tween made with the returned AnimateFn
+// d longer than the one before, so children animate in one after another.
+// Delays apply when a tween first starts, not when its target changes.
+// Tweens count in the order each template compiles them, so every Build
+// starts again from no delay.
+//
+//	in := Animate.Duration(200 * time.Millisecond).From(0.0).Stagger(50 * time.Millisecond)
+//	VBox(VBox.Opacity(in(1.0))(a), VBox.Opacity(in(1.0))(b))
+func (f AnimateFn) Stagger(d time.Duration) AnimateFn {
+	s := &stagger{d: d}
+	return func(target any) *tween {
+		tw := f(target)
+		tw.stagger = s
+		return tw
+	}
+}
+
 // tweenNode interface for the compiler to detect tween nodes
 type tweenNode interface {
 	getTarget() any
 	getTweenDuration() time.Duration
 	getTweenEasing() func(float64) float64
 	getTweenFrom() any
+	getTweenSpring() *spring
+	getTweenKeyframes() []any
+	getTweenDelay() time.Duration
+	getTweenStagger() *stagger
+	getTweenStep() *timelineStep
 }
 
 func (tw *tween) getTarget() any                        { return tw.target }
 func (tw *tween) getTweenDuration() time.Duration       { return tw.duration }
 func (tw *tween) getTweenEasing() func(float64) float64 { return tw.ease }
 func (tw *tween) getTweenFrom() any                     { return tw.from }
+func (tw *tween) getTweenSpring() *spring               { return tw.spring }
+func (tw *tween) getTweenKeyframes() []any              { return tw.keys }
+func (tw *tween) getTweenDelay() time.Duration          { return tw.delay }
+func (tw *tween) getTweenStagger() *stagger             { return tw.stagger }
+func (tw *tween) getTweenStep() *timelineStep           { return tw.step }
 
 var _ tweenNode = (*tween)(nil)
 
+// --- the tween engine ---
+
+// tweenValues is how tweens move values of one type: lerp for curves and
+// keyframes, and split and join between values and channels for springs.
+type tweenValues[T comparable] struct {
+	convert func(any) (T, bool) // a From or keyframe value as T
+	lerp    func(a, b T, t float64) T
+	dims    int
+	split   func(v, like T, ch []float64) // channels v can't move in come from like
+	join    func(ch []float64, like T) T  // what channels don't cover comes from like
+}
+
+func scalarValues[T int16 | int8 | float32 | float64](convert func(any) T) tweenValues[T] {
+	return tweenValues[T]{
+		convert: func(v any) (T, bool) { return convert(v), true },
+		lerp:    func(a, b T, t float64) T { return T(float64(a) + t*(float64(b)-float64(a))) },
+		dims:    1,
+		split:   func(v, _ T, ch []float64) { ch[0] = float64(v) },
+		join:    func(ch []float64, _ T) T { return T(ch[0]) },
+	}
+}
+
+var (
+	int16Values   = scalarValues(anyToInt16)
+	int8Values    = scalarValues(anyToInt8)
+	float32Values = scalarValues(anyToFloat32)
+	float64Values = scalarValues(anyToFloat64)
+
+	colorValues = tweenValues[Color]{
+		convert: func(v any) (Color, bool) { c, ok := v.(Color); return c, ok },
+		lerp:    lerpColor,
+		dims:    3,
+		split: func(v, _ Color, ch []float64) {
+			ch[0], ch[1], ch[2] = float64(v.R), float64(v.G), float64(v.B)
+		},
+		join: func(ch []float64, _ Color) Color { return RGB(clamp8(ch[0]), clamp8(ch[1]), clamp8(ch[2])) },
+	}
+
+	styleValues = tweenValues[Style]{
+		convert: func(v any) (Style, bool) { s, ok := v.(Style); return s, ok },
+		lerp:    lerpStyle,
+		dims:    9,
+		split: func(v, like Style, ch []float64) {
+			for i, c := range [3][2]Color{{v.FG, like.FG}, {v.BG, like.BG}, {v.Fill, like.Fill}} {
+				if c[0].Mode == ColorDefault {
+					c[0] = c[1] // lerpStyle snaps these, so a spring doesn't move them
+				}
+				colorValues.split(c[0], c[0], ch[i*3:])
+			}
+		},
+		join: func(ch []float64, like Style) Style {
+			s := like
+			for i, c := range [3]*Color{&s.FG, &s.BG, &s.Fill} {
+				if c.Mode != ColorDefault {
+					*c = colorValues.join(ch[i*3:], *c)
+				}
+			}
+			return s
+		},
+	}
+)
+
+// staggerDelay returns the delay for the next tween of s the template
+// compiles: s's step once for each of s's tweens compiled before it.
+func (t *Template) staggerDelay(s *stagger) time.Duration {
+	if s == nil {
+		return 0
+	}
+	if t.staggers == nil {
+		t.staggers = make(map[*stagger]int)
+	}
+	n := t.staggers[s]
+	t.staggers[s] = n + 1
+	return time.Duration(n) * s.d
+}
+
+func clamp8(v float64) uint8 {
+	return uint8(max(0, min(255, math.Round(v))))
+}
+
+// springStep is the time step springs are integrated at.
+const springStep = time.Millisecond
+
+// compileTween allocates storage for a tween and registers a per-frame
+// evaluator that moves it toward *watchPtr: along the ease over the
+// duration, by spring, or through keyframes. all tweens in a frame share
+// root.frameTime. armed gates tweens of screen effects, see
+// compileTweenFloat64.
+func compileTween[T comparable](t *Template, tw tweenNode, watchPtr *T, vals tweenValues[T], armed *bool) *T {
+	root := t.evalRoot()
+	storage := new(T)
+	*storage = *watchPtr
+	dur := tw.getTweenDuration()
+	ease := tw.getTweenEasing()
+	spr := tw.getTweenSpring()
+	delay := tw.getTweenDelay() + root.staggerDelay(tw.getTweenStagger())
+	step := tw.getTweenStep()
+
+	var keys []T
+	for _, k := range tw.getTweenKeyframes() {
+		if v, ok := vals.convert(k); ok {
+			keys = append(keys, v)
+		}
+	}
+	if len(keys) > 0 {
+		watchPtr = &keys[len(keys)-1]
+		spr = nil // keyframes keep to their duration
+	}
+
+	lastTarget := *watchPtr
+	startVal := *watchPtr
+	var startTime time.Time
+	needsFirstFrame := false
+
+	var fromVal T
+	if from := tw.getTweenFrom(); from != nil {
+		if v, ok := vals.convert(from); ok {
+			fromVal = v
+			needsFirstFrame = true
+		}
+	}
+	if len(keys) > 0 {
+		fromVal = keys[0]
+		needsFirstFrame = true
+	}
+	if needsFirstFrame {
+		*storage = fromVal
+		startVal = fromVal
+	}
+
+	// tracks whether resolve() was called last frame (effect was active)
+	wasActive := armed == nil // nil armed = always active (non-effect tweens)
+
+	// a delayed tween or one on a timeline holds until its start
+	held := delay > 0 || step != nil
+	var heldSince time.Time
+	member := -1
+	if step != nil {
+		member = step.join(root)
+	}
+	finished := func(done bool) {
+		if member >= 0 && step.tl.root == root {
+			step.done[member] = done
+		}
+	}
+
+	// spring state, in channels
+	var pos, vel, goal []float64
+	var eps float64
+	var springTime time.Time
+	springing := false
+	if spr != nil {
+		pos = make([]float64, vals.dims)
+		vel = make([]float64, vals.dims)
+		goal = make([]float64, vals.dims)
+	}
+
+	begin := func(target T, now time.Time) {
+		startVal = *storage
+		lastTarget = target
+		startTime = now
+		needsFirstFrame = false
+		finished(false)
+		if spr == nil {
+			return
+		}
+		if !springing {
+			vals.split(*storage, target, pos)
+			clear(vel)
+			springTime = now
+			springing = true
+		}
+		vals.split(target, target, goal)
+		eps = 0
+		for i := range pos {
+			eps = max(eps, math.Abs(goal[i]-pos[i]))
+		}
+		eps = 1e-3 * max(1, eps)
+	}
+
+	root.evals = append(root.evals, func() {
+		target := *watchPtr
+		now := root.frameTime
+
+		// activation gating: From tweens in screen effects wait for resolve()
+		if armed != nil {
+			active := *armed
+			*armed = false // reset each frame; resolve() re-sets if still active
+
+			if !active {
+				wasActive = false
+				*storage = fromVal // reset so stale target doesn't flash on re-open
+				startTime, springing = time.Time{}, false
+				finished(true) // an effect not drawn doesn't hold up its timeline
+				return
+			}
+
+			if !wasActive {
+				// inactive → active transition: (re)start From animation
+				wasActive = true
+				*storage = fromVal
+				needsFirstFrame = true
+				held = delay > 0 || step != nil
+				heldSince = time.Time{}
+			}
+		}
+
+		if held {
+			at, ok := now, true
+			if step != nil {
+				at, ok = step.opensAt(now)
+			} else {
+				if heldSince.IsZero() {
+					heldSince = now
+				}
+				at = heldSince
+			}
+			at = at.Add(delay)
+			if !ok || now.Before(at) {
+				return
+			}
+			held = false
+			if needsFirstFrame || target != *storage {
+				begin(target, at)
+			} else {
+				finished(true)
+			}
+		} else if needsFirstFrame || target != lastTarget {
+			begin(target, now)
+		}
+
+		if spr != nil {
+			if !springing {
+				return
+			}
+			dt := min(now.Sub(springTime), time.Second)
+			springTime = now
+			for ; dt > 0; dt -= springStep {
+				h := min(dt, springStep).Seconds()
+				for i := range pos {
+					a := -spr.stiffness*(pos[i]-goal[i]) - spr.damping*vel[i]
+					vel[i] += a * h
+					pos[i] += vel[i] * h
+				}
+			}
+			for i := range pos {
+				if math.Abs(pos[i]-goal[i]) > eps || math.Abs(vel[i]) > eps {
+					*storage = vals.join(pos, target)
+					root.animating = true
+					return
+				}
+			}
+			*storage = target
+			springing = false
+			finished(true)
+			return
+		}
+
+		if startTime.IsZero() {
+			return
+		}
+		elapsed := now.Sub(startTime)
+		if elapsed >= dur {
+			*storage = target
+			startTime = time.Time{}
+			finished(true)
+			return
+		}
+		progress := float64(elapsed) / float64(dur)
+		if len(keys) > 1 {
+			n := float64(len(keys) - 1)
+			seg := min(int(progress*n), len(keys)-2)
+			progress = progress*n - float64(seg)
+			if ease != nil {
+				progress = ease(progress)
+			}
+			*storage = vals.lerp(keys[seg], keys[seg+1], progress)
+		} else {
+			if ease != nil {
+				progress = ease(progress)
+			}
+			*storage = vals.lerp(startVal, target, progress)
+		}
+		root.animating = true
+	})
+	return storage
+}
+
 // --- color and style interpolation ---
 
 func lerpColor(from, to Color, t float64) Color {
@@ -112,8 +470,8 @@ func lerpStyle(from, to Style, t float64) Style {
 // --- easing functions ---
 // all take t in [0,1] and return eased value in [0,1]
 
-func EaseInQuad(t float64) float64    { return t * t }
-func EaseOutQuad(t float64) float64   { return t * (2 - t) }
+func EaseInQuad(t float64) float64  { return t * t }
+func EaseOutQuad(t float64) float64 { return t * (2 - t) }
 func EaseInOutQuad(t float64) float64 {
 	if t < 0.5 {
 		return 2 * t * t
@@ -121,8 +479,8 @@ func EaseInOutQuad(t float64) float64 {
 	return -1 + (4-2*t)*t
 }
 
-func EaseInCubic(t float64) float64    { return t * t * t }
-func EaseOutCubic(t float64) float64   { t--; return 1 + t*t*t }
+func EaseInCubic(t float64) float64  { return t * t * t }
+func EaseOutCubic(t float64) float64 { t--; return 1 + t*t*t }
 func EaseInOutCubic(t float64) float64 {
 	if t < 0.5 {
 		return 4 * t * t * t
@@ -131,8 +489,8 @@ func EaseInOutCubic(t float64) float64 {
 	return 1 + 8*t*t*t
 }
 
-func EaseInQuart(t float64) float64    { return t * t * t * t }
-func EaseOutQuart(t float64) float64   { t--; return 1 - t*t*t*t }
+func EaseInQuart(t float64) float64  { return t * t * t * t }
+func EaseOutQuart(t float64) float64 { t--; return 1 - t*t*t*t }
 func EaseInOutQuart(t float64) float64 {
 	if t < 0.5 {
 		return 8 * t * t * t * t
@@ -141,8 +499,8 @@ func EaseInOutQuart(t float64) float64 {
 	return 1 - 8*t*t*t*t
 }
 
-func EaseInQuint(t float64) float64    { return t * t * t * t * t }
-func EaseOutQuint(t float64) float64   { t--; return 1 + t*t*t*t*t }
+func EaseInQuint(t float64) float64  { return t * t * t * t * t }
+func EaseOutQuint(t float64) float64 { t--; return 1 + t*t*t*t*t }
 func EaseInOutQuint(t float64) float64 {
 	if t < 0.5 {
 		return 16 * t * t * t * t * t
@@ -182,8 +540,8 @@ func EaseInOutExpo(t float64) float64 {
 	return (2 - math.Pow(2, -20*t+10)) / 2
 }
 
-func EaseInCirc(t float64) float64    { return 1 - math.Sqrt(1-t*t) }
-func EaseOutCirc(t float64) float64   { t--; return math.Sqrt(1 - t*t) }
+func EaseInCirc(t float64) float64  { return 1 - math.Sqrt(1-t*t) }
+func EaseOutCirc(t float64) float64 { t--; return math.Sqrt(1 - t*t) }
 func EaseInOutCirc(t float64) float64 {
 	if t < 0.5 {
 		return (1 - math.Sqrt(1-4*t*t)) / 2
@@ -228,7 +586,7 @@ func EaseOutBounce(t float64) float64 {
 	}
 }
 
-func EaseInBounce(t float64) float64    { return 1 - EaseOutBounce(1-t) }
+func EaseInBounce(t float64) float64 { return 1 - EaseOutBounce(1-t) }
 func EaseInOutBounce(t float64) float64 {
 	if t < 0.5 {
 		return (1 - EaseOutBounce(1-2*t)) / 2
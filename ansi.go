@@ -0,0 +1,415 @@
+package glyph
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// ============================================================================
+// ANSI escape sequence ingestion
+// ============================================================================
+
+// ParseANSI converts a line of terminal output into styled spans.
+// Understands SGR colours (16, 256 and RGB) and attributes, consumes OSC
+// sequences such as OSC 8 hyperlinks (the link text is kept), and applies
+// carriage-return and erase-line redraws the way a terminal would, so a
+// progress bar redrawn in place collapses to its final state.
+// Other escape sequences are dropped. s is treated as a single line.
+func ParseANSI(s string) []Span {
+	p := ansiParser{tabWidth: 8}
+	spans, _ := p.parse(s)
+	return spans
+}
+
+// StripANSI returns the visible text of s with all escape sequences removed
+// and in-place redraws applied. This is the text ParseANSI would display.
+func StripANSI(s string) string {
+	p := ansiParser{tabWidth: 8}
+	_, plain := p.parse(s)
+	return plain
+}
+
+// WriteANSI writes a line containing ANSI escape sequences at (x, y),
+// translating SGR state into cell styles. See ParseANSI.
+func (b *Buffer) WriteANSI(x, y int, s string, maxWidth int) {
+	b.WriteSpans(x, y, ParseANSI(s), maxWidth)
+}
+
+// ansiParser is a minimal line-oriented terminal emulator. SGR state carries
+// across lines so multi-line coloured output renders as it would in a terminal.
+type ansiParser struct {
+	style    Style
+	cells    []Cell
+	col      int
+	tabWidth int
+}
+
+// parse processes one line and returns its styled spans and plain text.
+func (p *ansiParser) parse(s string) ([]Span, string) {
+	if !hasControl(s) {
+		if s == "" {
+			return nil, ""
+		}
+		return []Span{{Text: s, Style: p.style}}, s
+	}
+	cells := p.line(s)
+	return spansFromCells(cells), plainFromCells(cells)
+}
+
+// line runs s through the parser and returns the resulting cells.
+// The returned slice is owned by the caller.
+func (p *ansiParser) line(s string) []Cell {
+	p.cells = nil
+	p.col = 0
+
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == 0x1b:
+			i = p.escape(s, i+1)
+			continue
+		case c == '\r':
+			p.col = 0
+		case c == '\b':
+			if p.col > 0 {
+				p.col--
+			}
+		case c == '\t':
+			tw := p.tabWidth
+			if tw <= 0 {
+				tw = 8
+			}
+			for n := tw - p.col%tw; n > 0; n-- {
+				p.put(' ')
+			}
+		case c < 0x20 || c == 0x7f:
+			// other C0 controls have no visible effect on a single line
+		default:
+			r, size := utf8.DecodeRuneInString(s[i:])
+			p.put(r)
+			i += size
+			continue
+		}
+		i++
+	}
+	return p.cells
+}
+
+// put writes r at the cursor and advances it by r's width, padding any gap
+// with blanks. The second cell of a wide rune holds Rune 0, as in Buffer.
+func (p *ansiParser) put(r rune) {
+	w := runewidth.RuneWidth(r)
+	if w == 0 {
+		w = 1 // as WriteSpans places it
+	}
+	end := p.col + w
+	for len(p.cells) < end {
+		p.cells = append(p.cells, Cell{Rune: ' '})
+	}
+	// overwriting half of a wide rune blanks the other half
+	if p.cells[p.col].Rune == 0 && p.col > 0 {
+		p.cells[p.col-1].Rune = ' '
+	}
+	if end < len(p.cells) && p.cells[end].Rune == 0 {
+		p.cells[end].Rune = ' '
+	}
+	p.cells[p.col] = Cell{Rune: r, Style: p.style}
+	if w == 2 {
+		p.cells[p.col+1] = Cell{Style: p.style}
+	}
+	p.col = end
+}
+
+// escape consumes the sequence following an ESC at s[i-1] and returns the
+// index of the first byte after it.
+func (p *ansiParser) escape(s string, i int) int {
+	if i >= len(s) {
+		return i
+	}
+	switch s[i] {
+	case '[':
+		// CSI: parameter bytes, intermediate bytes, one final byte
+		start := i + 1
+		j := start
+		for j < len(s) && s[j] >= 0x30 && s[j] <= 0x3f {
+			j++
+		}
+		params := s[start:j]
+		for j < len(s) && s[j] >= 0x20 && s[j] <= 0x2f {
+			j++
+		}
+		if j >= len(s) {
+			return j
+		}
+		p.csi(params, s[j])
+		return j + 1
+
+	case ']', 'P', '_', '^', 'X':
+		// OSC, DCS, APC, PM, SOS: string terminated by BEL or ST.
+		// OSC 8 hyperlinks land here; the URL is dropped and the text kept.
+		for j := i + 1; j < len(s); j++ {
+			if s[j] == 0x07 {
+				return j + 1
+			}
+			if s[j] == 0x1b && j+1 < len(s) && s[j+1] == '\\' {
+				return j + 2
+			}
+		}
+		return len(s)
+
+	default:
+		// nF escapes (e.g. ESC ( B) carry intermediates before the final byte
+		j := i
+		for j < len(s) && s[j] >= 0x20 && s[j] <= 0x2f {
+			j++
+		}
+		if j < len(s) {
+			j++
+		}
+		return j
+	}
+}
+
+// csi applies the CSI sequences that matter within a single line.
+func (p *ansiParser) csi(params string, final byte) {
+	switch final {
+	case 'm':
+		applySGR(&p.style, params)
+	case 'K':
+		switch ansiParam(params, 0) {
+		case 0:
+			if p.col < len(p.cells) {
+				p.cells = p.cells[:p.col]
+			}
+		case 1:
+			for x := 0; x <= p.col && x < len(p.cells); x++ {
+				p.cells[x] = Cell{Rune: ' ', Style: Style{BG: p.style.BG}}
+			}
+		case 2:
+			p.cells = p.cells[:0]
+		}
+	case 'G':
+		p.col = max(ansiParam(params, 1)-1, 0)
+	case 'C':
+		p.col += max(ansiParam(params, 1), 1)
+	case 'D':
+		p.col = max(p.col-max(ansiParam(params, 1), 1), 0)
+	}
+}
+
+// ansiParam returns the first numeric parameter, or def if absent.
+func ansiParam(params string, def int) int {
+	if i := strings.IndexAny(params, ";:"); i >= 0 {
+		params = params[:i]
+	}
+	n, err := strconv.Atoi(params)
+	if err != nil {
+		return def
+	}
+	return n
+}
+
+// applySGR updates st from an SGR parameter string such as "1;38;5;208".
+// Both the ';' and ':' forms of extended colours are accepted.
+func applySGR(st *Style, params string) {
+	if params == "" {
+		*st = Style{}
+		return
+	}
+	fields := strings.Split(params, ";")
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+		if strings.IndexByte(f, ':') >= 0 {
+			applySGRColon(st, strings.Split(f, ":"))
+			continue
+		}
+		n, err := strconv.Atoi(f)
+		if err != nil && f != "" {
+			continue
+		}
+		switch {
+		case n == 0:
+			*st = Style{}
+		case n == 1:
+			st.Attr = st.Attr.With(AttrBold)
+		case n == 2:
+			st.Attr = st.Attr.With(AttrDim)
+		case n == 3:
+			st.Attr = st.Attr.With(AttrItalic)
+		case n == 4:
+			st.Attr = st.Attr.With(AttrUnderline)
+		case n == 5 || n == 6:
+			st.Attr = st.Attr.With(AttrBlink)
+		case n == 7:
+			st.Attr = st.Attr.With(AttrInverse)
+		case n == 9:
+			st.Attr = st.Attr.With(AttrStrikethrough)
+		case n == 21 || n == 22:
+			st.Attr = st.Attr.Without(AttrBold).Without(AttrDim)
+		case n == 23:
+			st.Attr = st.Attr.Without(AttrItalic)
+		case n == 24:
+			st.Attr = st.Attr.Without(AttrUnderline)
+		case n == 25:
+			st.Attr = st.Attr.Without(AttrBlink)
+		case n == 27:
+			st.Attr = st.Attr.Without(AttrInverse)
+		case n == 29:
+			st.Attr = st.Attr.Without(AttrStrikethrough)
+		case n >= 30 && n <= 37:
+			st.FG = BasicColor(uint8(n - 30))
+		case n == 39:
+			st.FG = Color{}
+		case n >= 40 && n <= 47:
+			st.BG = BasicColor(uint8(n - 40))
+		case n == 49:
+			st.BG = Color{}
+		case n >= 90 && n <= 97:
+			st.FG = BasicColor(uint8(n - 90 + 8))
+		case n >= 100 && n <= 107:
+			st.BG = BasicColor(uint8(n - 100 + 8))
+		case n == 38 || n == 48:
+			c, used := sgrColor(fields[i+1:])
+			i += used
+			if used > 0 {
+				if n == 38 {
+					st.FG = c
+				} else {
+					st.BG = c
+				}
+			}
+		}
+	}
+}
+
+// applySGRColon handles the colon-separated form, e.g. "38:2::255:128:0".
+func applySGRColon(st *Style, sub []string) {
+	if len(sub) < 2 {
+		return
+	}
+	target := &st.FG
+	switch sub[0] {
+	case "38":
+	case "48":
+		target = &st.BG
+	case "4":
+		// underline styles (4:0 off, 4:n on)
+		if sub[1] == "0" {
+			st.Attr = st.Attr.Without(AttrUnderline)
+		} else {
+			st.Attr = st.Attr.With(AttrUnderline)
+		}
+		return
+	default:
+		return
+	}
+	rest := sub[1:]
+	// 38:2:<colourspace>:r:g:b carries an extra (usually empty) id
+	if len(rest) == 5 && rest[0] == "2" {
+		rest = append([]string{"2"}, rest[2:]...)
+	}
+	if c, used := sgrColor(rest); used > 0 {
+		*target = c
+	}
+}
+
+// sgrColor parses the arguments of an extended colour (5;n or 2;r;g;b) and
+// returns the colour and the number of fields consumed.
+func sgrColor(args []string) (Color, int) {
+	if len(args) == 0 {
+		return Color{}, 0
+	}
+	switch args[0] {
+	case "5":
+		if len(args) < 2 {
+			return Color{}, len(args)
+		}
+		n, _ := strconv.Atoi(args[1])
+		if n < 16 {
+			return BasicColor(uint8(n)), 2
+		}
+		return PaletteColor(uint8(n)), 2
+	case "2":
+		if len(args) < 4 {
+			return Color{}, len(args)
+		}
+		r, _ := strconv.Atoi(args[1])
+		g, _ := strconv.Atoi(args[2])
+		b, _ := strconv.Atoi(args[3])
+		return RGB(uint8(r), uint8(g), uint8(b)), 4
+	}
+	return Color{}, 0
+}
+
+// hasControl reports whether s contains any C0 control byte.
+func hasControl(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x20 || s[i] == 0x7f {
+			return true
+		}
+	}
+	return false
+}
+
+// spansFromCells coalesces runs of identically styled cells into spans.
+func spansFromCells(cells []Cell) []Span {
+	if len(cells) == 0 {
+		return nil
+	}
+	var spans []Span
+	var sb strings.Builder
+	cur := cells[0].Style
+	for _, c := range cells {
+		if c.Rune == 0 {
+			continue // second half of a wide rune
+		}
+		if c.Style != cur {
+			spans = append(spans, Span{Text: sb.String(), Style: cur})
+			sb.Reset()
+			cur = c.Style
+		}
+		sb.WriteRune(c.Rune)
+	}
+	return append(spans, Span{Text: sb.String(), Style: cur})
+}
+
+// plainFromCells returns the text content of cells.
+func plainFromCells(cells []Cell) string {
+	var sb strings.Builder
+	sb.Grow(len(cells))
+	for _, c := range cells {
+		if c.Rune != 0 {
+			sb.WriteRune(c.Rune)
+		}
+	}
+	return sb.String()
+}
+
+// wrapANSI is the escape-aware counterpart of wrapText: splits on newlines,
+// expands tabs, then character-wraps each line at width.
+func wrapANSI(s string, width int) [][]Span {
+	if width <= 0 {
+		return nil
+	}
+	p := ansiParser{tabWidth: 4}
+	var out [][]Span
+	for _, line := range strings.Split(s, "\n") {
+		cells := p.line(line)
+		if len(cells) == 0 {
+			out = append(out, nil)
+			continue
+		}
+		for len(cells) > 0 {
+			n := min(width, len(cells))
+			if n < len(cells) && cells[n].Rune == 0 && n > 1 {
+				n-- // keep a wide rune whole on the next line
+			}
+			out = append(out, spansFromCells(cells[:n]))
+			cells = cells[n:]
+		}
+	}
+	return out
+}
@@ -0,0 +1,221 @@
+package glyph
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseANSIPlain(t *testing.T) {
+	spans := ParseANSI("hello")
+	if len(spans) != 1 || spans[0].Text != "hello" || spans[0].Style != (Style{}) {
+		t.Errorf("expected single unstyled span, got %+v", spans)
+	}
+	if spans := ParseANSI(""); len(spans) != 0 {
+		t.Errorf("expected no spans for empty input, got %+v", spans)
+	}
+}
+
+func TestParseANSIBasicColours(t *testing.T) {
+	spans := ParseANSI("\x1b[31mred\x1b[0m plain \x1b[1;92mbright\x1b[m")
+	if len(spans) != 3 {
+		t.Fatalf("expected 3 spans, got %d: %+v", len(spans), spans)
+	}
+	if spans[0].Text != "red" || spans[0].Style.FG != BasicColor(1) {
+		t.Errorf("span 0: %+v", spans[0])
+	}
+	if spans[1].Text != " plain " || spans[1].Style != (Style{}) {
+		t.Errorf("span 1: %+v", spans[1])
+	}
+	if spans[2].Text != "bright" || spans[2].Style.FG != BasicColor(10) || !spans[2].Style.Attr.Has(AttrBold) {
+		t.Errorf("span 2: %+v", spans[2])
+	}
+}
+
+func TestParseANSIExtendedColours(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		fg   Color
+		bg   Color
+	}{
+		{"256 fg", "\x1b[38;5;208mx", PaletteColor(208), Color{}},
+		{"256 low index", "\x1b[38;5;4mx", BasicColor(4), Color{}},
+		{"rgb fg", "\x1b[38;2;10;20;30mx", RGB(10, 20, 30), Color{}},
+		{"rgb bg", "\x1b[48;2;1;2;3mx", Color{}, RGB(1, 2, 3)},
+		{"colon rgb", "\x1b[38:2::10:20:30mx", RGB(10, 20, 30), Color{}},
+		{"colon 256", "\x1b[48:5:100mx", Color{}, PaletteColor(100)},
+		{"bright bg", "\x1b[101mx", Color{}, BasicColor(9)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spans := ParseANSI(tt.in)
+			if len(spans) != 1 {
+				t.Fatalf("expected 1 span, got %+v", spans)
+			}
+			if spans[0].Style.FG != tt.fg || spans[0].Style.BG != tt.bg {
+				t.Errorf("got fg=%+v bg=%+v, want fg=%+v bg=%+v", spans[0].Style.FG, spans[0].Style.BG, tt.fg, tt.bg)
+			}
+		})
+	}
+}
+
+func TestParseANSIAttributes(t *testing.T) {
+	spans := ParseANSI("\x1b[1;3;4;9mon\x1b[22;23mmid")
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %+v", spans)
+	}
+	want := AttrBold | AttrItalic | AttrUnderline | AttrStrikethrough
+	if spans[0].Style.Attr != want {
+		t.Errorf("span 0 attrs = %v, want %v", spans[0].Style.Attr, want)
+	}
+	if spans[1].Style.Attr != AttrUnderline|AttrStrikethrough {
+		t.Errorf("span 1 attrs = %v", spans[1].Style.Attr)
+	}
+}
+
+func TestParseANSIHyperlink(t *testing.T) {
+	in := "see \x1b]8;;https://example.com\x1b\\docs\x1b]8;;\x1b\\ or \x1b]8;;http://x\x07here\x1b]8;;\x07"
+	if got := StripANSI(in); got != "see docs or here" {
+		t.Errorf("StripANSI = %q", got)
+	}
+}
+
+func TestParseANSIRedraw(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"carriage return overwrite", "10%\r55%\r100%", "100%"},
+		{"shorter overwrite keeps tail", "abcdef\rXY", "XYcdef"},
+		{"erase to end", "abcdef\rXY\x1b[K", "XY"},
+		{"erase whole line", "downloading...\r\x1b[2Kdone", "done"},
+		{"backspace", "ab\bc", "ac"},
+		{"column absolute", "abcdef\x1b[3GZ", "abZdef"},
+		{"cursor forward pads", "a\x1b[2Cb", "a  b"},
+		{"tab expands", "a\tb", "a       b"},
+		{"charset designation", "\x1b(Bok", "ok"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StripANSI(tt.in); got != tt.want {
+				t.Errorf("StripANSI(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseANSIStyleCarriesAcrossLines(t *testing.T) {
+	p := ansiParser{tabWidth: 8}
+	p.parse("\x1b[32mstart")
+	spans, plain := p.parse("continued")
+	if plain != "continued" {
+		t.Errorf("plain = %q", plain)
+	}
+	if len(spans) != 1 || spans[0].Style.FG != BasicColor(2) {
+		t.Errorf("expected style to carry over, got %+v", spans)
+	}
+}
+
+func TestBufferWriteANSI(t *testing.T) {
+	buf := NewBuffer(20, 1)
+	buf.WriteANSI(0, 0, "\x1b[31mab\x1b[0mcd", 20)
+	if got := buf.GetLine(0); !strings.HasPrefix(got, "abcd") {
+		t.Errorf("line = %q", got)
+	}
+	if buf.Get(0, 0).Style.FG != BasicColor(1) {
+		t.Errorf("cell 0 fg = %+v", buf.Get(0, 0).Style.FG)
+	}
+	if buf.Get(2, 0).Style.FG != (Color{}) {
+		t.Errorf("cell 2 fg = %+v", buf.Get(2, 0).Style.FG)
+	}
+}
+
+func TestBufferWriteANSIWideRunes(t *testing.T) {
+	// the tab and the cursor move count the CJK runes as two columns each
+	buf := NewBuffer(20, 1)
+	buf.WriteANSI(0, 0, "\x1b[31m日本\x1b[0m\tx\x1b[12G\x1b[32my", 20)
+	for _, c := range []struct {
+		x  int
+		r  rune
+		fg Color
+	}{
+		{0, '日', BasicColor(1)},
+		{2, '本', BasicColor(1)},
+		{8, 'x', Color{}},
+		{11, 'y', BasicColor(2)},
+	} {
+		if cell := buf.Get(c.x, 0); cell.Rune != c.r || cell.Style.FG != c.fg {
+			t.Errorf("cell %d = %q FG %+v, want %q FG %+v", c.x, cell.Rune, cell.Style.FG, c.r, c.fg)
+		}
+	}
+
+	// writing over half of a wide rune blanks the other half
+	if got := plainFromCells((&ansiParser{}).line("日本\rab\x1b[4Gc")); got != "ab c" {
+		t.Errorf("overwritten = %q", got)
+	}
+}
+
+func TestLogANSI(t *testing.T) {
+	lv := Log(strings.NewReader("\x1b[31mFAIL\x1b[0m pkg\n")).ANSI()
+	Build(VBox(lv))
+	time.Sleep(50 * time.Millisecond)
+
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+	if len(lv.lines) != 1 || lv.lines[0] != "FAIL pkg" {
+		t.Fatalf("expected stripped line, got %q", lv.lines)
+	}
+	buf := lv.Layer().Buffer()
+	if !strings.HasPrefix(buf.GetLine(0), "FAIL pkg") {
+		t.Errorf("layer line = %q", buf.GetLine(0))
+	}
+	if buf.Get(0, 0).Style.FG != BasicColor(1) {
+		t.Errorf("expected red FAIL, got %+v", buf.Get(0, 0).Style.FG)
+	}
+}
+
+func TestFilterLogANSIMatchesStrippedText(t *testing.T) {
+	// "31m" only appears in the escape code, never in the visible text
+	fl := FilterLog(strings.NewReader("\x1b[31merror\x1b[0m one\nplain two\n")).ANSI()
+	Build(VBox(fl))
+	time.Sleep(50 * time.Millisecond)
+
+	fl.input.SetValue("'31m")
+	fl.updateFilter()
+	if got := fl.Layer().Buffer().GetLine(0); !strings.HasPrefix(got, "(no matches)") {
+		t.Errorf("escape codes should not match, got %q", got)
+	}
+
+	fl.input.SetValue("'error")
+	fl.updateFilter()
+	buf := fl.Layer().Buffer()
+	if buf.Height() != 1 || !strings.HasPrefix(buf.GetLine(0), "error one") {
+		t.Errorf("expected styled match, got %q", buf.GetLine(0))
+	}
+	if buf.Get(0, 0).Style.FG != BasicColor(1) {
+		t.Errorf("expected match to keep its colour, got %+v", buf.Get(0, 0).Style.FG)
+	}
+}
+
+func TestTextViewANSI(t *testing.T) {
+	content := "\x1b[1mtitle\x1b[0m\nbody text"
+	tv := TextView(&content).ANSI()
+	tv.layer.SetViewport(4, 2)
+	tv.sync()
+
+	buf := tv.layer.Buffer()
+	lines := []string{"titl", "e", "body", " tex", "t"}
+	if buf.Height() != len(lines) {
+		t.Fatalf("height = %d, want %d", buf.Height(), len(lines))
+	}
+	for i, want := range lines {
+		if got := strings.TrimRight(buf.GetLine(i), " "); got != want {
+			t.Errorf("line %d = %q, want %q", i, got, want)
+		}
+	}
+	if !buf.Get(0, 1).Style.Attr.Has(AttrBold) {
+		t.Error("expected wrapped continuation to stay bold")
+	}
+}
@@ -1,9 +1,15 @@
 package glyph
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/kungfusheep/riffkey"
@@ -14,15 +20,9 @@ var (
 	DebugTiming     bool
 	DebugFullRedraw bool // force full redraws instead of diff-based (set TUI_FULL_REDRAW=1 to enable)
 	DebugFlush      bool // dump flush debug info (set TUI_DEBUG_FLUSH=1 to enable)
-	lastBuildTime   time.Duration
-	lastLayoutTime  time.Duration
-	lastRenderTime  time.Duration
-	lastFlushTime   time.Duration
-
-	// fine-grained post-processing phase timings (only populated when DebugTiming=true)
-	lastEffectTime time.Duration // resolveColor16 + all Effect passes
-	lastDiffTime   time.Duration // Flush(): diff + escape-sequence building
-	lastWriteTime  time.Duration // FlushBuffer(): Write() syscall to terminal
+
+	// lastTimed is the app that rendered last, for GetTimings
+	lastTimed atomic.Pointer[App]
 )
 
 func init() {
@@ -53,10 +53,48 @@ type App struct {
 	currentView   string
 	viewStack     []string // pushed views (for modal overlays)
 
+	// Path routing (see Route)
+	routes      []*route
+	history     []routeEntry
+	historyPos  int
+	navEffect   Effect
+	navProgress float64 // transition progress, guarded by renderMu
+	navDuration time.Duration
+	navStart    time.Time // of the running transition, zero when done
+
+	// Key help (see ShowHelp)
+	keyHelp    map[*riffkey.Router][]keyEntry
+	lastAction string // last App.Handle action, for Describe
+	leader     string
+	help       *helpOverlay
+	whichKey   bool
+
+	// Command palette (see CommandPalette)
+	palette        *palette
+	commands       []keylessCommand
+	recentCommands []string // labels, most recent first
+
+	// Keymap (see Keymap)
+	keymap      *Keymap
+	actions     map[string]bool // registered action names
+	bound       map[*riffkey.Router]map[string][]boundAction
+	routerViews map[*riffkey.Router]string
+
 	// State
-	running    bool
+	running    atomic.Bool
 	renderMu   sync.Mutex
 	renderChan chan struct{}
+	done       chan struct{} // closed when Run returns
+	stream     *streamInput  // input from NewAppWithIO; nil on the process's terminal
+
+	// Suspend and Exec
+	released   atomic.Bool // terminal handed to the shell or a child
+	redrawFull bool        // next frame redraws everything, guarded by renderMu
+	jobSigs    chan os.Signal
+
+	// Lines printed above an inline view (see Println)
+	printMu sync.Mutex
+	printed [][]Span
 
 	// Cursor state
 	cursorX, cursorY int
@@ -74,6 +112,9 @@ type App struct {
 	// After-render callback (for cursor updates after layout is known)
 	onAfterRender func()
 
+	// Keymap problems callback, called by run once the views are wired
+	onKeymapError func(error)
+
 	// Active layer for cursor (set during template render)
 	activeLayer *Layer
 
@@ -93,8 +134,18 @@ type App struct {
 	frameCount    uint64
 	startTime     time.Time
 	lastFrameTime time.Time
-	defaultFG     Color // terminal's default FG (detected via OSC 10)
-	defaultBG     Color // terminal's default BG (detected via OSC 11)
+	frameCtx      PostContext   // this frame's, set before the view renders
+	nodeEffected  bool          // a container's Effect ran this frame
+	fps           int           // frame clock cap, defaultFPS if 0
+	clockEvery    time.Duration // frame clock interval, 0 when stopped
+	clockStop     chan struct{}
+	blurred       atomic.Bool // the terminal reported losing focus
+	defaultFG     Color       // terminal's default FG (detected via OSC 10)
+	defaultBG     Color       // terminal's default BG (detected via OSC 11)
+
+	// Frame timings (only populated when DebugTiming=true)
+	timingMu sync.Mutex
+	timings  frameTimings
 
 	// SetView limit (for catching anti-patterns)
 	setViewCount int
@@ -107,22 +158,58 @@ func NewApp() (*App, error) {
 	if err != nil {
 		return nil, err
 	}
+	return newApp(screen, os.Stdin), nil
+}
 
-	router := riffkey.NewRouter()
-	input := riffkey.NewInput(router)
-	reader := riffkey.NewReader(os.Stdin).SetUTF8(true)
+// NewAppWithIO creates a fullscreen application on a terminal other than
+// the process's own, such as an SSH session's: keys are read from in, frames
+// written to out, size reports the terminal's size and resize delivers its
+// changes (nil if it never resizes). The terminal is expected to be in raw
+// mode already, as a pty's far end is, so no termios or signals are touched
+// and any number of apps can run in one process.
+//
+// Stop ends Run without closing in; Run also returns, with io.EOF, when in
+// does. Ctrl-Z isn't bound, and Suspend does nothing.
+//
+//	app, _ := glyph.NewAppWithIO(session, session, size, resizes)
+func NewAppWithIO(in io.Reader, out io.Writer, size func() Size, resize <-chan Size) (*App, error) {
+	if in == nil || out == nil || size == nil {
+		return nil, errors.New("glyph: NewAppWithIO needs in, out and size")
+	}
+	stream := newStreamInput(in)
+	app := newApp(newStreamScreen(out, size, resize), stream.r)
+	app.stream = stream
+	return app, nil
+}
 
-	app := &App{
+// newApp creates an application drawing to screen, with keys from in.
+func newApp(screen *Screen, in io.Reader) *App {
+	router := riffkey.NewRouter()
+	a := &App{
 		screen:     screen,
 		router:     router,
-		input:      input,
-		reader:     reader,
+		input:      riffkey.NewInput(router),
 		renderChan: make(chan struct{}, 1),
 		jumpMode:   &JumpMode{},
 		jumpStyle:  DefaultJumpStyle,
 	}
+	a.reader = riffkey.NewReader(focusInput{recordedInput{in, screen}, a}).SetUTF8(true)
+	return a
+}
 
-	return app, nil
+// streamInput passes a stream's input on through a pipe, which Stop can
+// close to end the input loop without closing the stream.
+type streamInput struct {
+	r *io.PipeReader
+}
+
+func newStreamInput(in io.Reader) *streamInput {
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := io.Copy(pw, in)
+		pw.CloseWithError(err) // nil err closes with io.EOF
+	}()
+	return &streamInput{r: pr}
 }
 
 // NewInlineApp creates a new inline TUI application.
@@ -169,8 +256,11 @@ func (a *App) RunNonInteractive() error {
 		return fmt.Errorf("RunNonInteractive only works with inline apps")
 	}
 
-	a.running = true
+	a.running.Store(true)
 	a.nonInteractive = true
+	a.done = make(chan struct{})
+	defer close(a.done)
+	defer a.stopClock()
 
 	// Clean up buffer pool on exit
 	if a.pool != nil {
@@ -186,7 +276,7 @@ func (a *App) RunNonInteractive() error {
 	a.render()
 
 	// Wait for Stop() to be called
-	for a.running {
+	for a.running.Load() {
 		select {
 		case <-a.renderChan:
 			a.render()
@@ -195,8 +285,12 @@ func (a *App) RunNonInteractive() error {
 		}
 	}
 
-	// Clean up
+	// Clean up, printing any last lines above the final frame
+	if a.hasPrinted() {
+		a.render()
+	}
 	a.screen.ExitInlineMode(a.linesUsed, a.clearOnExit)
+	a.printHeld()
 	return nil
 }
 
@@ -236,7 +330,6 @@ func (a *App) SetView(view any) *App {
 
 	a.template = Build(view)
 	a.template.SetApp(a) // Link for jump mode support
-	a.template.requestRender = a.RequestRender
 	a.wireBindings(a.template, a.router)
 	// Create buffer pool for async clearing (or reuse existing)
 	size := a.screen.Size()
@@ -250,79 +343,20 @@ func (a *App) SetView(view any) *App {
 
 // wireBindings registers all declarative component bindings on the given router.
 func (a *App) wireBindings(tmpl *Template, router *riffkey.Router) {
+	view := a.routerViews[router]
 	for _, b := range tmpl.pendingBindings {
-		switch h := b.handler.(type) {
-		case func(riffkey.Match):
-			pattern := b.pattern
-			router.Handle(pattern, func(m riffkey.Match) { h(m); a.RequestRender() })
-		case func(any):
-			pattern := b.pattern
-			router.Handle(pattern, func(_ riffkey.Match) { h(nil); a.RequestRender() })
-		case func():
-			pattern := b.pattern
-			router.Handle(pattern, func(_ riffkey.Match) { h(); a.RequestRender() })
+		if h := a.handler(b.handler); h != nil {
+			a.bind(router, view, b, h, false)
 		}
 	}
 	// focus manager takes precedence over single pendingTIB
 	if fm := tmpl.pendingFocusManager; fm != nil {
 		// wire focus manager bindings (Tab/Shift-Tab) on the base router
 		for _, b := range fm.bindings() {
-			if h, ok := b.handler.(func(riffkey.Match)); ok {
-				pattern := b.pattern
-				router.Handle(pattern, func(m riffkey.Match) { h(m); a.RequestRender() })
-			}
-		}
-
-		// build a sub-router per focusable item.
-		// each gets pushed on focus and popped on blur.
-		fm.push = func(r *riffkey.Router) { a.Push(r) }
-		fm.pop = func() { a.Pop() }
-		fm.routers = make([]*riffkey.Router, len(fm.items))
-
-		for i, item := range fm.items {
-			sub := riffkey.NewRouter()
-
-			// common: Tab/Shift-Tab to cycle, Escape to blur
-			sub.Handle(fm.nextKey, func(_ riffkey.Match) { fm.Next(); a.RequestRender() })
-			if fm.prevKey != "" {
-				sub.Handle(fm.prevKey, func(_ riffkey.Match) { fm.Prev(); a.RequestRender() })
-			}
-			sub.Handle("<Escape>", func(_ riffkey.Match) { fm.BlurCurrent(); a.RequestRender() })
-
-			// sub-bindings (e.g., Enter for form submit)
-			for _, sb := range fm.subBindings {
-				switch h := sb.handler.(type) {
-				case func():
-					pattern := sb.pattern
-					sub.Handle(pattern, func(_ riffkey.Match) { h(); a.RequestRender() })
-				case func(riffkey.Match):
-					pattern := sb.pattern
-					sub.Handle(pattern, func(m riffkey.Match) { h(m); a.RequestRender() })
-				}
-			}
-
-			// per-item bindings (e.g., j/k for Radio, Space for Checkbox)
-			for _, cb := range item.bindings {
-				switch h := cb.handler.(type) {
-				case func():
-					pattern := cb.pattern
-					sub.Handle(pattern, func(_ riffkey.Match) { h(); a.RequestRender() })
-				case func(riffkey.Match):
-					pattern := cb.pattern
-					sub.Handle(pattern, func(m riffkey.Match) { h(m); a.RequestRender() })
-				}
-			}
-
-			if item.tib != nil {
-				// text input: route unmatched keys to TextHandler
-				th := fm.handlers[i]
-				sub.HandleUnmatched(th.HandleKey)
-				sub.NoCounts()
-			}
-
-			fm.routers[i] = sub
+			a.bind(router, view, b, a.handler(b.handler), false)
 		}
 
+		a.wireFocusRouters(fm, view)
 		fm.initialPush()
 	} else if tmpl.pendingTIB != nil {
 		th := riffkey.NewTextHandler(tmpl.pendingTIB.value, tmpl.pendingTIB.cursor)
@@ -334,13 +368,97 @@ func (a *App) wireBindings(tmpl *Template, router *riffkey.Router) {
 	for _, lv := range tmpl.pendingLogs {
 		lv.onUpdate = a.RequestRender
 	}
+	// search prompts push their own text-input router
+	for _, s := range tmpl.pendingSearches {
+		s.push = a.Push
+		s.pop = a.Pop
+		s.render = a.RequestRender
+	}
+	// wizard step forms get their routers; the current step takes input
+	for _, w := range tmpl.pendingWizards {
+		for _, fm := range w.focusManagers() {
+			a.wireFocusRouters(fm, view)
+		}
+		w.start()
+	}
+	// async validators redraw as they spin and settle
+	for _, ac := range tmpl.pendingAsync {
+		ac.mu.Lock()
+		ac.render = a.RequestRender
+		ac.mu.Unlock()
+	}
+	// terminals push a key-forwarding router while focused
+	for _, tc := range tmpl.pendingTerminals {
+		tc.push = a.Push
+		tc.pop = a.Pop
+		tc.render = a.RequestRender
+	}
+}
+
+// wireFocusRouters builds a sub-router per focusable item of fm.
+// each gets pushed on focus and popped on blur.
+func (a *App) wireFocusRouters(fm *FocusManager, view string) {
+	fm.push = func(r *riffkey.Router) { a.Push(r) }
+	fm.pop = func() { a.Pop() }
+	fm.render = a.RequestRender
+	fm.routers = make([]*riffkey.Router, len(fm.items))
+
+	for i, item := range fm.items {
+		sub := riffkey.NewRouter()
+
+		// blocked while the manager is disabled (e.g., a form submitting)
+		handle := func(b binding) {
+			if h := a.handler(b.handler); h != nil {
+				a.bind(sub, view, b, func(m riffkey.Match) {
+					if !fm.disabled.Load() {
+						h(m)
+					}
+				}, false)
+			}
+		}
+
+		// sub-bindings (e.g., Enter for form submit)
+		for _, sb := range fm.subBindings {
+			handle(sb)
+		}
+
+		// per-item bindings (e.g., j/k for Radio, Space for Checkbox)
+		for _, cb := range item.bindings {
+			handle(cb)
+		}
+
+		// common: Tab/Shift-Tab to cycle, Escape to blur, on keys the
+		// item's own bindings leave free
+		a.bind(sub, view, binding{pattern: fm.nextKey, name: "focus.next", desc: "next field", group: "Focus"},
+			func(_ riffkey.Match) { fm.Next(); a.RequestRender() }, true)
+		a.bind(sub, view, binding{pattern: fm.prevKey, name: "focus.prev", desc: "previous field", group: "Focus"},
+			func(_ riffkey.Match) { fm.Prev(); a.RequestRender() }, true)
+		a.bind(sub, view, binding{pattern: "<Escape>", name: "focus.leave", desc: "leave field", group: "Focus"},
+			func(_ riffkey.Match) { fm.BlurCurrent(); a.RequestRender() }, true)
+		a.bindSuspendOn(sub, view)
+
+		if item.tib != nil {
+			// text input: route unmatched keys to TextHandler
+			th := fm.handlers[i]
+			sub.HandleUnmatched(func(k riffkey.Key) bool {
+				if fm.disabled.Load() {
+					return true
+				}
+				return th.HandleKey(k)
+			})
+			sub.NoCounts()
+		}
+
+		fm.routers[i] = sub
+	}
 }
 
 // ViewBuilder allows chaining Handle() calls after View().
 type ViewBuilder struct {
-	app    *App
-	name   string
-	router *riffkey.Router
+	app        *App
+	name       string
+	router     *riffkey.Router
+	lastAction string // for Describe
 }
 
 // View registers a named view for multi-view routing.
@@ -370,6 +488,10 @@ func (a *App) View(name string, view any) *ViewBuilder {
 	tmpl := Build(view)
 	tmpl.SetApp(a) // Link for jump mode support
 	router := riffkey.NewRouter()
+	if a.routerViews == nil {
+		a.routerViews = make(map[*riffkey.Router]string)
+	}
+	a.routerViews[router] = name
 	a.wireBindings(tmpl, router)
 	a.viewTemplates[name] = tmpl
 	a.viewRouters[name] = router
@@ -395,13 +517,19 @@ func (vb *ViewBuilder) NoCounts() *ViewBuilder {
 // Accepts func(riffkey.Match), func(any), or func() for convenience.
 // Automatically requests a re-render after the handler runs.
 func (vb *ViewBuilder) Handle(pattern string, handler any) *ViewBuilder {
-	switch h := handler.(type) {
-	case func(riffkey.Match):
-		vb.router.Handle(pattern, func(m riffkey.Match) { h(m); vb.app.RequestRender() })
-	case func(any):
-		vb.router.Handle(pattern, func(_ riffkey.Match) { h(nil); vb.app.RequestRender() })
-	case func():
-		vb.router.Handle(pattern, func(_ riffkey.Match) { h(); vb.app.RequestRender() })
+	vb.lastAction = ""
+	if h := vb.app.handler(handler); h != nil {
+		vb.lastAction = vb.app.bind(vb.router, vb.name, binding{pattern: pattern}, h, false)
+	}
+	return vb
+}
+
+// HandleNamed registers a key handler as a named action, which a Keymap
+// can rebind or unbind for this view. See App.HandleNamed.
+func (vb *ViewBuilder) HandleNamed(name, pattern string, handler any) *ViewBuilder {
+	vb.lastAction = ""
+	if h := vb.app.handler(handler); h != nil {
+		vb.lastAction = vb.app.bind(vb.router, vb.name, binding{pattern: pattern, name: name, desc: actionDesc(name)}, h, false)
 	}
 	return vb
 }
@@ -431,11 +559,21 @@ func (a *App) Go(name string) {
 	a.RequestRender()
 }
 
-// Back returns to the previous view.
-// Currently an alias for Pop().
+// Back closes the top pushed router or view, if any, as for a modal.
+// Otherwise it returns to the previous route in the navigation history.
 func (a *App) Back() {
-	a.input.Pop()
-	a.RequestRender()
+	if a.input.Depth() > 1 {
+		if n := len(a.viewStack); n > 0 && a.viewRouters[a.viewStack[n-1]] == a.input.Current() {
+			a.PopView()
+			return
+		}
+		a.input.Pop()
+		a.RequestRender()
+		return
+	}
+	if len(a.routes) > 0 && a.historyPos > 0 {
+		a.enterRoute(a.historyPos - 1)
+	}
 }
 
 // PushView pushes a view as a modal overlay.
@@ -489,24 +627,30 @@ func (a *App) Input() *riffkey.Input {
 // Accepts func(riffkey.Match), func(any), or func() for convenience.
 // Automatically requests a re-render after the handler runs.
 func (a *App) Handle(pattern string, handler any) *App {
-	switch h := handler.(type) {
-	case func(riffkey.Match):
-		a.router.Handle(pattern, func(m riffkey.Match) { h(m); a.RequestRender() })
-	case func(any):
-		a.router.Handle(pattern, func(_ riffkey.Match) { h(nil); a.RequestRender() })
-	case func():
-		a.router.Handle(pattern, func(_ riffkey.Match) { h(); a.RequestRender() })
+	a.lastAction = ""
+	if h := a.handler(handler); h != nil {
+		a.lastAction = a.bind(a.router, "", binding{pattern: pattern}, h, false)
 	}
 	return a
 }
 
-// HandleNamed registers a named key binding (for rebinding support).
-// Automatically requests a re-render after the handler runs.
-func (a *App) HandleNamed(name, pattern string, handler func(riffkey.Match)) *App {
-	a.router.HandleNamed(name, pattern, func(m riffkey.Match) { handler(m); a.RequestRender() })
+// HandleNamed registers a key binding as a named action, which a Keymap
+// can rebind or unbind. The name, with underscores as spaces, is its
+// description in help. Accepts the same handlers as Handle.
+func (a *App) HandleNamed(name, pattern string, handler any) *App {
+	a.lastAction = ""
+	if h := a.handler(handler); h != nil {
+		a.lastAction = a.bind(a.router, "", binding{pattern: pattern, name: name, desc: actionDesc(name)}, h, false)
+	}
 	return a
 }
 
+// actionDesc derives a help description from an action name,
+// e.g. "go_top" as "go top".
+func actionDesc(name string) string {
+	return strings.ReplaceAll(name, "_", " ")
+}
+
 // BindField routes unmatched keys to a text input field.
 func (a *App) BindField(f *InputState) *App {
 	a.router.TextInput(&f.Value, &f.Cursor)
@@ -631,7 +775,12 @@ func (a *App) render() {
 	a.renderMu.Lock()
 	defer a.renderMu.Unlock()
 
+	if a.released.Load() {
+		return // the terminal isn't ours; see Suspend
+	}
+
 	var t0, t1 time.Time
+	var ft frameTimings
 	if DebugTiming {
 		t0 = time.Now()
 	}
@@ -683,6 +832,29 @@ func (a *App) render() {
 			return // No view set
 		}
 	}
+	// effects on nodes run during render, so the frame's context comes first
+	now := time.Now()
+	if a.startTime.IsZero() {
+		a.startTime = now
+	}
+	var delta time.Duration
+	if !a.lastFrameTime.IsZero() {
+		delta = now.Sub(a.lastFrameTime)
+	}
+	a.lastFrameTime = now
+	a.stepNavTransition(now)
+	a.frameCtx = PostContext{
+		Width:     size.Width,
+		Height:    int(renderHeight),
+		Frame:     a.frameCount,
+		Delta:     delta,
+		Time:      now.Sub(a.startTime),
+		DefaultFG: a.defaultFG,
+		DefaultBG: a.defaultBG,
+		Bounds:    Rect{W: size.Width, H: int(renderHeight)},
+	}
+	a.nodeEffected = false
+
 	activeTmpl.Execute(buf, int16(size.Width), renderHeight)
 
 	// for inline auto-size, use content height instead of full terminal height
@@ -692,6 +864,9 @@ func (a *App) render() {
 		}
 	}
 
+	// help overlay and which-key popup sit above the view
+	a.drawKeyOverlays(buf, size.Width, int(renderHeight))
+
 	// apply layer cursor if one was set during template render
 	if a.activeLayer != nil {
 		if x, y, visible := a.activeLayer.ScreenCursor(); visible {
@@ -709,14 +884,13 @@ func (a *App) render() {
 
 	if DebugTiming {
 		t1 = time.Now()
-		lastBuildTime = 0
-		lastLayoutTime = 0
-		lastRenderTime = t1.Sub(t0)
+		ft.render = t1.Sub(t0)
 	}
 
 	// post-processing pipeline: tree-declared ScreenEffects first, then imperative
 	treeEffects := activeTmpl.ScreenEffects()
-	a.screen.forceRGB = len(treeEffects) > 0 || len(a.postProcess) > 0
+	navActive := a.navEffect != nil && a.navProgress > 0
+	a.screen.forceRGB = len(treeEffects) > 0 || len(a.postProcess) > 0 || navActive || a.nodeEffected
 	if a.screen.forceRGB {
 		var tEffect time.Time
 		if DebugTiming {
@@ -724,46 +898,36 @@ func (a *App) render() {
 		}
 
 		// resolve Color16 cells to detected palette RGB before effects run
-		resolveColor16(buf, size.Width, int(renderHeight))
+		resolveColor16(buf, size.Width, int(renderHeight), &a.screen.palette)
 
-		now := time.Now()
-		if a.startTime.IsZero() {
-			a.startTime = now
-		}
-		var delta time.Duration
-		if !a.lastFrameTime.IsZero() {
-			delta = now.Sub(a.lastFrameTime)
-		}
-		a.lastFrameTime = now
-
-		ppCtx := PostContext{
-			Width:     size.Width,
-			Height:    int(renderHeight),
-			Frame:     a.frameCount,
-			Delta:     delta,
-			Time:      now.Sub(a.startTime),
-			DefaultFG: a.defaultFG,
-			DefaultBG: a.defaultBG,
-		}
+		ppCtx := a.frameCtx
+		ppCtx.Height = int(renderHeight)
+		ppCtx.Bounds.H = ppCtx.Height
 		for _, pp := range treeEffects {
 			pp.Apply(buf, ppCtx)
 		}
 		for _, pp := range a.postProcess {
 			pp.Apply(buf, ppCtx)
 		}
+		if navActive {
+			a.navEffect.Apply(buf, ppCtx)
+		}
 		a.frameCount++
 
 		if DebugTiming {
-			lastEffectTime = time.Since(tEffect)
+			ft.effect = time.Since(tEffect)
 		}
 	}
 
+	// keep frames coming while something in view moves with time
+	a.runClock(a.frameInterval(activeTmpl, treeEffects))
+
 	// Copy to screen's back buffer for flush
 	a.copyToScreen(buf)
 
 	if a.inline {
 		// Inline mode: render at cursor position
-		a.linesUsed = a.screen.FlushInline(int(renderHeight), a.linesUsed)
+		a.linesUsed = a.screen.flushInline(a.takePrinted(), int(renderHeight), a.linesUsed)
 		a.pool.Swap() // Queue async clear
 	} else {
 		// Fullscreen mode
@@ -771,13 +935,14 @@ func (a *App) render() {
 		if DebugTiming {
 			tDiff = time.Now()
 		}
-		if DebugFullRedraw {
+		if DebugFullRedraw || a.redrawFull {
 			a.screen.FlushFull()
+			a.redrawFull = false
 		} else {
 			a.screen.Flush() // diff + escape-sequence building
 		}
 		if DebugTiming {
-			lastDiffTime = time.Since(tDiff)
+			ft.diff = time.Since(tDiff)
 		}
 		a.pool.Swap()
 
@@ -793,12 +958,16 @@ func (a *App) render() {
 		}
 		a.screen.FlushBuffer() // single Write() syscall to terminal
 		if DebugTiming {
-			lastWriteTime = time.Since(tWrite)
+			ft.write = time.Since(tWrite)
 		}
 	}
 
 	if DebugTiming {
-		lastFlushTime = time.Since(t1)
+		ft.flush = time.Since(t1)
+		a.timingMu.Lock()
+		a.timings = ft
+		a.timingMu.Unlock()
+		lastTimed.Store(a)
 	}
 }
 
@@ -808,13 +977,35 @@ func (a *App) copyToScreen(src *Buffer) {
 	dst.CopyFrom(src) // Fast bulk copy
 }
 
-// TimingString returns a formatted timing string.
-func TimingString() string {
+// frameTimings holds how long the phases of a frame took.
+type frameTimings struct {
+	render, flush time.Duration
+	effect        time.Duration // resolveColor16 + all Effect passes
+	diff          time.Duration // Flush(): diff + escape-sequence building
+	write         time.Duration // FlushBuffer(): Write() syscall to terminal
+}
+
+// TimingString returns a formatted timing string for the app's last frame.
+func (a *App) TimingString() string {
+	a.timingMu.Lock()
+	ft := a.timings
+	a.timingMu.Unlock()
 	return fmt.Sprintf("render:%v effect:%v diff:%v write:%v",
-		lastRenderTime.Round(time.Microsecond),
-		lastEffectTime.Round(time.Microsecond),
-		lastDiffTime.Round(time.Microsecond),
-		lastWriteTime.Round(time.Microsecond))
+		ft.render.Round(time.Microsecond),
+		ft.effect.Round(time.Microsecond),
+		ft.diff.Round(time.Microsecond),
+		ft.write.Round(time.Microsecond))
+}
+
+// TimingString returns a formatted timing string for the last frame of the
+// app that rendered last.
+//
+// Deprecated: use App.TimingString, which is per app.
+func TimingString() string {
+	if a := lastTimed.Load(); a != nil {
+		return a.TimingString()
+	}
+	return (&App{}).TimingString()
 }
 
 // Timings holds timing data for the last frame.
@@ -830,17 +1021,29 @@ type Timings struct {
 	WriteUs  float64 // FlushBuffer(): Write() syscall — time spent waiting on terminal
 }
 
-// GetTimings returns the timing data for the last frame.
-func GetTimings() Timings {
+// Timings returns the timing data for the app's last frame. Set DebugTiming
+// to collect it.
+func (a *App) Timings() Timings {
+	a.timingMu.Lock()
+	defer a.timingMu.Unlock()
 	return Timings{
-		BuildUs:  float64(lastBuildTime.Microseconds()),
-		LayoutUs: float64(lastLayoutTime.Microseconds()),
-		RenderUs: float64(lastRenderTime.Microseconds()),
-		FlushUs:  float64(lastFlushTime.Microseconds()),
-		EffectUs: float64(lastEffectTime.Microseconds()),
-		DiffUs:   float64(lastDiffTime.Microseconds()),
-		WriteUs:  float64(lastWriteTime.Microseconds()),
+		RenderUs: float64(a.timings.render.Microseconds()),
+		FlushUs:  float64(a.timings.flush.Microseconds()),
+		EffectUs: float64(a.timings.effect.Microseconds()),
+		DiffUs:   float64(a.timings.diff.Microseconds()),
+		WriteUs:  float64(a.timings.write.Microseconds()),
+	}
+}
+
+// GetTimings returns the timing data for the last frame of the app that
+// rendered last.
+//
+// Deprecated: use App.Timings, which is per app.
+func GetTimings() Timings {
+	if a := lastTimed.Load(); a != nil {
+		return a.Timings()
 	}
+	return Timings{}
 }
 
 // Run starts the application. Blocks until Stop is called.
@@ -850,22 +1053,40 @@ func (a *App) Run() error {
 }
 
 // RunFrom starts the application on the specified view.
-// Use this for multi-view apps.
+// Use this for multi-view apps. A path such as "/pods" navigates to the
+// matching Route.
 func (a *App) RunFrom(startView string) error {
 	return a.run(startView)
 }
 
 func (a *App) run(startView string) error {
-	a.running = true
-
-	// Set up starting view if specified
-	if startView != "" && a.viewTemplates != nil {
+	if a.stream == nil {
+		a.bindSuspend()
+	}
+	a.running.Store(true)
+	a.done = make(chan struct{})
+	defer close(a.done)
+	defer a.stopClock()
+
+	// Set up starting view if specified; paths navigate to a route
+	if strings.HasPrefix(startView, "/") && len(a.routes) > 0 {
+		if err := a.Navigate(startView); err != nil {
+			return err
+		}
+	} else if startView != "" && a.viewTemplates != nil {
 		a.currentView = startView
 		if router, ok := a.viewRouters[startView]; ok {
 			a.input.SetRouter(router)
 		}
 	}
 
+	// Keymap problems don't stop the app; hand them over to be shown
+	if a.onKeymapError != nil {
+		if err := a.KeymapErr(); err != nil {
+			a.onKeymapError(err)
+		}
+	}
+
 	// Clean up buffer pool on exit if using fast path
 	if a.pool != nil {
 		defer a.pool.Stop()
@@ -877,17 +1098,34 @@ func (a *App) run(startView string) error {
 			return err
 		}
 		// Use closure so linesUsed is read at defer time, not now (when it's 0)
-		defer func() { a.screen.ExitInlineMode(a.linesUsed, a.clearOnExit) }()
+		defer func() {
+			if a.hasPrinted() {
+				a.render() // print the last lines above the final frame
+			}
+			a.screen.ExitInlineMode(a.linesUsed, a.clearOnExit)
+			a.printHeld()
+		}()
 	} else {
 		if err := a.screen.EnterRawMode(); err != nil {
 			return err
 		}
-		defer a.screen.ExitRawMode()
+		defer func() {
+			a.screen.ExitRawMode()
+			a.printHeld()
+		}()
 	}
 
 	// Handle resize
 	go a.handleResize()
 
+	// Handle SIGTSTP sent from outside (Ctrl-Z itself arrives as a key)
+	if a.stream == nil {
+		a.jobSigs = make(chan os.Signal, 1)
+		signal.Notify(a.jobSigs, syscall.SIGTSTP)
+		defer signal.Stop(a.jobSigs)
+		go a.handleJobSignals(a.jobSigs)
+	}
+
 	// Handle async render requests (from timers, data updates, etc)
 	go a.handleRenderRequests()
 
@@ -897,14 +1135,19 @@ func (a *App) run(startView string) error {
 
 	// Detect terminal's default colours for post-processing.
 	// Runs after first render so the blank gap is gone; runs before input.Run so
-	// there's no race on stdin.
-	a.defaultFG, a.defaultBG = a.screen.QueryDefaultColors()
+	// there's no race on stdin. The round trip can take 100ms, so only the
+	// results are set under the render lock.
+	fg, bg, palette := a.screen.queryColors()
+	a.renderMu.Lock()
+	a.defaultFG, a.defaultBG = fg, bg
+	a.screen.setPalette(palette)
+	a.renderMu.Unlock()
 	a.RequestRender()
 
 	// Run riffkey input loop
 	// afterDispatch is called after every key - perfect for rendering
 	err := a.input.Run(a.reader, func(handled bool) {
-		if !a.running {
+		if !a.running.Load() {
 			return
 		}
 		// Always render after input (state may have changed)
@@ -912,9 +1155,9 @@ func (a *App) run(startView string) error {
 	})
 
 	// Normal termination via Stop() causes reader to return error
-	if !a.running {
+	if !a.running.Load() {
 		// Reopen stdin for inline apps so subsequent apps can use it
-		if a.inline {
+		if a.inline && a.stream == nil {
 			reopenStdin()
 		}
 		return nil
@@ -927,19 +1170,23 @@ func (a *App) handleRenderRequests() {
 	for {
 		select {
 		case <-a.renderChan:
-			if !a.running {
+			if !a.running.Load() {
 				return
 			}
 			a.render()
+		case <-a.done:
+			return
 		}
 	}
 }
 
 // Stop signals the application to stop.
 func (a *App) Stop() {
-	a.running = false
+	a.running.Store(false)
 	// Close stdin to unblock the input reader (not needed for non-interactive)
-	if !a.nonInteractive {
+	if a.stream != nil {
+		a.stream.r.Close()
+	} else if !a.nonInteractive {
 		os.Stdin.Close()
 	}
 }
@@ -955,7 +1202,13 @@ func reopenStdin() {
 
 // handleResize watches for terminal resize events.
 func (a *App) handleResize() {
-	for size := range a.screen.ResizeChan() {
+	for {
+		var size Size
+		select {
+		case size = <-a.screen.ResizeChan():
+		case <-a.done:
+			return
+		}
 		// Resize the buffer pool to match new terminal dimensions
 		if a.pool != nil {
 			a.pool.Resize(size.Width, size.Height)
@@ -973,6 +1226,18 @@ func (a *App) Size() Size {
 	return a.screen.Size()
 }
 
+// Palette returns the terminal's basic 16 colours as detected when the app
+// started, or the standard ANSI colours. Set ExportOptions.Palette to it to
+// export frames in the terminal's colours.
+func (a *App) Palette() [16][3]uint8 {
+	return a.screen.palette
+}
+
+// FlushStats returns stats from the app's last frame.
+func (a *App) FlushStats() FlushStats {
+	return a.screen.FlushStats()
+}
+
 // =============================================================================
 // Jump Labels
 // =============================================================================
@@ -980,9 +1245,9 @@ func (a *App) Size() Size {
 // JumpKey registers a key pattern to trigger jump mode.
 // This is a convenience method that calls EnterJumpMode when the key is pressed.
 func (a *App) JumpKey(pattern string) *App {
-	a.router.Handle(pattern, func(_ riffkey.Match) {
+	a.bind(a.router, "", binding{pattern: pattern, desc: "jump"}, func(_ riffkey.Match) {
 		a.EnterJumpMode()
-	})
+	}, false)
 	return a
 }
 
@@ -0,0 +1,95 @@
+package glyph
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// streamTerm is the far end of an app on NewAppWithIO: a terminal emulator
+// fed the app's output, and a pipe to type into.
+type streamTerm struct {
+	mu     sync.Mutex
+	vt     *vtScreen
+	keys   *io.PipeWriter
+	resize chan Size
+	app    *App
+}
+
+func newStreamTerm(t *testing.T, w, h int) *streamTerm {
+	t.Helper()
+	pr, pw := io.Pipe()
+	st := &streamTerm{vt: newVTScreen(w, h), keys: pw, resize: make(chan Size, 1)}
+	size := func() Size { return Size{w, h} }
+	app, err := NewAppWithIO(pr, st, size, st.resize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st.app = app
+	return st
+}
+
+func (st *streamTerm) Write(p []byte) (int, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.vt.Write(p)
+}
+
+func (st *streamTerm) screen() string {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return strings.Join(vtLines(st.vt), "|")
+}
+
+func (st *streamTerm) width() int {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.vt.grid.Width()
+}
+
+func TestNewAppWithIO(t *testing.T) {
+	if _, err := NewAppWithIO(nil, io.Discard, nil, nil); err == nil {
+		t.Error("NewAppWithIO without streams succeeded")
+	}
+
+	// two sessions in one process, each with its own state
+	terms := []*streamTerm{newStreamTerm(t, 12, 2), newStreamTerm(t, 16, 3)}
+	errs := make(chan error, len(terms))
+	for i, st := range terms {
+		var n atomic.Int32
+		st.app.SetView(Text(func() string { return fmt.Sprintf("s%d n=%d", i, n.Load()) }))
+		st.app.Handle("j", func() { n.Add(1) })
+		st.app.Handle("q", st.app.Stop)
+		go func() { errs <- st.app.Run() }()
+	}
+
+	for i, st := range terms {
+		waitFor(t, "first frame", func() bool { return strings.HasPrefix(st.screen(), fmt.Sprintf("s%d n=0", i)) })
+	}
+	terms[1].keys.Write([]byte("jj"))
+	waitFor(t, "keys", func() bool { return strings.HasPrefix(terms[1].screen(), "s1 n=2") })
+	if got := terms[0].screen(); !strings.HasPrefix(got, "s0 n=0") {
+		t.Errorf("other session = %q", got)
+	}
+
+	// resizes come from the channel
+	terms[0].resize <- Size{20, 2}
+	waitFor(t, "resize", func() bool { return terms[0].app.Size().Width == 20 })
+
+	// Stop ends Run without the input closing; closing it ends Run too
+	terms[0].keys.Write([]byte("q"))
+	if err := <-errs; err != nil {
+		t.Errorf("stopped Run = %v", err)
+	}
+	terms[1].keys.Close()
+	if err := <-errs; !errors.Is(err, io.EOF) {
+		t.Errorf("Run after input closed = %v", err)
+	}
+	if terms[0].width() != 12 {
+		t.Errorf("emulator width changed to %d", terms[0].width())
+	}
+}
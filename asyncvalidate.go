@@ -0,0 +1,206 @@
+package glyph
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// AsyncValidator validates a string in the background, for checks that hit
+// the network or disk. ctx is cancelled when the value changes before the
+// check finishes. Pass to Input().ValidateAsync().
+type AsyncValidator func(ctx context.Context, s string) error
+
+// defaultDebounce is how long an input waits after the last keystroke
+// before starting an async check.
+const defaultDebounce = 300 * time.Millisecond
+
+// asyncCheck runs an AsyncValidator off the input goroutine. Each new value
+// supersedes the previous one: its timer is stopped, its context cancelled
+// and its result discarded.
+type asyncCheck struct {
+	fn       AsyncValidator
+	debounce time.Duration
+	render   func() // set by the app during wiring
+	onDone   func() // result available, called on the render pass (wired by Form)
+
+	mu      sync.Mutex
+	gen     uint64
+	timer   *time.Timer
+	cancel  context.CancelFunc
+	value   string        // value the latest check is for
+	checked bool          // err holds the result for value
+	err     string        // error from the latest finished check
+	done    chan struct{} // closed when the latest check settles
+	running bool          // validator is executing
+	settled bool          // a finished check is waiting to be reported
+
+	spinning bool // render copy of running, written only by sync
+}
+
+// schedule starts a check for value after delay, unless one for the same
+// value is already pending or finished.
+func (a *asyncCheck) schedule(value string, delay time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.value == value && (a.checked || a.done != nil) {
+		return
+	}
+	a.stop()
+	a.gen++
+	gen := a.gen
+	a.value, a.checked, a.err = value, false, ""
+	if a.done == nil {
+		a.done = make(chan struct{})
+	}
+	a.timer = time.AfterFunc(delay, func() { a.run(gen, value) })
+}
+
+// reset abandons any pending check and forgets the last result.
+func (a *asyncCheck) reset() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.stop()
+	a.gen++
+	a.value, a.checked, a.err = "", false, ""
+	a.running = false
+	if a.done != nil {
+		close(a.done)
+		a.done = nil
+	}
+}
+
+// stop halts the current timer and cancels a running validator. Caller holds mu.
+func (a *asyncCheck) stop() {
+	if a.timer != nil {
+		a.timer.Stop()
+		a.timer = nil
+	}
+	if a.cancel != nil {
+		a.cancel()
+		a.cancel = nil
+	}
+}
+
+func (a *asyncCheck) run(gen uint64, value string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	a.mu.Lock()
+	if gen != a.gen {
+		a.mu.Unlock()
+		cancel()
+		return
+	}
+	a.cancel = cancel
+	a.running = true
+	render := a.render
+	a.mu.Unlock()
+
+	if render != nil {
+		render()
+	}
+	err := a.fn(ctx, value)
+	cancel()
+
+	a.mu.Lock()
+	if gen != a.gen {
+		a.mu.Unlock()
+		return
+	}
+	a.cancel = nil
+	a.running = false
+	a.checked = true
+	if err != nil {
+		a.err = err.Error()
+	}
+	a.settled = true
+	done := a.done
+	a.done = nil
+	render = a.render
+	a.mu.Unlock()
+
+	close(done)
+	if render != nil {
+		render()
+	}
+}
+
+// sync publishes progress to the render pass: it updates the spinner and
+// reports a finished check to onDone. Registered as a template eval, so the
+// validator goroutine never touches state the view reads.
+func (a *asyncCheck) sync() {
+	a.mu.Lock()
+	a.spinning = a.running
+	settled := a.settled
+	a.settled = false
+	a.mu.Unlock()
+	if settled && a.onDone != nil {
+		a.onDone()
+	}
+}
+
+// result returns the error for value if its check has finished.
+func (a *asyncCheck) result(value string) (string, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.checked && a.value == value {
+		return a.err, true
+	}
+	return "", false
+}
+
+// failed reports whether the latest finished check returned an error.
+func (a *asyncCheck) failed() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.checked && a.err != ""
+}
+
+// pending reports whether a check is scheduled or running.
+func (a *asyncCheck) pending() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.done != nil
+}
+
+// wait blocks until no check is pending or ctx is done.
+func (a *asyncCheck) wait(ctx context.Context) error {
+	for {
+		a.mu.Lock()
+		done := a.done
+		a.mu.Unlock()
+		if done == nil {
+			return nil
+		}
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// SubmitState is the progress of a form submitted with OnSubmitAsync.
+type SubmitState uint8
+
+const (
+	SubmitIdle       SubmitState = iota // not yet submitted
+	SubmitValidating                    // waiting on async validators
+	Submitting                          // OnSubmitAsync is running
+	Submitted                           // OnSubmitAsync returned nil
+	SubmitFailed                        // OnSubmitAsync returned an error
+)
+
+// String returns the state name.
+func (s SubmitState) String() string {
+	switch s {
+	case SubmitValidating:
+		return "validating"
+	case Submitting:
+		return "submitting"
+	case Submitted:
+		return "submitted"
+	case SubmitFailed:
+		return "failed"
+	}
+	return "idle"
+}
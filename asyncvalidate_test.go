@@ -0,0 +1,262 @@
+package glyph
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// typeInto sets the input's text as if typed.
+func typeInto(in *InputC, s string) {
+	in.field.Value, in.field.Cursor = s, len(s)
+	in.handleChange(s)
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestInputValidateAsyncDebounce(t *testing.T) {
+	var mu sync.Mutex
+	var seen []string
+	in := Input().Debounce(20 * time.Millisecond).ValidateAsync(func(ctx context.Context, s string) error {
+		mu.Lock()
+		seen = append(seen, s)
+		mu.Unlock()
+		if s == "taken" {
+			return errors.New("already taken")
+		}
+		return nil
+	})
+
+	for _, s := range []string{"t", "ta", "tak", "take", "taken"} {
+		typeInto(in, s)
+	}
+	if !in.Pending() || in.Err() != "" {
+		t.Fatalf("pending=%v err=%q", in.Pending(), in.Err())
+	}
+	if err := in.async.wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	got := strings.Join(seen, ",")
+	mu.Unlock()
+	if got != "taken" {
+		t.Errorf("validated %q, want only the last value", got)
+	}
+	if in.Err() != "already taken" {
+		t.Errorf("err = %q", in.Err())
+	}
+
+	// the result belongs to its value
+	in.field.Value = "other"
+	if in.Err() != "" {
+		t.Errorf("stale err = %q", in.Err())
+	}
+}
+
+func TestInputValidateAsyncCancels(t *testing.T) {
+	cancelled := make(chan string, 1)
+	in := Input().Debounce(0).ValidateAsync(func(ctx context.Context, s string) error {
+		if s == "slow" {
+			<-ctx.Done()
+			cancelled <- s
+			return ctx.Err()
+		}
+		return nil
+	})
+
+	typeInto(in, "slow")
+	waitFor(t, "slow check to start", func() bool {
+		in.async.mu.Lock()
+		defer in.async.mu.Unlock()
+		return in.async.running
+	})
+	typeInto(in, "fast")
+	select {
+	case s := <-cancelled:
+		if s != "slow" {
+			t.Errorf("cancelled %q", s)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("superseded check was not cancelled")
+	}
+	if err := in.async.wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if in.Err() != "" {
+		t.Errorf("err = %q, cancelled result leaked", in.Err())
+	}
+
+	// a failing sync validator drops the async check
+	in.Validate(VRequired, VOnChange)
+	typeInto(in, "")
+	if in.Pending() || in.Err() != "required" {
+		t.Errorf("pending=%v err=%q", in.Pending(), in.Err())
+	}
+}
+
+func TestInputValidateAsyncSpinner(t *testing.T) {
+	release := make(chan struct{})
+	in := Input().Width(10).Debounce(0).ValidateAsync(func(ctx context.Context, s string) error {
+		<-release
+		return nil
+	})
+	tmpl := Build(VBox(in))
+	if len(tmpl.pendingAsync) != 1 {
+		t.Fatalf("pendingAsync = %d", len(tmpl.pendingAsync))
+	}
+
+	typeInto(in, "bob")
+	waitFor(t, "check to start", func() bool {
+		in.async.mu.Lock()
+		defer in.async.mu.Unlock()
+		return in.async.running
+	})
+	buf := NewBuffer(20, 1)
+	tmpl.Execute(buf, 20, 1)
+	if got := buf.GetLine(0); !strings.HasPrefix(got, "bob") || !strings.ContainsAny(got, strings.Join(SpinnerBraille, "")) {
+		t.Errorf("pending row = %q", got)
+	}
+
+	close(release)
+	in.async.wait(context.Background())
+	buf.Clear()
+	tmpl.Execute(buf, 20, 1)
+	if got := strings.TrimRight(buf.GetLine(0), " "); got != "bob" {
+		t.Errorf("settled row = %q", got)
+	}
+}
+
+func TestFormLevelValidate(t *testing.T) {
+	pass, confirm := "secret", "secrte"
+	form := Form.Validate(func() map[string]error {
+		errs := map[string]error{}
+		if pass != confirm {
+			errs["Confirm"] = errors.New("passwords differ")
+		}
+		if len(pass) < 8 {
+			errs["policy"] = errors.New("password too short")
+		}
+		return errs
+	})(
+		Field("Password", Input(&pass)),
+		Field("Confirm", Input(&confirm)),
+	)
+
+	if form.ValidateAll() {
+		t.Fatal("expected errors")
+	}
+	if form.fields[1].err != "passwords differ" || form.fields[0].err != "" {
+		t.Errorf("field errs = %q, %q", form.fields[0].err, form.fields[1].err)
+	}
+	buf := NewBuffer(40, 6)
+	Build(form).Execute(buf, 40, 6)
+	if out := buf.StringTrimmed(); !strings.Contains(out, "password too short") {
+		t.Errorf("form error not shown:\n%s", out)
+	}
+
+	pass, confirm = "long enough", "long enough"
+	if !form.ValidateAll() || form.formErr != "" || form.fields[1].err != "" {
+		t.Errorf("still invalid: %q %q", form.formErr, form.fields[1].err)
+	}
+}
+
+func TestFormSubmitAsync(t *testing.T) {
+	release := make(chan error)
+	name := ""
+	form := Form.OnSubmitAsync(func(ctx context.Context) error {
+		return <-release
+	})(
+		Field("User", Input(&name).Debounce(50*time.Millisecond).ValidateAsync(func(ctx context.Context, s string) error {
+			if s == "root" {
+				return errors.New("reserved")
+			}
+			return nil
+		})),
+	)
+	in := form.fields[0].control.(*InputC)
+	fm := form.FocusManager()
+	tmpl := Build(form)
+	buf := NewBuffer(40, 4)
+
+	// submit waits out the debounce; the async failure is reported on
+	// the field at the next render and the form returns to idle
+	typeInto(in, "root")
+	form.submit()
+	if form.State() != SubmitValidating || !fm.Disabled() {
+		t.Errorf("state=%v disabled=%v", form.State(), fm.Disabled())
+	}
+	waitFor(t, "validation", func() bool { return form.State() == SubmitIdle && !fm.Disabled() })
+	tmpl.Execute(buf, 40, 4)
+	if form.fields[0].err != "reserved" {
+		t.Errorf("field err = %q", form.fields[0].err)
+	}
+
+	typeInto(in, "ada")
+	form.submit()
+	waitFor(t, "submitting", func() bool { return form.State() == Submitting })
+	if !fm.Disabled() {
+		t.Error("input should be disabled while submitting")
+	}
+	form.submit() // ignored while busy
+	release <- nil
+	waitFor(t, "submitted", func() bool { return form.State() == Submitted })
+	tmpl.Execute(buf, 40, 4)
+	if fm.Disabled() || form.fields[0].err != "" {
+		t.Errorf("disabled=%v err=%q", fm.Disabled(), form.fields[0].err)
+	}
+
+	form.submit()
+	waitFor(t, "submitting", func() bool { return form.State() == Submitting })
+	release <- errors.New("server down")
+	waitFor(t, "failed", func() bool { return form.State() == SubmitFailed })
+	if form.SubmitErr() == nil || form.SubmitErr().Error() != "server down" {
+		t.Errorf("submit err = %v", form.SubmitErr())
+	}
+	buf.Clear()
+	tmpl.Execute(buf, 40, 4)
+	if out := buf.StringTrimmed(); !strings.Contains(out, "✗ server down") {
+		t.Errorf("status not shown:\n%s", out)
+	}
+}
+
+func TestFormSubmitAsyncRendersConcurrently(t *testing.T) {
+	name := ""
+	form := Form.OnSubmitAsync(func(ctx context.Context) error {
+		time.Sleep(5 * time.Millisecond)
+		return nil
+	})(
+		Field("User", Input(&name).Debounce(time.Millisecond).ValidateAsync(func(ctx context.Context, s string) error {
+			time.Sleep(5 * time.Millisecond)
+			return nil
+		})),
+	)
+	in := form.fields[0].control.(*InputC)
+	tmpl := Build(form)
+	buf := NewBuffer(40, 4)
+
+	// this goroutine plays the UI, rendering while the validator and the
+	// submit run in the background; under -race any shared write shows up
+	typeInto(in, "ada")
+	form.submit()
+	waitFor(t, "submitted", func() bool {
+		tmpl.Execute(buf, 40, 4)
+		return form.State() == Submitted
+	})
+	buf.Clear()
+	tmpl.Execute(buf, 40, 4)
+	if out := buf.StringTrimmed(); !strings.Contains(out, "✓ submitted") {
+		t.Errorf("status not shown:\n%s", out)
+	}
+}
@@ -0,0 +1,439 @@
+package glyph
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OptionsProvider is implemented by enum-like field types to list their
+// choices. AutoForm renders such fields as a Radio group.
+type OptionsProvider interface {
+	Options() []string
+}
+
+var (
+	optionsProviderType = reflect.TypeFor[OptionsProvider]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+	durationType        = reflect.TypeFor[time.Duration]()
+)
+
+// autoField binds one form control to a struct field.
+type autoField struct {
+	path     string
+	value    reflect.Value // settable struct field
+	formIdx  int           // index into the form's fields
+	input    *InputC
+	checked  *bool
+	selected *int
+	options  []string
+}
+
+// AutoFormC is a Form generated from a struct. See AutoForm.
+type AutoFormC struct {
+	target     reflect.Value
+	options    map[string]func() []string
+	labelStyle Style
+	gap        int8
+	onSubmit   func()
+
+	form   *FormC
+	fields []autoField
+}
+
+// AutoForm creates a form from the exported fields of the struct pointed to
+// by ptr. Controls are picked by type: Input for strings and numbers,
+// Checkbox for bools, Radio for types implementing OptionsProvider or fields
+// with an options tag, and a titled group for nested structs. Edited values
+// are parsed and written back to the struct on submit.
+//
+// Fields are configured with the glyph tag:
+//
+//	type Config struct {
+//	    Host  string        `glyph:"label=Host,placeholder=localhost,validate=required"`
+//	    Port  int           `glyph:"placeholder=8080,min=1,max=65535"`
+//	    Mode  string        `glyph:"options=dev|staging|prod"`
+//	    Debug bool
+//	    Wait  time.Duration
+//	    Token string        `glyph:"-"`
+//	}
+//
+// validate accepts required and email, joined with |. min and max bound
+// numbers by value and strings by length. Zero numbers start empty so the
+// placeholder shows, and an empty number submits as zero.
+func AutoForm(ptr any) *AutoFormC {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		panic("glyph: AutoForm requires a pointer to a struct")
+	}
+	return &AutoFormC{target: v.Elem()}
+}
+
+// Options sets the choices for a field, by name. Nested fields use a
+// dotted path such as "Server.Mode". Overrides the type's OptionsProvider.
+func (a *AutoFormC) Options(field string, fn func() []string) *AutoFormC {
+	if a.options == nil {
+		a.options = make(map[string]func() []string)
+	}
+	a.options[field] = fn
+	return a
+}
+
+// LabelStyle sets the style for all labels.
+func (a *AutoFormC) LabelStyle(s Style) *AutoFormC {
+	a.labelStyle = s
+	return a
+}
+
+// LabelBold sets labels to bold.
+func (a *AutoFormC) LabelBold() *AutoFormC {
+	a.labelStyle = a.labelStyle.Bold()
+	return a
+}
+
+// Gap sets the vertical gap between fields.
+func (a *AutoFormC) Gap(g int8) *AutoFormC {
+	a.gap = g
+	return a
+}
+
+// OnSubmit sets a callback that fires after Enter validates the form and
+// writes the values back to the struct.
+func (a *AutoFormC) OnSubmit(fn func()) *AutoFormC {
+	a.onSubmit = fn
+	return a
+}
+
+// FocusManager returns the generated form's focus manager.
+func (a *AutoFormC) FocusManager() *FocusManager {
+	return a.build().fm
+}
+
+// Submit validates every field and, if all are valid, writes the values
+// back to the struct. Returns false and shows per-field errors otherwise.
+func (a *AutoFormC) Submit() bool {
+	f := a.build()
+	if !f.ValidateAll() {
+		return false
+	}
+	valid := true
+	for i := range a.fields {
+		af := &a.fields[i]
+		if err := af.apply(); err != nil {
+			f.fields[af.formIdx].err = err.Error()
+			valid = false
+		}
+	}
+	return valid
+}
+
+func (a *AutoFormC) toTemplate() any {
+	return a.build()
+}
+
+// build generates the form on first use.
+func (a *AutoFormC) build() *FormC {
+	if a.form != nil {
+		return a.form
+	}
+	var ff []FormField
+	a.addFields(&ff, a.target, "")
+	a.form = Form.Gap(a.gap).LabelStyle(a.labelStyle).OnSubmit(func() {
+		if a.Submit() && a.onSubmit != nil {
+			a.onSubmit()
+		}
+	})(ff...)
+	return a.form
+}
+
+// addFields appends a form field per exported field of v, recursing into
+// nested structs under a group heading.
+func (a *AutoFormC) addFields(ff *[]FormField, v reflect.Value, prefix string) {
+	t := v.Type()
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		tag := parseFormTag(sf.Tag.Get("glyph"))
+		if tag.skip {
+			continue
+		}
+		label := sf.Name
+		if tag.label != "" {
+			label = tag.label
+		}
+		path := prefix + sf.Name
+		fv := v.Field(i)
+
+		opts := a.fieldOptions(path, fv, tag)
+		if opts == nil && fv.Kind() == reflect.Pointer && fv.Type().Elem().Kind() == reflect.Struct {
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		}
+		if opts == nil && fv.Kind() == reflect.Struct && !isTextField(fv) {
+			*ff = append(*ff, FormField{label: label, group: true})
+			a.addFields(ff, fv, path+".")
+			continue
+		}
+
+		af := autoField{path: path, value: fv, formIdx: len(*ff)}
+		var control any
+		switch {
+		case opts != nil:
+			af.options = opts
+			af.selected = new(int)
+			*af.selected = optionIndex(fv, opts)
+			control = Radio(af.selected, opts...)
+		case fv.Kind() == reflect.Bool:
+			af.checked = new(bool)
+			*af.checked = fv.Bool()
+			cb := Checkbox(af.checked, "")
+			if slices.Contains(tag.validate, "required") {
+				cb.Validate(VTrue)
+			}
+			control = cb
+		default:
+			if !isTextField(fv) && !isNumberField(fv) && fv.Kind() != reflect.String {
+				continue // unsupported type
+			}
+			in := Input().Placeholder(tag.placeholder)
+			in.SetValue(formatField(fv, tag.placeholder != ""))
+			in.Validate(fieldValidator(fv, tag))
+			af.input = in
+			control = in
+		}
+		a.fields = append(a.fields, af)
+		*ff = append(*ff, Field(label, control))
+	}
+}
+
+// fieldOptions returns the choices for an enum-like field, or nil.
+func (a *AutoFormC) fieldOptions(path string, v reflect.Value, tag formTag) []string {
+	if fn, ok := a.options[path]; ok {
+		return fn()
+	}
+	if tag.options != nil {
+		return tag.options
+	}
+	if v.Type().Implements(optionsProviderType) {
+		return v.Interface().(OptionsProvider).Options()
+	}
+	return nil
+}
+
+// apply writes the control's value back to the struct field.
+func (af *autoField) apply() error {
+	switch {
+	case af.selected != nil:
+		idx := *af.selected
+		if idx < 0 || idx >= len(af.options) {
+			return nil
+		}
+		switch {
+		case af.value.Kind() == reflect.String:
+			af.value.SetString(af.options[idx])
+		case af.value.CanInt():
+			af.value.SetInt(int64(idx))
+		case af.value.CanUint():
+			af.value.SetUint(uint64(idx))
+		default:
+			return parseField(af.value, af.options[idx])
+		}
+		return nil
+	case af.checked != nil:
+		af.value.SetBool(*af.checked)
+		return nil
+	}
+	return parseField(af.value, af.input.Value())
+}
+
+// formTag is a parsed glyph struct tag.
+type formTag struct {
+	skip        bool
+	label       string
+	placeholder string
+	validate    []string
+	options     []string
+	min, max    *float64
+}
+
+func parseFormTag(s string) formTag {
+	var t formTag
+	if s == "-" {
+		t.skip = true
+		return t
+	}
+	for part := range strings.SplitSeq(s, ",") {
+		key, val, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "label":
+			t.label = val
+		case "placeholder":
+			t.placeholder = val
+		case "validate":
+			t.validate = strings.Split(val, "|")
+		case "options":
+			t.options = strings.Split(val, "|")
+		case "min", "max":
+			n, err := strconv.ParseFloat(val, 64)
+			if err != nil {
+				continue
+			}
+			if key == "min" {
+				t.min = &n
+			} else {
+				t.max = &n
+			}
+		}
+	}
+	return t
+}
+
+// fieldValidator builds the Input validator for a field from its type and tag.
+func fieldValidator(v reflect.Value, tag formTag) StringValidator {
+	required := slices.Contains(tag.validate, "required")
+	email := slices.Contains(tag.validate, "email")
+	number := isNumberField(v) && v.Type() != durationType
+	return func(s string) error {
+		if required {
+			if err := VRequired(s); err != nil {
+				return err
+			}
+		}
+		if s == "" {
+			return nil
+		}
+		if email {
+			if err := VEmail(s); err != nil {
+				return err
+			}
+		}
+		tmp := reflect.New(v.Type()).Elem()
+		if err := parseField(tmp, s); err != nil {
+			return err
+		}
+		var n float64
+		var what string
+		switch {
+		case number && tmp.CanInt():
+			n = float64(tmp.Int())
+		case number && tmp.CanUint():
+			n = float64(tmp.Uint())
+		case number:
+			n = tmp.Float()
+		case v.Kind() == reflect.String:
+			n, what = float64(len(s)), " characters"
+		default:
+			return nil
+		}
+		if tag.min != nil && n < *tag.min {
+			return fmt.Errorf("min %s%s", strconv.FormatFloat(*tag.min, 'f', -1, 64), what)
+		}
+		if tag.max != nil && n > *tag.max {
+			return fmt.Errorf("max %s%s", strconv.FormatFloat(*tag.max, 'f', -1, 64), what)
+		}
+		return nil
+	}
+}
+
+func isNumberField(v reflect.Value) bool {
+	return v.CanInt() || v.CanUint() || v.CanFloat()
+}
+
+// isTextField reports whether v's type parses itself from text.
+func isTextField(v reflect.Value) bool {
+	return reflect.PointerTo(v.Type()).Implements(textUnmarshalerType)
+}
+
+// formatField renders a field value as input text. Zero numbers are left
+// empty when the input has a placeholder to show instead.
+func formatField(v reflect.Value, placeholder bool) string {
+	if placeholder && isNumberField(v) && v.IsZero() {
+		return ""
+	}
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		if b, err := m.MarshalText(); err == nil {
+			return string(b)
+		}
+	}
+	switch {
+	case v.Type() == durationType:
+		return time.Duration(v.Int()).String()
+	case v.CanInt():
+		return strconv.FormatInt(v.Int(), 10)
+	case v.CanUint():
+		return strconv.FormatUint(v.Uint(), 10)
+	case v.CanFloat():
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits())
+	case v.Kind() == reflect.String:
+		return v.String()
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// parseField parses s into v according to v's type.
+func parseField(v reflect.Value, s string) error {
+	if isTextField(v) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+	if s == "" && isNumberField(v) {
+		v.SetZero()
+		return nil
+	}
+	switch {
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("invalid duration")
+		}
+		v.SetInt(int64(d))
+	case v.CanInt():
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid number")
+		}
+		v.SetInt(n)
+	case v.CanUint():
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid number")
+		}
+		v.SetUint(n)
+	case v.CanFloat():
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid number")
+		}
+		v.SetFloat(n)
+	case v.Kind() == reflect.String:
+		v.SetString(s)
+	}
+	return nil
+}
+
+// optionIndex returns the index of v's current value among opts, or the
+// first option when the value matches none.
+func optionIndex(v reflect.Value, opts []string) int {
+	switch {
+	case v.Kind() == reflect.String:
+		return max(slices.Index(opts, v.String()), 0)
+	case v.CanInt():
+		if i := v.Int(); i >= 0 && i < int64(len(opts)) {
+			return int(i)
+		}
+		return 0
+	case v.CanUint():
+		if i := v.Uint(); i < uint64(len(opts)) {
+			return int(i)
+		}
+		return 0
+	}
+	return max(slices.Index(opts, formatField(v, false)), 0)
+}
@@ -0,0 +1,166 @@
+package glyph
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type testLevel int
+
+func (testLevel) Options() []string { return []string{"debug", "info", "warn"} }
+
+type testServer struct {
+	Host string `glyph:"validate=required"`
+	Port int    `glyph:"label=Port,placeholder=8080,min=1,max=65535"`
+}
+
+type testConfig struct {
+	Name    string `glyph:"label=Name,placeholder=your name,validate=required,min=2"`
+	Email   string `glyph:"validate=email"`
+	Ratio   float64
+	Wait    time.Duration
+	Debug   bool
+	Level   testLevel
+	Mode    string `glyph:"options=dev|prod"`
+	Server  testServer
+	Secret  string `glyph:"-"`
+	private int
+}
+
+func autoInput(t *testing.T, a *AutoFormC, path string) *InputC {
+	t.Helper()
+	for _, af := range a.fields {
+		if af.path == path {
+			return af.input
+		}
+	}
+	t.Fatalf("no field %q", path)
+	return nil
+}
+
+func TestAutoFormBuildsControls(t *testing.T) {
+	cfg := testConfig{Name: "pete", Ratio: 0.5, Wait: 2 * time.Second, Level: 1, Mode: "prod", Server: testServer{Host: "localhost"}}
+	a := AutoForm(&cfg)
+	f := a.build()
+
+	var labels []string
+	for _, ff := range f.fields {
+		labels = append(labels, ff.label)
+	}
+	want := "Name Email Ratio Wait Debug Level Mode Server Host Port"
+	if got := strings.Join(labels, " "); got != want {
+		t.Fatalf("labels = %q, want %q", got, want)
+	}
+
+	if _, ok := f.fields[4].control.(*CheckboxC); !ok {
+		t.Errorf("Debug control = %T, want checkbox", f.fields[4].control)
+	}
+	if r, ok := f.fields[5].control.(*RadioC); !ok || r.Selected() != "info" {
+		t.Errorf("Level control = %T", f.fields[5].control)
+	}
+	if r, ok := f.fields[6].control.(*RadioC); !ok || r.Selected() != "prod" {
+		t.Errorf("Mode control = %T", f.fields[6].control)
+	}
+	if !f.fields[7].group {
+		t.Error("Server should be a group heading")
+	}
+
+	if v := autoInput(t, a, "Wait").Value(); v != "2s" {
+		t.Errorf("Wait = %q, want 2s", v)
+	}
+	if v := autoInput(t, a, "Ratio").Value(); v != "0.5" {
+		t.Errorf("Ratio = %q, want 0.5", v)
+	}
+	// zero number with placeholder starts empty
+	if v := autoInput(t, a, "Server.Port").Value(); v != "" {
+		t.Errorf("Port = %q, want empty", v)
+	}
+
+	buf := NewBuffer(60, 20)
+	Build(a).Execute(buf, 60, 20)
+	if out := buf.StringTrimmed(); !strings.Contains(out, "Server") || !strings.Contains(out, "Port:") {
+		t.Errorf("render:\n%s", out)
+	}
+}
+
+func TestAutoFormSubmitWritesBack(t *testing.T) {
+	cfg := testConfig{Name: "pete", Server: testServer{Host: "localhost"}}
+	submitted := false
+	a := AutoForm(&cfg).OnSubmit(func() { submitted = true })
+	f := a.build()
+
+	autoInput(t, a, "Name").SetValue("ada")
+	autoInput(t, a, "Ratio").SetValue("0.25")
+	autoInput(t, a, "Wait").SetValue("1m30s")
+	autoInput(t, a, "Server.Port").SetValue("9000")
+	f.fields[4].control.(*CheckboxC).Toggle()
+	f.fields[5].control.(*RadioC).Next()
+	f.fields[5].control.(*RadioC).Next()
+
+	f.onSubmit()
+	if !submitted {
+		t.Fatalf("submit failed: %+v", f.fields)
+	}
+	if cfg.Name != "ada" || cfg.Ratio != 0.25 || cfg.Wait != 90*time.Second ||
+		cfg.Server.Port != 9000 || !cfg.Debug || cfg.Level != 2 || cfg.Mode != "dev" {
+		t.Errorf("cfg = %+v", cfg)
+	}
+}
+
+func TestAutoFormErrors(t *testing.T) {
+	cfg := testConfig{Name: "pete"}
+	a := AutoForm(&cfg)
+	f := a.build()
+
+	autoInput(t, a, "Name").SetValue("a")
+	autoInput(t, a, "Email").SetValue("nope")
+	autoInput(t, a, "Ratio").SetValue("lots")
+	autoInput(t, a, "Server.Port").SetValue("70000")
+
+	if a.Submit() {
+		t.Fatal("Submit should fail")
+	}
+	errs := map[string]string{}
+	for _, ff := range f.fields {
+		if ff.err != "" {
+			errs[ff.label] = ff.err
+		}
+	}
+	want := map[string]string{
+		"Name":  "min 2 characters",
+		"Email": "invalid email",
+		"Ratio": "invalid number",
+		"Host":  "required",
+		"Port":  "max 65535",
+	}
+	for k, v := range want {
+		if errs[k] != v {
+			t.Errorf("%s error = %q, want %q", k, errs[k], v)
+		}
+	}
+	if cfg.Name != "pete" {
+		t.Errorf("struct written despite errors: %+v", cfg)
+	}
+}
+
+func TestAutoFormOptionOutOfRange(t *testing.T) {
+	for _, level := range []testLevel{-1, 3} {
+		cfg := testConfig{Level: level}
+		f := AutoForm(&cfg).build()
+		if r := f.fields[5].control.(*RadioC); r.Selected() != "debug" {
+			t.Errorf("Level %d selects %q, want the first option", level, r.Selected())
+		}
+	}
+}
+
+func TestParseFormTag(t *testing.T) {
+	tag := parseFormTag("label=Port,placeholder=8080,validate=required|email,min=1,max=65535,options=a|b")
+	if tag.label != "Port" || tag.placeholder != "8080" || len(tag.validate) != 2 ||
+		*tag.min != 1 || *tag.max != 65535 || len(tag.options) != 2 {
+		t.Errorf("tag = %+v", tag)
+	}
+	if !parseFormTag("-").skip {
+		t.Error("- should skip")
+	}
+}
@@ -480,13 +480,13 @@ func (t AutoTableC) BindNav(down, up string) AutoTableC {
 			}
 			total := reflect.ValueOf(data).Elem().Len()
 			sc.scrollDown(1, total)
-		}},
+		}, name: "table.down", desc: "down", group: "Table"},
 		binding{pattern: up, handler: func() {
 			if sc == nil {
 				return
 			}
 			sc.scrollUp(1)
-		}},
+		}, name: "table.up", desc: "up", group: "Table"},
 	)
 	return t
 }
@@ -502,13 +502,13 @@ func (t AutoTableC) BindPageNav(pageDown, pageUp string) AutoTableC {
 			}
 			total := reflect.ValueOf(data).Elem().Len()
 			sc.pageDown(total)
-		}},
+		}, name: "table.page_down", desc: "page down", group: "Table"},
 		binding{pattern: pageUp, handler: func() {
 			if sc == nil {
 				return
 			}
 			sc.pageUp()
-		}},
+		}, name: "table.page_up", desc: "page up", group: "Table"},
 	)
 	return t
 }
@@ -21,8 +21,24 @@ type Buffer struct {
 	allDirty  bool // true after Clear() - all rows need checking
 }
 
-// emptyBufferCache is a pre-filled buffer of empty cells for fast clearing via copy()
-var emptyBufferCache []Cell
+// emptyBufferCache is a pre-filled buffer of empty cells for fast clearing via
+// copy(). It is replaced, never written, when it grows, so buffers of any app
+// can clear at once.
+var emptyBufferCache atomic.Pointer[[]Cell]
+
+// emptyCells returns at least n empty cells, to copy from.
+func emptyCells(n int) []Cell {
+	if cache := emptyBufferCache.Load(); cache != nil && len(*cache) >= n {
+		return *cache
+	}
+	cells := make([]Cell, n)
+	empty := EmptyCell()
+	for i := range cells {
+		cells[i] = empty
+	}
+	emptyBufferCache.Store(&cells)
+	return cells
+}
 
 // NewBuffer creates a new buffer with the given dimensions.
 func NewBuffer(width, height int) *Buffer {
@@ -502,19 +518,8 @@ func (b *Buffer) Fill(c Cell) {
 // Clear clears the buffer to empty cells with default style.
 // Uses copy() from a cached empty buffer.
 func (b *Buffer) Clear() {
-	size := len(b.cells)
-
-	// Grow cache if needed (one-time cost)
-	if len(emptyBufferCache) < size {
-		emptyBufferCache = make([]Cell, size)
-		empty := EmptyCell()
-		for i := range emptyBufferCache {
-			emptyBufferCache[i] = empty
-		}
-	}
-
 	// Fast path: copy uses optimized memmove
-	copy(b.cells, emptyBufferCache[:size])
+	copy(b.cells, emptyCells(len(b.cells)))
 	b.dirtyMaxY = 0
 	b.allDirty = true
 	// Clear individual row flags (allDirty takes precedence)
@@ -569,16 +574,7 @@ func (b *Buffer) ClearDirty() {
 		size = len(b.cells)
 	}
 
-	// Ensure cache is big enough
-	if len(emptyBufferCache) < size {
-		emptyBufferCache = make([]Cell, len(b.cells))
-		empty := EmptyCell()
-		for i := range emptyBufferCache {
-			emptyBufferCache[i] = empty
-		}
-	}
-
-	copy(b.cells[:size], emptyBufferCache[:size])
+	copy(b.cells[:size], emptyCells(size))
 
 	// Mark cleared rows as dirty (content changed) and reset tracking
 	for y := 0; y <= b.dirtyMaxY && y < b.height; y++ {
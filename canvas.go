@@ -0,0 +1,436 @@
+package glyph
+
+import "reflect"
+
+// CanvasMode sets how a Canvas divides each cell into pixels.
+type CanvasMode uint8
+
+const (
+	CanvasBraille   CanvasMode = iota // 2×4 dots per cell (⣿)
+	CanvasQuadrant                    // 2×2 blocks per cell (▛)
+	CanvasHalfBlock                   // 1×2 blocks per cell (▀), each with its own colour
+)
+
+// cellPixels returns the pixel columns and rows in one cell.
+func (m CanvasMode) cellPixels() (cw, ch int) {
+	switch m {
+	case CanvasQuadrant:
+		return 2, 2
+	case CanvasHalfBlock:
+		return 1, 2
+	}
+	return 2, 4
+}
+
+// quadrantChars maps quadrant bits (1 top left, 2 top right, 4 bottom left,
+// 8 bottom right) to block characters.
+var quadrantChars = [16]rune{' ', '▘', '▝', '▀', '▖', '▌', '▞', '▛', '▗', '▚', '▐', '▜', '▄', '▙', '▟', '█'}
+
+// CanvasContext is the pixel grid a Canvas draws into. Coordinates are in
+// pixels from the top left; drawing outside the grid is clipped.
+type CanvasContext struct {
+	mode   CanvasMode
+	w, h   int
+	seq    []uint32 // per pixel: 0 off, else the order it was last drawn
+	color  []Color
+	next   uint32
+	pen    Color
+	labels []canvasLabel
+}
+
+type canvasLabel struct {
+	x, y  int
+	text  string
+	color Color
+}
+
+// Size returns the grid size in pixels.
+func (c *CanvasContext) Size() (w, h int) { return c.w, c.h }
+
+// SetColor sets the colour of everything drawn after it. A cell shows the
+// colour most of its pixels were drawn in.
+func (c *CanvasContext) SetColor(col Color) { c.pen = col }
+
+// Clear turns every pixel off and removes the labels.
+func (c *CanvasContext) Clear() {
+	clear(c.seq)
+	c.labels = c.labels[:0]
+	c.next = 0
+}
+
+// Set turns on the pixel at x, y.
+func (c *CanvasContext) Set(x, y int) {
+	if x < 0 || y < 0 || x >= c.w || y >= c.h {
+		return
+	}
+	c.next++
+	i := y*c.w + x
+	c.seq[i] = c.next
+	c.color[i] = c.pen
+}
+
+// Unset turns off the pixel at x, y.
+func (c *CanvasContext) Unset(x, y int) {
+	if x < 0 || y < 0 || x >= c.w || y >= c.h {
+		return
+	}
+	c.seq[y*c.w+x] = 0
+}
+
+// IsSet reports whether the pixel at x, y is on.
+func (c *CanvasContext) IsSet(x, y int) bool {
+	if x < 0 || y < 0 || x >= c.w || y >= c.h {
+		return false
+	}
+	return c.seq[y*c.w+x] != 0
+}
+
+// Line draws a line between two points.
+func (c *CanvasContext) Line(x0, y0, x1, y1 int) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x1 < x0 {
+		sx = -1
+	}
+	if y1 < y0 {
+		sy = -1
+	}
+	err := dx + dy
+	for {
+		c.Set(x0, y0)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+// Polyline draws lines joining points in turn.
+func (c *CanvasContext) Polyline(points ...[2]int) {
+	if len(points) == 1 {
+		c.Set(points[0][0], points[0][1])
+	}
+	for i := 1; i < len(points); i++ {
+		c.Line(points[i-1][0], points[i-1][1], points[i][0], points[i][1])
+	}
+}
+
+// Rect draws the outline of a w×h rectangle.
+func (c *CanvasContext) Rect(x, y, w, h int) {
+	if w <= 0 || h <= 0 {
+		return
+	}
+	x1, y1 := x+w-1, y+h-1
+	c.Line(x, y, x1, y)
+	c.Line(x, y1, x1, y1)
+	c.Line(x, y, x, y1)
+	c.Line(x1, y, x1, y1)
+}
+
+// FillRect fills a w×h rectangle.
+func (c *CanvasContext) FillRect(x, y, w, h int) {
+	for py := y; py < y+h; py++ {
+		for px := x; px < x+w; px++ {
+			c.Set(px, py)
+		}
+	}
+}
+
+// Circle draws the outline of a circle. Pixels are square in braille and
+// half-block modes; quadrant pixels are twice as tall as wide.
+func (c *CanvasContext) Circle(cx, cy, r int) {
+	x, y, err := r, 0, 1-r
+	for x >= y {
+		c.Set(cx+x, cy+y)
+		c.Set(cx+y, cy+x)
+		c.Set(cx-y, cy+x)
+		c.Set(cx-x, cy+y)
+		c.Set(cx-x, cy-y)
+		c.Set(cx-y, cy-x)
+		c.Set(cx+y, cy-x)
+		c.Set(cx+x, cy-y)
+		y++
+		if err < 0 {
+			err += 2*y + 1
+		} else {
+			x--
+			err += 2*(y-x) + 1
+		}
+	}
+}
+
+// FillCircle fills a circle.
+func (c *CanvasContext) FillCircle(cx, cy, r int) {
+	for dy := -r; dy <= r; dy++ {
+		for dx := -r; dx <= r; dx++ {
+			if dx*dx+dy*dy <= r*r+r {
+				c.Set(cx+dx, cy+dy)
+			}
+		}
+	}
+}
+
+// Fill flood-fills the area of unset pixels around x, y, bounded by set
+// pixels and the grid edge.
+func (c *CanvasContext) Fill(x, y int) {
+	if x < 0 || y < 0 || x >= c.w || y >= c.h || c.IsSet(x, y) {
+		return
+	}
+	stack := [][2]int{{x, y}}
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		px, py := p[0], p[1]
+		if px < 0 || py < 0 || px >= c.w || py >= c.h || c.IsSet(px, py) {
+			continue
+		}
+		c.Set(px, py)
+		stack = append(stack, [2]int{px + 1, py}, [2]int{px - 1, py}, [2]int{px, py + 1}, [2]int{px, py - 1})
+	}
+}
+
+// Text writes a label in the cell holding pixel x, y, over any pixels there.
+func (c *CanvasContext) Text(x, y int, s string) {
+	c.labels = append(c.labels, canvasLabel{x: x, y: y, text: s, color: c.pen})
+}
+
+// resize sets the grid for a w×h cell area, clearing it.
+func (c *CanvasContext) resize(mode CanvasMode, w, h int) {
+	cw, ch := mode.cellPixels()
+	c.mode, c.w, c.h = mode, w*cw, h*ch
+	if n := c.w * c.h; cap(c.seq) >= n {
+		c.seq, c.color = c.seq[:n], c.color[:n]
+	} else {
+		c.seq, c.color = make([]uint32, n), make([]Color, n)
+	}
+	c.Clear()
+}
+
+// cell returns the glyph for the cell at cx, cy and the colours of its lit
+// pixels: the colour most were drawn in, ties going to the latest drawn.
+// In half-block mode top and bottom keep their own colours, as FG and BG.
+func (c *CanvasContext) cell(cx, cy int) (r rune, fg Color, bg Color, lit bool) {
+	cw, ch := c.mode.cellPixels()
+	var (
+		bits  uint8
+		cols  [8]Color
+		count [8]int
+		last  [8]uint32
+		n     int
+	)
+	for py := 0; py < ch; py++ {
+		for px := 0; px < cw; px++ {
+			i := (cy*ch+py)*c.w + cx*cw + px
+			s := c.seq[i]
+			if s == 0 {
+				continue
+			}
+			bits |= 1 << (py*cw + px)
+			k := 0
+			for k < n && cols[k] != c.color[i] {
+				k++
+			}
+			if k == n {
+				cols[k] = c.color[i]
+				n++
+			}
+			count[k]++
+			last[k] = max(last[k], s)
+		}
+	}
+	if bits == 0 {
+		return ' ', fg, bg, false
+	}
+	best := 0
+	for k := 1; k < n; k++ {
+		if count[k] > count[best] || count[k] == count[best] && last[k] > last[best] {
+			best = k
+		}
+	}
+	fg = cols[best]
+
+	switch c.mode {
+	case CanvasQuadrant:
+		return quadrantChars[bits], fg, bg, true
+	case CanvasHalfBlock:
+		top := (cy*2)*c.w + cx
+		switch bits {
+		case 1:
+			return '▀', c.color[top], bg, true
+		case 2:
+			return '▄', c.color[top+c.w], bg, true
+		}
+		if c.color[top] == c.color[top+c.w] {
+			return '█', c.color[top], bg, true
+		}
+		return '▀', c.color[top], c.color[top+c.w], true
+	}
+	var dots rune
+	for bit, d := range brailleDots {
+		if bits&(1<<(d[1]*2+d[0])) != 0 {
+			dots |= 1 << bit
+		}
+	}
+	return 0x2800 + dots, fg, bg, true
+}
+
+// opCanvas holds canvas-specific data.
+type opCanvas struct {
+	ctx      CanvasContext
+	draw     func(c *CanvasContext)
+	mode     CanvasMode
+	style    Style
+	stylePtr *Style
+	watches  []canvasWatch
+	cellW    int16
+	cellH    int16
+	drawn    bool
+}
+
+// canvasWatch keeps a copy of a watched value to notice changes.
+type canvasWatch struct {
+	v    reflect.Value // the pointed-to value
+	last reflect.Value
+}
+
+func newCanvasWatch(ptr any) canvasWatch {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		panic("glyph: Canvas.Watch requires non-nil pointers")
+	}
+	w := canvasWatch{v: v.Elem()}
+	w.snapshot()
+	return w
+}
+
+func (w *canvasWatch) snapshot() { w.last = deepCopy(w.v) }
+
+// changed reports whether the value differs from the last copy, and takes
+// a new copy when it does.
+func (w *canvasWatch) changed() bool {
+	if reflect.DeepEqual(w.v.Interface(), w.last.Interface()) {
+		return false
+	}
+	w.snapshot()
+	return true
+}
+
+// deepCopy copies v through its slices, maps, arrays and exported struct
+// fields, so an in-place change to v doesn't reach the copy. Pointers and
+// unexported fields are copied as they are.
+func deepCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := range v.Len() {
+			c.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for it := v.MapRange(); it.Next(); {
+			c.SetMapIndex(it.Key(), deepCopy(it.Value()))
+		}
+		return c
+	case reflect.Array:
+		c := reflect.New(v.Type()).Elem()
+		for i := range v.Len() {
+			c.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := range v.NumField() {
+			if c.Field(i).CanSet() {
+				c.Field(i).Set(deepCopy(v.Field(i)))
+			}
+		}
+		return c
+	}
+	return v
+}
+
+// redraw reports whether the draw function must run this frame: always,
+// unless the canvas watches values, and then only when one of them or the
+// size has changed.
+func (s *opCanvas) redraw(w, h int16) bool {
+	stale := !s.drawn || w != s.cellW || h != s.cellH || len(s.watches) == 0
+	for i := range s.watches {
+		if s.watches[i].changed() {
+			stale = true
+		}
+	}
+	return stale
+}
+
+func (s *opCanvas) render(t *Template, buf *Buffer, x, y, w, h int16) {
+	if w <= 0 || h <= 0 {
+		return
+	}
+	if s.redraw(w, h) {
+		s.ctx.resize(s.mode, int(w), int(h))
+		if s.draw != nil {
+			s.draw(&s.ctx)
+		}
+		s.cellW, s.cellH, s.drawn = w, h, true
+	}
+
+	baseStyle := s.style
+	if s.stylePtr != nil {
+		baseStyle = *s.stylePtr
+	}
+	s.ctx.blit(buf, int(x), int(y), t.effectiveStyle(baseStyle))
+}
+
+// blit writes the grid and its labels to buf with its top left cell at x, y.
+func (c *CanvasContext) blit(buf *Buffer, x, y int, style Style) {
+	cw, ch := c.mode.cellPixels()
+	w, h := c.w/cw, c.h/ch
+	for cy := 0; cy < h; cy++ {
+		for cx := 0; cx < w; cx++ {
+			r, fg, bg, lit := c.cell(cx, cy)
+			st := style
+			if lit && fg.Mode != ColorDefault {
+				st.FG = fg
+			}
+			if lit && bg.Mode != ColorDefault {
+				st.BG = bg
+			}
+			buf.Set(x+cx, y+cy, Cell{Rune: r, Style: st})
+		}
+	}
+
+	for _, l := range c.labels {
+		lx, ly := l.x/cw, l.y/ch
+		if l.x < 0 || l.y < 0 || lx >= w || ly >= h {
+			continue
+		}
+		st := style
+		if l.color.Mode != ColorDefault {
+			st.FG = l.color
+		}
+		buf.WriteStringFast(x+lx, y+ly, l.text, st, w-lx)
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
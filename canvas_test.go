@@ -0,0 +1,131 @@
+package glyph
+
+import "testing"
+
+func TestCanvasBraille(t *testing.T) {
+	tmpl := Build(VBox(Canvas(2, 1, func(c *CanvasContext) {
+		if w, h := c.Size(); w != 4 || h != 4 {
+			t.Errorf("size = %dx%d, want 4x4", w, h)
+		}
+		c.Line(0, 0, 0, 3) // left column of the first cell
+		c.Set(3, 3)        // bottom right dot of the second
+	})))
+	buf := NewBuffer(4, 1)
+	if got := frame(tmpl, buf); got != "⡇⢀" {
+		t.Errorf("braille = %q", got)
+	}
+}
+
+func TestCanvasModes(t *testing.T) {
+	tests := []struct {
+		mode CanvasMode
+		want string
+	}{
+		{CanvasQuadrant, "▀▄"},
+		{CanvasHalfBlock, "▀▀▄▄"},
+	}
+	for _, tt := range tests {
+		tmpl := Build(VBox(Canvas(0, 1, func(c *CanvasContext) {
+			c.Line(0, 0, 3, 1)
+		}).Mode(tt.mode)))
+		buf := NewBuffer(4, 1)
+		if got := frame(tmpl, buf); got != tt.want {
+			t.Errorf("mode %d = %q, want %q", tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestCanvasShapes(t *testing.T) {
+	tmpl := Build(VBox(Canvas(4, 2, func(c *CanvasContext) {
+		c.Rect(0, 0, 8, 8)
+		c.Fill(3, 3)
+	})))
+	buf := NewBuffer(4, 2)
+	if got := frame(tmpl, buf); got != "⣿⣿⣿⣿\n⣿⣿⣿⣿" {
+		t.Errorf("filled rect = %q", got)
+	}
+
+	tmpl = Build(VBox(Canvas(4, 2, func(c *CanvasContext) {
+		c.Circle(3, 3, 3)
+		c.Text(4, 4, "x")
+	})))
+	got := frame(tmpl, buf)
+	if got != "⡔⠉⠑⡄\n⠑⠤x⠁" {
+		t.Errorf("circle with label = %q", got)
+	}
+}
+
+func TestCanvasColorMerge(t *testing.T) {
+	tmpl := Build(VBox(Canvas(1, 1, func(c *CanvasContext) {
+		c.SetColor(Red)
+		c.Line(0, 0, 0, 2)
+		c.SetColor(Blue)
+		c.Set(1, 0)
+	})))
+	buf := NewBuffer(1, 1)
+	frame(tmpl, buf)
+	if fg := buf.Get(0, 0).Style.FG; fg != Red {
+		t.Errorf("cell FG = %+v, want the colour most pixels are drawn in", fg)
+	}
+
+	// half blocks carry the bottom pixel's colour as the background
+	tmpl = Build(VBox(Canvas(1, 1, func(c *CanvasContext) {
+		c.SetColor(Red)
+		c.Set(0, 0)
+		c.SetColor(Blue)
+		c.Set(0, 1)
+	}).Mode(CanvasHalfBlock)))
+	frame(tmpl, buf)
+	if cell := buf.Get(0, 0); cell.Rune != '▀' || cell.Style.FG != Red || cell.Style.BG != Blue {
+		t.Errorf("half block = %q FG %+v BG %+v", cell.Rune, cell.Style.FG, cell.Style.BG)
+	}
+}
+
+func TestCanvasWatch(t *testing.T) {
+	points := []int{0, 1}
+	draws := 0
+	tmpl := Build(VBox(Canvas(2, 1, func(c *CanvasContext) {
+		draws++
+		for x, y := range points {
+			c.Set(x, y)
+		}
+	}).Watch(&points)))
+	buf := NewBuffer(2, 1)
+	frame(tmpl, buf)
+	frame(tmpl, buf)
+	if draws != 1 {
+		t.Errorf("draws = %d with nothing changed, want 1", draws)
+	}
+
+	points[1] = 3
+	if got := frame(tmpl, buf); draws != 2 || got != "⢁" {
+		t.Errorf("after in-place change: draws %d, frame %q", draws, got)
+	}
+}
+
+func TestCanvasWatchNested(t *testing.T) {
+	type point struct {
+		Tags []string
+	}
+	points := []point{{Tags: []string{"a"}}}
+	seen := map[string]int{"a": 1}
+	draws := 0
+	tmpl := Build(VBox(Canvas(1, 1, func(c *CanvasContext) { draws++ }).Watch(&points, &seen)))
+	buf := NewBuffer(1, 1)
+	frame(tmpl, buf)
+	frame(tmpl, buf)
+	if draws != 1 {
+		t.Errorf("draws = %d with nothing changed, want 1", draws)
+	}
+
+	points[0].Tags[0] = "b"
+	frame(tmpl, buf)
+	if draws != 2 {
+		t.Errorf("draws = %d after a nested slice change, want 2", draws)
+	}
+	seen["a"]++
+	frame(tmpl, buf)
+	if draws != 3 {
+		t.Errorf("draws = %d after a map change, want 3", draws)
+	}
+}
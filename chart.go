@@ -0,0 +1,1495 @@
+package glyph
+
+import (
+	"math"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// ChartAxis configures a chart axis. The zero value is an automatic axis
+// fitted to the data. Time axes take Min, Max and Ticks in Unix seconds.
+type ChartAxis struct {
+	Min, Max   float64                // range; fitted to the data when both are 0
+	Ticks      []float64              // tick values; evenly spaced when nil
+	Format     func(v float64) string // tick labels; numbers or times when nil
+	TimeFormat string                 // time.Format layout for time ticks, chosen by span when empty
+	Hidden     bool                   // leave out the axis line and labels
+}
+
+// ChartSeries is a named set of values for a chart.
+type ChartSeries struct {
+	name   string
+	values any
+	x      any
+	color  Color
+}
+
+// Series creates a named chart series. Pass []float64 or *[]float64 for
+// reactive updates.
+//
+//	LineChart(Series("cpu", &cpu), Series("mem", &mem).Color(Yellow)).Legend()
+func Series(name string, values any) ChartSeries {
+	return ChartSeries{name: name, values: values}
+}
+
+// X sets where each value sits on the x axis, for line charts and scatter
+// plots; without it values are spaced by index. Pass []float64, *[]float64,
+// []time.Time or *[]time.Time; times give a time axis.
+func (s ChartSeries) X(xs any) ChartSeries {
+	s.x = xs
+	return s
+}
+
+// Color sets the series colour in place of the next theme colour.
+func (s ChartSeries) Color(c Color) ChartSeries {
+	s.color = c
+	return s
+}
+
+// defaultSeriesColors are given to series of charts without a Theme.
+var defaultSeriesColors = []Color{Cyan, Magenta, Yellow, Green, Blue, Red}
+
+type chartKind uint8
+
+const (
+	chartLine chartKind = iota
+	chartBar
+	chartHistogram
+	chartScatter
+	chartHeatmap
+)
+
+// chartDefaultHeight is the height of a chart given neither a height nor Grow.
+const chartDefaultHeight = 10
+
+type chartThreshold struct {
+	value float64
+	color Color
+}
+
+// chartSpec holds the settings shared by the chart components.
+type chartSpec struct {
+	kind         chartKind
+	series       []ChartSeries
+	labels       any // []string or *[]string
+	xAxis        ChartAxis
+	yAxis        ChartAxis
+	thresholds   []chartThreshold
+	legend       bool
+	theme        *ThemeEx
+	mode         CanvasMode
+	horizontal   bool
+	stacked      bool
+	bins         int
+	grid         any // [][]float64 or *[][]float64
+	rowLabels    any
+	colLabels    any
+	lo, hi       Color
+	width        int16
+	height       int16
+	widthPtr     *int16
+	heightPtr    *int16
+	widthCond    any
+	heightCond   any
+	flexGrow     float32
+	flexGrowPtr  *float32
+	flexGrowCond any
+	style        Style
+	styleDyn     any
+}
+
+func (c *chartSpec) addSeries(series []any) {
+	for _, s := range series {
+		if cs, ok := s.(ChartSeries); ok {
+			c.series = append(c.series, cs)
+		} else {
+			c.series = append(c.series, ChartSeries{values: s})
+		}
+	}
+}
+
+func (c *chartSpec) setWidth(w any) {
+	switch val := w.(type) {
+	case int16:
+		c.width = val
+	case int:
+		c.width = int16(val)
+	case *int16:
+		c.widthPtr = val
+	case conditionNode:
+		c.widthCond = val
+	case tweenNode:
+		c.widthCond = val
+	}
+}
+
+func (c *chartSpec) setHeight(h any) {
+	switch val := h.(type) {
+	case int16:
+		c.height = val
+	case int:
+		c.height = int16(val)
+	case *int16:
+		c.heightPtr = val
+	case conditionNode:
+		c.heightCond = val
+	case tweenNode:
+		c.heightCond = val
+	}
+}
+
+func (c *chartSpec) setGrow(g any) {
+	switch val := g.(type) {
+	case float32:
+		c.flexGrow = val
+	case float64:
+		c.flexGrow = float32(val)
+	case int:
+		c.flexGrow = float32(val)
+	case *float32:
+		c.flexGrowPtr = val
+	case conditionNode:
+		c.flexGrowCond = val
+	case tweenNode:
+		c.flexGrowCond = val
+	}
+}
+
+func (c *chartSpec) setStyle(st any) {
+	switch val := st.(type) {
+	case Style:
+		c.style = val
+	case *Style:
+		c.styleDyn = val
+	case conditionNode:
+		c.styleDyn = val
+	case tweenNode:
+		c.styleDyn = val
+	}
+}
+
+func (c *chartSpec) setTheme(th ThemeEx) { c.theme = &th }
+
+func (c *chartSpec) addThreshold(v float64, col Color) {
+	c.thresholds = append(c.thresholds, chartThreshold{value: v, color: col})
+}
+
+// ============================================================================
+// LineChart
+// ============================================================================
+
+type LineChartC struct{ spec chartSpec }
+
+// LineChart plots series as lines on a braille grid, with axes fitted to
+// the data. Pass []float64, *[]float64 or Series values; width 0 fills the
+// available space, and height 0 is 10 rows, or a flex share with Grow.
+//
+//	LineChart(&history)
+//	LineChart(Series("rx", &rx).X(&times), Series("tx", &tx).X(&times)).Legend().Grow(1)
+func LineChart(series ...any) LineChartC {
+	c := LineChartC{spec: chartSpec{kind: chartLine}}
+	c.spec.addSeries(series)
+	return c
+}
+
+// Width sets the width in cells. Accepts int16, int, or *int16 for dynamic values.
+func (c LineChartC) Width(w any) LineChartC { c.spec.setWidth(w); return c }
+
+// Height sets the height in rows. Accepts int16, int, or *int16 for dynamic values.
+func (c LineChartC) Height(h any) LineChartC { c.spec.setHeight(h); return c }
+
+// Grow sets the flex grow factor. Accepts float32, float64, int, or *float32 for dynamic values.
+func (c LineChartC) Grow(g any) LineChartC { c.spec.setGrow(g); return c }
+
+// XAxis configures the x axis.
+func (c LineChartC) XAxis(a ChartAxis) LineChartC { c.spec.xAxis = a; return c }
+
+// YAxis configures the y axis.
+func (c LineChartC) YAxis(a ChartAxis) LineChartC { c.spec.yAxis = a; return c }
+
+// Threshold draws a dashed line across the chart at value v.
+func (c LineChartC) Threshold(v float64, col Color) LineChartC {
+	c.spec.addThreshold(v, col)
+	return c
+}
+
+// Legend shows the series names under the chart.
+func (c LineChartC) Legend() LineChartC { c.spec.legend = true; return c }
+
+// Theme colours the axes with th.Muted, the legend with th.Base and the
+// series with th.Series in turn.
+func (c LineChartC) Theme(th ThemeEx) LineChartC { c.spec.setTheme(th); return c }
+
+// Mode sets how cells divide into pixels. Default is CanvasBraille.
+func (c LineChartC) Mode(m CanvasMode) LineChartC { c.spec.mode = m; return c }
+
+// Style sets the component style. Accepts Style, *Style, conditionNode, or tweenNode.
+func (c LineChartC) Style(st any) LineChartC { c.spec.setStyle(st); return c }
+
+// Margin sets uniform margin on all sides.
+func (c LineChartC) Margin(all int16) LineChartC {
+	c.spec.style.margin = [4]int16{all, all, all, all}
+	return c
+}
+
+// MarginVH sets vertical and horizontal margin.
+func (c LineChartC) MarginVH(v, h int16) LineChartC {
+	c.spec.style.margin = [4]int16{v, h, v, h}
+	return c
+}
+
+// MarginTRBL sets individual margins for top, right, bottom, left.
+func (c LineChartC) MarginTRBL(t, r, b, l int16) LineChartC {
+	c.spec.style.margin = [4]int16{t, r, b, l}
+	return c
+}
+
+// ============================================================================
+// Scatter
+// ============================================================================
+
+type ScatterC struct{ spec chartSpec }
+
+// Scatter plots series as points on a braille grid. Give each series its
+// x values with Series(...).X.
+//
+//	Scatter(Series("hosts", &latency).X(&load)).Grow(1)
+func Scatter(series ...any) ScatterC {
+	c := ScatterC{spec: chartSpec{kind: chartScatter}}
+	c.spec.addSeries(series)
+	return c
+}
+
+// Width sets the width in cells. Accepts int16, int, or *int16 for dynamic values.
+func (c ScatterC) Width(w any) ScatterC { c.spec.setWidth(w); return c }
+
+// Height sets the height in rows. Accepts int16, int, or *int16 for dynamic values.
+func (c ScatterC) Height(h any) ScatterC { c.spec.setHeight(h); return c }
+
+// Grow sets the flex grow factor. Accepts float32, float64, int, or *float32 for dynamic values.
+func (c ScatterC) Grow(g any) ScatterC { c.spec.setGrow(g); return c }
+
+// XAxis configures the x axis.
+func (c ScatterC) XAxis(a ChartAxis) ScatterC { c.spec.xAxis = a; return c }
+
+// YAxis configures the y axis.
+func (c ScatterC) YAxis(a ChartAxis) ScatterC { c.spec.yAxis = a; return c }
+
+// Threshold draws a dashed line across the chart at value v.
+func (c ScatterC) Threshold(v float64, col Color) ScatterC {
+	c.spec.addThreshold(v, col)
+	return c
+}
+
+// Legend shows the series names under the chart.
+func (c ScatterC) Legend() ScatterC { c.spec.legend = true; return c }
+
+// Theme colours the axes with th.Muted, the legend with th.Base and the
+// series with th.Series in turn.
+func (c ScatterC) Theme(th ThemeEx) ScatterC { c.spec.setTheme(th); return c }
+
+// Mode sets how cells divide into pixels. Default is CanvasBraille.
+func (c ScatterC) Mode(m CanvasMode) ScatterC { c.spec.mode = m; return c }
+
+// Style sets the component style. Accepts Style, *Style, conditionNode, or tweenNode.
+func (c ScatterC) Style(st any) ScatterC { c.spec.setStyle(st); return c }
+
+// Margin sets uniform margin on all sides.
+func (c ScatterC) Margin(all int16) ScatterC {
+	c.spec.style.margin = [4]int16{all, all, all, all}
+	return c
+}
+
+// MarginVH sets vertical and horizontal margin.
+func (c ScatterC) MarginVH(v, h int16) ScatterC {
+	c.spec.style.margin = [4]int16{v, h, v, h}
+	return c
+}
+
+// MarginTRBL sets individual margins for top, right, bottom, left.
+func (c ScatterC) MarginTRBL(t, r, b, l int16) ScatterC {
+	c.spec.style.margin = [4]int16{t, r, b, l}
+	return c
+}
+
+// ============================================================================
+// BarChart
+// ============================================================================
+
+type BarChartC struct{ spec chartSpec }
+
+// BarChart draws a bar for each value, to an eighth of a cell. Several
+// series are grouped side by side, or stacked with Stacked.
+//
+//	BarChart(&requests).Labels(&hosts)
+//	BarChart(Series("read", &reads), Series("write", &writes)).Labels(&disks).Stacked().Legend()
+func BarChart(series ...any) BarChartC {
+	c := BarChartC{spec: chartSpec{kind: chartBar}}
+	c.spec.addSeries(series)
+	return c
+}
+
+// Labels names the bars, one label per value. Pass []string or *[]string.
+func (c BarChartC) Labels(labels any) BarChartC { c.spec.labels = labels; return c }
+
+// Horizontal lays bars along rows, growing to the right.
+func (c BarChartC) Horizontal() BarChartC { c.spec.horizontal = true; return c }
+
+// Stacked stacks the series into one bar per label.
+func (c BarChartC) Stacked() BarChartC { c.spec.stacked = true; return c }
+
+// Width sets the width in cells. Accepts int16, int, or *int16 for dynamic values.
+func (c BarChartC) Width(w any) BarChartC { c.spec.setWidth(w); return c }
+
+// Height sets the height in rows. Accepts int16, int, or *int16 for dynamic values.
+func (c BarChartC) Height(h any) BarChartC { c.spec.setHeight(h); return c }
+
+// Grow sets the flex grow factor. Accepts float32, float64, int, or *float32 for dynamic values.
+func (c BarChartC) Grow(g any) BarChartC { c.spec.setGrow(g); return c }
+
+// XAxis configures the x axis: the labels, or the values when Horizontal.
+func (c BarChartC) XAxis(a ChartAxis) BarChartC { c.spec.xAxis = a; return c }
+
+// YAxis configures the y axis: the values, or the labels when Horizontal.
+func (c BarChartC) YAxis(a ChartAxis) BarChartC { c.spec.yAxis = a; return c }
+
+// Threshold draws a dashed line across the chart at value v.
+func (c BarChartC) Threshold(v float64, col Color) BarChartC {
+	c.spec.addThreshold(v, col)
+	return c
+}
+
+// Legend shows the series names under the chart.
+func (c BarChartC) Legend() BarChartC { c.spec.legend = true; return c }
+
+// Theme colours the axes with th.Muted, the legend with th.Base and the
+// series with th.Series in turn.
+func (c BarChartC) Theme(th ThemeEx) BarChartC { c.spec.setTheme(th); return c }
+
+// Style sets the component style. Accepts Style, *Style, conditionNode, or tweenNode.
+func (c BarChartC) Style(st any) BarChartC { c.spec.setStyle(st); return c }
+
+// Margin sets uniform margin on all sides.
+func (c BarChartC) Margin(all int16) BarChartC {
+	c.spec.style.margin = [4]int16{all, all, all, all}
+	return c
+}
+
+// MarginVH sets vertical and horizontal margin.
+func (c BarChartC) MarginVH(v, h int16) BarChartC {
+	c.spec.style.margin = [4]int16{v, h, v, h}
+	return c
+}
+
+// MarginTRBL sets individual margins for top, right, bottom, left.
+func (c BarChartC) MarginTRBL(t, r, b, l int16) BarChartC {
+	c.spec.style.margin = [4]int16{t, r, b, l}
+	return c
+}
+
+// ============================================================================
+// Histogram
+// ============================================================================
+
+type HistogramC struct{ spec chartSpec }
+
+// Histogram counts values into equal bins, 10 unless set with Bins, over
+// the range of the data or of XAxis, and draws the counts as bars.
+// Pass []float64 or *[]float64 for reactive updates.
+//
+//	Histogram(&latencies).Bins(20).XAxis(ChartAxis{Min: 0, Max: 500})
+func Histogram(values any) HistogramC {
+	c := HistogramC{spec: chartSpec{kind: chartHistogram}}
+	c.spec.addSeries([]any{values})
+	return c
+}
+
+// Bins sets the number of bins.
+func (c HistogramC) Bins(n int) HistogramC { c.spec.bins = n; return c }
+
+// Color sets the bar colour in place of the first theme colour.
+func (c HistogramC) Color(col Color) HistogramC {
+	c.spec.series = []ChartSeries{c.spec.series[0].Color(col)}
+	return c
+}
+
+// Width sets the width in cells. Accepts int16, int, or *int16 for dynamic values.
+func (c HistogramC) Width(w any) HistogramC { c.spec.setWidth(w); return c }
+
+// Height sets the height in rows. Accepts int16, int, or *int16 for dynamic values.
+func (c HistogramC) Height(h any) HistogramC { c.spec.setHeight(h); return c }
+
+// Grow sets the flex grow factor. Accepts float32, float64, int, or *float32 for dynamic values.
+func (c HistogramC) Grow(g any) HistogramC { c.spec.setGrow(g); return c }
+
+// XAxis configures the value axis; its range sets the range binned.
+func (c HistogramC) XAxis(a ChartAxis) HistogramC { c.spec.xAxis = a; return c }
+
+// YAxis configures the count axis.
+func (c HistogramC) YAxis(a ChartAxis) HistogramC { c.spec.yAxis = a; return c }
+
+// Threshold draws a dashed line across the chart at count v.
+func (c HistogramC) Threshold(v float64, col Color) HistogramC {
+	c.spec.addThreshold(v, col)
+	return c
+}
+
+// Theme colours the axes with th.Muted and the bars with the first of th.Series.
+func (c HistogramC) Theme(th ThemeEx) HistogramC { c.spec.setTheme(th); return c }
+
+// Style sets the component style. Accepts Style, *Style, conditionNode, or tweenNode.
+func (c HistogramC) Style(st any) HistogramC { c.spec.setStyle(st); return c }
+
+// Margin sets uniform margin on all sides.
+func (c HistogramC) Margin(all int16) HistogramC {
+	c.spec.style.margin = [4]int16{all, all, all, all}
+	return c
+}
+
+// MarginVH sets vertical and horizontal margin.
+func (c HistogramC) MarginVH(v, h int16) HistogramC {
+	c.spec.style.margin = [4]int16{v, h, v, h}
+	return c
+}
+
+// MarginTRBL sets individual margins for top, right, bottom, left.
+func (c HistogramC) MarginTRBL(t, r, b, l int16) HistogramC {
+	c.spec.style.margin = [4]int16{t, r, b, l}
+	return c
+}
+
+// ============================================================================
+// Heatmap
+// ============================================================================
+
+type HeatmapC struct{ spec chartSpec }
+
+// Heatmap colours a grid of values, rows by columns, from a low to a high
+// colour. Each cell holds two rows, in half blocks. Pass [][]float64 or
+// *[][]float64 for reactive updates.
+//
+//	Heatmap(&load).RowLabels(&hosts).ColLabels([]string{"00", "06", "12", "18"}).Legend()
+func Heatmap(grid any) HeatmapC {
+	return HeatmapC{spec: chartSpec{kind: chartHeatmap, grid: grid, lo: Hex(0x0B1D3A), hi: Hex(0xF5D547)}}
+}
+
+// Colors sets the colours of the lowest and highest values.
+func (c HeatmapC) Colors(lo, hi Color) HeatmapC { c.spec.lo, c.spec.hi = lo, hi; return c }
+
+// Range sets the values given the low and high colours, in place of the
+// data's own range.
+func (c HeatmapC) Range(min, max float64) HeatmapC {
+	c.spec.yAxis.Min, c.spec.yAxis.Max = min, max
+	return c
+}
+
+// RowLabels labels the rows down the left. Pass []string or *[]string.
+func (c HeatmapC) RowLabels(labels any) HeatmapC { c.spec.rowLabels = labels; return c }
+
+// ColLabels labels the columns along the bottom. Pass []string or *[]string.
+func (c HeatmapC) ColLabels(labels any) HeatmapC { c.spec.colLabels = labels; return c }
+
+// Legend shows the colour scale under the heatmap.
+func (c HeatmapC) Legend() HeatmapC { c.spec.legend = true; return c }
+
+// Theme colours the labels with th.Muted and the legend with th.Base.
+func (c HeatmapC) Theme(th ThemeEx) HeatmapC { c.spec.setTheme(th); return c }
+
+// Width sets the width in cells. Accepts int16, int, or *int16 for dynamic values.
+func (c HeatmapC) Width(w any) HeatmapC { c.spec.setWidth(w); return c }
+
+// Height sets the height in rows. Accepts int16, int, or *int16 for dynamic values.
+func (c HeatmapC) Height(h any) HeatmapC { c.spec.setHeight(h); return c }
+
+// Grow sets the flex grow factor. Accepts float32, float64, int, or *float32 for dynamic values.
+func (c HeatmapC) Grow(g any) HeatmapC { c.spec.setGrow(g); return c }
+
+// Style sets the component style. Accepts Style, *Style, conditionNode, or tweenNode.
+func (c HeatmapC) Style(st any) HeatmapC { c.spec.setStyle(st); return c }
+
+// Margin sets uniform margin on all sides.
+func (c HeatmapC) Margin(all int16) HeatmapC {
+	c.spec.style.margin = [4]int16{all, all, all, all}
+	return c
+}
+
+// MarginVH sets vertical and horizontal margin.
+func (c HeatmapC) MarginVH(v, h int16) HeatmapC {
+	c.spec.style.margin = [4]int16{v, h, v, h}
+	return c
+}
+
+// MarginTRBL sets individual margins for top, right, bottom, left.
+func (c HeatmapC) MarginTRBL(t, r, b, l int16) HeatmapC {
+	c.spec.style.margin = [4]int16{t, r, b, l}
+	return c
+}
+
+// ============================================================================
+// Rendering
+// ============================================================================
+
+// chartSeries is a series resolved at compile time.
+type chartSeries struct {
+	name      string
+	color     Color
+	values    []float64
+	valuesPtr *[]float64
+	xs        []float64
+	xsPtr     *[]float64
+	times     []time.Time
+	timesPtr  *[]time.Time
+}
+
+func (s *chartSeries) vals() []float64 {
+	if s.valuesPtr != nil {
+		return *s.valuesPtr
+	}
+	return s.values
+}
+
+func (s *chartSeries) isTime() bool { return s.times != nil || s.timesPtr != nil }
+
+// x returns the x position of value i: its x value, time in Unix seconds,
+// or i itself.
+func (s *chartSeries) x(i int) float64 {
+	xs, times := s.xs, s.times
+	if s.xsPtr != nil {
+		xs = *s.xsPtr
+	}
+	if s.timesPtr != nil {
+		times = *s.timesPtr
+	}
+	switch {
+	case xs != nil:
+		if i < len(xs) {
+			return xs[i]
+		}
+		return math.NaN()
+	case times != nil:
+		if i < len(times) {
+			return float64(times[i].UnixNano()) / 1e9
+		}
+		return math.NaN()
+	}
+	return float64(i)
+}
+
+// opChart holds chart-specific data.
+type opChart struct {
+	kind       chartKind
+	series     []chartSeries
+	labels     []string
+	labelsPtr  *[]string
+	xAxis      ChartAxis
+	yAxis      ChartAxis
+	thresholds []chartThreshold
+	legend     bool
+	theme      *ThemeEx
+	horizontal bool
+	stacked    bool
+	bins       int
+	grid       [][]float64
+	gridPtr    *[][]float64
+	rowLabels  []string
+	rowPtr     *[]string
+	colLabels  []string
+	colPtr     *[]string
+	lo, hi     Color
+	style      Style
+	stylePtr   *Style
+
+	ctx    CanvasContext
+	xTicks []chartTick
+	yTicks []chartTick
+	counts []float64
+	bounds []int
+	colors []Color
+}
+
+// chartTick is a labelled position along an axis, in cells from its start.
+type chartTick struct {
+	pos   int
+	label string
+}
+
+func stringsOf(v any) ([]string, *[]string) {
+	switch val := v.(type) {
+	case []string:
+		return val, nil
+	case *[]string:
+		return nil, val
+	}
+	return nil, nil
+}
+
+func resolveStrings(s []string, p *[]string) []string {
+	if p != nil {
+		return *p
+	}
+	return s
+}
+
+func (t *Template) compileChart(v chartSpec, parent int16, depth int) int16 {
+	ext := &opChart{
+		kind: v.kind, xAxis: v.xAxis, yAxis: v.yAxis, thresholds: v.thresholds,
+		legend: v.legend, theme: v.theme, horizontal: v.horizontal, stacked: v.stacked,
+		bins: v.bins, lo: v.lo, hi: v.hi, style: v.style,
+	}
+	ext.stylePtr = t.compileStyleDyn(v.style, v.styleDyn, nil, nil)
+	ext.ctx.mode = v.mode
+
+	palette := defaultSeriesColors
+	if v.theme != nil {
+		palette = nil
+		for _, c := range v.theme.Series {
+			if c.Mode != ColorDefault {
+				palette = append(palette, c)
+			}
+		}
+	}
+	for i, s := range v.series {
+		cs := chartSeries{name: s.name, color: s.color}
+		if cs.color.Mode == ColorDefault && len(palette) > 0 {
+			cs.color = palette[i%len(palette)]
+		}
+		switch vals := s.values.(type) {
+		case []float64:
+			cs.values = vals
+		case *[]float64:
+			cs.valuesPtr = vals
+		}
+		switch xs := s.x.(type) {
+		case []float64:
+			cs.xs = xs
+		case *[]float64:
+			cs.xsPtr = xs
+		case []time.Time:
+			cs.times = xs
+		case *[]time.Time:
+			cs.timesPtr = xs
+		}
+		ext.series = append(ext.series, cs)
+	}
+	ext.labels, ext.labelsPtr = stringsOf(v.labels)
+	ext.rowLabels, ext.rowPtr = stringsOf(v.rowLabels)
+	ext.colLabels, ext.colPtr = stringsOf(v.colLabels)
+	switch g := v.grid.(type) {
+	case [][]float64:
+		ext.grid = g
+	case *[][]float64:
+		ext.gridPtr = g
+	}
+
+	idx := t.addOp(Op{
+		Kind:     OpChart,
+		Parent:   parent,
+		Width:    v.width,
+		Height:   v.height,
+		FlexGrow: v.flexGrow,
+		Margin:   v.style.margin,
+		Ext:      ext,
+	}, depth)
+	hasDyn := v.widthPtr != nil || v.heightPtr != nil || v.widthCond != nil || v.heightCond != nil ||
+		v.flexGrowPtr != nil || v.flexGrowCond != nil
+	if hasDyn {
+		t.ops[idx].Dyn = &OpDyn{}
+		if v.widthCond != nil {
+			t.ops[idx].Dyn.Width = t.compileDynInt16(v.widthCond)
+		} else if v.widthPtr != nil {
+			t.ops[idx].Dyn.Width = v.widthPtr
+		}
+		if v.heightCond != nil {
+			t.ops[idx].Dyn.Height = t.compileDynInt16(v.heightCond)
+		} else if v.heightPtr != nil {
+			t.ops[idx].Dyn.Height = v.heightPtr
+		}
+		if v.flexGrowCond != nil {
+			t.ops[idx].Dyn.FlexGrow = t.compileDynFloat32(v.flexGrowCond)
+		} else if v.flexGrowPtr != nil {
+			t.ops[idx].Dyn.FlexGrow = v.flexGrowPtr
+		}
+	}
+	return idx
+}
+
+// chartStyles holds the styles a chart draws in.
+type chartStyles struct {
+	base, axis, text Style
+}
+
+func (c *opChart) render(t *Template, buf *Buffer, x, y, w, h int16) {
+	if w <= 0 || h <= 0 {
+		return
+	}
+	baseStyle := c.style
+	if c.stylePtr != nil {
+		baseStyle = *c.stylePtr
+	}
+	st := chartStyles{base: t.effectiveStyle(baseStyle)}
+	st.axis, st.text = st.base, st.base
+	if c.theme != nil {
+		st.axis, st.text = t.effectiveStyle(c.theme.Muted), t.effectiveStyle(c.theme.Base)
+	}
+
+	cx, cy, cw, ch := int(x), int(y), int(w), int(h)
+	if c.legend && ch > 1 {
+		ch--
+		c.drawLegend(buf, cx, cy+ch, cw, st)
+	}
+	switch c.kind {
+	case chartLine, chartScatter:
+		c.renderXY(buf, cx, cy, cw, ch, st)
+	case chartBar, chartHistogram:
+		c.renderBars(buf, cx, cy, cw, ch, st)
+	case chartHeatmap:
+		c.renderHeatmap(buf, cx, cy, cw, ch, st)
+	}
+}
+
+// plotArea works out where the plot sits once room is made for the axes:
+// y tick labels and the axis line on the left, the x axis line and labels
+// below. yTicks is called with the plot's height to give the y ticks, as
+// their labels decide its width.
+func (c *opChart) plotArea(x, y, w, h int, yTicks func(plotH int) []chartTick) (px, py, pw, ph int) {
+	px, py, pw, ph = x, y, w, h
+	if !c.xAxis.Hidden && ph > 2 {
+		ph -= 2
+	}
+	ticks := yTicks(ph)
+	if !c.yAxis.Hidden {
+		labelW := 0
+		for _, tk := range ticks {
+			labelW = max(labelW, utf8.RuneCountInString(tk.label))
+		}
+		if labelW+2 < pw {
+			px += labelW + 1
+			pw -= labelW + 1
+		}
+	}
+	return px, py, pw, ph
+}
+
+// drawAxes draws the axis lines, ticks and labels around the plot at
+// px, py, pw×ph.
+func (c *opChart) drawAxes(buf *Buffer, x, px, py, pw, ph int, st chartStyles) {
+	showY := !c.yAxis.Hidden && px > x
+	showX := !c.xAxis.Hidden
+	if showY {
+		ax := px - 1
+		for row := 0; row < ph; row++ {
+			buf.Set(ax, py+row, Cell{Rune: '│', Style: st.axis})
+		}
+		for _, tk := range c.yTicks {
+			if tk.pos < 0 || tk.pos >= ph {
+				continue
+			}
+			buf.Set(ax, py+tk.pos, Cell{Rune: '┤', Style: st.axis})
+			n := utf8.RuneCountInString(tk.label)
+			buf.WriteStringFast(ax-n, py+tk.pos, tk.label, st.axis, n)
+		}
+	}
+	if !showX {
+		return
+	}
+	ay := py + ph
+	for col := 0; col < pw; col++ {
+		buf.Set(px+col, ay, Cell{Rune: '─', Style: st.axis})
+	}
+	if showY {
+		buf.Set(px-1, ay, Cell{Rune: '└', Style: st.axis})
+	}
+	end := px
+	for _, tk := range c.xTicks {
+		if tk.pos < 0 || tk.pos >= pw {
+			continue
+		}
+		buf.Set(px+tk.pos, ay, Cell{Rune: '┬', Style: st.axis})
+		n := utf8.RuneCountInString(tk.label)
+		lx := min(max(px+tk.pos-n/2, px), px+pw-n)
+		if lx < end {
+			continue // would overlap the label before
+		}
+		buf.WriteStringFast(lx, ay+1, tk.label, st.axis, px+pw-lx)
+		end = lx + n + 1
+	}
+}
+
+// drawThresholds draws each threshold as a dashed line over the empty
+// cells of the plot. pos maps a value to its row, or its column when
+// vertical.
+func (c *opChart) drawThresholds(buf *Buffer, px, py, pw, ph int, vertical bool, pos func(v float64) int, st chartStyles) {
+	for _, th := range c.thresholds {
+		p := pos(th.value)
+		style := st.base
+		if th.color.Mode != ColorDefault {
+			style.FG = th.color
+		}
+		if vertical {
+			if p < 0 || p >= pw {
+				continue
+			}
+			for row := 0; row < ph; row++ {
+				if buf.Get(px+p, py+row).Rune == ' ' {
+					buf.Set(px+p, py+row, Cell{Rune: '┆', Style: style})
+				}
+			}
+			continue
+		}
+		if p < 0 || p >= ph {
+			continue
+		}
+		for col := 0; col < pw; col++ {
+			if buf.Get(px+col, py+p).Rune == ' ' {
+				buf.Set(px+col, py+p, Cell{Rune: '┄', Style: style})
+			}
+		}
+	}
+}
+
+func (c *opChart) drawLegend(buf *Buffer, x, y, w int, st chartStyles) {
+	if c.kind == chartHeatmap {
+		c.drawScale(buf, x, y, w, st)
+		return
+	}
+	end := x + w
+	for _, s := range c.series {
+		if s.name == "" || x+2 >= end {
+			continue
+		}
+		key := st.text
+		if s.color.Mode != ColorDefault {
+			key.FG = s.color
+		}
+		buf.Set(x, y, Cell{Rune: '■', Style: key})
+		buf.WriteStringFast(x+2, y, s.name, st.text, end-x-2)
+		x += utf8.RuneCountInString(s.name) + 4
+	}
+}
+
+// renderXY draws line charts and scatter plots.
+func (c *opChart) renderXY(buf *Buffer, x, y, w, h int, st chartStyles) {
+	timeAxis := false
+	xlo, xhi := math.Inf(1), math.Inf(-1)
+	ylo, yhi := math.Inf(1), math.Inf(-1)
+	for i := range c.series {
+		s := &c.series[i]
+		timeAxis = timeAxis || s.isTime()
+		for j, v := range s.vals() {
+			xv := s.x(j)
+			if math.IsNaN(v) || math.IsNaN(xv) {
+				continue
+			}
+			xlo, xhi = min(xlo, xv), max(xhi, xv)
+			ylo, yhi = min(ylo, v), max(yhi, v)
+		}
+	}
+	for _, th := range c.thresholds {
+		ylo, yhi = min(ylo, th.value), max(yhi, th.value)
+	}
+
+	var ymin, ymax float64
+	cw, ch := c.ctx.mode.cellPixels()
+	px, py, pw, ph := c.plotArea(x, y, w, h, func(plotH int) []chartTick {
+		ymin, ymax = c.valueTicks(&c.yTicks, c.yAxis, ylo, yhi, max(2, plotH/3), pixelScale(plotH, ch, true))
+		return c.yTicks
+	})
+	if pw <= 0 || ph <= 0 {
+		return
+	}
+	var xmin, xmax float64
+	if timeAxis {
+		xmin, xmax = c.timeTicks(&c.xTicks, c.xAxis, xlo, xhi, max(2, pw/12), pixelScale(pw, cw, false))
+	} else {
+		xmin, xmax = c.valueTicks(&c.xTicks, c.xAxis, xlo, xhi, max(2, pw/8), pixelScale(pw, cw, false))
+	}
+
+	c.ctx.resize(c.ctx.mode, pw, ph)
+	mapX := func(v float64) int { return scaleTo(v, xmin, xmax, pw*cw-1) }
+	mapY := func(v float64) int { return ph*ch - 1 - scaleTo(v, ymin, ymax, ph*ch-1) }
+	for i := range c.series {
+		s := &c.series[i]
+		c.ctx.SetColor(s.color)
+		prevOK := false
+		var lx, ly int
+		for j, v := range s.vals() {
+			xv := s.x(j)
+			if math.IsNaN(v) || math.IsNaN(xv) {
+				prevOK = false
+				continue
+			}
+			sx, sy := mapX(xv), mapY(v)
+			switch {
+			case c.kind == chartScatter:
+				c.ctx.Set(sx, sy)
+			case prevOK:
+				c.ctx.Line(lx, ly, sx, sy)
+			default:
+				c.ctx.Set(sx, sy)
+			}
+			lx, ly, prevOK = sx, sy, true
+		}
+	}
+	c.ctx.blit(buf, px, py, st.base)
+
+	c.drawThresholds(buf, px, py, pw, ph, false, func(v float64) int { return mapY(v) / ch }, st)
+	c.drawAxes(buf, x, px, py, pw, ph, st)
+}
+
+// scaleTo maps v in lo..hi onto 0..n, rounding to the nearest step.
+func scaleTo(v, lo, hi float64, n int) int {
+	if hi <= lo {
+		return 0
+	}
+	f := (v - lo) / (hi - lo) * float64(n)
+	f = min(max(f, -1e6), 1e6)
+	return int(math.Round(f))
+}
+
+// vBlocks and hBlocks fill a cell in eighths, from the bottom and the left.
+var (
+	vBlocks = [9]rune{' ', '▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+	hBlocks = [9]rune{' ', '▏', '▎', '▍', '▌', '▋', '▊', '▉', '█'}
+)
+
+// renderBars draws bar charts and histograms.
+func (c *opChart) renderBars(buf *Buffer, x, y, w, h int, st chartStyles) {
+	labels := resolveStrings(c.labels, c.labelsPtr)
+	n := len(labels)
+	groups := len(c.series)
+	value := func(s, i int) float64 {
+		vals := c.series[s].vals()
+		if i < len(vals) && !math.IsNaN(vals[i]) {
+			return vals[i]
+		}
+		return 0
+	}
+
+	valueAxis, catAxis := c.yAxis, c.xAxis
+	if c.horizontal {
+		valueAxis, catAxis = c.xAxis, c.yAxis
+	}
+	var binLo, binHi float64
+	if c.kind == chartHistogram {
+		n, groups = c.binCounts(&binLo, &binHi)
+		value = func(_, i int) float64 { return c.counts[i] }
+	} else {
+		for i := range c.series {
+			n = max(n, len(c.series[i].vals()))
+		}
+	}
+	bars := groups
+	if c.stacked {
+		bars = 1
+	}
+
+	lo, hi := 0.0, 0.0
+	for i := 0; i < n; i++ {
+		sum := 0.0
+		for s := 0; s < groups; s++ {
+			v := value(s, i)
+			if c.stacked {
+				sum += max(v, 0)
+				continue
+			}
+			lo, hi = min(lo, v), max(hi, v)
+		}
+		hi = max(hi, sum)
+	}
+	for _, th := range c.thresholds {
+		lo, hi = min(lo, th.value), max(hi, th.value)
+	}
+
+	// the category axis labels each slot; the value axis carries the ticks
+	var vmin, vmax float64
+	catTicks := func(span int, out *[]chartTick) {
+		*out = (*out)[:0]
+		if c.kind == chartHistogram {
+			a := catAxis
+			a.Min, a.Max = binLo, binHi
+			c.valueTicks(out, a, binLo, binHi, max(2, span/8), eighthScale(span, false))
+			return
+		}
+		for i := 0; i < n && i < len(labels); i++ {
+			mid := (slotStart(i, n, span) + slotStart(i+1, n, span) - 1) / 2
+			*out = append(*out, chartTick{pos: mid, label: labels[i]})
+		}
+	}
+	var px, py, pw, ph int
+	if c.horizontal {
+		px, py, pw, ph = c.plotArea(x, y, w, h, func(plotH int) []chartTick {
+			catTicks(plotH, &c.yTicks)
+			return c.yTicks
+		})
+		if pw <= 0 || ph <= 0 {
+			return
+		}
+		vmin, vmax = c.valueTicks(&c.xTicks, valueAxis, lo, hi, max(2, pw/8), eighthScale(pw, false))
+	} else {
+		px, py, pw, ph = c.plotArea(x, y, w, h, func(plotH int) []chartTick {
+			vmin, vmax = c.valueTicks(&c.yTicks, valueAxis, lo, hi, max(2, plotH/3), eighthScale(plotH, true))
+			return c.yTicks
+		})
+		if pw <= 0 || ph <= 0 {
+			return
+		}
+		catTicks(pw, &c.xTicks)
+	}
+
+	// lengths run along the value axis in eighths of a cell, with the
+	// baseline at zero, or the nearer end of the range, on a cell boundary
+	span := ph
+	if c.horizontal {
+		span = pw
+	}
+	toEighths := func(v float64) int { return scaleTo(v, vmin, vmax, span*8) }
+	base := toEighths(min(max(0, vmin), vmax)) / 8 * 8
+
+	slots := ph
+	if !c.horizontal {
+		slots = pw
+	}
+	for i := 0; i < n; i++ {
+		start, end := slotStart(i, n, slots), slotStart(i+1, n, slots)
+		room := end - start
+		if room > 2 && c.kind != chartHistogram {
+			room-- // gap between groups
+		}
+		size := max(room/bars, 1)
+		off := start + (end-start-size*bars)/2
+
+		for b := 0; b < bars; b++ {
+			c.bounds = append(c.bounds[:0], base)
+			c.colors = c.colors[:0]
+			if c.stacked {
+				top := base
+				for s := 0; s < groups; s++ {
+					if v := value(s, i); v > 0 {
+						top = max(top, toEighths(min(v+c.stackBelow(value, s, i), vmax)))
+						c.bounds = append(c.bounds, top)
+						c.colors = append(c.colors, c.series[s].color)
+					}
+				}
+			} else {
+				c.bounds = append(c.bounds, toEighths(value(b, i)))
+				c.colors = append(c.colors, c.series[b%len(c.series)].color)
+			}
+			for k := 0; k < size; k++ {
+				lane := off + b*size + k
+				if lane >= end {
+					break
+				}
+				c.drawBar(buf, px, py, pw, ph, lane, st)
+			}
+		}
+	}
+
+	if c.horizontal {
+		c.drawThresholds(buf, px, py, pw, ph, true, func(v float64) int { return eighthScale(pw, false)(v, vmin, vmax) }, st)
+	} else {
+		c.drawThresholds(buf, px, py, pw, ph, false, func(v float64) int { return eighthScale(ph, true)(v, vmin, vmax) }, st)
+	}
+	c.drawAxes(buf, x, px, py, pw, ph, st)
+}
+
+// stackBelow sums the positive values stacked under series s at i.
+func (c *opChart) stackBelow(value func(s, i int) float64, s, i int) float64 {
+	sum := 0.0
+	for k := 0; k < s; k++ {
+		sum += max(value(k, i), 0)
+	}
+	return sum
+}
+
+// slotStart returns where slot i of n begins across span cells.
+func slotStart(i, n, span int) int {
+	if n == 0 {
+		return 0
+	}
+	return i * span / n
+}
+
+// drawBar draws one lane of a bar from c.bounds and c.colors: segment k
+// runs from bounds[k] to bounds[k+1], in eighths along the value axis.
+// Bars below the baseline are drawn in whole cells.
+func (c *opChart) drawBar(buf *Buffer, px, py, pw, ph, lane int, st chartStyles) {
+	span := ph
+	if c.horizontal {
+		span = pw
+	}
+	set := func(cell int, r rune, fg, bg Color) {
+		style := st.base
+		if fg.Mode != ColorDefault {
+			style.FG = fg
+		}
+		if bg.Mode != ColorDefault {
+			style.BG = bg
+		}
+		if c.horizontal {
+			buf.Set(px+cell, py+lane, Cell{Rune: r, Style: style})
+		} else {
+			buf.Set(px+lane, py+ph-1-cell, Cell{Rune: r, Style: style})
+		}
+	}
+	blocks := &vBlocks
+	if c.horizontal {
+		blocks = &hBlocks
+	}
+
+	base, top := c.bounds[0], c.bounds[len(c.bounds)-1]
+	if top < base {
+		for cell := (top + 4) / 8; cell < base/8; cell++ {
+			set(cell, '█', c.colors[0], Color{})
+		}
+		return
+	}
+	for cell := base / 8; cell < span && cell*8 < top; cell++ {
+		e0 := cell * 8
+		filled := min(top-e0, 8)
+		k := 0
+		for k+2 < len(c.bounds) && c.bounds[k+1] <= e0 {
+			k++
+		}
+		if next := c.bounds[k+1]; filled == 8 && next < e0+8 && k+2 < len(c.bounds) {
+			// a segment boundary inside the cell: lower part in FG, upper in BG
+			set(cell, blocks[next-e0], c.colors[k], c.colors[k+1])
+			continue
+		}
+		set(cell, blocks[filled], c.colors[k], Color{})
+	}
+}
+
+// binCounts counts the first series into bins over the x axis range or
+// the data's, leaving the counts in c.counts. It returns the bin count and
+// a single group.
+func (c *opChart) binCounts(lo, hi *float64) (n, groups int) {
+	vals := c.series[0].vals()
+	*lo, *hi = c.xAxis.Min, c.xAxis.Max
+	if *lo == 0 && *hi == 0 {
+		*lo, *hi = math.Inf(1), math.Inf(-1)
+		for _, v := range vals {
+			if !math.IsNaN(v) {
+				*lo, *hi = min(*lo, v), max(*hi, v)
+			}
+		}
+		if math.IsInf(*lo, 0) {
+			*lo, *hi = 0, 1
+		}
+	}
+	if *hi < *lo {
+		*lo, *hi = *hi, *lo
+	}
+	if *hi == *lo {
+		*hi = *lo + 1
+	}
+	n = c.bins
+	if n <= 0 {
+		n = 10
+	}
+	if cap(c.counts) >= n {
+		c.counts = c.counts[:n]
+		clear(c.counts)
+	} else {
+		c.counts = make([]float64, n)
+	}
+	for _, v := range vals {
+		if math.IsNaN(v) || v < *lo || v > *hi {
+			continue
+		}
+		b := int((v - *lo) / (*hi - *lo) * float64(n))
+		c.counts[min(max(b, 0), n-1)]++
+	}
+	return n, 1
+}
+
+// renderHeatmap draws the grid in half blocks: each cell holds two rows,
+// the upper in FG and the lower in BG.
+func (c *opChart) renderHeatmap(buf *Buffer, x, y, w, h int, st chartStyles) {
+	grid, lo, hi := c.heatGrid()
+	rowLabels := resolveStrings(c.rowLabels, c.rowPtr)
+	colLabels := resolveStrings(c.colLabels, c.colPtr)
+	rows, cols := len(grid), 0
+	for _, row := range grid {
+		cols = max(cols, len(row))
+	}
+	if rows == 0 || cols == 0 {
+		return
+	}
+
+	px, pw, ph := x, w, h
+	if len(colLabels) > 0 && ph > 1 {
+		ph--
+	}
+	labelW := 0
+	for _, l := range rowLabels {
+		labelW = max(labelW, utf8.RuneCountInString(l))
+	}
+	if labelW > 0 && labelW+2 < pw {
+		px += labelW + 1
+		pw -= labelW + 1
+	}
+
+	// label each row in the cell holding its first half block, unless the
+	// row above already took it
+	last := -1
+	for r, l := range rowLabels {
+		if r >= rows {
+			break
+		}
+		if ly := (r*ph*2 + rows - 1) / rows / 2; ly > last && ly < ph {
+			buf.WriteStringFast(x, y+ly, l, st.axis, labelW)
+			last = ly
+		}
+	}
+	end := px
+	for i, l := range colLabels {
+		if i >= cols {
+			break
+		}
+		lx := px + slotStart(i, cols, pw)
+		if lx < end {
+			continue
+		}
+		buf.WriteStringFast(lx, y+ph, l, st.axis, px+pw-lx)
+		end = lx + utf8.RuneCountInString(l) + 1
+	}
+
+	colorAt := func(r, col int) Color {
+		if col >= len(grid[r]) || math.IsNaN(grid[r][col]) {
+			return Color{}
+		}
+		return c.scaleColor(grid[r][col], lo, hi)
+	}
+	for cy := 0; cy < ph; cy++ {
+		upper, lower := cy*2*rows/(ph*2), (cy*2+1)*rows/(ph*2)
+		for cx := 0; cx < pw; cx++ {
+			col := cx * cols / pw
+			style := st.base
+			style.FG, style.BG = colorAt(upper, col), colorAt(lower, col)
+			r := '▀'
+			if style.FG.Mode == ColorDefault {
+				r, style.FG, style.BG = '▄', style.BG, st.base.BG
+			}
+			buf.Set(px+cx, y+cy, Cell{Rune: r, Style: style})
+		}
+	}
+}
+
+// heatGrid returns the grid and the range its colours span.
+func (c *opChart) heatGrid() (grid [][]float64, lo, hi float64) {
+	grid = c.grid
+	if c.gridPtr != nil {
+		grid = *c.gridPtr
+	}
+	lo, hi = c.yAxis.Min, c.yAxis.Max
+	if lo != 0 || hi != 0 {
+		return grid, lo, hi
+	}
+	lo, hi = math.Inf(1), math.Inf(-1)
+	for _, row := range grid {
+		for _, v := range row {
+			if !math.IsNaN(v) {
+				lo, hi = min(lo, v), max(hi, v)
+			}
+		}
+	}
+	if math.IsInf(lo, 0) {
+		return grid, 0, 0
+	}
+	return grid, lo, hi
+}
+
+func (c *opChart) scaleColor(v, lo, hi float64) Color {
+	if hi <= lo {
+		return c.hi
+	}
+	return lerpColor(c.lo, c.hi, min(max((v-lo)/(hi-lo), 0), 1))
+}
+
+// drawScale draws the heatmap legend: the low value, a colour ramp and
+// the high value.
+func (c *opChart) drawScale(buf *Buffer, x, y, w int, st chartStyles) {
+	_, lo, hi := c.heatGrid()
+	step := niceStep(hi-lo, 4)
+	loLabel, hiLabel := c.formatValue(c.yAxis, lo, step), c.formatValue(c.yAxis, hi, step)
+	n := utf8.RuneCountInString(loLabel)
+	buf.WriteStringFast(x, y, loLabel, st.text, w)
+	ramp := min(16, w-n-utf8.RuneCountInString(hiLabel)-2)
+	for i := 0; i < ramp; i++ {
+		style := st.base
+		style.FG = c.scaleColor(lo+(hi-lo)*float64(i)/float64(max(ramp-1, 1)), lo, hi)
+		buf.Set(x+n+1+i, y, Cell{Rune: '█', Style: style})
+	}
+	if ramp > 0 {
+		buf.WriteStringFast(x+n+ramp+2, y, hiLabel, st.text, w-n-ramp-2)
+	}
+}
+
+// axisScale maps v in lo..hi to a cell along an axis.
+type axisScale func(v, lo, hi float64) int
+
+// pixelScale maps values to the cells of a span-cell axis holding their
+// pixels, sub to a cell, as the canvas plots them. Inverted runs from the
+// bottom, as rows do.
+func pixelScale(span, sub int, inverted bool) axisScale {
+	return func(v, lo, hi float64) int {
+		p := scaleTo(v, lo, hi, span*sub-1)
+		if inverted {
+			p = span*sub - 1 - p
+		}
+		return p / sub
+	}
+}
+
+// eighthScale maps values to the cell a bar of that value ends in.
+func eighthScale(span int, inverted bool) axisScale {
+	return func(v, lo, hi float64) int {
+		cell := max(scaleTo(v, lo, hi, span*8)-1, 0) / 8
+		if inverted {
+			return span - 1 - cell
+		}
+		return cell
+	}
+}
+
+// valueTicks fills out with about count ticks for a numeric axis, placed
+// by scale, and returns the range the axis covers: the axis's own, or the
+// data's widened to whole steps.
+func (c *opChart) valueTicks(out *[]chartTick, a ChartAxis, lo, hi float64, count int, scale axisScale) (float64, float64) {
+	*out = (*out)[:0]
+	explicit := a.Min != 0 || a.Max != 0
+	if explicit {
+		lo, hi = min(a.Min, a.Max), max(a.Min, a.Max)
+	}
+	if math.IsInf(lo, 0) || math.IsInf(hi, 0) {
+		lo, hi = 0, 1
+	}
+	if hi == lo {
+		lo, hi = lo-1, hi+1
+	}
+	step := niceStep(hi-lo, count)
+	if !explicit {
+		lo, hi = math.Floor(lo/step)*step, math.Ceil(hi/step)*step
+	}
+
+	add := func(v float64) {
+		*out = append(*out, chartTick{pos: scale(v, lo, hi), label: c.formatValue(a, v, step)})
+	}
+	if a.Ticks != nil {
+		for _, v := range a.Ticks {
+			if v >= lo && v <= hi {
+				add(v)
+			}
+		}
+		return lo, hi
+	}
+	first := math.Ceil(lo/step - 1e-9)
+	for i := first; i*step <= hi+step*1e-9; i++ {
+		add(i * step)
+	}
+	return lo, hi
+}
+
+// timeSteps are the tick intervals for time axes, in seconds.
+var timeSteps = []float64{1, 2, 5, 10, 15, 30, 60, 120, 300, 600, 900, 1800,
+	3600, 7200, 10800, 21600, 43200, 86400, 172800, 604800}
+
+// timeTicks is valueTicks for time axes, with ticks on whole seconds,
+// minutes, hours or days.
+func (c *opChart) timeTicks(out *[]chartTick, a ChartAxis, lo, hi float64, count int, scale axisScale) (float64, float64) {
+	*out = (*out)[:0]
+	if a.Min != 0 || a.Max != 0 {
+		lo, hi = min(a.Min, a.Max), max(a.Min, a.Max)
+	}
+	if math.IsInf(lo, 0) || math.IsInf(hi, 0) || hi == lo {
+		return c.valueTicks(out, a, lo, hi, count, scale)
+	}
+	step := timeSteps[len(timeSteps)-1]
+	for _, s := range timeSteps {
+		if s >= (hi-lo)/float64(count) {
+			step = s
+			break
+		}
+	}
+	layout := a.TimeFormat
+	switch {
+	case layout != "":
+	case step < 60:
+		layout = "15:04:05"
+	case step < 86400:
+		layout = "15:04"
+	default:
+		layout = "Jan 2"
+	}
+
+	add := func(v float64) {
+		label := ""
+		if a.Format != nil {
+			label = a.Format(v)
+		} else {
+			label = time.Unix(0, int64(v*1e9)).Format(layout)
+		}
+		*out = append(*out, chartTick{pos: scale(v, lo, hi), label: label})
+	}
+	if a.Ticks != nil {
+		for _, v := range a.Ticks {
+			if v >= lo && v <= hi {
+				add(v)
+			}
+		}
+		return lo, hi
+	}
+	for v := math.Ceil(lo/step) * step; v <= hi; v += step {
+		add(v)
+	}
+	return lo, hi
+}
+
+// niceStep returns the step of 1, 2 or 5 times a power of ten nearest to
+// splitting span into count parts.
+func niceStep(span float64, count int) float64 {
+	if span <= 0 || count < 1 {
+		return 1
+	}
+	raw := span / float64(count)
+	mag := math.Pow(10, math.Floor(math.Log10(raw)))
+	switch r := raw / mag; {
+	case r < 1.5:
+		return mag
+	case r < 3.5:
+		return 2 * mag
+	case r < 7.5:
+		return 5 * mag
+	}
+	return 10 * mag
+}
+
+// formatValue labels v with the axis's Format, or with as many decimals as
+// step needs and a k, M or G suffix for large steps.
+func (c *opChart) formatValue(a ChartAxis, v, step float64) string {
+	if a.Format != nil {
+		return a.Format(v)
+	}
+	suffix := ""
+	switch s := math.Abs(step); {
+	case s >= 1e9:
+		v, step, suffix = v/1e9, step/1e9, "G"
+	case s >= 1e6:
+		v, step, suffix = v/1e6, step/1e6, "M"
+	case s >= 1e3:
+		v, step, suffix = v/1e3, step/1e3, "k"
+	}
+	if math.Abs(v) < math.Abs(step)*1e-9 {
+		v = 0
+	}
+	decimals := 0
+	for decimals < 6 {
+		scaled := step * math.Pow(10, float64(decimals))
+		if math.Abs(scaled-math.Round(scaled)) < 1e-6 {
+			break
+		}
+		decimals++
+	}
+	return strconv.FormatFloat(v, 'f', decimals, 64) + suffix
+}
@@ -0,0 +1,213 @@
+package glyph
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLineChart(t *testing.T) {
+	tmpl := Build(VBox(LineChart([]float64{0, 2, 4}).Height(4)))
+	buf := NewBuffer(12, 4)
+	want := "4┤    ⢀⣀⠤⠔⠒⠉\n0┤⣀⠤⠒⠊⠁\n └┬────┬───┬\n  0    1   2"
+	if got := frame(tmpl, buf); got != want {
+		t.Errorf("line chart =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestLineChartTimeAxis(t *testing.T) {
+	start := time.Date(2026, 1, 1, 10, 0, 0, 0, time.Local)
+	var times []time.Time
+	for i := range 9 {
+		times = append(times, start.Add(time.Duration(i)*time.Minute))
+	}
+	vals := []float64{1, 4, 2, 8, 5, 7, 3, 9, 6}
+	tmpl := Build(VBox(LineChart(Series("load", &vals).X(&times)).Height(6)))
+	buf := NewBuffer(40, 6)
+	got := frame(tmpl, buf)
+	if last := got[strings.LastIndex(got, "\n")+1:]; !strings.Contains(last, "10:00") || !strings.Contains(last, "10:05") {
+		t.Errorf("time labels = %q", last)
+	}
+}
+
+func TestBarChart(t *testing.T) {
+	vals := []float64{1, 2}
+	labels := []string{"a", "b"}
+	tmpl := Build(VBox(BarChart(&vals).Labels(&labels).Height(4)))
+	buf := NewBuffer(10, 4)
+	want := "2┤    ███\n1┤███ ███\n └─┬───┬──\n   a   b"
+	if got := frame(tmpl, buf); got != want {
+		t.Errorf("bars =\n%s\nwant\n%s", got, want)
+	}
+
+	// bound to pointers, so a change shows on the next frame
+	vals[0] = 2
+	want = "2┤███ ███\n1┤███ ███\n └─┬───┬──\n   a   b"
+	if got := frame(tmpl, buf); got != want {
+		t.Errorf("after update =\n%s\nwant\n%s", got, want)
+	}
+
+	tmpl = Build(VBox(BarChart([]float64{1, 2}).Labels(labels).Horizontal().Height(4)))
+	buf = NewBuffer(12, 4)
+	want = "a┤█████\nb┤██████████\n └┬───┬────┬\n  0   1    2"
+	if got := frame(tmpl, buf); got != want {
+		t.Errorf("horizontal =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestBarChartStacked(t *testing.T) {
+	tmpl := Build(VBox(BarChart(Series("a", []float64{1}).Color(Red), Series("b", []float64{1}).Color(Blue)).
+		Stacked().
+		XAxis(ChartAxis{Hidden: true}).
+		YAxis(ChartAxis{Min: 0, Max: 4, Hidden: true}).
+		Height(2)))
+	buf := NewBuffer(1, 2)
+	frame(tmpl, buf)
+
+	// the segments meet halfway up the bottom cell
+	cell := buf.Get(0, 1)
+	if cell.Rune != '▄' || cell.Style.FG != Red || cell.Style.BG != Blue {
+		t.Errorf("boundary cell = %q FG %+v BG %+v", cell.Rune, cell.Style.FG, cell.Style.BG)
+	}
+	if cell := buf.Get(0, 0); cell.Rune != ' ' {
+		t.Errorf("above the stack = %q", cell.Rune)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	vals := []float64{0, 1, 1, 2, 3, 3, 3, 4}
+	tmpl := Build(VBox(Histogram(&vals).Bins(4).YAxis(ChartAxis{Hidden: true}).Height(5)))
+	buf := NewBuffer(8, 5)
+	want := "      ██\n  ▄▄  ██\n▆▆██▆▆██\n┬──┬───┬\n0  2   4"
+	if got := frame(tmpl, buf); got != want {
+		t.Errorf("histogram =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestHistogramExplicitRange(t *testing.T) {
+	vals := []float64{0, 1, 2, 3, 4, 5}
+	for _, a := range []ChartAxis{{Min: 5, Max: 5}, {Min: 4, Max: 0}} {
+		tmpl := Build(VBox(Histogram(&vals).Bins(4).XAxis(a).YAxis(ChartAxis{Hidden: true}).Height(5)))
+		buf := NewBuffer(8, 5)
+		frame(tmpl, buf) // must not panic
+
+		var c opChart
+		c.series = []chartSeries{{values: vals}}
+		c.xAxis, c.bins = a, 4
+		var lo, hi float64
+		c.binCounts(&lo, &hi)
+		total := 0.0
+		for _, n := range c.counts {
+			total += n
+		}
+		if lo >= hi || total == 0 {
+			t.Errorf("range %v..%v: binned %v..%v, %v values", a.Min, a.Max, lo, hi, total)
+		}
+	}
+}
+
+func TestScatter(t *testing.T) {
+	tmpl := Build(VBox(Scatter(Series("s", []float64{0, 1}).X([]float64{1, 0})).
+		XAxis(ChartAxis{Hidden: true}).
+		YAxis(ChartAxis{Hidden: true}).
+		Height(1)))
+	buf := NewBuffer(2, 1)
+	if got := frame(tmpl, buf); got != "⠁⢀" {
+		t.Errorf("scatter = %q", got)
+	}
+}
+
+func TestHeatmap(t *testing.T) {
+	lo, hi := RGB(0, 0, 0), RGB(255, 255, 255)
+	grid := [][]float64{{0, 1}, {1, 0}}
+	tmpl := Build(VBox(Heatmap(&grid).Colors(lo, hi).Height(1)))
+	buf := NewBuffer(2, 1)
+	frame(tmpl, buf)
+	for x, want := range [][2]Color{{lo, hi}, {hi, lo}} {
+		cell := buf.Get(x, 0)
+		if cell.Rune != '▀' || cell.Style.FG != want[0] || cell.Style.BG != want[1] {
+			t.Errorf("cell %d = %q FG %+v BG %+v", x, cell.Rune, cell.Style.FG, cell.Style.BG)
+		}
+	}
+}
+
+func TestChartThresholdAndLegend(t *testing.T) {
+	tmpl := Build(VBox(BarChart(Series("cpu", []float64{1}), Series("mem", []float64{1})).
+		Threshold(2, Red).
+		XAxis(ChartAxis{Hidden: true}).
+		YAxis(ChartAxis{Min: 0, Max: 2, Hidden: true}).
+		Legend().
+		Height(3)))
+	buf := NewBuffer(12, 3)
+	got := frame(tmpl, buf)
+	if lines := strings.Split(got, "\n"); len(lines) != 3 || lines[2] != "■ cpu  ■ mem" {
+		t.Fatalf("chart =\n%s", got)
+	}
+	if cell := buf.Get(0, 0); cell.Rune != '┄' || cell.Style.FG != Red {
+		t.Errorf("threshold cell = %q FG %+v", cell.Rune, cell.Style.FG)
+	}
+}
+
+func TestChartThemeSeries(t *testing.T) {
+	th := ThemeDark
+	th.Series = [8]Color{Red, {}, Blue}
+	if th == ThemeDark {
+		t.Fatal("themes with different series compare equal")
+	}
+	tmpl := Build(VBox(LineChart(Series("a", []float64{1}), Series("b", []float64{1}), Series("c", []float64{1})).Theme(th)))
+	var got []Color
+	for _, s := range tmpl.ops[1].Ext.(*opChart).series {
+		got = append(got, s.color)
+	}
+	if want := []Color{Red, Blue, Red}; !slices.Equal(got, want) {
+		t.Errorf("series colours = %v, want %v", got, want)
+	}
+}
+
+func TestChartFlex(t *testing.T) {
+	data := []float64{1, 2, 3}
+	tmpl := Build(VBox(
+		Text("title"),
+		HBox(LineChart(&data), BarChart(&data)),
+		LineChart(&data).Grow(1),
+	))
+	buf := NewBuffer(40, 30)
+	frame(tmpl, buf)
+
+	row, grow := tmpl.geom[2], tmpl.geom[5]
+	if row.H != chartDefaultHeight {
+		t.Errorf("row of charts H = %d, want %d", row.H, chartDefaultHeight)
+	}
+	if left, right := tmpl.geom[3], tmpl.geom[4]; left.W != 20 || right.W != 20 {
+		t.Errorf("charts in a row W = %d and %d, want 20 each", left.W, right.W)
+	}
+	if want := int16(30 - 1 - chartDefaultHeight); grow.H != want {
+		t.Errorf("growing chart H = %d, want %d", grow.H, want)
+	}
+}
+
+func TestNiceStepAndFormat(t *testing.T) {
+	var c opChart
+	tests := []struct {
+		span  float64
+		count int
+		step  float64
+		label string
+	}{
+		{10, 5, 2, "2"},
+		{1, 4, 0.2, "0.2"},
+		{8, 3, 2, "2"},
+		{5000, 5, 1000, "1k"},
+		{3e6, 3, 1e6, "1M"},
+	}
+	for _, tt := range tests {
+		step := niceStep(tt.span, tt.count)
+		if step != tt.step {
+			t.Errorf("niceStep(%v, %d) = %v, want %v", tt.span, tt.count, step, tt.step)
+		}
+		if got := c.formatValue(ChartAxis{}, step, step); got != tt.label {
+			t.Errorf("formatValue(%v) = %q, want %q", step, got, tt.label)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package glyph
+
+import (
+	"bytes"
+	"io"
+	"time"
+)
+
+// defaultFPS is the frame clock's rate unless MaxFPS sets another.
+const defaultFPS = 60
+
+// MaxFPS caps how often the App renders on its own for what moves with
+// time: tweens, Animated effects and spinners that animate themselves.
+// The default is 60. Renders asked for with RequestRender aren't capped.
+func (a *App) MaxFPS(fps int) *App {
+	a.renderMu.Lock()
+	a.fps = fps
+	a.renderMu.Unlock()
+	return a
+}
+
+// frameInterval returns how often the frame just drawn wants drawing
+// again, or 0 when nothing in it moves by itself, no route transition is
+// playing, or the terminal doesn't have focus. Called under renderMu.
+func (a *App) frameInterval(tmpl *Template, effects []Effect) time.Duration {
+	if a.blurred.Load() {
+		return 0
+	}
+	every := tmpl.tickEvery
+	if tmpl.Animating() || anyAnimated(effects) || anyAnimated(a.postProcess) || !a.navStart.IsZero() {
+		every = everyFrame
+	}
+	if every == 0 {
+		return 0
+	}
+	fps := a.fps
+	if fps <= 0 {
+		fps = defaultFPS
+	}
+	return max(every, time.Second/time.Duration(fps))
+}
+
+// runClock requests a render every interval, replacing the clock running
+// at another; 0 stops it. Called under renderMu.
+func (a *App) runClock(every time.Duration) {
+	if every == a.clockEvery {
+		return
+	}
+	if a.clockStop != nil {
+		close(a.clockStop)
+		a.clockStop = nil
+	}
+	a.clockEvery = every
+	if every == 0 {
+		return
+	}
+	stop, done := make(chan struct{}), a.done
+	a.clockStop = stop
+	go func() {
+		tick := time.NewTicker(every)
+		defer tick.Stop()
+		for {
+			select {
+			case <-tick.C:
+				a.RequestRender()
+			case <-stop:
+				return
+			case <-done:
+				return
+			}
+		}
+	}()
+}
+
+// stopClock stops the frame clock when the app stops.
+func (a *App) stopClock() {
+	a.renderMu.Lock()
+	a.runClock(0)
+	a.renderMu.Unlock()
+}
+
+// focusInput takes the terminal's focus reports, CSI I and CSI O, out of
+// the input and passes them to the app.
+type focusInput struct {
+	r   io.Reader
+	app *App
+}
+
+func (in focusInput) Read(p []byte) (int, error) {
+	for {
+		n, err := in.r.Read(p)
+		if n > 0 && bytes.IndexByte(p[:n], 0x1b) >= 0 {
+			n = in.app.takeFocusReports(p[:n])
+		}
+		// a read of nothing but reports isn't handed on empty
+		if n > 0 || err != nil {
+			return n, err
+		}
+	}
+}
+
+// takeFocusReports removes focus reports from p, returning its new length.
+// Losing focus pauses the frame clock; getting it back renders, which
+// starts the clock again.
+func (a *App) takeFocusReports(p []byte) int {
+	n := 0
+	for i := 0; i < len(p); i++ {
+		if p[i] == 0x1b && i+2 < len(p) && p[i+1] == '[' && (p[i+2] == 'I' || p[i+2] == 'O') {
+			focused := p[i+2] == 'I'
+			a.blurred.Store(!focused)
+			if focused {
+				a.RequestRender()
+			}
+			i += 2
+			continue
+		}
+		p[n] = p[i]
+		n++
+	}
+	return n
+}
@@ -0,0 +1,117 @@
+package glyph
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFrameInterval(t *testing.T) {
+	busy := true
+	tmpl := Build(VBox(If(&busy).Then(Spinner(nil).Interval(100*time.Millisecond)), Text("x")))
+	a := &App{}
+	buf := NewBuffer(10, 2)
+
+	tmpl.Execute(buf, 10, 2)
+	if got := a.frameInterval(tmpl, nil); got != 100*time.Millisecond {
+		t.Errorf("with a spinner = %v, want 100ms", got)
+	}
+	if got := a.frameInterval(tmpl, []Effect{SEPulse()}); got != time.Second/defaultFPS {
+		t.Errorf("with a pulse = %v, want %v", got, time.Second/defaultFPS)
+	}
+	a.MaxFPS(5)
+	if got := a.frameInterval(tmpl, []Effect{WithBlend(BlendMultiply, SEPulse())}); got != 200*time.Millisecond {
+		t.Errorf("capped at 5fps = %v, want 200ms", got)
+	}
+
+	busy = false
+	tmpl.Execute(buf, 10, 2)
+	if got := a.frameInterval(tmpl, []Effect{SETint(Red)}); got != 0 {
+		t.Errorf("with nothing moving = %v, want 0", got)
+	}
+	if got := a.frameInterval(tmpl, []Effect{Animated(EachCell(nil))}); got == 0 {
+		t.Error("Animated effect didn't start the clock")
+	}
+
+	// node effects count only while drawn
+	tmpl = Build(VBox(If(&busy).Then(VBox.Effect(SEScreenShake(1))(Text("shake")))))
+	tmpl.Execute(buf, 10, 2)
+	if got := a.frameInterval(tmpl, nil); got != 0 {
+		t.Errorf("hidden node effect = %v, want 0", got)
+	}
+	busy = true
+	tmpl.Execute(buf, 10, 2)
+	if got := a.frameInterval(tmpl, nil); got == 0 {
+		t.Error("shown node effect didn't start the clock")
+	}
+}
+
+func TestTakeFocusReports(t *testing.T) {
+	a := &App{renderChan: make(chan struct{}, 1)}
+	p := []byte("j\x1b[Ok\x1b[A")
+	if got := string(p[:a.takeFocusReports(p)]); got != "jk\x1b[A" {
+		t.Errorf("input = %q", got)
+	}
+	if !a.blurred.Load() {
+		t.Error("focus out not seen")
+	}
+	p = []byte("\x1b[I")
+	if n := a.takeFocusReports(p); n != 0 || a.blurred.Load() {
+		t.Errorf("focus in: n = %d, blurred = %v", n, a.blurred.Load())
+	}
+	if len(a.renderChan) != 1 {
+		t.Error("focus in didn't request a render")
+	}
+}
+
+func TestSpinnerAnimatesItself(t *testing.T) {
+	st := newStreamTerm(t, 10, 1)
+	st.app.SetView(HBox(Spinner(nil).Frames([]string{"a", "b"}).Interval(20*time.Millisecond), Text("!")))
+	errs := make(chan error, 1)
+	go func() { errs <- st.app.Run() }()
+
+	// frames come from the clock, with no keys or render requests
+	waitFor(t, "frame a", func() bool { return strings.HasPrefix(st.screen(), "a!") })
+	waitFor(t, "frame b", func() bool { return strings.HasPrefix(st.screen(), "b!") })
+
+	// losing focus stops the clock, and a key after the report still arrives
+	st.app.Handle("q", st.app.Stop)
+	st.keys.Write([]byte("\x1b[O"))
+	waitFor(t, "clock stopped", func() bool {
+		st.app.renderMu.Lock()
+		defer st.app.renderMu.Unlock()
+		return st.app.clockEvery == 0
+	})
+	st.keys.Write([]byte("\x1b[Iq"))
+	if err := <-errs; err != nil {
+		t.Errorf("Run = %v", err)
+	}
+}
+
+func TestRunNonInteractiveStopsClock(t *testing.T) {
+	st := newStreamTerm(t, 10, 1)
+	st.app.inline = true
+	st.app.SetView(HBox(Spinner(nil).Interval(5*time.Millisecond), Text("!")))
+	errs := make(chan error, 1)
+	go func() { errs <- st.app.RunNonInteractive() }()
+
+	waitFor(t, "clock running", func() bool {
+		st.app.renderMu.Lock()
+		defer st.app.renderMu.Unlock()
+		return st.app.clockEvery != 0
+	})
+	st.app.Stop()
+	if err := <-errs; err != nil {
+		t.Fatalf("RunNonInteractive = %v", err)
+	}
+
+	// with the app gone the clock asks for no more renders
+	select {
+	case <-st.app.renderChan:
+	default:
+	}
+	time.Sleep(30 * time.Millisecond)
+	if len(st.app.renderChan) != 0 {
+		t.Error("clock still running after Stop")
+	}
+}
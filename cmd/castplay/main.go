@@ -0,0 +1,45 @@
+// castplay: plays an asciicast recording, such as one from App.Record
+//
+// Usage: castplay session.cast
+// Space=pause, ←/→=seek 5s, +/-=speed, Home/End=start/end, q=quit
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	. "github.com/kungfusheep/glyph"
+	"github.com/kungfusheep/glyph/replay"
+)
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Fprintln(os.Stderr, "usage: castplay file.cast")
+		os.Exit(2)
+	}
+	cast, err := replay.Load(os.Args[1])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	app, err := NewApp()
+	if err != nil {
+		log.Fatal(err)
+	}
+	player := replay.NewPlayer(cast).Bind(app)
+	app.Handle("q", app.Stop)
+
+	app.SetView(VBox(
+		LayerView(player.Layer()).Grow(1),
+		HBox.Gap(2)(
+			Text(player.Status).Bold(),
+			Text("space pause  ←/→ seek  +/- speed  q quit").FG(BrightBlack),
+		),
+	))
+
+	player.Play()
+	if err := app.Run(); err != nil {
+		log.Fatal(err)
+	}
+}
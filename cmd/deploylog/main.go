@@ -10,7 +10,6 @@ import (
 
 func main() {
 	var (
-		frame  int
 		status = "deploying..."
 		pct    int
 		done   bool
@@ -24,7 +23,7 @@ func main() {
 	app.SetView(
 		VBox.FitContent()(
 			HBox.Gap(2)(
-				Spinner(&frame).FG(Cyan),
+				Spinner(nil).FG(Cyan),
 				Text(&status).Bold(),
 				Progress(&pct).Width(20),
 			),
@@ -59,14 +58,6 @@ func main() {
 		app.RequestRender()
 	}()
 
-	// animate spinner
-	go func() {
-		for range time.NewTicker(80 * time.Millisecond).C {
-			frame++
-			app.RequestRender()
-		}
-	}()
-
 	app.Handle("q", app.Stop)
 	app.Run()
 }
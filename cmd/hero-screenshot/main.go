@@ -1,12 +1,21 @@
+// Prints the README hero frame for VHS to capture, or with -o writes it
+// straight to an .svg, .png or .html file.
 package main
 
 import (
+	"flag"
 	"fmt"
+	"log"
+	"os"
+	"path/filepath"
 
 	. "github.com/kungfusheep/glyph"
 )
 
 func main() {
+	out := flag.String("o", "", "write the frame to an .svg, .png or .html file")
+	flag.Parse()
+
 	cpu, mem := 72, 48
 	online := true
 	history := []float64{3, 5, 2, 7, 4, 6, 3, 5, 8, 4}
@@ -50,6 +59,13 @@ func main() {
 	buf := NewBuffer(w, h)
 	tmpl.Execute(buf, int16(w), int16(h))
 
+	if *out != "" {
+		if err := export(buf, *out); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	// cursor home + clear screen so VHS captures from top-left
 	fmt.Print("\033[H\033[2J")
 
@@ -68,3 +84,25 @@ func main() {
 		fmt.Println(buf.GetLineStyled(y))
 	}
 }
+
+func export(buf *Buffer, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	opts := ExportOptions{Window: true, Title: "glyph", Padding: 16}
+	switch filepath.Ext(path) {
+	case ".svg":
+		err = buf.WriteSVG(f, opts)
+	case ".png":
+		err = buf.WritePNG(f, ScaledFont(FixedFont, 2), opts)
+	case ".html":
+		err = buf.WriteHTML(f, opts)
+	default:
+		err = fmt.Errorf("unknown format %q", filepath.Ext(path))
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
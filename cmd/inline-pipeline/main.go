@@ -9,59 +9,49 @@ import (
 	. "github.com/kungfusheep/glyph"
 )
 
-type step struct {
-	icon   string
-	name   string
-	status string
-}
-
-func (s *step) run() {
-	s.icon = "›"
-	s.status = "running"
-}
-
-func (s *step) done() {
-	s.icon = "✓"
-	s.status = "done"
-}
-
 func main() {
-	steps := []step{
-		{"○", "Build", "pending"},
-		{"○", "Test", "pending"},
-		{"○", "Push image", "pending"},
-		{"○", "Deploy", "pending"},
-		{"○", "Health check", "pending"},
-	}
+	steps := []string{"Build", "Test", "Push image", "Deploy", "Health check"}
+
+	current := ""
+	status := "starting"
+	pct := 0
 
 	app, err := NewInlineApp()
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	// finished steps scroll up above; the running one stays pinned below
 	app.SetView(VBox(
-		ForEach(&steps, func(s *step) any {
-			return HBox.Gap(1)(
-				Text(&s.icon).Width(1).Bold(),
-				Text(&s.name).Width(15),
-				Text(&s.status).FG(BrightBlack),
-			)
-		}),
+		HBox.Gap(1)(
+			Text("›").Width(1).Bold(),
+			Text(&current).Width(15),
+			Text(&status).FG(BrightBlack),
+		),
+		Progress(&pct).Width(30).FG(Cyan),
 	))
 
 	go func() {
-		for i := range steps {
-			steps[i].run()
+		for i, name := range steps {
+			current, status = name, "running"
 			app.RequestRender()
+
+			start := time.Now()
 			time.Sleep(400 + time.Duration(i*200)*time.Millisecond)
-			steps[i].done()
-			app.RequestRender()
+
+			pct = (i + 1) * 100 / len(steps)
+			app.Println(
+				Span{Text: "✓", Style: Style{FG: Green, Attr: AttrBold}},
+				Span{Text: fmt.Sprintf("%-15s", name)},
+				Span{Text: time.Since(start).Round(100 * time.Millisecond).String(), Style: Style{FG: BrightBlack}},
+			)
 		}
+		current, status = "", "done"
 		time.Sleep(300 * time.Millisecond)
 		app.Stop()
 	}()
 
-	if err := app.RunNonInteractive(); err != nil {
+	if err := app.ClearOnExit(true).RunNonInteractive(); err != nil {
 		log.Fatal(err)
 	}
 	fmt.Println("Deployed to production!")
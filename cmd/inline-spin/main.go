@@ -10,7 +10,6 @@ import (
 )
 
 func main() {
-	frame := 0
 	status := "Installing dependencies..."
 	done := false
 
@@ -22,7 +21,7 @@ func main() {
 	app.SetView(HBox(
 		If(&done).
 			Then(Text("✓ ").FG(Green).Bold()).
-			Else(Spinner(&frame).FG(Cyan)),
+			Else(Spinner(nil).FG(Cyan)),
 		Text(&status),
 	))
 
@@ -33,12 +32,9 @@ func main() {
 			"Linking binaries...",
 			"Done!",
 		}
-		for i, s := range steps {
+		for _, s := range steps {
 			time.Sleep(800 * time.Millisecond)
 			status = s
-			if i < len(steps)-1 {
-				frame++
-			}
 			app.RequestRender()
 		}
 		done = true
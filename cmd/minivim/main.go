@@ -1,6 +1,7 @@
 // minivim: A tiny vim-like editor demonstrating riffkey TextInput with glyph framework
 //
 // Normal mode: j/k=move, i=insert, a=append, o=new line, dd=delete line, q=quit
+// :sh or :!cmd hands the terminal to a shell; Ctrl-Z suspends
 // Insert mode: Type text, Esc=back to normal, all standard editing keys work
 package main
 
@@ -4799,7 +4800,16 @@ func (ed *Editor) executeColonCommand(app *glyph.App, cmd string) {
 		ed.refreshGitSigns()
 		ed.invalidateRenderedRange()
 		ed.StatusLine = "Git signs refreshed"
+	case "sh", "shell":
+		ed.runExternal(app, exec.Command(userShell()))
 	default:
+		if shellCmd, ok := strings.CutPrefix(cmd, "!"); ok {
+			// like vim, wait so the output can be read
+			script := shellCmd + `; printf '\nPress ENTER to continue'; read _`
+			ed.runExternal(app, exec.Command(userShell(), "-c", script))
+			return
+		}
+
 		// Try to parse as line number first
 		lineNum := 0
 		isNumber := len(cmd) > 0
@@ -4831,6 +4841,23 @@ func (ed *Editor) executeColonCommand(app *glyph.App, cmd string) {
 	}
 }
 
+// runExternal hands the terminal to cmd until it exits, for :! and :sh.
+func (ed *Editor) runExternal(app *glyph.App, cmd *exec.Cmd) {
+	if err := app.Exec(cmd); err != nil {
+		ed.StatusLine = fmt.Sprintf("shell returned: %v", err)
+		return
+	}
+	ed.StatusLine = ""
+}
+
+// userShell returns $SHELL, or sh.
+func userShell() string {
+	if sh := os.Getenv("SHELL"); sh != "" {
+		return sh
+	}
+	return "sh"
+}
+
 func (ed *Editor) executeSearch(pattern string, direction int) {
 	if pattern == "" {
 		// Use last search pattern
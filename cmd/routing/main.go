@@ -1,30 +1,31 @@
 package main
 
 import (
+	"fmt"
 	"log"
+	"time"
 
-	"github.com/kungfusheep/riffkey"
 	. "github.com/kungfusheep/glyph"
 )
 
 // Home view state
 var home = struct {
-	Title   string
 	Counter int
-}{
-	Title:   "Home Screen",
-	Counter: 0,
-}
+}{}
 
 // Settings view state
 var settings = struct {
-	Title  string
 	Volume int
 }{
-	Title:  "Settings",
 	Volume: 50,
 }
 
+// Item view state, loaded from the route params
+var item = struct {
+	Name   string
+	Detail string
+}{}
+
 // Help modal state
 var help = struct {
 	Title string
@@ -41,62 +42,48 @@ func main() {
 	}
 
 	// Global handler (works on all views)
-	app.Handle("q", func(_ riffkey.Match) {
-		app.Stop()
-	})
-
-	// Home view
-	app.View("home", homeView()).
-		Handle("j", func(_ riffkey.Match) {
-			home.Counter++
-		}).
-		Handle("k", func(_ riffkey.Match) {
-			home.Counter--
-		}).
-		Handle("s", func(_ riffkey.Match) {
-			app.Go("settings")
-		}).
-		Handle("?", func(_ riffkey.Match) {
-			app.PushView("help")
-		})
-
-	// Settings view
-	app.View("settings", settingsView()).
-		Handle("j", func(_ riffkey.Match) {
-			if settings.Volume > 0 {
-				settings.Volume--
-			}
-		}).
-		Handle("k", func(_ riffkey.Match) {
-			if settings.Volume < 100 {
-				settings.Volume++
-			}
-		}).
-		Handle("<Esc>", func(_ riffkey.Match) {
-			app.Go("home")
+	app.Handle("q", app.Stop)
+
+	app.Route("/", homeView(app)).Title("Home").
+		Handle("j", func() { home.Counter++ }).
+		Handle("k", func() { home.Counter-- }).
+		Handle("s", func() { app.Navigate("/settings") }).
+		Handle("i", func() { app.Navigate(fmt.Sprintf("/items/%d", home.Counter)) }).
+		Handle("?", func() { app.PushView("help") })
+
+	app.Route("/settings", settingsView(app)).Title("Settings").
+		Handle("j", func() { settings.Volume = max(settings.Volume-1, 0) }).
+		Handle("k", func() { settings.Volume = min(settings.Volume+1, 100) }).
+		Handle("<Esc>", app.Back).
+		Handle("<C-o>", app.Back).
+		Handle("<Tab>", func() { app.Forward() })
+
+	app.Route("/items/:id<int>", itemView(app)).
+		TitleFunc(func(p Params) string { return "Item " + p.Get("id") }).
+		OnEnter(func(p Params) {
+			item.Name = "Item " + p.Get("id")
+			item.Detail = fmt.Sprintf("loaded at %s", time.Now().Format(time.TimeOnly))
 		}).
-		Handle("?", func(_ riffkey.Match) {
-			app.PushView("help")
-		})
+		Handle("<Esc>", app.Back)
 
 	// Help modal
 	app.View("help", helpView()).
-		Handle("<Esc>", func(_ riffkey.Match) {
-			app.PopView()
-		})
+		Handle("<Esc>", app.PopView)
+
+	app.NavTransition(func(p *float64) Effect { return SEDissolve(p) }, 200*time.Millisecond)
 
-	// Start on home
-	if err := app.RunFrom("home"); err != nil {
+	if err := app.RunFrom("/"); err != nil {
 		log.Fatal(err)
 	}
 }
 
-func homeView() any {
+func homeView(app *App) any {
 	return VBox(
-		Text(&home.Title).Bold(),
+		Breadcrumbs(app),
 		Text(""),
 		Text("j/k: change counter"),
 		Text("s: go to settings"),
+		Text("i: open item <counter>"),
 		Text("?: help"),
 		Text("q: quit"),
 		Text(""),
@@ -104,19 +91,29 @@ func homeView() any {
 	)
 }
 
-func settingsView() any {
+func settingsView(app *App) any {
 	return VBox(
-		Text(&settings.Title).Bold(),
+		Breadcrumbs(app),
 		Text(""),
 		Text("j/k: adjust volume"),
-		Text("Esc: back to home"),
-		Text("?: help"),
+		Text("Esc: back"),
 		Text(""),
 		Text("Volume:"),
 		Progress(&settings.Volume).Width(30),
 	)
 }
 
+func itemView(app *App) any {
+	return VBox(
+		Breadcrumbs(app),
+		Text(""),
+		Text(&item.Name).Bold(),
+		Text(&item.Detail).Dim(),
+		Text(""),
+		Text("Esc: back"),
+	)
+}
+
 func helpView() any {
 	return VBox(
 		Text(&help.Title).Bold(),
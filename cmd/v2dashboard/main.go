@@ -510,10 +510,10 @@ func main() {
 			state.Sleeping = fmt.Sprintf("Sleeping: %3d", sleeping)
 
 			// Update timing stats
-			state.Timing = TimingString()
+			state.Timing = app.TimingString()
 
 			// Capture timing history for graphs
-			timings := GetTimings()
+			timings := app.Timings()
 			// Scale: 100 = 1000µs (1ms), so divide by 10 to get percentage
 			copy(state.RenderHistory, state.RenderHistory[1:])
 			copy(state.FlushHistory, state.FlushHistory[1:])
@@ -525,7 +525,7 @@ func main() {
 			state.FlushLabel = fmt.Sprintf("Flush:  %5.0fµs", timings.FlushUs)
 
 			// Get row stats from flush
-			flushStats := GetFlushStats()
+			flushStats := app.FlushStats()
 			state.RowStats = fmt.Sprintf("Rows: %d dirty, %d changed", flushStats.DirtyRows, flushStats.ChangedRows)
 
 			// Track actual FPS
@@ -2,6 +2,7 @@ package glyph
 
 import (
 	"reflect"
+	"time"
 )
 
 // binding represents a declared key binding on a component.
@@ -9,6 +10,9 @@ import (
 type binding struct {
 	pattern string
 	handler any
+	name    string // action name for keymaps, e.g. "list.down"
+	desc    string // shown in help and key hints
+	group   string
 }
 
 // textInputBinding represents an InputC that wants unmatched keys routed to it.
@@ -84,6 +88,7 @@ type VBoxC struct {
 	localStylePtr    *Style
 	localStyleCond   any
 	opacity          dynFloat64
+	effects          []Effect
 	children        []any
 }
 
@@ -340,6 +345,17 @@ func (f VBoxFn) NodeRef(ref *NodeRef) VBoxFn {
 	}
 }
 
+// Effect runs post-processing passes over this container once it has
+// drawn, clipped to its bounds. Each pass gets the container's cells as
+// its buffer, with PostContext.Bounds saying where they are on screen.
+func (f VBoxFn) Effect(e ...Effect) VBoxFn {
+	return func(children ...any) VBoxC {
+		v := f(children...)
+		v.effects = append(v.effects, e...)
+		return v
+	}
+}
+
 // VBox arranges children in a vertical stack.
 // Use method chaining to configure before calling with children:
 //
@@ -383,6 +399,7 @@ type HBoxC struct {
 	localStylePtr    *Style
 	localStyleCond   any
 	opacity          dynFloat64
+	effects          []Effect
 	children        []any
 }
 
@@ -639,6 +656,17 @@ func (f HBoxFn) NodeRef(ref *NodeRef) HBoxFn {
 	}
 }
 
+// Effect runs post-processing passes over this container once it has
+// drawn, clipped to its bounds. Each pass gets the container's cells as
+// its buffer, with PostContext.Bounds saying where they are on screen.
+func (f HBoxFn) Effect(e ...Effect) HBoxFn {
+	return func(children ...any) HBoxC {
+		h := f(children...)
+		h.effects = append(h.effects, e...)
+		return h
+	}
+}
+
 // HBox arranges children in a horizontal row.
 // Use method chaining to configure before calling with children:
 //
@@ -1269,18 +1297,28 @@ func (p ProgressC) MarginTRBL(a, b, c, d int16) ProgressC {
 type SpinnerC struct {
 	frame    *int
 	frames   []string
+	interval time.Duration
 	style    Style
 	styleDyn any
 	fgDyn    any
 	bgDyn    any
 }
 
+// spinInterval is how long a spinner that animates itself shows each frame.
+const spinInterval = 80 * time.Millisecond
+
 // Spinner creates an animated spinner bound to a frame counter.
-// Increment *frame and re-render to advance the animation.
+// Increment *frame and re-render to advance the animation. With a nil
+// frame the spinner animates itself, and the App renders frames for it
+// while it is in view.
 func Spinner(frame *int) SpinnerC {
 	return SpinnerC{frame: frame, frames: SpinnerBraille}
 }
 
+// Interval sets how long each frame shows when the spinner animates itself
+// (default 80ms).
+func (s SpinnerC) Interval(d time.Duration) SpinnerC { s.interval = d; return s }
+
 // Frames sets the animation frames.
 func (s SpinnerC) Frames(f []string) SpinnerC {
 	s.frames = f
@@ -1609,6 +1647,156 @@ func (s SparklineC) MarginTRBL(a, b, c, d int16) SparklineC {
 	return s
 }
 
+// ============================================================================
+// Canvas - Sub-cell drawing surface
+// ============================================================================
+
+type CanvasC struct {
+	draw       func(c *CanvasContext)
+	mode       CanvasMode
+	watches    []any
+	width      int16
+	height     int16
+	style      Style
+	widthPtr   *int16
+	heightPtr  *int16
+	widthCond  any
+	heightCond any
+	styleDyn   any
+	fgDyn      any
+	bgDyn      any
+}
+
+// Canvas creates a w×h cell drawing surface with braille dots for pixels,
+// 2×4 to a cell. Width 0 fills the available space. draw runs each frame
+// with a cleared grid, reading whatever the closure points at.
+// Accepts int16, int, *int16, conditionNode, or tweenNode for w and h.
+//
+//	Canvas(40, 10, func(c *CanvasContext) {
+//	    w, h := c.Size()
+//	    c.SetColor(Cyan)
+//	    c.Line(0, h-1, w-1, 0)
+//	    c.Circle(w/2, h/2, 8)
+//	    c.Text(0, 0, "peak")
+//	})
+func Canvas(w, h any, draw func(c *CanvasContext)) CanvasC {
+	return CanvasC{draw: draw}.Width(w).Height(h)
+}
+
+// Width sets the width in cells. Accepts int16, int, or *int16 for dynamic values.
+func (c CanvasC) Width(w any) CanvasC {
+	switch val := w.(type) {
+	case int16:
+		c.width = val
+	case int:
+		c.width = int16(val)
+	case *int16:
+		c.widthPtr = val
+	case conditionNode:
+		c.widthCond = val
+	case tweenNode:
+		c.widthCond = val
+	}
+	return c
+}
+
+// Height sets the height in cells. Default is 1.
+// Accepts int16, int, or *int16 for dynamic values.
+func (c CanvasC) Height(h any) CanvasC {
+	switch val := h.(type) {
+	case int16:
+		c.height = val
+	case int:
+		c.height = int16(val)
+	case *int16:
+		c.heightPtr = val
+	case conditionNode:
+		c.heightCond = val
+	case tweenNode:
+		c.heightCond = val
+	}
+	return c
+}
+
+// Mode sets how cells divide into pixels: CanvasBraille (default),
+// CanvasQuadrant or CanvasHalfBlock.
+func (c CanvasC) Mode(m CanvasMode) CanvasC {
+	c.mode = m
+	return c
+}
+
+// Watch makes the canvas retained: the grid is kept between frames and
+// draw runs again only when one of ptrs points at a changed value, or the
+// canvas is resized. Values are compared through slices, maps and struct
+// fields; changes behind pointers inside them aren't noticed.
+//
+//	Canvas(0, 8, plot).Watch(&samples, &zoom)
+func (c CanvasC) Watch(ptrs ...any) CanvasC {
+	c.watches = append(c.watches, ptrs...)
+	return c
+}
+
+// Style sets the component style. Accepts Style, *Style, conditionNode, or tweenNode.
+func (c CanvasC) Style(st any) CanvasC {
+	switch val := st.(type) {
+	case Style:
+		c.style = val
+	case *Style:
+		c.styleDyn = val
+	case conditionNode:
+		c.styleDyn = val
+	case tweenNode:
+		c.styleDyn = val
+	}
+	return c
+}
+
+// FG sets the colour for pixels drawn without SetColor.
+// Accepts Color, *Color, conditionNode, or tweenNode.
+func (c CanvasC) FG(col any) CanvasC {
+	switch val := col.(type) {
+	case Color:
+		c.style.FG = val
+	case *Color:
+		c.fgDyn = val
+	case conditionNode:
+		c.fgDyn = val
+	case tweenNode:
+		c.fgDyn = val
+	}
+	return c
+}
+
+// BG sets the background color. Accepts Color, *Color, conditionNode, or tweenNode.
+func (c CanvasC) BG(col any) CanvasC {
+	switch val := col.(type) {
+	case Color:
+		c.style.BG = val
+	case *Color:
+		c.bgDyn = val
+	case conditionNode:
+		c.bgDyn = val
+	case tweenNode:
+		c.bgDyn = val
+	}
+	return c
+}
+
+// Margin sets uniform margin on all sides.
+func (c CanvasC) Margin(all int16) CanvasC {
+	c.style.margin = [4]int16{all, all, all, all}
+	return c
+}
+
+// MarginVH sets vertical and horizontal margin.
+func (c CanvasC) MarginVH(v, h int16) CanvasC { c.style.margin = [4]int16{v, h, v, h}; return c }
+
+// MarginTRBL sets individual margins for top, right, bottom, left.
+func (c CanvasC) MarginTRBL(a, b, d, e int16) CanvasC {
+	c.style.margin = [4]int16{a, b, d, e}
+	return c
+}
+
 // ============================================================================
 // Jump - Jumpable target wrapper
 // ============================================================================
@@ -1653,6 +1841,9 @@ type LayerViewC struct {
 	margin       [4]int16
 	flexGrowPtr  *float32
 	flexGrowCond any
+	effects      []Effect
+
+	declaredBindings []binding
 }
 
 // LayerView displays a scrollable, pre-rendered layer within the view tree.
@@ -1704,6 +1895,23 @@ func (l LayerViewC) MarginTRBL(a, b, c, d int16) LayerViewC {
 	return l
 }
 
+// Effect runs post-processing passes over the layer's viewport once it has
+// drawn, as VBox.Effect does for a container.
+func (l LayerViewC) Effect(e ...Effect) LayerViewC {
+	l.effects = append(l.effects[:len(l.effects):len(l.effects)], e...)
+	return l
+}
+
+// BindSearch registers key to open an in-place search prompt over the layer,
+// with n/N to move between matches. Configure via Layer.Search.
+func (l LayerViewC) BindSearch(key string) LayerViewC {
+	l.declaredBindings = append(l.declaredBindings[:len(l.declaredBindings):len(l.declaredBindings)],
+		l.layer.Search().bindings(key, nil)...)
+	return l
+}
+
+func (l LayerViewC) bindings() []binding { return l.declaredBindings }
+
 // ============================================================================
 // Overlay - Modal/popup overlay
 // ============================================================================
@@ -1791,6 +1999,7 @@ var Overlay OverlayFn = func(children ...any) OverlayC {
 type ForEachC[T any] struct {
 	items    *[]T
 	template func(item *T) any
+	trans    *transition
 }
 
 // ForEach renders a template for each item in a slice.
@@ -1804,9 +2013,25 @@ func ForEach[T any](items *[]T, template func(item *T) any) ForEachC[T] {
 	return ForEachC[T]{items: items, template: template}
 }
 
+// Transition animates items in as they're added and out as they're removed,
+// rather than the list jumping. ease may be nil for linear. Items are told
+// apart by value, so an edit that also changes how many there are can
+// show as the old value leaving and the new one arriving. Ignored on a
+// ForEach inside another's items.
+//
+//	ForEach(&todos, row).Transition(Expand, 150*time.Millisecond, EaseOutCubic)
+func (f ForEachC[T]) Transition(kind TransitionKind, d time.Duration, ease func(float64) float64) ForEachC[T] {
+	f.trans = &transition{kind: kind, duration: d, ease: ease}
+	return f
+}
+
 // compileTo implements forEachCompiler for template compilation
-func (f ForEachC[T]) compileTo(t *Template, parent int16, depth int) int16 {
-	return t.compileForEach(f.items, f.template, parent, depth)
+func (f ForEachC[T]) compileTo(t *Template, parent int16, depth int, nested bool) int16 {
+	trans := f.trans
+	if nested {
+		trans = nil // one template serves every outer item, so rows can't be tracked
+	}
+	return t.compileForEach(f.items, f.template, trans, parent, depth)
 }
 
 // ============================================================================
@@ -2016,8 +2241,8 @@ func (l *ListC[T]) Last(m any) { l.toSelectionList().Last(m) }
 // BindNav registers key bindings for moving selection down and up.
 func (l *ListC[T]) BindNav(down, up string) *ListC[T] {
 	l.declaredBindings = append(l.declaredBindings,
-		binding{pattern: down, handler: l.Down},
-		binding{pattern: up, handler: l.Up},
+		binding{pattern: down, handler: l.Down, name: "list.down", desc: "down", group: "List"},
+		binding{pattern: up, handler: l.Up, name: "list.up", desc: "up", group: "List"},
 	)
 	return l
 }
@@ -2025,8 +2250,8 @@ func (l *ListC[T]) BindNav(down, up string) *ListC[T] {
 // BindPageNav registers key bindings for page-sized movement.
 func (l *ListC[T]) BindPageNav(pageDown, pageUp string) *ListC[T] {
 	l.declaredBindings = append(l.declaredBindings,
-		binding{pattern: pageDown, handler: l.PageDown},
-		binding{pattern: pageUp, handler: l.PageUp},
+		binding{pattern: pageDown, handler: l.PageDown, name: "list.page_down", desc: "page down", group: "List"},
+		binding{pattern: pageUp, handler: l.PageUp, name: "list.page_up", desc: "page up", group: "List"},
 	)
 	return l
 }
@@ -2034,8 +2259,8 @@ func (l *ListC[T]) BindPageNav(pageDown, pageUp string) *ListC[T] {
 // BindFirstLast registers key bindings for jumping to first/last item.
 func (l *ListC[T]) BindFirstLast(first, last string) *ListC[T] {
 	l.declaredBindings = append(l.declaredBindings,
-		binding{pattern: first, handler: l.First},
-		binding{pattern: last, handler: l.Last},
+		binding{pattern: first, handler: l.First, name: "list.first", desc: "first", group: "List"},
+		binding{pattern: last, handler: l.Last, name: "list.last", desc: "last", group: "List"},
 	)
 	return l
 }
@@ -2049,7 +2274,7 @@ func (l *ListC[T]) BindVimNav() *ListC[T] {
 // BindDelete registers a key binding to delete the selected item.
 func (l *ListC[T]) BindDelete(key string) *ListC[T] {
 	l.declaredBindings = append(l.declaredBindings,
-		binding{pattern: key, handler: l.Delete},
+		binding{pattern: key, handler: l.Delete, name: "list.delete", desc: "delete", group: "List"},
 	)
 	return l
 }
@@ -2075,6 +2300,7 @@ func (l *ListC[T]) bindings() []binding { return l.declaredBindings }
 
 type TabsC struct {
 	labels        []string
+	labelsPtr     *[]string // set by Wizard, whose steps come and go
 	selected      *int
 	tabStyle      TabsStyle
 	gap           int8
@@ -2293,7 +2519,7 @@ func (c *CheckboxC) MarginTRBL(t, r, b, l int16) *CheckboxC {
 // BindToggle registers a key binding to toggle the checked state.
 func (c *CheckboxC) BindToggle(key string) *CheckboxC {
 	c.declaredBindings = append(c.declaredBindings,
-		binding{pattern: key, handler: c.Toggle},
+		binding{pattern: key, handler: c.Toggle, name: "checkbox.toggle", desc: "toggle", group: "Checkbox"},
 	)
 	return c
 }
@@ -2460,8 +2686,8 @@ func (r *RadioC) Horizontal() *RadioC {
 // BindNav registers key bindings for cycling selection.
 func (r *RadioC) BindNav(next, prev string) *RadioC {
 	r.declaredBindings = append(r.declaredBindings,
-		binding{pattern: next, handler: func() { r.Next() }},
-		binding{pattern: prev, handler: func() { r.Prev() }},
+		binding{pattern: next, handler: func() { r.Next() }, name: "radio.next", desc: "next option", group: "Radio"},
+		binding{pattern: prev, handler: func() { r.Prev() }, name: "radio.prev", desc: "previous option", group: "Radio"},
 	)
 	return r
 }
@@ -2638,8 +2864,8 @@ func (c *CheckListC[T]) Gap(g any) *CheckListC[T] {
 // BindNav registers key bindings for moving selection down and up.
 func (c *CheckListC[T]) BindNav(down, up string) *CheckListC[T] {
 	c.declaredBindings = append(c.declaredBindings,
-		binding{pattern: down, handler: c.Down},
-		binding{pattern: up, handler: c.Up},
+		binding{pattern: down, handler: c.Down, name: "list.down", desc: "down", group: "List"},
+		binding{pattern: up, handler: c.Up, name: "list.up", desc: "up", group: "List"},
 	)
 	return c
 }
@@ -2647,8 +2873,8 @@ func (c *CheckListC[T]) BindNav(down, up string) *CheckListC[T] {
 // BindPageNav registers key bindings for page-sized movement.
 func (c *CheckListC[T]) BindPageNav(pageDown, pageUp string) *CheckListC[T] {
 	c.declaredBindings = append(c.declaredBindings,
-		binding{pattern: pageDown, handler: c.PageDown},
-		binding{pattern: pageUp, handler: c.PageUp},
+		binding{pattern: pageDown, handler: c.PageDown, name: "list.page_down", desc: "page down", group: "List"},
+		binding{pattern: pageUp, handler: c.PageUp, name: "list.page_up", desc: "page up", group: "List"},
 	)
 	return c
 }
@@ -2656,8 +2882,8 @@ func (c *CheckListC[T]) BindPageNav(pageDown, pageUp string) *CheckListC[T] {
 // BindFirstLast registers key bindings for jumping to first/last item.
 func (c *CheckListC[T]) BindFirstLast(first, last string) *CheckListC[T] {
 	c.declaredBindings = append(c.declaredBindings,
-		binding{pattern: first, handler: c.First},
-		binding{pattern: last, handler: c.Last},
+		binding{pattern: first, handler: c.First, name: "list.first", desc: "first", group: "List"},
+		binding{pattern: last, handler: c.Last, name: "list.last", desc: "last", group: "List"},
 	)
 	return c
 }
@@ -2678,7 +2904,7 @@ func (c *CheckListC[T]) BindToggle(key string) *CheckListC[T] {
 					*ptr = !*ptr
 				}
 			}
-		}},
+		}, name: "list.toggle", desc: "toggle", group: "List"},
 	)
 	return c
 }
@@ -2686,7 +2912,7 @@ func (c *CheckListC[T]) BindToggle(key string) *CheckListC[T] {
 // BindDelete registers a key binding to delete the selected item.
 func (c *CheckListC[T]) BindDelete(key string) *CheckListC[T] {
 	c.declaredBindings = append(c.declaredBindings,
-		binding{pattern: key, handler: c.Delete},
+		binding{pattern: key, handler: c.Delete, name: "list.delete", desc: "delete", group: "List"},
 	)
 	return c
 }
@@ -2843,6 +3069,8 @@ type InputC struct {
 	validator  StringValidator
 	validateOn ValidateOn
 	err        string
+	async      *asyncCheck
+	asyncOn    ValidateOn
 
 	// focus management
 	focused bool
@@ -2875,8 +3103,46 @@ func (i *InputC) Validate(fn StringValidator, when ...ValidateOn) *InputC {
 	return i
 }
 
+// ValidateAsync sets a validator that runs in the background, debounced
+// after typing. A spinner shows beside the input while it runs, and a newer
+// value cancels the check in flight. The synchronous validator, if any, must
+// pass first. If when is omitted, defaults to VOnChange|VOnBlur|VOnSubmit.
+func (i *InputC) ValidateAsync(fn AsyncValidator, when ...ValidateOn) *InputC {
+	if i.async == nil {
+		i.async = &asyncCheck{debounce: defaultDebounce}
+	}
+	i.async.fn = fn
+	if len(when) > 0 {
+		i.asyncOn = when[0]
+	} else {
+		i.asyncOn = VOnChange | VOnBlur | VOnSubmit
+	}
+	return i
+}
+
+// Debounce sets how long after the last keystroke an async check starts.
+// Defaults to 300ms.
+func (i *InputC) Debounce(d time.Duration) *InputC {
+	if i.async == nil {
+		i.async = &asyncCheck{}
+	}
+	i.async.debounce = d
+	return i
+}
+
+// Pending reports whether an async check is scheduled or running.
+func (i *InputC) Pending() bool {
+	return i.async != nil && i.async.pending()
+}
+
 // Err returns the current validation error message, or empty string if valid.
+// An async error is reported once its check for the current value finishes.
 func (i *InputC) Err() string {
+	if i.err == "" && i.async != nil && i.async.fn != nil {
+		if err, ok := i.async.result(i.field.Value); ok {
+			return err
+		}
+	}
 	return i.err
 }
 
@@ -2889,6 +3155,22 @@ func (i *InputC) runValidation() {
 			i.err = ""
 		}
 	}
+	if i.asyncOn&VOnSubmit != 0 {
+		i.checkAsync(0)
+	}
+}
+
+// checkAsync schedules an async check of the current value, or drops any
+// pending one when the synchronous validator rejects it.
+func (i *InputC) checkAsync(delay time.Duration) {
+	if i.async == nil || i.async.fn == nil {
+		return
+	}
+	if i.validator != nil && i.validator(i.field.Value) != nil {
+		i.async.reset()
+		return
+	}
+	i.async.schedule(i.field.Value, delay)
 }
 
 // Ref provides access to the component for external references.
@@ -2987,6 +3269,9 @@ func (i *InputC) setFocused(focused bool) {
 		if i.validateOn&VOnBlur != 0 {
 			i.runValidation()
 		}
+		if i.asyncOn&VOnBlur != 0 {
+			i.checkAsync(0)
+		}
 		if i.onBlur != nil {
 			i.onBlur()
 		}
@@ -3003,6 +3288,9 @@ func (i *InputC) handleChange(val string) {
 	if i.validateOn&VOnChange != 0 {
 		i.runValidation()
 	}
+	if i.asyncOn&VOnChange != 0 {
+		i.checkAsync(i.async.debounce)
+	}
 }
 
 // Focused returns whether this input currently has focus.
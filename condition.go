@@ -2,6 +2,7 @@ package glyph
 
 import (
 	"cmp"
+	"time"
 	"unsafe"
 )
 
@@ -116,6 +117,7 @@ type ConditionEval[T comparable] struct {
 	val    T
 	then   any
 	els    any
+	trans  *transition
 }
 
 // Then specifies what to render when true
@@ -130,6 +132,20 @@ func (e *ConditionEval[T]) Else(node any) *ConditionEval[T] {
 	return e
 }
 
+// Transition animates branches in and out when the condition changes,
+// rather than swapping them at once. ease may be nil for linear.
+//
+// Inside ForEach items the transition is ignored and branches swap at
+// once: the items share one compiled template, so they have no animation
+// state of their own. Give the ForEach a Transition to animate items as
+// they come and go.
+//
+//	If(&showHelp).Then(help).Transition(Expand, 150*time.Millisecond, EaseOutCubic)
+func (e *ConditionEval[T]) Transition(kind TransitionKind, d time.Duration, ease func(float64) float64) *ConditionEval[T] {
+	e.trans = &transition{kind: kind, duration: d, ease: ease}
+	return e
+}
+
 func (e *ConditionEval[T]) compare(v T) bool {
 	switch e.op {
 	case condOpEq:
@@ -146,6 +162,8 @@ func (e *ConditionEval[T]) evaluate() bool { return e.compare(*e.ptr) }
 func (e *ConditionEval[T]) getThen() any { return e.then }
 func (e *ConditionEval[T]) getElse() any { return e.els }
 
+func (e *ConditionEval[T]) getTransition() *transition { return e.trans }
+
 func (e *ConditionEval[T]) setOffset(offset uintptr) { e.offset = offset }
 func (e *ConditionEval[T]) getPtrAddr() uintptr      { return uintptr(unsafe.Pointer(e.ptr)) }
 
@@ -165,6 +183,7 @@ type OrdConditionEval[T cmp.Ordered] struct {
 	val    T
 	then   any
 	els    any
+	trans  *transition
 }
 
 // Then specifies what to render when true
@@ -179,6 +198,13 @@ func (e *OrdConditionEval[T]) Else(node any) *OrdConditionEval[T] {
 	return e
 }
 
+// Transition animates branches in and out when the condition changes.
+// See ConditionEval.Transition.
+func (e *OrdConditionEval[T]) Transition(kind TransitionKind, d time.Duration, ease func(float64) float64) *OrdConditionEval[T] {
+	e.trans = &transition{kind: kind, duration: d, ease: ease}
+	return e
+}
+
 func (e *OrdConditionEval[T]) compare(v T) bool {
 	switch e.op {
 	case condOpEq:
@@ -203,6 +229,8 @@ func (e *OrdConditionEval[T]) evaluate() bool { return e.compare(*e.ptr) }
 func (e *OrdConditionEval[T]) getThen() any { return e.then }
 func (e *OrdConditionEval[T]) getElse() any { return e.els }
 
+func (e *OrdConditionEval[T]) getTransition() *transition { return e.trans }
+
 func (e *OrdConditionEval[T]) setOffset(offset uintptr) { e.offset = offset }
 func (e *OrdConditionEval[T]) getPtrAddr() uintptr      { return uintptr(unsafe.Pointer(e.ptr)) }
 
@@ -227,6 +255,8 @@ type conditionNode interface {
 // ensure our types implement conditionNode
 var _ conditionNode = (*ConditionEval[int])(nil)
 var _ conditionNode = (*OrdConditionEval[int])(nil)
+var _ transitionNode = (*ConditionEval[int])(nil)
+var _ transitionNode = (*OrdConditionEval[int])(nil)
 
 // SwitchBuilder constructs a type-safe multi-way branch.
 // Use Switch(&ptr) to start, .Case() for branches, .Default() or .End() to finalise.
@@ -283,8 +313,22 @@ type SwitchNode[T comparable] struct {
 	offset uintptr // offset from ForEach element base; 0 = use ptr directly
 	cases  []switchCase[T]
 	def    any
+	trans  *transition
 }
 
+// Transition animates cases in and out when the value changes, rather than
+// swapping them at once. ease may be nil for linear. Ignored inside ForEach
+// items, as for ConditionEval.Transition.
+//
+//	Switch(&tab).Case("home", home).Case("logs", logs).End().
+//	    Transition(SlideLeft, 200*time.Millisecond, EaseOutCubic)
+func (s *SwitchNode[T]) Transition(kind TransitionKind, d time.Duration, ease func(float64) float64) *SwitchNode[T] {
+	s.trans = &transition{kind: kind, duration: d, ease: ease}
+	return s
+}
+
+func (s *SwitchNode[T]) getTransition() *transition { return s.trans }
+
 // switchNodeInterface for the compiler to detect switch nodes
 type switchNodeInterface interface {
 	evaluateSwitch() any                      // runtime: returns matching node
@@ -347,3 +391,4 @@ func (s *SwitchNode[T]) setPtrOffset(off uintptr) {
 }
 
 var _ switchNodeInterface = (*SwitchNode[int])(nil)
+var _ transitionNode = (*SwitchNode[int])(nil)
@@ -0,0 +1,647 @@
+package glyph
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// ExportOptions controls how WriteSVG, WriteHTML and WritePNG draw a frame.
+type ExportOptions struct {
+	FG, BG     Color         // colours of default-coloured cells; white on black if unset
+	FontFamily string        // CSS font-family for SVG and HTML
+	FontSize   float64       // pixels, for SVG and HTML; default 14
+	LineHeight float64       // row height as a multiple of FontSize; default 1.2
+	Padding    int           // pixels between the cells and the edge of the image
+	Window     bool          // frame the cells in a window with a title bar
+	Title      string        // window title
+	Palette    *[16][3]uint8 // the basic 16 colours, such as App.Palette; the terminal's if nil
+}
+
+const defaultExportFont = `ui-monospace, "SF Mono", Menlo, "DejaVu Sans Mono", Consolas, monospace`
+
+// window chrome colours
+var (
+	windowButtons = [3][3]uint8{{255, 95, 87}, {254, 188, 46}, {40, 200, 64}}
+	windowBorder  = [3]uint8{64, 64, 64}
+)
+
+// exportCell is a resolved glyph of an exported frame.
+type exportCell struct {
+	x, w   int // column and width in cells
+	r      rune
+	fg, bg [3]uint8
+	attr   Attribute
+}
+
+// exportColor returns c's RGB value from palette, or def for the default
+// colour.
+func exportColor(c Color, def [3]uint8, palette *[16][3]uint8) [3]uint8 {
+	switch c.Mode {
+	case Color16:
+		return palette[c.Index&0xF]
+	case Color256:
+		if c.Index < 16 {
+			return palette[c.Index]
+		}
+		return palette256RGB[c.Index]
+	case ColorRGB:
+		return [3]uint8{c.R, c.G, c.B}
+	}
+	return def
+}
+
+// palette returns the basic 16 colours to export with.
+func (o ExportOptions) palette() *[16][3]uint8 {
+	if o.Palette != nil {
+		return o.Palette
+	}
+	return &basic16RGB
+}
+
+// colors returns the default foreground and background.
+func (o ExportOptions) colors() (fg, bg [3]uint8) {
+	p := o.palette()
+	return exportColor(o.FG, p[15], p), exportColor(o.BG, p[0], p)
+}
+
+// font returns the font size, line height and family with defaults.
+func (o ExportOptions) font() (size, lineHeight float64, family string) {
+	size, lineHeight, family = o.FontSize, o.LineHeight, o.FontFamily
+	if size <= 0 {
+		size = 14
+	}
+	if lineHeight <= 0 {
+		lineHeight = 1.2
+	}
+	if family == "" {
+		family = defaultExportFont
+	}
+	return
+}
+
+// exportRows resolves the buffer's cells to glyphs with final colours:
+// inverse is applied and dim blends the foreground into the background.
+// The placeholder cell after a wide rune is folded into it.
+func (b *Buffer) exportRows(fg, bg [3]uint8, palette *[16][3]uint8) [][]exportCell {
+	rows := make([][]exportCell, b.height)
+	for y := range b.height {
+		row := make([]exportCell, 0, b.width)
+		for x := 0; x < b.width; x++ {
+			c := b.cells[y*b.width+x]
+			e := exportCell{x: x, w: 1, r: c.Rune, attr: c.Style.Attr,
+				fg: exportColor(c.Style.FG, fg, palette), bg: exportColor(c.Style.BG, bg, palette)}
+			if e.r == 0 {
+				e.r = ' '
+			} else if runewidth.RuneWidth(e.r) == 2 && x+1 < b.width && b.cells[y*b.width+x+1].Rune == 0 {
+				e.w = 2
+				x++
+			}
+			if e.attr.Has(AttrInverse) {
+				e.fg, e.bg = e.bg, e.fg
+			}
+			if e.attr.Has(AttrDim) {
+				e.fg = mixRGB(e.fg, e.bg, 0.5)
+			}
+			row = append(row, e)
+		}
+		rows[y] = row
+	}
+	return rows
+}
+
+func mixRGB(a, b [3]uint8, t float64) [3]uint8 {
+	var m [3]uint8
+	for i := range m {
+		m[i] = uint8(math.Round(float64(a[i])*(1-t) + float64(b[i])*t))
+	}
+	return m
+}
+
+func hexRGB(c [3]uint8) string {
+	return fmt.Sprintf("#%02x%02x%02x", c[0], c[1], c[2])
+}
+
+// exportLayout places the cells and the window chrome in an image.
+type exportLayout struct {
+	cw, ch float64 // cell size
+	x, y   float64 // top left of the cells
+	w, h   float64 // image size
+	bar    float64 // title bar height
+}
+
+func newExportLayout(b *Buffer, opts ExportOptions, cw, ch float64) exportLayout {
+	l := exportLayout{cw: cw, ch: ch, x: float64(opts.Padding), y: float64(opts.Padding)}
+	if opts.Window {
+		l.bar = math.Round(ch * 2)
+		l.y += l.bar
+	}
+	l.w = l.x*2 + cw*float64(b.width)
+	l.h = l.y + float64(opts.Padding) + ch*float64(b.height)
+	return l
+}
+
+// shapeKind is a primitive of a glyph drawn as geometry.
+type shapeKind uint8
+
+const (
+	shapeRect shapeKind = iota // filled from (x0,y0) to (x1,y1) at opacity a
+	shapeLine                  // stroke from (x0,y0) to (x1,y1), w wide
+	shapeArc                   // stroke from (x0,y0) to (x1,y1) bending towards (cx,cy)
+	shapeDisc                  // filled circle at (x0,y0), radius w
+	shapeRing                  // circle outline at (x0,y0), radius w, stroke a
+)
+
+type shape struct {
+	kind                   shapeKind
+	x0, y0, x1, y1, cx, cy float64
+	w, a                   float64
+}
+
+// boxArms holds the arms of U+2500–U+257F as up, right, down and left
+// weights: 0 none, 1 light, 2 heavy, 3 double.
+const boxArms = "" +
+	"0101020210102020010102021010202001010202101020200110021001200220" +
+	"0011001200210022110012002100220010011002200120021110121021101120" +
+	"2120221012202220101110122011102120212012102220220111011202110212" +
+	"0121012202210222110111021201120221012102220122021111111212111212" +
+	"2111112121212112221111221221221212222122222122220101020210102020" +
+	"0303303003100130033000130031003313003100330010033001300313103130" +
+	"3330101330313033031301310333130331013303131331313333011000111001" +
+	"1100000000000000000110000100001000022000020000200201102001022010"
+
+// cellShapes returns the geometry for runes drawn rather than taken from a
+// font, so box drawing, blocks and braille meet exactly across cells.
+// Coordinates are relative to the cell's top left.
+func cellShapes(r rune, cw, ch float64) ([]shape, bool) {
+	t := max(1, math.Round(cw/7)) // light line width
+	switch {
+	case r >= 0x2500 && r <= 0x257F:
+		return boxShapes(r, cw, ch, t), true
+	case r >= 0x2580 && r <= 0x259F:
+		return blockShapes(r, cw, ch), true
+	case r >= 0x2800 && r <= 0x28FF:
+		return brailleShapes(r, cw, ch), true
+	}
+	d := min(cw, ch)
+	switch r {
+	case '●': // black circle
+		return []shape{{kind: shapeDisc, x0: cw / 2, y0: ch / 2, w: d * 0.4}}, true
+	case '○': // white circle
+		return []shape{{kind: shapeRing, x0: cw / 2, y0: ch / 2, w: d*0.4 - t/2, a: t}}, true
+	case '•': // bullet
+		return []shape{{kind: shapeDisc, x0: cw / 2, y0: ch / 2, w: d * 0.2}}, true
+	case '■', '□': // black and white squares
+		s := math.Round(d * 0.8)
+		x0, y0 := math.Round((cw-s)/2), math.Round((ch-s)/2)
+		if r == '■' {
+			return []shape{{kind: shapeRect, x0: x0, y0: y0, x1: x0 + s, y1: y0 + s, a: 1}}, true
+		}
+		return []shape{
+			{kind: shapeRect, x0: x0, y0: y0, x1: x0 + s, y1: y0 + t, a: 1},
+			{kind: shapeRect, x0: x0, y0: y0 + s - t, x1: x0 + s, y1: y0 + s, a: 1},
+			{kind: shapeRect, x0: x0, y0: y0, x1: x0 + t, y1: y0 + s, a: 1},
+			{kind: shapeRect, x0: x0 + s - t, y0: y0, x1: x0 + s, y1: y0 + s, a: 1},
+		}, true
+	}
+	return nil, false
+}
+
+func boxShapes(r rune, cw, ch, t float64) []shape {
+	cx, cy := cw/2, ch/2
+	switch r {
+	case '┄', '┅', '┈', '┉', '╌', '╍', '┆', '┇', '┊', '┋', '╎', '╏':
+		n := map[rune]int{'┄': 3, '┅': 3, '┆': 3, '┇': 3, '╌': 2, '╍': 2, '╎': 2, '╏': 2}[r]
+		if n == 0 {
+			n = 4
+		}
+		th := t
+		if strings.ContainsRune("┅┉╍┇┋╏", r) {
+			th = t * 2
+		}
+		var s []shape
+		vertical := strings.ContainsRune("┆┇┊┋╎╏", r)
+		for i := range n {
+			if vertical {
+				seg := ch / float64(n)
+				s = append(s, shape{kind: shapeRect, x0: cx - th/2, x1: cx + th/2, y0: seg * float64(i), y1: seg*float64(i) + seg*0.6, a: 1})
+			} else {
+				seg := cw / float64(n)
+				s = append(s, shape{kind: shapeRect, y0: cy - th/2, y1: cy + th/2, x0: seg * float64(i), x1: seg*float64(i) + seg*0.6, a: 1})
+			}
+		}
+		return s
+	case '╭':
+		return []shape{{kind: shapeArc, x0: cw, y0: cy, cx: cx, cy: cy, x1: cx, y1: ch, w: t}}
+	case '╮':
+		return []shape{{kind: shapeArc, x0: 0, y0: cy, cx: cx, cy: cy, x1: cx, y1: ch, w: t}}
+	case '╯':
+		return []shape{{kind: shapeArc, x0: 0, y0: cy, cx: cx, cy: cy, x1: cx, y1: 0, w: t}}
+	case '╰':
+		return []shape{{kind: shapeArc, x0: cw, y0: cy, cx: cx, cy: cy, x1: cx, y1: 0, w: t}}
+	case '╱':
+		return []shape{{kind: shapeLine, x0: cw, y0: 0, x1: 0, y1: ch, w: t}}
+	case '╲':
+		return []shape{{kind: shapeLine, x0: 0, y0: 0, x1: cw, y1: ch, w: t}}
+	case '╳':
+		return []shape{{kind: shapeLine, x0: cw, y0: 0, x1: 0, y1: ch, w: t}, {kind: shapeLine, x0: 0, y0: 0, x1: cw, y1: ch, w: t}}
+	}
+
+	i := int(r-0x2500) * 4
+	var arms [4]int
+	for d := range arms {
+		arms[d] = int(boxArms[i+d] - '0')
+	}
+	g := max(t*1.5, math.Round(cw/4)) // double line offset from the centre
+	offset := func(d int) float64 {   // how far a perpendicular arm's lines sit off centre
+		if arms[d] == 3 {
+			return g
+		}
+		return 0
+	}
+	// arm returns the rect of a line along arm d, off the centre line by
+	// off, reaching reach past the centre.
+	arm := func(d int, off, reach, th float64) shape {
+		s := shape{kind: shapeRect, a: 1}
+		switch d {
+		case 0:
+			s.x0, s.x1, s.y0, s.y1 = cx+off-th/2, cx+off+th/2, 0, cy+reach
+		case 1:
+			s.x0, s.x1, s.y0, s.y1 = cx-reach, cw, cy+off-th/2, cy+off+th/2
+		case 2:
+			s.x0, s.x1, s.y0, s.y1 = cx+off-th/2, cx+off+th/2, cy-reach, ch
+		case 3:
+			s.x0, s.x1, s.y0, s.y1 = 0, cx+reach, cy+off-th/2, cy+off+th/2
+		}
+		return s
+	}
+
+	var s []shape
+	for d, w := range arms {
+		if w == 0 {
+			continue
+		}
+		// the perpendicular arms, towards negative and positive offsets
+		neg, pos := 0, 2
+		if d == 0 || d == 2 {
+			neg, pos = 3, 1
+		}
+		if w != 3 {
+			th := t * float64(w)
+			reach := th / 2
+			if arms[neg] != 0 || arms[pos] != 0 {
+				reach += max(offset(neg), offset(pos))
+			}
+			s = append(s, arm(d, 0, reach, th))
+			continue
+		}
+		for _, side := range [2][2]int{{neg, pos}, {pos, neg}} {
+			near, far := side[0], side[1]
+			off := g
+			if near == neg {
+				off = -g
+			}
+			// meet the line on this side, else turn into the far one
+			reach := t / 2
+			if arms[near] != 0 {
+				reach -= offset(near)
+			} else if arms[far] != 0 {
+				reach += offset(far)
+			}
+			s = append(s, arm(d, off, reach, t))
+		}
+	}
+	return s
+}
+
+func blockShapes(r rune, cw, ch float64) []shape {
+	rect := func(x0, y0, x1, y1 float64) shape {
+		return shape{kind: shapeRect, x0: x0 * cw, y0: y0 * ch, x1: x1 * cw, y1: y1 * ch, a: 1}
+	}
+	switch {
+	case r == '▀':
+		return []shape{rect(0, 0, 1, 0.5)}
+	case r >= '▁' && r <= '█': // lower eighths
+		return []shape{rect(0, 1-float64(r-'▀')/8, 1, 1)}
+	case r >= '▉' && r <= '▏': // left eighths
+		return []shape{rect(0, 0, float64('▐'-r)/8, 1)}
+	case r == '▐':
+		return []shape{rect(0.5, 0, 1, 1)}
+	case r >= '░' && r <= '▓':
+		s := rect(0, 0, 1, 1)
+		s.a = float64(r-'░'+1) / 4
+		return []shape{s}
+	case r == '▔':
+		return []shape{rect(0, 0, 1, 0.125)}
+	case r == '▕':
+		return []shape{rect(0.875, 0, 1, 1)}
+	}
+	// quadrants ▖▗▘▙▚▛▜▝▞▟ as upper left, upper right, lower left, lower right
+	quads := [...]uint8{0b0010, 0b0001, 0b1000, 0b1011, 0b1001, 0b1110, 0b1101, 0b0100, 0b0110, 0b0111}
+	q := quads[r-'▖']
+	var s []shape
+	for i, sh := range [4][4]float64{{0, 0, 0.5, 0.5}, {0.5, 0, 1, 0.5}, {0, 0.5, 0.5, 1}, {0.5, 0.5, 1, 1}} {
+		if q&(0b1000>>i) != 0 {
+			s = append(s, rect(sh[0], sh[1], sh[2], sh[3]))
+		}
+	}
+	return s
+}
+
+// brailleDots maps braille bits to dot column and row.
+var brailleDots = [8][2]int{{0, 0}, {0, 1}, {0, 2}, {1, 0}, {1, 1}, {1, 2}, {0, 3}, {1, 3}}
+
+func brailleShapes(r rune, cw, ch float64) []shape {
+	bits := r - 0x2800
+	rad := max(min(cw/2, ch/4)*0.3, 0.5)
+	var s []shape
+	for i, d := range brailleDots {
+		if bits&(1<<i) != 0 {
+			s = append(s, shape{kind: shapeDisc, x0: cw * (0.25 + 0.5*float64(d[0])), y0: ch * (0.125 + 0.25*float64(d[1])), w: rad})
+		}
+	}
+	return s
+}
+
+// WriteSVG writes the buffer as an SVG image. Text stays text in the
+// font of opts; box drawing, block and braille characters are drawn as
+// shapes so borders join.
+//
+//	buf := glyph.NewBuffer(80, 24)
+//	glyph.Build(view).Execute(buf, 80, 24)
+//	buf.WriteSVG(f, glyph.ExportOptions{Window: true, Title: "demo", Padding: 16})
+func (b *Buffer) WriteSVG(w io.Writer, opts ExportOptions) error {
+	size, lh, family := opts.font()
+	fg, bg := opts.colors()
+	l := newExportLayout(b, opts, size*0.6, math.Round(size*lh))
+
+	out := &svgWriter{Writer: bufio.NewWriter(w)}
+	f := svgNum
+	fmt.Fprintf(out, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %[1]s %[2]s">`+"\n", f(l.w), f(l.h))
+	fmt.Fprintf(out, "<style>text{font-family:%s;font-size:%spx;white-space:pre}.b{font-weight:bold}.i{font-style:italic}"+
+		".k{animation:blink 1s step-end infinite}@keyframes blink{50%%{opacity:0}}</style>\n", html.EscapeString(family), f(size))
+
+	if opts.Window {
+		out.rect(0.5, 0.5, l.w-1, l.h-1, bg, fmt.Sprintf(` rx="8" stroke="%s"`, hexRGB(windowBorder)))
+		out.flush()
+		for i, c := range windowButtons {
+			out.element(`<circle cx="%s" cy="%s" r="%s" fill="%s"/>`, f(l.bar*0.6+float64(i)*l.bar*0.45), f(l.bar/2), f(l.bar*0.14), hexRGB(c))
+		}
+		if opts.Title != "" {
+			out.element(`<text x="%s" y="%s" fill="%s" text-anchor="middle">%s</text>`,
+				f(l.w/2), f(l.bar/2+size*0.35), hexRGB(mixRGB(fg, bg, 0.4)), html.EscapeString(opts.Title))
+		}
+	} else {
+		out.rect(0, 0, l.w, l.h, bg, "")
+		out.flush()
+	}
+
+	rows := b.exportRows(fg, bg, opts.palette())
+	for y, row := range rows {
+		top := l.y + float64(y)*l.ch
+		// backgrounds, merged across runs of one colour
+		for i := 0; i < len(row); {
+			j := i + 1
+			for j < len(row) && row[j].bg == row[i].bg {
+				j++
+			}
+			if row[i].bg != bg {
+				end := row[j-1].x + row[j-1].w
+				out.rect(l.x+float64(row[i].x)*l.cw, top, float64(end-row[i].x)*l.cw, l.ch, row[i].bg, "")
+			}
+			i = j
+		}
+		out.flush()
+		for i := 0; i < len(row); {
+			c := row[i]
+			x := l.x + float64(c.x)*l.cw
+			if shapes, ok := cellShapes(c.r, l.cw, l.ch); ok {
+				out.shapes(shapes, x, top, c)
+				i++
+				continue
+			}
+			// a run of text in one style
+			j := i + 1
+			for j < len(row) && row[j].fg == c.fg && row[j].attr == c.attr {
+				if _, ok := cellShapes(row[j].r, l.cw, l.ch); ok {
+					break
+				}
+				j++
+			}
+			var text strings.Builder
+			for _, rc := range row[i:j] {
+				text.WriteRune(rc.r)
+			}
+			cols := row[j-1].x + row[j-1].w - c.x
+			if s := text.String(); strings.TrimSpace(s) != "" {
+				var class []string
+				for _, a := range []struct {
+					attr Attribute
+					name string
+				}{{AttrBold, "b"}, {AttrItalic, "i"}, {AttrBlink, "k"}} {
+					if c.attr.Has(a.attr) {
+						class = append(class, a.name)
+					}
+				}
+				cls := ""
+				if len(class) > 0 {
+					cls = fmt.Sprintf(` class="%s"`, strings.Join(class, " "))
+				}
+				out.element(`<text x="%s" y="%s" fill="%s"%s textLength="%s" lengthAdjust="spacingAndGlyphs">%s</text>`,
+					f(x), f(top+l.ch/2+size*0.35), hexRGB(c.fg), cls, f(float64(cols)*l.cw), html.EscapeString(s))
+			}
+			for _, line := range textLines(c.attr, l.ch, math.Max(1, size/14)) {
+				out.rect(x, top+line[0], float64(cols)*l.cw, line[1], c.fg, "")
+			}
+			i = j
+		}
+		out.flush()
+	}
+	out.WriteString("</svg>\n")
+	return out.Flush()
+}
+
+// svgNum formats v for SVG to two decimal places.
+func svgNum(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
+
+// svgWriter writes SVG elements. Rects are held until flush and joined
+// where they continue along a row, so a border is one rect per side.
+type svgWriter struct {
+	*bufio.Writer
+	rects []svgRect
+}
+
+type svgRect struct {
+	fill, extra string
+	y, h, x, w  float64
+}
+
+func (s *svgWriter) rect(x, y, w, h float64, c [3]uint8, extra string) {
+	s.rects = append(s.rects, svgRect{hexRGB(c), extra, y, h, x, w})
+}
+
+// flush writes the held rects.
+func (s *svgWriter) flush() {
+	slices.SortStableFunc(s.rects, func(a, b svgRect) int {
+		return cmp.Or(strings.Compare(a.fill, b.fill), strings.Compare(a.extra, b.extra),
+			cmp.Compare(a.y, b.y), cmp.Compare(a.h, b.h), cmp.Compare(a.x, b.x))
+	})
+	for i := 0; i < len(s.rects); {
+		r := s.rects[i]
+		j := i + 1
+		for ; j < len(s.rects); j++ {
+			n := s.rects[j]
+			if n.fill != r.fill || n.extra != r.extra || n.y != r.y || n.h != r.h || n.x > r.x+r.w+0.01 {
+				break
+			}
+			r.w = max(r.w, n.x+n.w-r.x)
+		}
+		fmt.Fprintf(s.Writer, `<rect x="%s" y="%s" width="%s" height="%s" fill="%s"%s/>`+"\n",
+			svgNum(r.x), svgNum(r.y), svgNum(r.w), svgNum(r.h), r.fill, r.extra)
+		i = j
+	}
+	s.rects = s.rects[:0]
+}
+
+// element writes an element on its own line.
+func (s *svgWriter) element(format string, args ...any) {
+	fmt.Fprintf(s.Writer, format+"\n", args...)
+}
+
+// textLines returns the offset from the top of the row and the thickness
+// of the underline and strikethrough attr asks for.
+func textLines(attr Attribute, ch, t float64) [][2]float64 {
+	var lines [][2]float64
+	if attr.Has(AttrUnderline) {
+		lines = append(lines, [2]float64{ch - math.Max(t, math.Round(ch/8)), t})
+	}
+	if attr.Has(AttrStrikethrough) {
+		lines = append(lines, [2]float64{math.Round(ch / 2), t})
+	}
+	return lines
+}
+
+// shapes writes the geometry of a cell at x, y.
+func (s *svgWriter) shapes(shapes []shape, x, y float64, c exportCell) {
+	f := svgNum
+	fill := hexRGB(c.fg)
+	blink := ""
+	if c.attr.Has(AttrBlink) {
+		blink = ` class="k"`
+	}
+	for _, sh := range shapes {
+		switch sh.kind {
+		case shapeRect:
+			op := blink
+			if sh.a < 1 {
+				op = fmt.Sprintf(` fill-opacity="%s"`, f(sh.a)) + blink
+			}
+			s.rect(x+sh.x0, y+sh.y0, sh.x1-sh.x0, sh.y1-sh.y0, c.fg, op)
+		case shapeLine:
+			s.element(`<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="%s" stroke-width="%s"%s/>`,
+				f(x+sh.x0), f(y+sh.y0), f(x+sh.x1), f(y+sh.y1), fill, f(sh.w), blink)
+		case shapeArc:
+			s.element(`<path d="M%s %sQ%s %s %s %s" fill="none" stroke="%s" stroke-width="%s"%s/>`,
+				f(x+sh.x0), f(y+sh.y0), f(x+sh.cx), f(y+sh.cy), f(x+sh.x1), f(y+sh.y1), fill, f(sh.w), blink)
+		case shapeDisc:
+			s.element(`<circle cx="%s" cy="%s" r="%s" fill="%s"%s/>`, f(x+sh.x0), f(y+sh.y0), f(sh.w), fill, blink)
+		case shapeRing:
+			s.element(`<circle cx="%s" cy="%s" r="%s" fill="none" stroke="%s" stroke-width="%s"%s/>`,
+				f(x+sh.x0), f(y+sh.y0), f(sh.w), fill, f(sh.a), blink)
+		}
+	}
+}
+
+// WriteHTML writes the buffer as an HTML fragment: a pre element of
+// styled spans, in a window frame if opts asks for one. Text, including
+// box drawing, stays selectable.
+func (b *Buffer) WriteHTML(w io.Writer, opts ExportOptions) error {
+	size, lh, family := opts.font()
+	fg, bg := opts.colors()
+	f := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+
+	out := bufio.NewWriter(w)
+	out.WriteString("<style>.glyph-blink{animation:glyph-blink 1s step-end infinite}@keyframes glyph-blink{50%{opacity:0}}</style>\n")
+	if opts.Window {
+		fmt.Fprintf(out, `<div style="display:inline-block;background:%s;border:1px solid %s;border-radius:8px;overflow:hidden">`+"\n",
+			hexRGB(bg), hexRGB(windowBorder))
+		fmt.Fprintf(out, `<div style="position:relative;height:%spx;line-height:%[1]spx;text-align:center;font:%spx %s;color:%s">`,
+			f(math.Round(size*lh*2)), f(size), html.EscapeString(family), hexRGB(mixRGB(fg, bg, 0.4)))
+		for i, c := range windowButtons {
+			fmt.Fprintf(out, `<span style="position:absolute;left:%spx;top:50%%;width:12px;height:12px;margin-top:-6px;border-radius:50%%;background:%s"></span>`,
+				f(14+float64(i)*20), hexRGB(c))
+		}
+		fmt.Fprintf(out, "%s</div>\n", html.EscapeString(opts.Title))
+	}
+	fmt.Fprintf(out, `<pre style="margin:0;padding:%dpx;background:%s;color:%s;font-family:%s;font-size:%spx;line-height:%s">`,
+		opts.Padding, hexRGB(bg), hexRGB(fg), html.EscapeString(family), f(size), f(lh))
+
+	for y, row := range b.exportRows(fg, bg, opts.palette()) {
+		if y > 0 {
+			out.WriteByte('\n')
+		}
+		for i := 0; i < len(row); {
+			c := row[i]
+			j := i + 1
+			for j < len(row) && row[j].fg == c.fg && row[j].bg == c.bg && row[j].attr == c.attr {
+				j++
+			}
+			var text strings.Builder
+			for _, rc := range row[i:j] {
+				text.WriteRune(rc.r)
+			}
+			var css []string
+			if c.fg != fg {
+				css = append(css, "color:"+hexRGB(c.fg))
+			}
+			if c.bg != bg {
+				css = append(css, "background:"+hexRGB(c.bg))
+			}
+			if c.attr.Has(AttrBold) {
+				css = append(css, "font-weight:bold")
+			}
+			if c.attr.Has(AttrItalic) {
+				css = append(css, "font-style:italic")
+			}
+			var deco []string
+			if c.attr.Has(AttrUnderline) {
+				deco = append(deco, "underline")
+			}
+			if c.attr.Has(AttrStrikethrough) {
+				deco = append(deco, "line-through")
+			}
+			if len(deco) > 0 {
+				css = append(css, "text-decoration:"+strings.Join(deco, " "))
+			}
+			s := html.EscapeString(text.String())
+			if len(css) == 0 && !c.attr.Has(AttrBlink) {
+				out.WriteString(s)
+			} else {
+				out.WriteString("<span")
+				if c.attr.Has(AttrBlink) {
+					out.WriteString(` class="glyph-blink"`)
+				}
+				if len(css) > 0 {
+					fmt.Fprintf(out, ` style="%s"`, strings.Join(css, ";"))
+				}
+				fmt.Fprintf(out, ">%s</span>", s)
+			}
+			i = j
+		}
+	}
+	out.WriteString("</pre>\n")
+	if opts.Window {
+		out.WriteString("</div>\n")
+	}
+	return out.Flush()
+}
@@ -0,0 +1,151 @@
+package glyph
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// exportBuffer returns a frame using each kind of colour and attribute.
+func exportBuffer() *Buffer {
+	buf := NewBuffer(12, 3)
+	buf.WriteString(0, 0, "┌──┐", Style{FG: Green})
+	buf.WriteString(4, 0, "bold", Style{FG: PaletteColor(208), Attr: AttrBold})
+	buf.WriteString(8, 0, "<&>", Style{FG: RGB(1, 2, 3), BG: Hex(0x102030), Attr: AttrUnderline | AttrItalic})
+	buf.WriteString(0, 1, "└──┘", Style{FG: Green})
+	buf.WriteString(4, 1, "inv", Style{FG: Red, Attr: AttrInverse})
+	buf.WriteSpans(7, 1, []Span{{Text: "日本", Style: Style{Attr: AttrBlink}}}, 5)
+	buf.WriteString(0, 2, "█", Style{FG: Blue})
+	buf.WriteString(1, 2, "dim", Style{Attr: AttrDim | AttrStrikethrough})
+	return buf
+}
+
+func TestWriteSVG(t *testing.T) {
+	palette := basic16RGB
+	palette[2] = [3]uint8{0x11, 0x22, 0x33} // as detected from the terminal
+
+	var out bytes.Buffer
+	if err := exportBuffer().WriteSVG(&out, ExportOptions{Window: true, Title: "demo <1>", Padding: 10, Palette: &palette}); err != nil {
+		t.Fatal(err)
+	}
+	svg := out.String()
+	for _, want := range []string{
+		`class="b" textLength="33.6"`, // bold, 4 cells of 8.4
+		`fill="#ff8700" class="b"`,    // 256 colour 208
+		`fill="#010203" class="i"`,    // RGB
+		`fill="#102030"`,              // RGB background
+		`&lt;&amp;&gt;</text>`,        // escaped
+		`fill="#aa0000"`,              // inverse: red background
+		`class="k" textLength="33.6"`, // two wide runes span four cells
+		`fill="#112233"`,              // detected palette
+		`<text x="60.4" y="21.9" fill="#999999" text-anchor="middle">demo &lt;1&gt;</text>`,
+		`rx="8"`, `fill="#ff5f57"`,
+	} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("missing %s in\n%s", want, svg)
+		}
+	}
+	// box drawing is geometry; the top border is one rect
+	if strings.Contains(svg, "┌") || strings.Contains(svg, "─") {
+		t.Error("box drawing left as text")
+	}
+	if !strings.Contains(svg, `<rect x="13.7" y="52" width="26.2" height="1" fill="#112233"/>`) {
+		t.Errorf("top border not joined:\n%s", svg)
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	var out bytes.Buffer
+	if err := exportBuffer().WriteHTML(&out, ExportOptions{BG: Hex(0x202020)}); err != nil {
+		t.Fatal(err)
+	}
+	html := out.String()
+	for _, want := range []string{
+		"background:#202020;color:#ffffff",
+		`<span style="color:#00aa00">┌──┐</span>`,
+		`<span style="color:#ff8700;font-weight:bold">bold</span>`,
+		`<span style="color:#010203;background:#102030;font-style:italic;text-decoration:underline">&lt;&amp;&gt;</span>`,
+		`<span style="color:#202020;background:#aa0000">inv</span>`,
+		`<span class="glyph-blink">日本</span>`,
+		`<span style="color:#909090;text-decoration:line-through">dim</span>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("missing %s in\n%s", want, html)
+		}
+	}
+	if strings.Contains(html, "Window") || strings.Contains(html, "border-radius") {
+		t.Error("window chrome without Window")
+	}
+}
+
+func TestWritePNG(t *testing.T) {
+	var out bytes.Buffer
+	if err := exportBuffer().WritePNG(&out, nil, ExportOptions{Padding: 2}); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b != image.Rect(0, 0, 12*7+4, 3*13+4) {
+		t.Fatalf("bounds = %v", b)
+	}
+	at := func(x, y int) [3]uint8 {
+		r, g, b, _ := img.At(x, y).RGBA()
+		return [3]uint8{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8)}
+	}
+	pad := 2
+	cases := []struct {
+		name string
+		x, y int
+		want [3]uint8
+	}{
+		{"padding", 0, 0, basic16RGB[0]},
+		{"horizontal line", pad + 7 + 3, pad + 6, basic16RGB[2]},
+		{"line ends at the centre", pad + 1, pad + 6, basic16RGB[0]},
+		{"corner meets", pad + 3, pad + 12, basic16RGB[2]},
+		{"joins the next row", pad + 3, pad + 13, basic16RGB[2]},
+		{"RGB background", pad + 8*7, pad + 12, [3]uint8{0x10, 0x20, 0x30}},
+		{"underline", pad + 8*7 + 1, pad + 11, [3]uint8{1, 2, 3}},
+		{"inverse background", pad + 4*7, pad + 13, basic16RGB[1]},
+		{"full block", pad + 6, pad + 26 + 12, basic16RGB[4]},
+		{"wide rune box spans two cells", pad + 7*7 + 10, pad + 13 + 2, basic16RGB[15]},
+	}
+	for _, c := range cases {
+		if got := at(c.x, c.y); got != c.want {
+			t.Errorf("%s: pixel (%d,%d) = %v, want %v", c.name, c.x, c.y, got, c.want)
+		}
+	}
+
+	// window chrome leaves rounded corners transparent
+	out.Reset()
+	if err := exportBuffer().WritePNG(&out, ScaledFont(FixedFont, 2), ExportOptions{Window: true, Title: "demo"}); err != nil {
+		t.Fatal(err)
+	}
+	img, _ = png.Decode(&out)
+	if b := img.Bounds(); b != image.Rect(0, 0, 12*14, 3*26+52) {
+		t.Fatalf("window bounds = %v", b)
+	}
+	if _, _, _, a := img.At(0, 0).RGBA(); a != 0 {
+		t.Error("window corner not transparent")
+	}
+	if got := at(14*12/2, 52+26*2+12); got != basic16RGB[0] {
+		t.Errorf("scaled cell background = %v", got)
+	}
+
+	// title runes are placed by cell width, not byte offset
+	out.Reset()
+	if err := exportBuffer().WritePNG(&out, nil, ExportOptions{Window: true, Title: "日日"}); err != nil {
+		t.Fatal(err)
+	}
+	img, _ = png.Decode(&out)
+	fg, bg := ExportOptions{}.colors()
+	tx := (img.Bounds().Dx() - 4*7) / 2
+	for _, x := range []int{tx + 1, tx + 14 + 1, tx + 28 - 2} {
+		if got := at(x, 6+6); got != mixRGB(fg, bg, 0.4) {
+			t.Errorf("title pixel (%d,12) = %v, want the edge of a two-cell glyph", x, got)
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package glyph
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// Font supplies the glyphs WritePNG draws text with.
+type Font interface {
+	// CellSize returns the size of a cell in pixels.
+	CellSize() (w, h int)
+	// Glyph returns r's coverage as an alpha mask one cell in size, two
+	// for wide runes, or false if the font has no glyph for r.
+	Glyph(r rune) (image.Image, bool)
+}
+
+// FixedFont is the built-in 7×13 bitmap font. It covers ASCII; WritePNG
+// draws box drawing, blocks and braille itself.
+var FixedFont Font = fixedFont{}
+
+type fixedFont struct{}
+
+func (fixedFont) CellSize() (w, h int) { return 7, 13 }
+
+func (fixedFont) Glyph(r rune) (image.Image, bool) {
+	var i int
+	switch {
+	case r >= 0x20 && r <= 0x7E:
+		i = int(r - 0x20)
+	case r == 0xFFFD:
+		i = 95
+	default:
+		return nil, false
+	}
+	m := image.NewAlpha(image.Rect(0, 0, 7, 13))
+	for y := range 13 {
+		row := fixedGlyphs[i*13+y]
+		for x := range 6 {
+			if row&(0x20>>x) != 0 {
+				m.Pix[y*m.Stride+x] = 0xFF
+			}
+		}
+	}
+	return m, true
+}
+
+// ScaledFont returns f with every pixel drawn n×n, for sharper images
+// from a bitmap font.
+//
+//	buf.WritePNG(f, glyph.ScaledFont(glyph.FixedFont, 2), opts)
+func ScaledFont(f Font, n int) Font {
+	return scaledFont{f, max(n, 1)}
+}
+
+type scaledFont struct {
+	Font
+	n int
+}
+
+func (s scaledFont) CellSize() (w, h int) {
+	w, h = s.Font.CellSize()
+	return w * s.n, h * s.n
+}
+
+func (s scaledFont) Glyph(r rune) (image.Image, bool) {
+	m, ok := s.Font.Glyph(r)
+	if !ok {
+		return nil, false
+	}
+	return scaledMask{m, s.n}, true
+}
+
+// scaledMask is an image drawing each pixel of another n×n.
+type scaledMask struct {
+	image.Image
+	n int
+}
+
+func (m scaledMask) Bounds() image.Rectangle {
+	b := m.Image.Bounds()
+	return image.Rectangle{b.Min.Mul(m.n), b.Max.Mul(m.n)}
+}
+
+func (m scaledMask) At(x, y int) color.Color {
+	return m.Image.At(floorDiv(x, m.n), floorDiv(y, m.n))
+}
+
+func floorDiv(a, n int) int {
+	if a < 0 {
+		return -((n - 1 - a) / n)
+	}
+	return a / n
+}
+
+// WritePNG writes the buffer as a PNG image drawn with font, FixedFont if
+// nil. Bold is drawn twice a pixel apart and italic slanted when the font
+// has no such faces; blink draws as on.
+func (b *Buffer) WritePNG(w io.Writer, font Font, opts ExportOptions) error {
+	if font == nil {
+		font = FixedFont
+	}
+	cw, ch := font.CellSize()
+	fg, bg := opts.colors()
+	l := newExportLayout(b, opts, float64(cw), float64(ch))
+	c := &pngCanvas{img: image.NewRGBA(image.Rect(0, 0, int(l.w), int(l.h)))}
+
+	if opts.Window {
+		c.roundRect(int(l.w), int(l.h), 8, bg, windowBorder)
+		for i, col := range windowButtons {
+			c.disc(l.bar*0.6+float64(i)*l.bar*0.45, l.bar/2, l.bar*0.14, col, 1)
+		}
+		if opts.Title != "" {
+			tx := (int(l.w) - runewidth.StringWidth(opts.Title)*cw) / 2
+			ty := int(l.bar-float64(ch)) / 2
+			col := 0
+			for _, r := range opts.Title {
+				w := runewidth.RuneWidth(r)
+				if w == 0 {
+					continue
+				}
+				c.glyph(font, r, tx+col*cw, ty, w, mixRGB(fg, bg, 0.4), 0)
+				col += w
+			}
+		}
+	} else {
+		c.rect(0, 0, l.w, l.h, bg, 1)
+	}
+
+	for y, row := range b.exportRows(fg, bg, opts.palette()) {
+		top := int(l.y) + y*ch
+		for _, e := range row {
+			x := int(l.x) + e.x*cw
+			c.rect(float64(x), float64(top), float64(x+e.w*cw), float64(top+ch), e.bg, 1)
+			if shapes, ok := cellShapes(e.r, float64(cw), float64(ch)); ok {
+				for _, s := range shapes {
+					c.shape(s, float64(x), float64(top), e.fg)
+				}
+			} else if e.r != ' ' {
+				c.glyph(font, e.r, x, top, e.w, e.fg, e.attr)
+			}
+			for _, line := range textLines(e.attr, float64(ch), math.Max(1, float64(ch/13))) {
+				c.rect(float64(x), float64(top)+line[0], float64(x+e.w*cw), float64(top)+line[0]+line[1], e.fg, 1)
+			}
+		}
+	}
+	return png.Encode(w, c.img)
+}
+
+// pngCanvas rasterises frames for WritePNG.
+type pngCanvas struct {
+	img *image.RGBA
+}
+
+// blend mixes col into the pixel at x, y with opacity a.
+func (c *pngCanvas) blend(x, y int, col [3]uint8, a float64) {
+	if !(image.Point{x, y}.In(c.img.Rect)) || a <= 0 {
+		return
+	}
+	i := c.img.PixOffset(x, y)
+	p := c.img.Pix[i : i+4 : i+4]
+	for k := range 3 {
+		p[k] = uint8(math.Round(float64(p[k])*(1-a) + float64(col[k])*a))
+	}
+	p[3] = 0xFF
+}
+
+// rect fills pixels from x0, y0 to x1, y1, rounded to the pixel grid and
+// at least one pixel across.
+func (c *pngCanvas) rect(x0, y0, x1, y1 float64, col [3]uint8, a float64) {
+	ix0, iy0 := int(math.Round(x0)), int(math.Round(y0))
+	ix1, iy1 := max(int(math.Round(x1)), ix0+1), max(int(math.Round(y1)), iy0+1)
+	for y := iy0; y < iy1; y++ {
+		for x := ix0; x < ix1; x++ {
+			c.blend(x, y, col, a)
+		}
+	}
+}
+
+// disc fills the pixels whose centres lie within r of x, y.
+func (c *pngCanvas) disc(cx, cy, r float64, col [3]uint8, a float64) {
+	r = max(r, 0.5)
+	for y := int(cy - r - 1); y <= int(cy+r+1); y++ {
+		for x := int(cx - r - 1); x <= int(cx+r+1); x++ {
+			if math.Hypot(float64(x)+0.5-cx, float64(y)+0.5-cy) <= r {
+				c.blend(x, y, col, a)
+			}
+		}
+	}
+}
+
+// stroke draws a w-wide pen along the points of fn for t from 0 to 1.
+func (c *pngCanvas) stroke(fn func(t float64) (x, y float64), length, w float64, col [3]uint8) {
+	steps := int(length*2) + 1
+	for i := range steps + 1 {
+		x, y := fn(float64(i) / float64(steps))
+		c.rect(x-w/2, y-w/2, x+w/2, y+w/2, col, 1)
+	}
+}
+
+func (c *pngCanvas) shape(s shape, x, y float64, col [3]uint8) {
+	switch s.kind {
+	case shapeRect:
+		c.rect(x+s.x0, y+s.y0, x+s.x1, y+s.y1, col, s.a)
+	case shapeLine:
+		c.stroke(func(t float64) (float64, float64) {
+			return x + s.x0 + (s.x1-s.x0)*t, y + s.y0 + (s.y1-s.y0)*t
+		}, math.Hypot(s.x1-s.x0, s.y1-s.y0), s.w, col)
+	case shapeArc:
+		c.stroke(func(t float64) (float64, float64) {
+			u := 1 - t
+			return x + u*u*s.x0 + 2*u*t*s.cx + t*t*s.x1, y + u*u*s.y0 + 2*u*t*s.cy + t*t*s.y1
+		}, math.Abs(s.x1-s.x0)+math.Abs(s.y1-s.y0), s.w, col)
+	case shapeDisc:
+		c.disc(x+s.x0, y+s.y0, s.w, col, 1)
+	case shapeRing:
+		for py := int(y + s.y0 - s.w - s.a - 1); py <= int(y+s.y0+s.w+s.a+1); py++ {
+			for px := int(x + s.x0 - s.w - s.a - 1); px <= int(x+s.x0+s.w+s.a+1); px++ {
+				if math.Abs(math.Hypot(float64(px)+0.5-x-s.x0, float64(py)+0.5-y-s.y0)-s.w) <= s.a/2 {
+					c.blend(px, py, col, 1)
+				}
+			}
+		}
+	}
+}
+
+// glyph draws r from font in cols cells at x, y. A rune the font lacks is
+// drawn as its U+FFFD glyph, or as an outlined box.
+func (c *pngCanvas) glyph(font Font, r rune, x, y, cols int, col [3]uint8, attr Attribute) {
+	m, ok := font.Glyph(r)
+	if !ok && cols == 1 {
+		m, ok = font.Glyph(0xFFFD)
+	}
+	cw, ch := font.CellSize()
+	if !ok {
+		w := cols * cw
+		t := max(1, float64(cw)/7)
+		fx, fy := float64(x), float64(y)
+		c.rect(fx+1, fy+2, fx+float64(w)-1, fy+2+t, col, 1)
+		c.rect(fx+1, fy+float64(ch)-2-t, fx+float64(w)-1, fy+float64(ch)-2, col, 1)
+		c.rect(fx+1, fy+2, fx+1+t, fy+float64(ch)-2, col, 1)
+		c.rect(fx+float64(w)-1-t, fy+2, fx+float64(w)-1, fy+float64(ch)-2, col, 1)
+		return
+	}
+	bounds := m.Bounds()
+	bold := 0
+	if attr.Has(AttrBold) {
+		bold = max(1, cw/7)
+	}
+	for my := bounds.Min.Y; my < bounds.Max.Y; my++ {
+		py := y + my - bounds.Min.Y
+		shift := 0
+		if attr.Has(AttrItalic) {
+			shift = (ch*3/4 - (my - bounds.Min.Y)) / 4 // lean about the baseline
+		}
+		for mx := bounds.Min.X; mx < bounds.Max.X; mx++ {
+			_, _, _, a := m.At(mx, my).RGBA()
+			if a == 0 {
+				continue
+			}
+			px := x + mx - bounds.Min.X + shift
+			for d := 0; d <= bold; d++ {
+				c.blend(px+d, py, col, float64(a)/0xFFFF)
+			}
+		}
+	}
+}
+
+// roundRect fills the whole image as a window with rounded corners and a
+// one pixel border, leaving the corners transparent.
+func (c *pngCanvas) roundRect(w, h int, r float64, fill, border [3]uint8) {
+	for y := range h {
+		for x := range w {
+			// distance outside the rect inset by r
+			dx := max(r-float64(x)-0.5, float64(x)+0.5-float64(w)+r, 0)
+			dy := max(r-float64(y)-0.5, float64(y)+0.5-float64(h)+r, 0)
+			d := math.Hypot(dx, dy)
+			switch {
+			case d > r:
+			case d > r-1:
+				c.blend(x, y, border, 1)
+			default:
+				c.blend(x, y, fill, 1)
+			}
+		}
+	}
+}
@@ -2,6 +2,7 @@ package glyph
 
 import (
 	"strings"
+	"sync"
 	"unicode"
 	"unicode/utf8"
 	"unsafe"
@@ -34,7 +35,9 @@ func init() {
 	algo.Init("default")
 }
 
-var fzfSlab = util.MakeSlab(100*1024, 2048)
+// fzfSlabs holds the matchers' scratch space. a pass over many candidates
+// takes one slab for the whole pass, so apps can filter at the same time.
+var fzfSlabs = sync.Pool{New: func() any { return util.MakeSlab(100*1024, 2048) }}
 
 // FzfQuery is a pre-parsed fzf query. parse once, score many.
 type FzfQuery struct {
@@ -187,10 +190,20 @@ func (q *FzfQuery) Score(candidate string) (int, bool) {
 		return 0, true
 	}
 
+	slab := fzfSlabs.Get().(*util.Slab)
+	defer fzfSlabs.Put(slab)
+	return q.score(candidate, slab)
+}
+
+// score is Score with the caller's slab, for scoring many candidates.
+func (q *FzfQuery) score(candidate string, slab *util.Slab) (int, bool) {
+	if len(q.groups) == 0 {
+		return 0, true
+	}
 	bestScore := -1
 	matched := false
 	for i := range q.groups {
-		score, ok := q.groups[i].score(candidate)
+		score, ok := q.groups[i].score(candidate, slab)
 		if ok && score > bestScore {
 			matched = true
 			bestScore = score
@@ -199,10 +212,10 @@ func (q *FzfQuery) Score(candidate string) (int, bool) {
 	return bestScore, matched
 }
 
-func (g *fzfGroup) score(candidate string) (int, bool) {
+func (g *fzfGroup) score(candidate string, slab *util.Slab) (int, bool) {
 	totalScore := 0
 	for i := range g.terms {
-		score, ok := g.terms[i].score(candidate)
+		score, ok := g.terms[i].score(candidate, slab)
 		if !ok {
 			return 0, false
 		}
@@ -211,7 +224,44 @@ func (g *fzfGroup) score(candidate string) (int, bool) {
 	return totalScore, true
 }
 
-func (t *fzfTerm) score(candidate string) (int, bool) {
+func (t *fzfTerm) score(candidate string, slab *util.Slab) (int, bool) {
+	result := t.match(candidate, slab)
+	matched := result.Start >= 0
+
+	if t.negated {
+		return 0, !matched
+	}
+	if !matched {
+		return 0, false
+	}
+	return result.Score, true
+}
+
+// matchRanges returns the rune ranges matched by the positive terms of the
+// first group that accepts candidate. Used to highlight search hits.
+func (q *FzfQuery) matchRanges(candidate string, slab *util.Slab) ([][2]int, bool) {
+	for i := range q.groups {
+		if _, ok := q.groups[i].score(candidate, slab); !ok {
+			continue
+		}
+		var ranges [][2]int
+		for j := range q.groups[i].terms {
+			t := &q.groups[i].terms[j]
+			if t.negated {
+				continue
+			}
+			if r := t.match(candidate, slab); r.Start >= 0 && r.End > r.Start {
+				ranges = append(ranges, [2]int{r.Start, r.End})
+			}
+		}
+		return ranges, true
+	}
+	return nil, false
+}
+
+// match runs the term's matcher and returns the raw result, including the
+// matched range for highlighting.
+func (t *fzfTerm) match(candidate string, slab *util.Slab) algo.Result {
 	// avoid []byte copy: algo functions only read from Chars, never mutate the backing slice
 	chars := util.ToChars(unsafe.Slice(unsafe.StringData(candidate), len(candidate)))
 
@@ -220,23 +270,15 @@ func (t *fzfTerm) score(candidate string) (int, bool) {
 	var result algo.Result
 	switch t.kind {
 	case termExact:
-		result, _ = algo.ExactMatchNaive(t.caseSensitive, false, true, &chars, t.patRunes, false, fzfSlab)
+		result, _ = algo.ExactMatchNaive(t.caseSensitive, false, true, &chars, t.patRunes, false, slab)
 	case termPrefix:
-		result, _ = algo.PrefixMatch(t.caseSensitive, false, true, &chars, t.patRunes, false, fzfSlab)
+		result, _ = algo.PrefixMatch(t.caseSensitive, false, true, &chars, t.patRunes, false, slab)
 	case termSuffix:
-		result, _ = algo.SuffixMatch(t.caseSensitive, false, true, &chars, t.patRunes, false, fzfSlab)
+		result, _ = algo.SuffixMatch(t.caseSensitive, false, true, &chars, t.patRunes, false, slab)
 	default:
-		result, _ = algo.FuzzyMatchV2(t.caseSensitive, false, true, &chars, t.patRunes, false, fzfSlab)
-	}
-	matched := result.Start >= 0
-
-	if t.negated {
-		return 0, !matched
+		result, _ = algo.FuzzyMatchV2(t.caseSensitive, false, true, &chars, t.patRunes, false, slab)
 	}
-	if !matched {
-		return 0, false
-	}
-	return result.Score, true
+	return result
 }
 
 // ============================================================================
@@ -301,13 +343,15 @@ func (f *Filter[T]) Update(query string) {
 	if cap(matches) < len(src) {
 		matches = make([]scored, 0, len(src))
 	}
+	slab := fzfSlabs.Get().(*util.Slab)
 	for i := range src {
 		text := f.extract(&src[i])
-		score, ok := f.query.Score(text)
+		score, ok := f.query.score(text, slab)
 		if ok {
 			matches = append(matches, scored{index: i, score: score})
 		}
 	}
+	fzfSlabs.Put(slab)
 
 	// sort by score descending, then by original index ascending
 	for i := 1; i < len(matches); i++ {
@@ -398,12 +442,14 @@ func (f *Filter[T]) appended() {
 		}
 	} else {
 		// filter active: score only new items, append matches
+		slab := fzfSlabs.Get().(*util.Slab)
 		for i := f.scored; i < len(src); i++ {
-			if _, ok := f.query.Score(f.extract(&src[i])); ok {
+			if _, ok := f.query.score(f.extract(&src[i]), slab); ok {
 				f.Items = append(f.Items, src[i])
 				f.indices = append(f.indices, i)
 			}
 		}
+		fzfSlabs.Put(slab)
 	}
 	f.scored = len(src)
 }
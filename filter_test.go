@@ -385,6 +385,19 @@ func BenchmarkFilterUpdate(b *testing.B) {
 	}
 }
 
+func BenchmarkLogFilter(b *testing.B) {
+	lc := Log(nil)
+	for i := range 1000 {
+		lc.lines = append(lc.lines, fmt.Sprintf("12:00:%02d service-name instance-%d heap profile written", i%60, i))
+	}
+	query := parseLogQuery("heap instance", false)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		lc.syncToLayerFiltered(&query)
+	}
+}
+
 func BenchmarkStreamWriter(b *testing.B) {
 	for _, size := range []int{100, 1000, 5000, 10000} {
 		prebuilt := make([]string, size)
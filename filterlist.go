@@ -278,7 +278,7 @@ func (fl *FilterListC[T]) HandleClear(key string, fallback func()) *FilterListC[
 			} else if fallback != nil {
 				fallback()
 			}
-		}},
+		}, name: "list.clear_filter", desc: "clear filter", group: "List"},
 	)
 	return fl
 }
@@ -3,6 +3,8 @@ package glyph
 import (
 	"bufio"
 	"io"
+
+	"github.com/junegunn/fzf/src/util"
 )
 
 type FilterLogC struct {
@@ -10,7 +12,7 @@ type FilterLogC struct {
 	log   *LogC
 
 	placeholder string
-	query       FzfQuery
+	query       logQuery
 	lastQuery   string
 
 	// layout
@@ -43,6 +45,8 @@ func FilterLog(r io.Reader) *FilterLogC {
 		},
 	}
 
+	fl.log.query = &fl.query
+
 	// wire input changes to filter
 	fl.input.declaredTIB = &textInputBinding{
 		value:  &fl.input.field.Value,
@@ -113,6 +117,39 @@ func (fl *FilterLogC) MaxLines(n int) *FilterLogC {
 	return fl
 }
 
+// ANSI interprets escape sequences in incoming lines. Filtering matches
+// against the visible text, not the raw escape codes.
+func (fl *FilterLogC) ANSI() *FilterLogC {
+	fl.log.ansi = true
+	return fl
+}
+
+// Structured parses JSON and logfmt lines into records. The filter then
+// also accepts field terms such as "level:error" or "!user:bob".
+func (fl *FilterLogC) Structured() *FilterLogC {
+	fl.log.structured = true
+	return fl
+}
+
+// Fields sets how structured records show their fields.
+func (fl *FilterLogC) Fields(m LogFieldMode) *FilterLogC {
+	fl.log.fieldMode = m
+	return fl
+}
+
+// BindFields registers a key that cycles structured field display.
+func (fl *FilterLogC) BindFields(key string) *FilterLogC {
+	fl.log.BindFields(key)
+	return fl
+}
+
+// BindExpand registers a key that expands or collapses the fields of the
+// record on the cursor line. See LogC.BindExpand.
+func (fl *FilterLogC) BindExpand(key string) *FilterLogC {
+	fl.log.BindExpand(key)
+	return fl
+}
+
 // Grow sets the flex grow factor. Accepts float32, float64, int, or *float32 for dynamic values.
 func (fl *FilterLogC) Grow(g any) *FilterLogC {
 	switch val := g.(type) {
@@ -237,40 +274,38 @@ func (fl *FilterLogC) updateFilter() {
 		return
 	}
 	fl.lastQuery = query
-	fl.query = ParseFzfQuery(query)
 
 	// trigger re-sync on next render
 	fl.log.mu.Lock()
+	fl.query = parseLogQuery(query, fl.log.structured)
 	fl.log.syncToLayerFiltered(&fl.query)
 	fl.log.mu.Unlock()
 }
 
 // Override the log's syncToLayer to support filtering
-func (lc *LogC) syncToLayerFiltered(query *FzfQuery) {
+func (lc *LogC) syncToLayerFiltered(query *logQuery) {
 	if len(lc.lines) == 0 {
+		lc.shown = lc.shown[:0]
 		return
 	}
 
 	const bufferWidth = 500
 
-	if query == nil || query.Empty() {
-		// no filter, show all lines
-		buf := NewBuffer(bufferWidth, len(lc.lines))
-		for i, line := range lc.lines {
-			buf.WriteStringFast(0, i, line, Style{}, bufferWidth)
-		}
-		lc.layer.SetBuffer(buf)
-		return
-	}
-
-	// filter lines
-	var filtered []string
-	for _, line := range lc.lines {
-		if _, ok := query.Score(line); ok {
-			filtered = append(filtered, line)
+	// filter lines (in ANSI and structured mode lines holds the visible text)
+	all := query == nil || query.Empty()
+	filtered := lc.shown[:0]
+	rows := 0
+	slab := fzfSlabs.Get().(*util.Slab)
+	for i, line := range lc.lines {
+		if !all && !query.matches(line, lc.record(i), slab) {
+			continue
 		}
+		filtered = append(filtered, i)
+		rows += lc.lineRows(i)
 	}
+	fzfSlabs.Put(slab)
 
+	lc.shown = filtered
 	if len(filtered) == 0 {
 		// no matches, show empty
 		buf := NewBuffer(bufferWidth, 1)
@@ -279,9 +314,11 @@ func (lc *LogC) syncToLayerFiltered(query *FzfQuery) {
 		return
 	}
 
-	buf := NewBuffer(bufferWidth, len(filtered))
-	for i, line := range filtered {
-		buf.WriteStringFast(0, i, line, Style{}, bufferWidth)
+	// create exact-sized buffer (EnsureSize only grows, which breaks maxScroll after ring buffer truncates)
+	buf := NewBuffer(bufferWidth, rows)
+	y := 0
+	for _, i := range filtered {
+		y += lc.writeLine(buf, y, i)
 	}
 	lc.layer.SetBuffer(buf)
 }
@@ -303,12 +340,12 @@ func (fl *FilterLogC) readLoopFiltered() {
 
 		fl.log.mu.Lock()
 
-		fl.log.lines = append(fl.log.lines, line)
+		fl.log.appendLine(line)
 
 		// ring buffer: drop oldest if over limit
 		if fl.log.maxLines > 0 && len(fl.log.lines) > fl.log.maxLines {
 			dropped := len(fl.log.lines) - fl.log.maxLines
-			fl.log.lines = fl.log.lines[dropped:]
+			fl.log.dropLines(dropped)
 			if !fl.log.following {
 				newScrollY := fl.log.layer.ScrollY() - dropped
 				if newScrollY >= 0 {
@@ -0,0 +1,102 @@
+package glyph
+
+// fixedGlyphs holds the 7×13 X11 misc-fixed font (public domain) for
+// U+0020–U+007E and U+FFFD: 13 rows per glyph, each a byte whose low six
+// bits are pixels, most significant leftmost.
+const fixedGlyphs = "" +
+	"\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00" + // space
+	"\x00\x00\x04\x04\x04\x04\x04\x04\x04\x00\x04\x00\x00" + // !
+	"\x00\x00\x0a\x0a\x0a\x00\x00\x00\x00\x00\x00\x00\x00" + // "
+	"\x00\x00\x00\x0a\x0a\x1f\x0a\x1f\x0a\x0a\x00\x00\x00" + // #
+	"\x00\x00\x00\x04\x0f\x14\x0e\x05\x1e\x04\x00\x00\x00" + // $
+	"\x00\x00\x11\x29\x12\x04\x04\x08\x12\x25\x22\x00\x00" + // %
+	"\x00\x00\x00\x00\x18\x24\x24\x18\x25\x22\x1d\x00\x00" + // &
+	"\x00\x00\x04\x04\x04\x00\x00\x00\x00\x00\x00\x00\x00" + // '
+	"\x00\x00\x02\x04\x04\x08\x08\x08\x04\x04\x02\x00\x00" + // (
+	"\x00\x00\x08\x04\x04\x02\x02\x02\x04\x04\x08\x00\x00" + // )
+	"\x00\x00\x00\x00\x12\x0c\x3f\x0c\x12\x00\x00\x00\x00" + // *
+	"\x00\x00\x00\x00\x04\x04\x1f\x04\x04\x00\x00\x00\x00" + // +
+	"\x00\x00\x00\x00\x00\x00\x00\x00\x00\x0e\x0c\x10\x00" + // ,
+	"\x00\x00\x00\x00\x00\x00\x1f\x00\x00\x00\x00\x00\x00" + // -
+	"\x00\x00\x00\x00\x00\x00\x00\x00\x00\x04\x0e\x04\x00" + // .
+	"\x00\x00\x01\x01\x02\x02\x04\x08\x08\x10\x10\x00\x00" + // /
+	"\x00\x00\x0c\x12\x21\x21\x21\x21\x21\x12\x0c\x00\x00" + // 0
+	"\x00\x00\x04\x0c\x14\x04\x04\x04\x04\x04\x1f\x00\x00" + // 1
+	"\x00\x00\x1e\x21\x21\x01\x02\x0c\x10\x20\x3f\x00\x00" + // 2
+	"\x00\x00\x3f\x01\x02\x04\x0e\x01\x01\x21\x1e\x00\x00" + // 3
+	"\x00\x00\x02\x06\x0a\x12\x22\x22\x3f\x02\x02\x00\x00" + // 4
+	"\x00\x00\x3f\x20\x20\x2e\x31\x01\x01\x21\x1e\x00\x00" + // 5
+	"\x00\x00\x0e\x10\x20\x20\x2e\x31\x21\x21\x1e\x00\x00" + // 6
+	"\x00\x00\x3f\x01\x02\x04\x04\x08\x08\x10\x10\x00\x00" + // 7
+	"\x00\x00\x1e\x21\x21\x21\x1e\x21\x21\x21\x1e\x00\x00" + // 8
+	"\x00\x00\x1e\x21\x21\x23\x1d\x01\x01\x02\x1c\x00\x00" + // 9
+	"\x00\x00\x00\x00\x04\x0e\x04\x00\x00\x04\x0e\x04\x00" + // :
+	"\x00\x00\x00\x00\x04\x0e\x04\x00\x00\x0e\x0c\x10\x00" + // ;
+	"\x00\x00\x01\x02\x04\x08\x10\x08\x04\x02\x01\x00\x00" + // <
+	"\x00\x00\x00\x00\x00\x3f\x00\x00\x3f\x00\x00\x00\x00" + // =
+	"\x00\x00\x10\x08\x04\x02\x01\x02\x04\x08\x10\x00\x00" + // >
+	"\x00\x00\x1e\x21\x21\x01\x02\x04\x04\x00\x04\x00\x00" + // ?
+	"\x00\x00\x1e\x21\x21\x27\x29\x2b\x25\x20\x1e\x00\x00" + // @
+	"\x00\x00\x0c\x12\x21\x21\x21\x3f\x21\x21\x21\x00\x00" + // A
+	"\x00\x00\x3e\x11\x11\x11\x1e\x11\x11\x11\x3e\x00\x00" + // B
+	"\x00\x00\x1e\x21\x20\x20\x20\x20\x20\x21\x1e\x00\x00" + // C
+	"\x00\x00\x3e\x11\x11\x11\x11\x11\x11\x11\x3e\x00\x00" + // D
+	"\x00\x00\x3f\x20\x20\x20\x3c\x20\x20\x20\x3f\x00\x00" + // E
+	"\x00\x00\x3f\x20\x20\x20\x3c\x20\x20\x20\x20\x00\x00" + // F
+	"\x00\x00\x1e\x21\x20\x20\x20\x27\x21\x23\x1d\x00\x00" + // G
+	"\x00\x00\x21\x21\x21\x21\x3f\x21\x21\x21\x21\x00\x00" + // H
+	"\x00\x00\x1f\x04\x04\x04\x04\x04\x04\x04\x1f\x00\x00" + // I
+	"\x00\x00\x07\x02\x02\x02\x02\x02\x02\x22\x1c\x00\x00" + // J
+	"\x00\x00\x21\x22\x24\x28\x30\x28\x24\x22\x21\x00\x00" + // K
+	"\x00\x00\x20\x20\x20\x20\x20\x20\x20\x20\x3f\x00\x00" + // L
+	"\x00\x00\x21\x33\x33\x2d\x2d\x21\x21\x21\x21\x00\x00" + // M
+	"\x00\x00\x21\x21\x31\x29\x25\x23\x21\x21\x21\x00\x00" + // N
+	"\x00\x00\x1e\x21\x21\x21\x21\x21\x21\x21\x1e\x00\x00" + // O
+	"\x00\x00\x3e\x21\x21\x21\x3e\x20\x20\x20\x20\x00\x00" + // P
+	"\x00\x00\x1e\x21\x21\x21\x21\x21\x29\x25\x1e\x01\x00" + // Q
+	"\x00\x00\x3e\x21\x21\x21\x3e\x28\x24\x22\x21\x00\x00" + // R
+	"\x00\x00\x1e\x21\x20\x20\x1e\x01\x01\x21\x1e\x00\x00" + // S
+	"\x00\x00\x1f\x04\x04\x04\x04\x04\x04\x04\x04\x00\x00" + // T
+	"\x00\x00\x21\x21\x21\x21\x21\x21\x21\x21\x1e\x00\x00" + // U
+	"\x00\x00\x21\x21\x21\x12\x12\x12\x0c\x0c\x0c\x00\x00" + // V
+	"\x00\x00\x21\x21\x21\x21\x2d\x2d\x33\x33\x21\x00\x00" + // W
+	"\x00\x00\x21\x21\x12\x12\x0c\x12\x12\x21\x21\x00\x00" + // X
+	"\x00\x00\x11\x11\x0a\x0a\x04\x04\x04\x04\x04\x00\x00" + // Y
+	"\x00\x00\x3f\x01\x02\x04\x0c\x08\x10\x20\x3f\x00\x00" + // Z
+	"\x00\x1e\x10\x10\x10\x10\x10\x10\x10\x10\x10\x1e\x00" + // [
+	"\x00\x00\x10\x10\x08\x08\x04\x02\x02\x01\x01\x00\x00" + // \
+	"\x00\x1e\x02\x02\x02\x02\x02\x02\x02\x02\x02\x1e\x00" + // ]
+	"\x00\x00\x04\x0a\x11\x00\x00\x00\x00\x00\x00\x00\x00" + // ^
+	"\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x3f\x00" + // _
+	"\x00\x08\x04\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00" + // `
+	"\x00\x00\x00\x00\x00\x1e\x01\x1f\x21\x23\x1d\x00\x00" + // a
+	"\x00\x00\x20\x20\x20\x2e\x31\x21\x21\x31\x2e\x00\x00" + // b
+	"\x00\x00\x00\x00\x00\x1e\x21\x20\x20\x21\x1e\x00\x00" + // c
+	"\x00\x00\x01\x01\x01\x1d\x23\x21\x21\x23\x1d\x00\x00" + // d
+	"\x00\x00\x00\x00\x00\x1e\x21\x3f\x20\x21\x1e\x00\x00" + // e
+	"\x00\x00\x0e\x11\x10\x10\x3c\x10\x10\x10\x10\x00\x00" + // f
+	"\x00\x00\x00\x00\x00\x1d\x22\x22\x1c\x20\x1e\x21\x1e" + // g
+	"\x00\x00\x20\x20\x20\x2e\x31\x21\x21\x21\x21\x00\x00" + // h
+	"\x00\x00\x00\x04\x00\x0c\x04\x04\x04\x04\x1f\x00\x00" + // i
+	"\x00\x00\x00\x01\x00\x03\x01\x01\x01\x01\x11\x11\x0e" + // j
+	"\x00\x00\x20\x20\x20\x22\x24\x38\x24\x22\x21\x00\x00" + // k
+	"\x00\x00\x0c\x04\x04\x04\x04\x04\x04\x04\x1f\x00\x00" + // l
+	"\x00\x00\x00\x00\x00\x1a\x15\x15\x15\x15\x11\x00\x00" + // m
+	"\x00\x00\x00\x00\x00\x2e\x31\x21\x21\x21\x21\x00\x00" + // n
+	"\x00\x00\x00\x00\x00\x1e\x21\x21\x21\x21\x1e\x00\x00" + // o
+	"\x00\x00\x00\x00\x00\x2e\x31\x21\x31\x2e\x20\x20\x20" + // p
+	"\x00\x00\x00\x00\x00\x1d\x23\x21\x23\x1d\x01\x01\x01" + // q
+	"\x00\x00\x00\x00\x00\x2e\x11\x10\x10\x10\x10\x00\x00" + // r
+	"\x00\x00\x00\x00\x00\x1e\x21\x18\x06\x21\x1e\x00\x00" + // s
+	"\x00\x00\x00\x10\x10\x3c\x10\x10\x10\x11\x0e\x00\x00" + // t
+	"\x00\x00\x00\x00\x00\x21\x21\x21\x21\x23\x1d\x00\x00" + // u
+	"\x00\x00\x00\x00\x00\x11\x11\x11\x0a\x0a\x04\x00\x00" + // v
+	"\x00\x00\x00\x00\x00\x11\x11\x15\x15\x15\x0a\x00\x00" + // w
+	"\x00\x00\x00\x00\x00\x21\x12\x0c\x0c\x12\x21\x00\x00" + // x
+	"\x00\x00\x00\x00\x00\x21\x21\x21\x23\x1d\x01\x21\x1e" + // y
+	"\x00\x00\x00\x00\x00\x3f\x02\x04\x08\x10\x3f\x00\x00" + // z
+	"\x00\x07\x08\x08\x08\x04\x18\x04\x08\x08\x08\x07\x00" + // {
+	"\x00\x00\x04\x04\x04\x04\x04\x04\x04\x04\x04\x00\x00" + // |
+	"\x00\x1c\x02\x02\x02\x04\x03\x04\x02\x02\x02\x1c\x00" + // }
+	"\x00\x00\x09\x15\x12\x00\x00\x00\x00\x00\x00\x00\x00" + // ~
+	"\x00\x00\x0e\x1b\x15\x1d\x1b\x1b\x1f\x1b\x0e\x00\x00" // �
@@ -1,6 +1,10 @@
 package glyph
 
-import "github.com/kungfusheep/riffkey"
+import (
+	"sync/atomic"
+
+	"github.com/kungfusheep/riffkey"
+)
 
 // focusable is implemented by components that can receive keyboard focus.
 type focusable interface {
@@ -35,6 +39,10 @@ type FocusManager struct {
 	push    func(r *riffkey.Router)
 	pop     func()
 	pushed  bool // whether a sub-router is currently pushed
+	render  func()
+
+	// when set, items ignore keys other than focus cycling
+	disabled atomic.Bool
 
 	// bindings that should be available on every sub-router
 	// (e.g., Enter for form submit)
@@ -115,6 +123,9 @@ func (fm *FocusManager) OnBlur(fn func()) *FocusManager {
 
 // BlurCurrent unfocuses the current item and pops its sub-router.
 func (fm *FocusManager) BlurCurrent() {
+	if len(fm.items) == 0 {
+		return
+	}
 	if fm.pushed && fm.pop != nil {
 		fm.pop()
 		fm.pushed = false
@@ -125,6 +136,19 @@ func (fm *FocusManager) BlurCurrent() {
 	}
 }
 
+// resume refocuses the current item and pushes its sub-router after
+// BlurCurrent, e.g. when a wizard returns to a step.
+func (fm *FocusManager) resume() {
+	if fm.pushed || len(fm.items) == 0 {
+		return
+	}
+	fm.items[fm.current].focusable.setFocused(true)
+	fm.pushCurrent()
+	if fm.onChange != nil {
+		fm.onChange(fm.current)
+	}
+}
+
 // Next moves focus to the next component.
 func (fm *FocusManager) Next() {
 	fm.moveFocus(1)
@@ -202,8 +226,22 @@ func (fm *FocusManager) Current() int {
 	return fm.current
 }
 
+// SetDisabled blocks or restores input to the managed items. Focus can
+// still move while disabled. Safe to call from any goroutine.
+func (fm *FocusManager) SetDisabled(disabled bool) {
+	fm.disabled.Store(disabled)
+}
+
+// Disabled reports whether input to the managed items is blocked.
+func (fm *FocusManager) Disabled() bool {
+	return fm.disabled.Load()
+}
+
 // HandleKey routes a key to the currently focused component.
 func (fm *FocusManager) HandleKey(k riffkey.Key) bool {
+	if fm.disabled.Load() {
+		return true
+	}
 	if len(fm.handlers) == 0 {
 		return false
 	}
@@ -218,10 +256,10 @@ func (fm *FocusManager) HandleKey(k riffkey.Key) bool {
 func (fm *FocusManager) bindings() []binding {
 	var binds []binding
 	if fm.nextKey != "" {
-		binds = append(binds, binding{pattern: fm.nextKey, handler: func(_ riffkey.Match) { fm.Next() }})
+		binds = append(binds, binding{pattern: fm.nextKey, handler: func(_ riffkey.Match) { fm.Next() }, name: "focus.next", desc: "next field", group: "Focus"})
 	}
 	if fm.prevKey != "" {
-		binds = append(binds, binding{pattern: fm.prevKey, handler: func(_ riffkey.Match) { fm.Prev() }})
+		binds = append(binds, binding{pattern: fm.prevKey, handler: func(_ riffkey.Match) { fm.Prev() }, name: "focus.prev", desc: "previous field", group: "Focus"})
 	}
 	return binds
 }
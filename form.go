@@ -1,9 +1,13 @@
 package glyph
 
 import (
+	"context"
 	"fmt"
+	"maps"
 	"regexp"
+	"slices"
 	"strings"
+	"sync"
 )
 
 // validatable is implemented by controls that support validation.
@@ -18,6 +22,7 @@ type FormField struct {
 	control any
 	err     string // validation error for this field
 	focused bool
+	group   bool // section heading with no control
 }
 
 // Field creates a form field pairing a label with any control component.
@@ -38,6 +43,25 @@ type FormC struct {
 	flexGrowPtr  *float32
 	gapCond      conditionNode
 	flexGrowCond conditionNode
+
+	// form-level validation
+	validate func() map[string]error
+	formErr  string // errors not tied to a field
+
+	// background submit, guarded by mu
+	onSubmitAsync func(ctx context.Context) error
+	mu            sync.Mutex
+	state         SubmitState
+	submitErr     error
+	cancel        context.CancelFunc
+	submits       int // successful OnSubmitAsync calls
+
+	// status line, written only by syncStatus on the render pass
+	status      string
+	statusStyle Style
+	busy        bool
+	seenSubmits int    // submits as of the last render pass
+	onSubmitted func() // run on the render pass after each successful submit
 }
 
 type FormFn func(fields ...FormField) *FormC
@@ -58,6 +82,9 @@ var Form FormFn = func(fields ...FormField) *FormC {
 
 	// auto-calculate label width from longest label + colon
 	for _, ff := range fields {
+		if ff.group {
+			continue
+		}
 		w := int16(len(ff.label) + 1) // +1 for ":"
 		if w > f.labelWidth {
 			f.labelWidth = w
@@ -77,20 +104,31 @@ var Form FormFn = func(fields ...FormField) *FormC {
 				ctrl.onBlur = func() {
 					fieldRef.err = ctrl.Err()
 				}
+				if ctrl.async != nil {
+					ctrl.async.onDone = func() {
+						fieldRef.err = ctrl.Err()
+					}
+				}
 			case *CheckboxC:
 				f.fm.Register(fc)
 				ctrl.onBlur = func() {
 					fieldRef.err = ctrl.Err()
 				}
 				f.fm.ItemBindings(
-					binding{pattern: "<Space>", handler: func() { ctrl.Toggle() }},
+					binding{pattern: "<Space>", handler: func() { ctrl.Toggle() }, name: "checkbox.toggle", desc: "toggle", group: "Checkbox"},
 				)
 			case *RadioC:
 				f.fm.Register(fc)
 				f.fm.ItemBindings(
-					binding{pattern: "j", handler: func() { ctrl.Next() }},
-					binding{pattern: "k", handler: func() { ctrl.Prev() }},
+					binding{pattern: "j", handler: func() { ctrl.Next() }, name: "radio.next", desc: "next option", group: "Radio"},
+					binding{pattern: "k", handler: func() { ctrl.Prev() }, name: "radio.prev", desc: "previous option", group: "Radio"},
 				)
+			case formControl:
+				f.fm.Register(fc)
+				ctrl.setOnBlur(func() {
+					fieldRef.err = ctrl.Err()
+				})
+				f.fm.ItemBindings(ctrl.formBindings(f.fm, f.submit)...)
 			default:
 				f.fm.Register(fc)
 			}
@@ -198,6 +236,36 @@ func (f FormFn) OnSubmit(fn func()) FormFn {
 	}
 }
 
+// OnSubmitAsync sets a callback that runs in the background when Enter is
+// pressed and every field is valid. Input is disabled and a status line
+// shows progress until it returns; pending async validators finish first.
+// ctx is cancelled by Cancel.
+func (f FormFn) OnSubmitAsync(fn func(ctx context.Context) error) FormFn {
+	return func(fields ...FormField) *FormC {
+		form := f(fields...)
+		form.onSubmitAsync = fn
+		return form
+	}
+}
+
+// Validate sets a form-level validator that runs after the field validators,
+// for rules that span fields. Errors are keyed by field label; an error whose
+// key matches no field shows below the form.
+//
+//	Form.Validate(func() map[string]error {
+//	    if pass != confirm {
+//	        return map[string]error{"Confirm": errors.New("passwords differ")}
+//	    }
+//	    return nil
+//	})(...)
+func (f FormFn) Validate(fn func() map[string]error) FormFn {
+	return func(fields ...FormField) *FormC {
+		form := f(fields...)
+		form.validate = fn
+		return form
+	}
+}
+
 // Grow sets the flex grow factor. Accepts float32, float64, int, or *float32 for dynamic values.
 func (f FormFn) Grow(g any) FormFn {
 	return func(fields ...FormField) *FormC {
@@ -250,9 +318,21 @@ func (f *FormC) FocusManager() *FocusManager {
 	return f.fm
 }
 
-// ValidateAll runs validation on all fields that have VOnSubmit set.
-// Returns true if all fields are valid.
+// ValidateAll runs validation on all fields that have VOnSubmit set, then
+// the form-level validator. Returns true if all fields are valid; a field
+// whose async check is still pending counts as not yet valid.
 func (f *FormC) ValidateAll() bool {
+	valid := f.validateFields()
+	for i := range f.fields {
+		if in, ok := f.fields[i].control.(*InputC); ok && in.Pending() {
+			valid = false
+		}
+	}
+	return valid
+}
+
+// validateFields runs the synchronous field and form-level validators.
+func (f *FormC) validateFields() bool {
 	valid := true
 	for i := range f.fields {
 		ff := &f.fields[i]
@@ -264,14 +344,72 @@ func (f *FormC) ValidateAll() bool {
 			}
 		}
 	}
+	f.formErr = ""
+	if f.validate == nil {
+		return valid
+	}
+	errs := f.validate()
+	var other []string
+	for _, key := range slices.Sorted(maps.Keys(errs)) {
+		err := errs[key]
+		if err == nil {
+			continue
+		}
+		valid = false
+		if ff := f.field(key); ff != nil {
+			if ff.err == "" {
+				ff.err = err.Error()
+			}
+		} else {
+			other = append(other, err.Error())
+		}
+	}
+	f.formErr = strings.Join(other, "; ")
 	return valid
 }
 
+// field returns the field with the given label, or nil.
+func (f *FormC) field(label string) *FormField {
+	for i := range f.fields {
+		if !f.fields[i].group && f.fields[i].label == label {
+			return &f.fields[i]
+		}
+	}
+	return nil
+}
+
+// State returns the progress of the last OnSubmitAsync submit.
+func (f *FormC) State() SubmitState {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.state
+}
+
+// SubmitErr returns the error from the last failed submit, or nil.
+func (f *FormC) SubmitErr() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.submitErr
+}
+
+// Cancel cancels an in-flight OnSubmitAsync submit.
+func (f *FormC) Cancel() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.cancel != nil {
+		f.cancel()
+	}
+}
+
 // toTemplate builds the VBox of HBox rows with optional error display.
 func (f *FormC) toTemplate() any {
 	rows := make([]any, 0, len(f.fields)*2)
 	for i := range f.fields {
 		ff := &f.fields[i]
+		if ff.group {
+			rows = append(rows, HBox(Text("").Width(1), Text(ff.label).Style(f.labelStyle.Bold())))
+			continue
+		}
 		ls := f.labelStyle
 		ls.Align = AlignRight
 		ls = ls.MarginTRBL(0, 1, 0, 0)
@@ -291,6 +429,19 @@ func (f *FormC) toTemplate() any {
 		}
 	}
 
+	if f.validate != nil || f.onSubmitAsync != nil {
+		spacer := Text("").Width(f.labelWidth+2).MarginTRBL(0, 1, 0, 0)
+		rows = append(rows, If(&f.formErr).Then(
+			HBox(spacer, Text(&f.formErr).FG(Red)),
+		))
+	}
+	if f.onSubmitAsync != nil {
+		spacer := Text("").Width(f.labelWidth+2).MarginTRBL(0, 1, 0, 0)
+		rows = append(rows, If(&f.status).Then(
+			HBox(spacer, If(&f.busy).Then(Spinner(nil)), Text(&f.status).Style(&f.statusStyle)),
+		))
+	}
+
 	var box VBoxFn
 	if f.gapCond != nil {
 		box = VBox.Gap(f.gapCond)
@@ -312,11 +463,124 @@ func (f *FormC) toTemplate() any {
 	return box(rows...)
 }
 
+// submit runs the OnSubmit callback, if any, or starts a background submit.
+func (f *FormC) submit() {
+	if f.onSubmitAsync != nil {
+		f.submitAsync()
+		return
+	}
+	if f.onSubmit != nil {
+		f.onSubmit()
+	}
+}
+
+// submitAsync validates the form and, if nothing is invalid, runs
+// OnSubmitAsync in the background with input disabled.
+func (f *FormC) submitAsync() {
+	f.mu.Lock()
+	busy := f.state == SubmitValidating || f.state == Submitting
+	f.mu.Unlock()
+	if busy || !f.validateFields() {
+		return
+	}
+	var pending []*asyncCheck
+	for i := range f.fields {
+		if in, ok := f.fields[i].control.(*InputC); ok && in.Pending() {
+			pending = append(pending, in.async)
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	f.mu.Lock()
+	f.cancel = cancel
+	f.mu.Unlock()
+	if len(pending) > 0 {
+		f.setState(SubmitValidating, nil)
+	} else {
+		f.setState(Submitting, nil)
+	}
+	go f.runSubmit(ctx, cancel, pending)
+}
+
+// runSubmit waits for pending async validators, then calls OnSubmitAsync.
+func (f *FormC) runSubmit(ctx context.Context, cancel context.CancelFunc, pending []*asyncCheck) {
+	defer cancel()
+
+	for _, a := range pending {
+		if err := a.wait(ctx); err != nil {
+			f.setState(SubmitFailed, err)
+			return
+		}
+	}
+	// fields show their results via onDone on the next render
+	valid := true
+	for _, a := range pending {
+		if a.failed() {
+			valid = false
+		}
+	}
+	if !valid {
+		f.setState(SubmitIdle, nil)
+		return
+	}
+
+	f.setState(Submitting, nil)
+	if err := f.onSubmitAsync(ctx); err != nil {
+		f.setState(SubmitFailed, err)
+		return
+	}
+	f.mu.Lock()
+	f.submits++
+	f.mu.Unlock()
+	f.setState(Submitted, nil)
+}
+
+// setState records submit progress and disables input while busy.
+func (f *FormC) setState(state SubmitState, err error) {
+	f.mu.Lock()
+	f.state, f.submitErr = state, err
+	f.fm.SetDisabled(state == SubmitValidating || state == Submitting)
+	f.mu.Unlock()
+	f.requestRender()
+}
+
+// syncStatus updates the status line from the submit progress. It runs as
+// a template eval, so the view never reads state the submit goroutine writes.
+func (f *FormC) syncStatus() {
+	f.mu.Lock()
+	state, err, submits := f.state, f.submitErr, f.submits
+	f.mu.Unlock()
+	if submits != f.seenSubmits {
+		f.seenSubmits = submits
+		if f.onSubmitted != nil {
+			f.onSubmitted()
+		}
+	}
+	f.busy = state == SubmitValidating || state == Submitting
+	switch state {
+	case SubmitValidating:
+		f.status, f.statusStyle = " validating…", Style{Attr: AttrDim}
+	case Submitting:
+		f.status, f.statusStyle = " submitting…", Style{Attr: AttrDim}
+	case Submitted:
+		f.status, f.statusStyle = "✓ submitted", Style{FG: Green}
+	case SubmitFailed:
+		f.status, f.statusStyle = "✗ "+err.Error(), Style{FG: Red}
+	default:
+		f.status = ""
+	}
+}
+
+func (f *FormC) requestRender() {
+	if f.fm.render != nil {
+		f.fm.render()
+	}
+}
+
 // bindings returns Form-specific bindings only.
 // Tab/Shift-Tab are handled by the FocusManager in wireBindings.
 func (f *FormC) bindings() []binding {
-	if f.onSubmit != nil {
-		enterBinding := binding{pattern: "<Enter>", handler: f.onSubmit}
+	if f.onSubmit != nil || f.onSubmitAsync != nil {
+		enterBinding := binding{pattern: "<Enter>", handler: f.submit, name: "form.submit", desc: "submit", group: "Form"}
 		f.fm.subBindings = append(f.fm.subBindings, enterBinding)
 		return []binding{enterBinding}
 	}
@@ -0,0 +1,919 @@
+package glyph
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// formControl is implemented by controls that wire their own keys and blur
+// callback when registered with a FocusManager or placed in a Form.
+type formControl interface {
+	focusable
+	validatable
+	// formBindings returns the control's keys while focused. submit is the
+	// form's Enter handler, or nil outside a form.
+	formBindings(fm *FocusManager, submit func()) []binding
+	setOnBlur(fn func())
+}
+
+// managedControl is implemented by controls registered with ManagedBy.
+type managedControl interface {
+	focusManager() *FocusManager
+}
+
+// controlState holds the focus and validation state shared by the
+// Select, NumberInput, Slider and DatePicker controls.
+type controlState struct {
+	focused    bool
+	manager    *FocusManager
+	onBlur     func()
+	validate   func() error
+	validateOn ValidateOn
+	err        string
+}
+
+// Focused returns whether the control currently has focus.
+func (c *controlState) Focused() bool { return c.focused }
+
+// Err returns the current validation error message, or empty string if valid.
+func (c *controlState) Err() string { return c.err }
+
+func (c *controlState) runValidation() {
+	if c.validate == nil {
+		return
+	}
+	if err := c.validate(); err != nil {
+		c.err = err.Error()
+	} else {
+		c.err = ""
+	}
+}
+
+// changed runs VOnChange validation after the value changes.
+func (c *controlState) changed() {
+	if c.validateOn&VOnChange != 0 {
+		c.runValidation()
+	}
+}
+
+func (c *controlState) setOnBlur(fn func())             { c.onBlur = fn }
+func (c *controlState) focusManager() *FocusManager     { return c.manager }
+func (c *controlState) focusBinding() *textInputBinding { return nil }
+
+func (c *controlState) setFocused(focused bool) {
+	was := c.focused
+	c.focused = focused
+	if was && !focused {
+		if c.validateOn&VOnBlur != 0 {
+			c.runValidation()
+		}
+		if c.onBlur != nil {
+			c.onBlur()
+		}
+	}
+}
+
+func (c *controlState) setValidator(fn func() error, when []ValidateOn) {
+	c.validate = fn
+	if len(when) > 0 {
+		c.validateOn = when[0]
+	} else {
+		c.validateOn = VOnBlur | VOnSubmit
+	}
+}
+
+// manage registers a control with fm along with its keys.
+func manage(fm *FocusManager, c formControl, state *controlState) {
+	state.manager = fm
+	state.focused = false
+	fm.Register(c)
+	fm.ItemBindings(c.formBindings(fm, nil)...)
+}
+
+// ============================================================================
+// Select
+// ============================================================================
+
+// SelectC is a collapsible single-choice control. Closed, it shows the
+// selected item; opened, it lists the items beneath with a filter query.
+// Typing filters the list with fzf syntax, Up/Down move and Enter chooses.
+type SelectC[T any] struct {
+	controlState
+	items       *[]T
+	label       func(*T) string
+	selected    *int
+	filter      *Filter[T]
+	query       InputState
+	tib         *textInputBinding
+	open        bool
+	cursor      int
+	maxVisible  int
+	placeholder string
+	width       int
+	style       Style
+	onChange    func(*T)
+}
+
+// Select creates a select over items, bound to the index of the chosen
+// item (-1 for none). label returns the text shown for an item.
+//
+//	Select(&regions, &region, func(r *Region) string { return r.Name }).
+//	    Placeholder("choose a region")
+func Select[T any](items *[]T, selected *int, label func(*T) string) *SelectC[T] {
+	s := &SelectC[T]{
+		items:      items,
+		label:      label,
+		selected:   selected,
+		filter:     NewFilter(items, label),
+		maxVisible: 8,
+	}
+	s.tib = &textInputBinding{
+		value:    &s.query.Value,
+		cursor:   &s.query.Cursor,
+		onChange: s.search,
+	}
+	return s
+}
+
+// Placeholder sets the text shown when nothing is selected.
+func (s *SelectC[T]) Placeholder(p string) *SelectC[T] {
+	s.placeholder = p
+	return s
+}
+
+// MaxVisible sets how many items the open list shows. Default is 8.
+func (s *SelectC[T]) MaxVisible(n int) *SelectC[T] {
+	s.maxVisible = max(n, 1)
+	return s
+}
+
+// Width sets the control width. Default fits the longest item.
+func (s *SelectC[T]) Width(w int) *SelectC[T] {
+	s.width = w
+	return s
+}
+
+// Style sets the text style.
+func (s *SelectC[T]) Style(st Style) *SelectC[T] {
+	s.style = st
+	return s
+}
+
+// OnChange sets a callback that fires when an item is chosen.
+func (s *SelectC[T]) OnChange(fn func(*T)) *SelectC[T] {
+	s.onChange = fn
+	return s
+}
+
+// Validate sets a validation function and when it runs. fn receives nil
+// when nothing is selected. If when is omitted, defaults to VOnBlur|VOnSubmit.
+func (s *SelectC[T]) Validate(fn func(*T) error, when ...ValidateOn) *SelectC[T] {
+	s.setValidator(func() error { return fn(s.Selected()) }, when)
+	return s
+}
+
+// ManagedBy registers this select with a FocusManager.
+func (s *SelectC[T]) ManagedBy(fm *FocusManager) *SelectC[T] {
+	manage(fm, s, &s.controlState)
+	return s
+}
+
+// Selected returns the chosen item, or nil.
+func (s *SelectC[T]) Selected() *T {
+	if i := *s.selected; i >= 0 && i < len(*s.items) {
+		return &(*s.items)[i]
+	}
+	return nil
+}
+
+// Index returns the index of the chosen item, or -1.
+func (s *SelectC[T]) Index() int {
+	return *s.selected
+}
+
+// IsOpen reports whether the item list is showing.
+func (s *SelectC[T]) IsOpen() bool { return s.open }
+
+// Open shows the item list with the cursor on the chosen item. The list
+// is read from items each time it opens.
+func (s *SelectC[T]) Open() {
+	s.open = true
+	s.filter.Reset()
+	s.cursor = 0
+	for i := range s.filter.Len() {
+		if s.filter.OriginalIndex(i) == *s.selected {
+			s.cursor = i
+			break
+		}
+	}
+}
+
+// Close hides the item list and clears the query.
+func (s *SelectC[T]) Close() {
+	s.open = false
+	s.query.Clear()
+	s.filter.Reset()
+}
+
+// Move moves the list cursor by delta, opening the list if needed.
+func (s *SelectC[T]) Move(delta int) {
+	if !s.open {
+		s.Open()
+		return
+	}
+	s.cursor = max(0, min(s.cursor+delta, s.filter.Len()-1))
+}
+
+// Choose selects the item under the list cursor and closes the list.
+func (s *SelectC[T]) Choose() {
+	// items may have shrunk since the list was filtered
+	if idx := s.filter.OriginalIndex(s.cursor); idx >= 0 && idx < len(*s.items) {
+		*s.selected = idx
+		if s.onChange != nil {
+			s.onChange(&(*s.items)[idx])
+		}
+		s.changed()
+	}
+	s.Close()
+}
+
+func (s *SelectC[T]) focusBinding() *textInputBinding { return s.tib }
+
+func (s *SelectC[T]) setFocused(focused bool) {
+	if !focused && s.open {
+		s.Close()
+	}
+	s.controlState.setFocused(focused)
+}
+
+func (s *SelectC[T]) search(q string) {
+	s.filter.Update(q)
+	s.open = true
+	s.cursor = 0
+}
+
+func (s *SelectC[T]) formBindings(fm *FocusManager, submit func()) []binding {
+	return []binding{
+		{pattern: "<Enter>", handler: func() {
+			switch {
+			case s.open:
+				s.Choose()
+			case submit != nil:
+				submit()
+			default:
+				s.Open()
+			}
+		}, name: "select.choose", desc: "choose", group: "Select"},
+		{pattern: "<Escape>", handler: func() {
+			if s.open {
+				s.Close()
+			} else {
+				fm.BlurCurrent()
+			}
+		}, name: "select.close", desc: "close", group: "Select"},
+		{pattern: "<Down>", handler: func() { s.Move(1) }, name: "select.next", desc: "next option", group: "Select"},
+		{pattern: "<Up>", handler: func() { s.Move(-1) }, name: "select.prev", desc: "previous option", group: "Select"},
+		{pattern: "<C-n>", handler: func() { s.Move(1) }},
+		{pattern: "<C-p>", handler: func() { s.Move(-1) }},
+	}
+}
+
+func (s *SelectC[T]) toTemplate() any {
+	return Widget(s.measure, s.render)
+}
+
+func (s *SelectC[T]) measure(availW int16) (w, h int16) {
+	width := s.width
+	if width == 0 {
+		width = runewidth.StringWidth(s.placeholder)
+		for i := range *s.items {
+			width = max(width, runewidth.StringWidth(s.label(&(*s.items)[i])))
+		}
+		width += 4 // list marker and arrow
+	}
+	h = 1
+	if s.open {
+		h += 1 + int16(max(min(s.filter.Len(), s.maxVisible), 1))
+	}
+	return min(int16(width), availW), h
+}
+
+func (s *SelectC[T]) render(buf *Buffer, x, y, w, h int16) {
+	bx, by, bw := int(x), int(y), int(w)
+
+	st := s.style
+	text := s.placeholder
+	if item := s.Selected(); item != nil {
+		text = s.label(item)
+	} else {
+		st = st.Dim()
+	}
+	if s.focused && !s.open {
+		st = st.Inverse()
+	}
+	arrow := "▾"
+	if s.open {
+		arrow = "▴"
+	}
+	buf.FillRect(bx, by, bw, 1, Cell{Rune: ' ', Style: st})
+	buf.WriteStringFast(bx, by, text, st, bw-2)
+	buf.WriteStringFast(bx+bw-1, by, arrow, st, 1)
+	if !s.open {
+		return
+	}
+
+	buf.WriteStringFast(bx, by+1, "> "+s.query.Value, s.style, bw)
+	drawCursor(buf, bx+2, by+1, bx+bw, s.query.Value, s.query.Cursor, s.style)
+
+	n := s.filter.Len()
+	if n == 0 {
+		buf.WriteStringFast(bx+2, by+2, "no matches", s.style.Dim(), bw-2)
+		return
+	}
+	rows := min(n, s.maxVisible)
+	top := max(0, min(s.cursor-rows+1, n-rows))
+	for r := range rows {
+		i := top + r
+		st, mark := s.style, "  "
+		if i == s.cursor {
+			st, mark = st.Inverse(), "▸ "
+		}
+		if s.filter.OriginalIndex(i) == *s.selected {
+			st = st.Bold()
+		}
+		ry := by + 2 + r
+		buf.FillRect(bx, ry, bw, 1, Cell{Rune: ' ', Style: st})
+		buf.WriteStringFast(bx, ry, mark+s.label(&s.filter.Items[i]), st, bw)
+	}
+}
+
+// ============================================================================
+// NumberInput
+// ============================================================================
+
+// numeric is the set of types a NumberInput can edit.
+type numeric interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// NumberInputC is a numeric text field with bounds and a step for Up/Down.
+type NumberInputC[N numeric] struct {
+	controlState
+	value    *N
+	min, max N
+	hasMin   bool
+	hasMax   bool
+	step     N
+	decimals int
+	kind     reflect.Kind
+	bits     int // size of N, for parsing and its limits
+	field    InputState
+	tib      *textInputBinding
+	width    int
+	style    Style
+	check    func(N) error
+}
+
+// NumberInput creates a numeric input bound to value. Typing edits the
+// number; Up/Down step it within the bounds.
+//
+//	NumberInput(&port).Range(1, 65535)
+//	NumberInput(&ratio).Step(0.05).Decimals(2)
+func NumberInput[N numeric](value *N) *NumberInputC[N] {
+	n := &NumberInputC[N]{
+		value:    value,
+		step:     1,
+		decimals: -1,
+		kind:     reflect.TypeFor[N]().Kind(),
+		bits:     reflect.TypeFor[N]().Bits(),
+		width:    10,
+	}
+	n.field.Value = n.format(*value)
+	n.field.Cursor = len(n.field.Value)
+	n.tib = &textInputBinding{
+		value:    &n.field.Value,
+		cursor:   &n.field.Cursor,
+		onChange: n.edit,
+	}
+	n.setValidator(n.validateValue, nil)
+	return n
+}
+
+// Min sets the lower bound.
+func (n *NumberInputC[N]) Min(v N) *NumberInputC[N] {
+	n.min, n.hasMin = v, true
+	return n
+}
+
+// Max sets the upper bound.
+func (n *NumberInputC[N]) Max(v N) *NumberInputC[N] {
+	n.max, n.hasMax = v, true
+	return n
+}
+
+// Range sets both bounds.
+func (n *NumberInputC[N]) Range(lo, hi N) *NumberInputC[N] {
+	return n.Min(lo).Max(hi)
+}
+
+// Step sets the amount Up/Down change the value by. Default is 1.
+func (n *NumberInputC[N]) Step(v N) *NumberInputC[N] {
+	n.step = v
+	return n
+}
+
+// Decimals sets the decimal places shown for floats. Default is the
+// shortest representation.
+func (n *NumberInputC[N]) Decimals(d int) *NumberInputC[N] {
+	n.decimals = d
+	n.field.Value = n.format(*n.value)
+	n.field.Cursor = len(n.field.Value)
+	return n
+}
+
+// Width sets the field width. Default is 10.
+func (n *NumberInputC[N]) Width(w int) *NumberInputC[N] {
+	n.width = w
+	return n
+}
+
+// Style sets the text style.
+func (n *NumberInputC[N]) Style(s Style) *NumberInputC[N] {
+	n.style = s
+	return n
+}
+
+// Validate adds a validation function run after the number parses and is
+// within bounds. If when is omitted, defaults to VOnBlur|VOnSubmit.
+func (n *NumberInputC[N]) Validate(fn func(N) error, when ...ValidateOn) *NumberInputC[N] {
+	n.check = fn
+	n.setValidator(n.validateValue, when)
+	return n
+}
+
+// ManagedBy registers this input with a FocusManager.
+func (n *NumberInputC[N]) ManagedBy(fm *FocusManager) *NumberInputC[N] {
+	manage(fm, n, &n.controlState)
+	return n
+}
+
+// Value returns the current value.
+func (n *NumberInputC[N]) Value() N { return *n.value }
+
+// SetValue sets the value, clamped to the bounds.
+func (n *NumberInputC[N]) SetValue(v N) {
+	*n.value = n.clamp(float64(v))
+	n.field.Value = n.format(*n.value)
+	n.field.Cursor = len(n.field.Value)
+}
+
+// Increment steps the value up.
+func (n *NumberInputC[N]) Increment() { n.stepBy(1) }
+
+// Decrement steps the value down.
+func (n *NumberInputC[N]) Decrement() { n.stepBy(-1) }
+
+func (n *NumberInputC[N]) stepBy(dir float64) {
+	n.SetValue(n.clamp(float64(*n.value) + dir*float64(n.step)))
+	n.changed()
+}
+
+func (n *NumberInputC[N]) clamp(v float64) N {
+	if n.hasMin {
+		v = max(v, float64(n.min))
+	}
+	if n.hasMax {
+		v = min(v, float64(n.max))
+	}
+	// N's own range, so a step past it stops rather than wrapping
+	switch {
+	case n.isFloat():
+		if n.bits == 32 {
+			v = max(-math.MaxFloat32, min(v, math.MaxFloat32))
+		}
+	case n.isUnsigned():
+		hi := uint64(1)<<n.bits - 1
+		if v <= 0 {
+			return 0
+		}
+		if v >= float64(hi) {
+			return N(hi)
+		}
+	default:
+		hi := int64(1)<<(n.bits-1) - 1
+		if v >= float64(hi) {
+			return N(hi)
+		}
+		if v <= float64(-hi-1) {
+			return N(-hi - 1)
+		}
+	}
+	return N(v)
+}
+
+func (n *NumberInputC[N]) isFloat() bool {
+	return n.kind == reflect.Float32 || n.kind == reflect.Float64
+}
+
+func (n *NumberInputC[N]) isUnsigned() bool {
+	return n.kind >= reflect.Uint && n.kind <= reflect.Uintptr
+}
+
+func (n *NumberInputC[N]) format(v N) string {
+	switch {
+	case n.isFloat():
+		return strconv.FormatFloat(float64(v), 'f', n.decimals, 64)
+	case n.isUnsigned():
+		return strconv.FormatUint(uint64(v), 10)
+	}
+	return strconv.FormatInt(int64(v), 10)
+}
+
+func (n *NumberInputC[N]) parse(s string) (N, error) {
+	switch {
+	case n.isFloat():
+		f, err := strconv.ParseFloat(s, n.bits)
+		return N(f), err
+	case n.isUnsigned():
+		u, err := strconv.ParseUint(s, 10, n.bits)
+		return N(u), err
+	}
+	i, err := strconv.ParseInt(s, 10, n.bits)
+	return N(i), err
+}
+
+// edit writes typed text through to the bound value when it parses.
+func (n *NumberInputC[N]) edit(s string) {
+	if v, err := n.parse(s); err == nil {
+		*n.value = v
+	}
+	n.changed()
+}
+
+func (n *NumberInputC[N]) validateValue() error {
+	v, err := n.parse(n.field.Value)
+	if err != nil {
+		return fmt.Errorf("invalid number")
+	}
+	if n.hasMin && v < n.min {
+		return fmt.Errorf("min %s", n.format(n.min))
+	}
+	if n.hasMax && v > n.max {
+		return fmt.Errorf("max %s", n.format(n.max))
+	}
+	if n.check != nil {
+		return n.check(v)
+	}
+	return nil
+}
+
+func (n *NumberInputC[N]) focusBinding() *textInputBinding { return n.tib }
+
+func (n *NumberInputC[N]) setFocused(focused bool) {
+	if focused && !n.focused {
+		// pick up changes made to the bound value while unfocused
+		n.field.Value = n.format(*n.value)
+		n.field.Cursor = len(n.field.Value)
+	}
+	n.controlState.setFocused(focused)
+}
+
+func (n *NumberInputC[N]) formBindings(fm *FocusManager, submit func()) []binding {
+	return []binding{
+		{pattern: "<Up>", handler: n.Increment, name: "number.increment", desc: "increment", group: "Number"},
+		{pattern: "<Down>", handler: n.Decrement, name: "number.decrement", desc: "decrement", group: "Number"},
+	}
+}
+
+func (n *NumberInputC[N]) toTemplate() any {
+	return Widget(
+		func(availW int16) (int16, int16) { return min(int16(n.width), availW), 1 },
+		n.render,
+	)
+}
+
+func (n *NumberInputC[N]) render(buf *Buffer, x, y, w, h int16) {
+	text := n.field.Value
+	if !n.focused {
+		text = n.format(*n.value)
+	}
+	buf.WriteStringFast(int(x), int(y), text, n.style, int(w))
+	if n.focused {
+		drawCursor(buf, int(x), int(y), int(x+w), text, n.field.Cursor, n.style)
+	}
+}
+
+// drawCursor draws a block cursor over the rune at byte offset cursor of
+// text, which starts at x. Nothing is drawn at or beyond limit.
+func drawCursor(buf *Buffer, x, y, limit int, text string, cursor int, st Style) {
+	cursor = max(0, min(cursor, len(text)))
+	cx := x + runewidth.StringWidth(text[:cursor])
+	if cx >= limit {
+		return
+	}
+	r := ' '
+	if cursor < len(text) {
+		r, _ = utf8.DecodeRuneInString(text[cursor:])
+	}
+	buf.Set(cx, y, Cell{Rune: r, Style: st.Inverse()})
+}
+
+// ============================================================================
+// Slider
+// ============================================================================
+
+// SliderC is a horizontal slider bound to a float64 and drawn as a
+// progress bar. Left/Right step the value, Home/End jump to the bounds.
+type SliderC struct {
+	controlState
+	value  *float64
+	min    float64
+	max    float64
+	step   float64
+	width  int
+	style  Style
+	format func(float64) string
+}
+
+// Slider creates a slider bound to value over [0, 1].
+//
+//	Slider(&volume).Range(0, 100).Step(5).Label(func(v float64) string {
+//	    return fmt.Sprintf("%.0f%%", v)
+//	})
+func Slider(value *float64) *SliderC {
+	return &SliderC{value: value, max: 1, step: 0.05, width: 20}
+}
+
+// Range sets the bounds. The step becomes a twentieth of the range unless
+// set explicitly afterwards.
+func (s *SliderC) Range(lo, hi float64) *SliderC {
+	s.min, s.max = lo, hi
+	s.step = (hi - lo) / 20
+	return s
+}
+
+// Step sets the amount each key press moves the value.
+func (s *SliderC) Step(v float64) *SliderC {
+	s.step = v
+	return s
+}
+
+// Width sets the bar width. Default is 20.
+func (s *SliderC) Width(w int) *SliderC {
+	s.width = w
+	return s
+}
+
+// Style sets the bar style.
+func (s *SliderC) Style(st Style) *SliderC {
+	s.style = st
+	return s
+}
+
+// Label shows the value after the bar, formatted by fn.
+func (s *SliderC) Label(fn func(float64) string) *SliderC {
+	s.format = fn
+	return s
+}
+
+// Validate sets a validation function and when it runs.
+// If when is omitted, defaults to VOnBlur|VOnSubmit.
+func (s *SliderC) Validate(fn func(float64) error, when ...ValidateOn) *SliderC {
+	s.setValidator(func() error { return fn(*s.value) }, when)
+	return s
+}
+
+// ManagedBy registers this slider with a FocusManager.
+func (s *SliderC) ManagedBy(fm *FocusManager) *SliderC {
+	manage(fm, s, &s.controlState)
+	return s
+}
+
+// Value returns the current value.
+func (s *SliderC) Value() float64 { return *s.value }
+
+// SetValue sets the value, clamped to the range.
+func (s *SliderC) SetValue(v float64) {
+	*s.value = max(s.min, min(v, s.max))
+	s.changed()
+}
+
+// Increment moves the value up one step.
+func (s *SliderC) Increment() { s.SetValue(*s.value + s.step) }
+
+// Decrement moves the value down one step.
+func (s *SliderC) Decrement() { s.SetValue(*s.value - s.step) }
+
+func (s *SliderC) formBindings(fm *FocusManager, submit func()) []binding {
+	return []binding{
+		{pattern: "<Right>", handler: s.Increment, name: "slider.increase", desc: "increase", group: "Slider"},
+		{pattern: "<Left>", handler: s.Decrement, name: "slider.decrease", desc: "decrease", group: "Slider"},
+		{pattern: "l", handler: s.Increment, name: "slider.increase", desc: "increase", group: "Slider"},
+		{pattern: "h", handler: s.Decrement, name: "slider.decrease", desc: "decrease", group: "Slider"},
+		{pattern: "<Home>", handler: func() { s.SetValue(s.min) }, name: "slider.minimum", desc: "minimum", group: "Slider"},
+		{pattern: "<End>", handler: func() { s.SetValue(s.max) }, name: "slider.maximum", desc: "maximum", group: "Slider"},
+	}
+}
+
+func (s *SliderC) toTemplate() any {
+	return Widget(s.measure, s.render)
+}
+
+func (s *SliderC) measure(availW int16) (int16, int16) {
+	w := s.width
+	if s.format != nil {
+		w += 1 + runewidth.StringWidth(s.format(*s.value))
+	}
+	return min(int16(w), availW), 1
+}
+
+func (s *SliderC) render(buf *Buffer, x, y, w, h int16) {
+	ratio := 0.0
+	if s.max > s.min {
+		ratio = (*s.value - s.min) / (s.max - s.min)
+	}
+	barW := min(s.width, int(w))
+	buf.WriteProgressBar(int(x), int(y), barW, float32(ratio), s.style)
+	if s.format != nil {
+		st := s.style
+		if s.focused {
+			st = st.Bold()
+		}
+		buf.WriteStringFast(int(x)+barW+1, int(y), s.format(*s.value), st, int(w)-barW-1)
+	}
+}
+
+// ============================================================================
+// DatePicker
+// ============================================================================
+
+// DatePickerC is a month calendar bound to a time.Time. Arrow keys or
+// h/j/k/l move by day and week, PgUp/PgDn (or H/L) by month and t jumps
+// to today. Moving the cursor selects the date.
+type DatePickerC struct {
+	controlState
+	value     *time.Time
+	min, max  time.Time
+	weekStart time.Weekday
+	style     Style
+	now       func() time.Time
+}
+
+// DatePicker creates a calendar bound to value. A zero value shows today
+// until a date is picked.
+func DatePicker(value *time.Time) *DatePickerC {
+	return &DatePickerC{value: value, weekStart: time.Monday, now: time.Now}
+}
+
+// Min sets the earliest selectable date.
+func (d *DatePickerC) Min(t time.Time) *DatePickerC {
+	d.min = t
+	return d
+}
+
+// Max sets the latest selectable date.
+func (d *DatePickerC) Max(t time.Time) *DatePickerC {
+	d.max = t
+	return d
+}
+
+// WeekStart sets the first day of the week. Default is Monday.
+func (d *DatePickerC) WeekStart(w time.Weekday) *DatePickerC {
+	d.weekStart = w
+	return d
+}
+
+// Style sets the calendar text style.
+func (d *DatePickerC) Style(s Style) *DatePickerC {
+	d.style = s
+	return d
+}
+
+// Validate sets a validation function and when it runs.
+// If when is omitted, defaults to VOnBlur|VOnSubmit.
+func (d *DatePickerC) Validate(fn func(time.Time) error, when ...ValidateOn) *DatePickerC {
+	d.setValidator(func() error { return fn(*d.value) }, when)
+	return d
+}
+
+// ManagedBy registers this picker with a FocusManager.
+func (d *DatePickerC) ManagedBy(fm *FocusManager) *DatePickerC {
+	manage(fm, d, &d.controlState)
+	return d
+}
+
+// Value returns the selected date; zero if none.
+func (d *DatePickerC) Value() time.Time { return *d.value }
+
+// SetValue selects t, clamped to the days of the bounds.
+func (d *DatePickerC) SetValue(t time.Time) {
+	if !d.min.IsZero() && dayOf(t).Before(dayOf(d.min)) {
+		t = d.min
+	}
+	if !d.max.IsZero() && dayOf(t).After(dayOf(d.max)) {
+		t = d.max
+	}
+	*d.value = t
+	d.changed()
+}
+
+// MoveDays moves the selection by n days.
+func (d *DatePickerC) MoveDays(n int) {
+	d.SetValue(d.cursor().AddDate(0, 0, n))
+}
+
+// MoveMonths moves the selection by n months, keeping the day where the
+// target month is long enough.
+func (d *DatePickerC) MoveMonths(n int) {
+	c := d.cursor()
+	first := time.Date(c.Year(), c.Month()+time.Month(n), 1, c.Hour(), c.Minute(), c.Second(), c.Nanosecond(), c.Location())
+	day := min(c.Day(), daysIn(first))
+	d.SetValue(first.AddDate(0, 0, day-1))
+}
+
+// cursor is the selected date, or today when none is selected.
+func (d *DatePickerC) cursor() time.Time {
+	if d.value.IsZero() {
+		return dayOf(d.now())
+	}
+	return *d.value
+}
+
+// dayOf truncates t to midnight in its location.
+func dayOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func daysIn(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, t.Location()).Day()
+}
+
+func (d *DatePickerC) formBindings(fm *FocusManager, submit func()) []binding {
+	return []binding{
+		{pattern: "<Left>", handler: func() { d.MoveDays(-1) }, name: "date.prev_day", desc: "previous day", group: "Date"},
+		{pattern: "<Right>", handler: func() { d.MoveDays(1) }, name: "date.next_day", desc: "next day", group: "Date"},
+		{pattern: "<Up>", handler: func() { d.MoveDays(-7) }, name: "date.prev_week", desc: "previous week", group: "Date"},
+		{pattern: "<Down>", handler: func() { d.MoveDays(7) }, name: "date.next_week", desc: "next week", group: "Date"},
+		{pattern: "h", handler: func() { d.MoveDays(-1) }, name: "date.prev_day", desc: "previous day", group: "Date"},
+		{pattern: "l", handler: func() { d.MoveDays(1) }, name: "date.next_day", desc: "next day", group: "Date"},
+		{pattern: "k", handler: func() { d.MoveDays(-7) }, name: "date.prev_week", desc: "previous week", group: "Date"},
+		{pattern: "j", handler: func() { d.MoveDays(7) }, name: "date.next_week", desc: "next week", group: "Date"},
+		{pattern: "<PgUp>", handler: func() { d.MoveMonths(-1) }, name: "date.prev_month", desc: "previous month", group: "Date"},
+		{pattern: "<PgDn>", handler: func() { d.MoveMonths(1) }, name: "date.next_month", desc: "next month", group: "Date"},
+		{pattern: "H", handler: func() { d.MoveMonths(-1) }, name: "date.prev_month", desc: "previous month", group: "Date"},
+		{pattern: "L", handler: func() { d.MoveMonths(1) }, name: "date.next_month", desc: "next month", group: "Date"},
+		{pattern: "t", handler: func() { d.SetValue(dayOf(d.now())) }, name: "date.today", desc: "today", group: "Date"},
+	}
+}
+
+func (d *DatePickerC) toTemplate() any {
+	// month title, weekday header and six week rows of "dd "
+	return Widget(
+		func(availW int16) (int16, int16) { return min(20, availW), 8 },
+		d.render,
+	)
+}
+
+func (d *DatePickerC) render(buf *Buffer, x, y, w, h int16) {
+	bx, by := int(x), int(y)
+	c := d.cursor()
+
+	title := c.Format("January 2006")
+	buf.WriteStringFast(bx+max(0, (20-len(title))/2), by, title, d.style.Bold(), int(w))
+
+	for i := range 7 {
+		name := (d.weekStart + time.Weekday(i)) % 7
+		buf.WriteStringFast(bx+i*3, by+1, name.String()[:2], d.style.Dim(), int(w)-i*3)
+	}
+
+	first := time.Date(c.Year(), c.Month(), 1, 0, 0, 0, 0, c.Location())
+	offset := (int(first.Weekday()) - int(d.weekStart) + 7) % 7
+	n := d.now()
+	for day := 1; day <= daysIn(first); day++ {
+		cell := offset + day - 1
+		cx, cy := bx+(cell%7)*3, by+2+cell/7
+		st := d.style
+		date := first.AddDate(0, 0, day-1)
+		if (!d.min.IsZero() && date.Before(dayOf(d.min))) || (!d.max.IsZero() && date.After(dayOf(d.max))) {
+			st = st.Dim()
+		}
+		if c.Year() == n.Year() && c.Month() == n.Month() && day == n.Day() {
+			st = st.Underline()
+		}
+		if day == c.Day() && !d.value.IsZero() {
+			st = st.Bold()
+			if d.focused {
+				st = st.Inverse()
+			}
+		}
+		buf.WriteStringFast(cx, cy, fmt.Sprintf("%2d", day), st, int(x+w)-cx)
+	}
+}
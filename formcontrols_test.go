@@ -0,0 +1,309 @@
+package glyph
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// itemKey invokes the handler bound to pattern for the item at idx.
+func itemKey(t *testing.T, fm *FocusManager, idx int, pattern string) {
+	t.Helper()
+	for _, b := range fm.items[idx].bindings {
+		if b.pattern == pattern {
+			b.handler.(func())()
+			return
+		}
+	}
+	t.Fatalf("item %d has no %s binding", idx, pattern)
+}
+
+func TestSelectFollowsItems(t *testing.T) {
+	var items []string
+	sel := -1
+	s := Select(&items, &sel, func(r *string) string { return *r })
+
+	// items loaded after Select are listed when it opens
+	items = []string{"a", "b", "c", "d"}
+	s.Open()
+	s.Move(3)
+	s.Choose()
+	if sel != 3 {
+		t.Fatalf("sel = %d, want 3", sel)
+	}
+
+	// choosing after items shrank under the open list selects nothing
+	s.Open()
+	items = items[:1]
+	s.Choose()
+	if sel != 3 || s.IsOpen() {
+		t.Errorf("sel = %d open = %v", sel, s.IsOpen())
+	}
+}
+
+func TestSelectFilterAndChoose(t *testing.T) {
+	regions := []string{"eu-west-1", "eu-central-1", "us-east-1", "ap-south-1"}
+	sel := -1
+	fm := NewFocusManager()
+	s := Select(&regions, &sel, func(r *string) string { return *r }).
+		Placeholder("region").
+		Validate(func(r *string) error {
+			if r == nil {
+				return errors.New("required")
+			}
+			return nil
+		}).
+		ManagedBy(fm)
+
+	buf := NewBuffer(20, 6)
+	tmpl := Build(VBox(s))
+	tmpl.Execute(buf, 20, 6)
+	if got := buf.GetLine(0); !strings.HasPrefix(got, "region") || !strings.HasSuffix(got, "▾") {
+		t.Errorf("closed row = %q", got)
+	}
+
+	s.runValidation()
+	if s.Err() != "required" {
+		t.Errorf("err = %q, want required", s.Err())
+	}
+
+	// typing filters and opens the list
+	s.query.Value, s.query.Cursor = "^us", 3
+	s.tib.onChange("^us")
+	if !s.IsOpen() || s.filter.Len() != 1 {
+		t.Fatalf("open=%v matches=%d", s.IsOpen(), s.filter.Len())
+	}
+	buf.Clear()
+	tmpl.Execute(buf, 20, 6)
+	if got := buf.GetLine(1); got != "> ^us" {
+		t.Errorf("query row = %q", got)
+	}
+	if got := buf.GetLine(2); got != "▸ us-east-1" {
+		t.Errorf("item row = %q", got)
+	}
+
+	itemKey(t, fm, 0, "<Enter>")
+	if sel != 2 || s.IsOpen() || s.query.Value != "" {
+		t.Errorf("sel=%d open=%v query=%q", sel, s.IsOpen(), s.query.Value)
+	}
+
+	// reopening puts the cursor on the chosen item
+	itemKey(t, fm, 0, "<Down>")
+	itemKey(t, fm, 0, "<Down>")
+	itemKey(t, fm, 0, "<Enter>")
+	if sel != 3 {
+		t.Errorf("sel = %d, want 3", sel)
+	}
+	itemKey(t, fm, 0, "<Down>")
+	itemKey(t, fm, 0, "<Escape>")
+	if s.IsOpen() || sel != 3 {
+		t.Errorf("escape: open=%v sel=%d", s.IsOpen(), sel)
+	}
+}
+
+func TestNumberInput(t *testing.T) {
+	port := 8080
+	n := NumberInput(&port).Range(1, 65535).Step(1000)
+	if n.field.Value != "8080" {
+		t.Fatalf("text = %q", n.field.Value)
+	}
+	for range 60 {
+		n.Increment()
+	}
+	if port != 65535 {
+		t.Errorf("port = %d, want clamped 65535", port)
+	}
+
+	n.edit("123")
+	n.field.Value = "123"
+	if port != 123 {
+		t.Errorf("typed port = %d", port)
+	}
+	n.field.Value = "12x"
+	n.edit("12x")
+	n.runValidation()
+	if n.Err() != "invalid number" || port != 123 {
+		t.Errorf("err = %q port = %d", n.Err(), port)
+	}
+	n.field.Value = "70000"
+	n.runValidation()
+	if n.Err() != "max 65535" {
+		t.Errorf("err = %q", n.Err())
+	}
+
+	var count uint8
+	u := NumberInput(&count)
+	u.Decrement()
+	if count != 0 {
+		t.Errorf("unsigned underflow: %d", count)
+	}
+	count = 255
+	u.Increment()
+	if count != 255 {
+		t.Errorf("unsigned overflow: %d", count)
+	}
+
+	// the type's own range bounds typing and stepping
+	var small int8 = 5
+	i8 := NumberInput(&small)
+	i8.edit("200")
+	if small != 5 {
+		t.Errorf("typed 200 into int8: %d", small)
+	}
+	i8.SetValue(-128)
+	i8.Decrement()
+	if small != -128 {
+		t.Errorf("int8 underflow: %d", small)
+	}
+	var big int64 = math.MaxInt64
+	NumberInput(&big).Increment()
+	if big != math.MaxInt64 {
+		t.Errorf("int64 overflow: %d", big)
+	}
+
+	ratio := 0.5
+	f := NumberInput(&ratio).Step(0.25).Decimals(2).Max(1)
+	f.Increment()
+	f.Increment()
+	if ratio != 1 || f.field.Value != "1.00" {
+		t.Errorf("ratio = %v text = %q", ratio, f.field.Value)
+	}
+}
+
+func TestSlider(t *testing.T) {
+	vol := 50.0
+	s := Slider(&vol).Range(0, 100).Step(10).Width(10).Label(func(v float64) string {
+		return strings.Repeat("|", int(v/25))
+	})
+	s.Increment()
+	if vol != 60 {
+		t.Errorf("vol = %v", vol)
+	}
+	s.SetValue(150)
+	if vol != 100 {
+		t.Errorf("vol = %v, want clamped", vol)
+	}
+	s.SetValue(50)
+
+	buf := NewBuffer(20, 1)
+	Build(VBox(s)).Execute(buf, 20, 1)
+	full := 0
+	for x := range 10 {
+		if buf.Get(x, 0).Rune == '█' {
+			full++
+		}
+	}
+	if full != 5 {
+		t.Errorf("filled cells = %d, want 5", full)
+	}
+	if got := buf.GetLine(0); !strings.HasSuffix(got, " ||") {
+		t.Errorf("label row = %q", got)
+	}
+}
+
+func TestDatePicker(t *testing.T) {
+	day := time.Date(2026, time.January, 31, 0, 0, 0, 0, time.UTC)
+	d := DatePicker(&day)
+
+	d.MoveMonths(1)
+	if !day.Equal(time.Date(2026, time.February, 28, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("month step = %v", day)
+	}
+	d.MoveDays(1)
+	if day.Month() != time.March || day.Day() != 1 {
+		t.Errorf("day step = %v", day)
+	}
+	d.Min(time.Date(2026, time.February, 25, 0, 0, 0, 0, time.UTC))
+	d.MoveDays(-7)
+	if day.Day() != 25 {
+		t.Errorf("min clamp = %v", day)
+	}
+
+	// bounds with a time of day still allow their whole day
+	d.Min(time.Date(2026, time.February, 25, 9, 30, 0, 0, time.UTC))
+	d.SetValue(time.Date(2026, time.February, 25, 0, 0, 0, 0, time.UTC))
+	if !day.Equal(time.Date(2026, time.February, 25, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("min day rejected: %v", day)
+	}
+	d.Min(time.Date(2026, time.February, 25, 0, 0, 0, 0, time.UTC))
+
+	// March 2026 starts on a Sunday
+	day = time.Date(2026, time.March, 4, 0, 0, 0, 0, time.UTC)
+	buf := NewBuffer(20, 8)
+	Build(VBox(d)).Execute(buf, 20, 8)
+	if got := buf.GetLine(0); strings.TrimSpace(got) != "March 2026" {
+		t.Errorf("title = %q", got)
+	}
+	if got := buf.GetLine(1); got != "Mo Tu We Th Fr Sa Su" {
+		t.Errorf("weekdays = %q", got)
+	}
+	if got := buf.GetLine(2); got != "                   1" {
+		t.Errorf("first week = %q", got)
+	}
+	if got := buf.Get(7, 3); got.Rune != '4' || got.Style.Attr&AttrBold == 0 {
+		t.Errorf("selected day cell = %+v", got)
+	}
+
+	d.WeekStart(time.Sunday)
+	buf.Clear()
+	Build(VBox(d)).Execute(buf, 20, 8)
+	if got := buf.GetLine(2); got != " 1  2  3  4  5  6  7" {
+		t.Errorf("sunday first week = %q", got)
+	}
+}
+
+func TestFormWiresNewControls(t *testing.T) {
+	sizes := []string{"small", "large"}
+	size := -1
+	qty := 0
+	when := time.Time{}
+	submitted := false
+
+	form := Form.OnSubmit(func() { submitted = true })(
+		Field("Size", Select(&sizes, &size, func(s *string) string { return *s }).
+			Validate(func(s *string) error {
+				if s == nil {
+					return errors.New("required")
+				}
+				return nil
+			})),
+		Field("Qty", NumberInput(&qty).Min(1)),
+		Field("When", DatePicker(&when).Validate(func(t time.Time) error {
+			if t.IsZero() {
+				return errors.New("pick a date")
+			}
+			return nil
+		})),
+	)
+	fm := form.FocusManager()
+	if len(fm.items) != 3 {
+		t.Fatalf("registered %d items, want 3", len(fm.items))
+	}
+
+	if form.ValidateAll() {
+		t.Fatal("expected validation errors")
+	}
+	errs := []string{form.fields[0].err, form.fields[1].err, form.fields[2].err}
+	if strings.Join(errs, ",") != "required,min 1,pick a date" {
+		t.Errorf("errors = %q", errs)
+	}
+
+	// Enter on a closed select submits the form
+	f := form.fields[0].control.(*SelectC[string])
+	itemKey(t, fm, 0, "<Enter>")
+	if !submitted || f.IsOpen() {
+		t.Errorf("submitted=%v open=%v", submitted, f.IsOpen())
+	}
+
+	// blur validation reports into the field
+	fm.Next()
+	if form.fields[0].err != "required" {
+		t.Errorf("blur err = %q", form.fields[0].err)
+	}
+
+	if tmpl := Build(form); tmpl.pendingFocusManager != fm {
+		t.Error("form focus manager not collected")
+	}
+}
@@ -3,6 +3,7 @@ module github.com/kungfusheep/glyph
 go 1.25.1
 
 require (
+	github.com/BurntSushi/toml v1.6.0
 	github.com/junegunn/fzf v0.67.0
 	github.com/kungfusheep/riffkey v0.0.0-20260216102013-df19649e3a0d
 	github.com/mattn/go-runewidth v0.0.19
@@ -10,7 +11,6 @@ require (
 )
 
 require (
-	github.com/BurntSushi/toml v1.6.0 // indirect
 	github.com/clipperhouse/stringish v0.1.1 // indirect
 	github.com/clipperhouse/uax29/v2 v2.4.0 // indirect
 	github.com/mattn/go-isatty v0.0.20 // indirect
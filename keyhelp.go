@@ -0,0 +1,493 @@
+package glyph
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/kungfusheep/riffkey"
+)
+
+// KeyHelp describes a key binding for ShowHelp, the which-key popup and
+// KeyHints.
+type KeyHelp struct {
+	Pattern string // as registered, e.g. "<C-d>" or "<leader>f"
+	Keys    string // display form, e.g. "ctrl-d"
+	Action  string // action name for keymaps, if any
+	Desc    string
+	Group   string
+}
+
+// keyEntry is a registered binding with its expanded key sequence.
+type keyEntry struct {
+	KeyHelp
+	keys   []riffkey.Key
+	run    riffkey.Handler // for the CommandPalette
+	action string          // as returned by bind, for Describe and Group
+}
+
+// defaultLeader is the key <leader> expands to until LeaderKey is called.
+const defaultLeader = `\`
+
+// Describe sets the description of the binding registered by the last
+// Handle call, shown by ShowHelp, the which-key popup and KeyHints.
+//
+//	app.Handle("q", app.Stop).Describe("quit")
+func (a *App) Describe(desc string) *App {
+	a.describeKey(a.router, a.lastAction, desc, "")
+	return a
+}
+
+// Group sets the group the last handled binding is listed under in ShowHelp.
+func (a *App) Group(name string) *App {
+	a.describeKey(a.router, a.lastAction, "", name)
+	return a
+}
+
+// Describe sets the description of the view's last handled binding.
+func (vb *ViewBuilder) Describe(desc string) *ViewBuilder {
+	vb.app.describeKey(vb.router, vb.lastAction, desc, "")
+	return vb
+}
+
+// Group sets the group the view's last handled binding is listed under.
+func (vb *ViewBuilder) Group(name string) *ViewBuilder {
+	vb.app.describeKey(vb.router, vb.lastAction, "", name)
+	return vb
+}
+
+// Describe sets the description of the route's last handled binding.
+func (rb *RouteBuilder) Describe(desc string) *RouteBuilder {
+	rb.view.Describe(desc)
+	return rb
+}
+
+// Group sets the group the route's last handled binding is listed under.
+func (rb *RouteBuilder) Group(name string) *RouteBuilder {
+	rb.view.Group(name)
+	return rb
+}
+
+// LeaderKey sets the key that <leader> in binding patterns stands for
+// (default: backslash). Call it before registering bindings.
+//
+//	app.LeaderKey("<Space>")
+//	app.Handle("<leader>f", openFile).Describe("open file")
+func (a *App) LeaderKey(key string) *App {
+	a.leader = key
+	return a
+}
+
+// WhichKey enables a popup listing the possible continuations while a
+// multi-key sequence such as "g" or "<leader>" is pending.
+func (a *App) WhichKey(enabled bool) *App {
+	a.whichKey = enabled
+	return a
+}
+
+// ActiveKeys returns the bindings that apply now: the focused component's
+// first, then the current view's.
+func (a *App) ActiveKeys() []KeyHelp {
+	var keys []KeyHelp
+	seen := make(map[string]bool)
+	for _, r := range a.activeRouters() {
+		for _, e := range a.keyHelp[r] {
+			if !seen[e.Keys] {
+				seen[e.Keys] = true
+				keys = append(keys, e.KeyHelp)
+			}
+		}
+	}
+	return keys
+}
+
+// activeRouters returns the top of the input stack and the view beneath it.
+func (a *App) activeRouters() []*riffkey.Router {
+	base := a.router
+	if r, ok := a.viewRouters[a.currentView]; ok {
+		base = r
+	}
+	top := a.input.Current()
+	if top == nil || top == base {
+		return []*riffkey.Router{base}
+	}
+	return []*riffkey.Router{top, base}
+}
+
+// expandLeader replaces <leader> in pattern with the leader key.
+func (a *App) expandLeader(pattern string) string {
+	const alias = "<leader>"
+	i := strings.Index(strings.ToLower(pattern), alias)
+	if i < 0 {
+		return pattern
+	}
+	leader := a.leader
+	if leader == "" {
+		leader = defaultLeader
+	}
+	return pattern[:i] + leader + a.expandLeader(pattern[i+len(alias):])
+}
+
+// noteKey records binding b of action, registered on r under pattern. A
+// later binding for the same keys replaces the earlier one, as it does in
+// the router.
+func (a *App) noteKey(r *riffkey.Router, pattern, action string, b binding, h riffkey.Handler) {
+	if a.keyHelp == nil {
+		a.keyHelp = make(map[*riffkey.Router][]keyEntry)
+	}
+	keys := riffkey.ParsePattern(a.expandLeader(pattern))
+	if len(keys) == 0 {
+		return
+	}
+	e := keyEntry{KeyHelp: KeyHelp{Pattern: pattern, Keys: keyLabel(keys), Action: b.name, Desc: b.desc, Group: b.group}, keys: keys, run: h, action: action}
+	entries := a.keyHelp[r]
+	if i := slices.IndexFunc(entries, func(o keyEntry) bool { return slices.Equal(o.keys, keys) }); i >= 0 {
+		entries[i] = e
+		return
+	}
+	a.keyHelp[r] = append(entries, e)
+}
+
+// describeKey sets the description and/or group of the bindings recorded
+// for action on r, under whichever keys the keymap gave it.
+func (a *App) describeKey(r *riffkey.Router, action, desc, group string) {
+	set := func(kh *KeyHelp) {
+		if desc != "" {
+			kh.Desc = desc
+		}
+		if group != "" {
+			kh.Group = group
+		}
+	}
+	entries := a.keyHelp[r]
+	for i := range entries {
+		if entries[i].action == action {
+			set(&entries[i].KeyHelp)
+		}
+	}
+	// an unbound named action is still listed in the command palette
+	for i := range a.commands {
+		if c := &a.commands[i]; c.router == r && c.label == action {
+			set(&c.KeyHelp)
+		}
+	}
+}
+
+// continuations returns the pending key prefix and the bindings that
+// complete it on the active router.
+func (a *App) continuations() ([]riffkey.Key, []KeyHelp) {
+	_, pending := a.input.Pending()
+	if len(pending) == 0 {
+		return nil, nil
+	}
+	var next []KeyHelp
+	for _, e := range a.keyHelp[a.input.Current()] {
+		if len(e.keys) > len(pending) && slices.Equal(e.keys[:len(pending)], pending) {
+			kh := e.KeyHelp
+			kh.Keys = keyLabel(e.keys[len(pending):])
+			next = append(next, kh)
+		}
+	}
+	slices.SortStableFunc(next, func(x, y KeyHelp) int { return strings.Compare(x.Keys, y.Keys) })
+	return pending, next
+}
+
+// keyLabel formats a key sequence for display, e.g. "gg" or "ctrl-w j".
+func keyLabel(keys []riffkey.Key) string {
+	plain := !slices.ContainsFunc(keys, func(k riffkey.Key) bool {
+		return k.Mod != riffkey.ModNone || k.Special != riffkey.SpecialNone
+	})
+	var sb strings.Builder
+	for i, k := range keys {
+		if i > 0 && !plain {
+			sb.WriteByte(' ')
+		}
+		if k.Mod&riffkey.ModCtrl != 0 {
+			sb.WriteString("ctrl-")
+		}
+		if k.Mod&riffkey.ModAlt != 0 {
+			sb.WriteString("alt-")
+		}
+		if k.Mod&riffkey.ModShift != 0 {
+			sb.WriteString("shift-")
+		}
+		switch k.Special {
+		case riffkey.SpecialNone:
+			sb.WriteRune(k.Rune)
+		case riffkey.SpecialEnter:
+			sb.WriteString("enter")
+		case riffkey.SpecialBackspace:
+			sb.WriteString("backspace")
+		case riffkey.SpecialPageUp:
+			sb.WriteString("pgup")
+		case riffkey.SpecialPageDown:
+			sb.WriteString("pgdn")
+		default:
+			sb.WriteString(strings.ToLower(k.Special.String()))
+		}
+	}
+	return sb.String()
+}
+
+// keyName formats a binding pattern for display, e.g. "<C-b>" as "ctrl-b".
+func keyName(pattern string) string {
+	return keyLabel(riffkey.ParsePattern(pattern))
+}
+
+// =============================================================================
+// Help overlay
+// =============================================================================
+
+// helpOverlay is the state of the ShowHelp overlay.
+type helpOverlay struct {
+	keys   []KeyHelp
+	query  string
+	cursor int
+	scroll int
+}
+
+// helpRow is one line of the help overlay: a group header or a binding.
+type helpRow struct {
+	header string
+	key    KeyHelp
+}
+
+// ShowHelp opens an overlay listing the bindings active in the current view
+// and focused component, grouped by Group. Typing filters the list, Up and
+// Down scroll and Escape closes it.
+//
+//	app.Handle("?", app.ShowHelp).Describe("help")
+func (a *App) ShowHelp() {
+	if a.help != nil {
+		return
+	}
+	h := &helpOverlay{keys: a.ActiveKeys()}
+
+	r := riffkey.NewRouter().NoCounts()
+	r.Handle("<Esc>", func(_ riffkey.Match) { a.HideHelp() })
+	r.Handle("<Down>", func(_ riffkey.Match) { h.scroll++; a.RequestRender() })
+	r.Handle("<Up>", func(_ riffkey.Match) { h.scroll = max(h.scroll-1, 0); a.RequestRender() })
+	r.Handle("<C-n>", func(_ riffkey.Match) { h.scroll++; a.RequestRender() })
+	r.Handle("<C-p>", func(_ riffkey.Match) { h.scroll = max(h.scroll-1, 0); a.RequestRender() })
+	th := riffkey.NewTextHandler(&h.query, &h.cursor)
+	th.OnChange = func(string) { h.scroll = 0 }
+	r.HandleUnmatched(func(k riffkey.Key) bool {
+		th.HandleKey(k)
+		a.RequestRender()
+		return true
+	})
+
+	a.help = h
+	a.input.Push(r)
+	a.RequestRender()
+}
+
+// HideHelp closes the ShowHelp overlay.
+func (a *App) HideHelp() {
+	if a.help == nil {
+		return
+	}
+	a.help = nil
+	a.input.Pop()
+	a.RequestRender()
+}
+
+// HelpVisible reports whether the ShowHelp overlay is open.
+func (a *App) HelpVisible() bool {
+	return a.help != nil
+}
+
+// rows returns the bindings matching the query under their group headers.
+func (h *helpOverlay) rows() []helpRow {
+	q := strings.ToLower(h.query)
+	var groups []string
+	byGroup := make(map[string][]KeyHelp)
+	for _, k := range h.keys {
+		text := strings.ToLower(k.Keys + " " + k.Pattern + " " + k.Desc + " " + k.Group)
+		if q != "" && !strings.Contains(text, q) {
+			continue
+		}
+		g := k.Group
+		if g == "" {
+			g = "General"
+		}
+		if _, ok := byGroup[g]; !ok {
+			groups = append(groups, g)
+		}
+		byGroup[g] = append(byGroup[g], k)
+	}
+	var rows []helpRow
+	for _, g := range groups {
+		rows = append(rows, helpRow{header: g})
+		for _, k := range byGroup[g] {
+			rows = append(rows, helpRow{key: k})
+		}
+	}
+	return rows
+}
+
+// drawKeyOverlays draws the command palette, help overlay or which-key
+// popup over a frame.
+func (a *App) drawKeyOverlays(buf *Buffer, w, h int) {
+	if a.palette != nil {
+		a.palette.draw(buf, w, h)
+		return
+	}
+	if a.help != nil {
+		a.help.draw(buf, w, h)
+		return
+	}
+	if a.whichKey {
+		if prefix, next := a.continuations(); len(next) > 0 {
+			drawWhichKey(buf, w, h, keyLabel(prefix), next)
+		}
+	}
+}
+
+var (
+	overlayBorder = Style{Attr: AttrDim}
+	overlayKey    = Style{FG: Cyan, Attr: AttrBold}
+	overlayHeader = Style{Attr: AttrBold}
+	overlayDim    = Style{Attr: AttrDim}
+)
+
+// draw renders the help box centred in a w×h frame.
+func (o *helpOverlay) draw(buf *Buffer, w, h int) {
+	rows := o.rows()
+	bw := min(w, 64)
+	bh := min(h, max(len(rows)+3, 4))
+	if bw < 12 || bh < 4 {
+		return
+	}
+	x, y := (w-bw)/2, (h-bh)/2
+	buf.FillRect(x, y, bw, bh, EmptyCell())
+	buf.DrawBorder(x, y, bw, bh, BorderRounded, overlayBorder)
+	buf.WriteString(x+2, y, " Keys ", overlayHeader)
+
+	inner := bw - 4
+	if o.query == "" {
+		buf.WriteStringClipped(x+2, y+1, "type to filter", overlayDim, inner)
+	} else {
+		buf.WriteStringClipped(x+2, y+1, "/"+o.query, Style{}, inner)
+	}
+
+	keyW := 0
+	for _, r := range rows {
+		keyW = max(keyW, len([]rune(r.key.Keys)))
+	}
+	keyW = min(keyW, inner/2)
+
+	visible := bh - 3
+	o.scroll = max(min(o.scroll, len(rows)-visible), 0)
+	for i, r := range rows[o.scroll:min(o.scroll+visible, len(rows))] {
+		ry := y + 2 + i
+		if r.header != "" {
+			buf.WriteStringClipped(x+2, ry, r.header, overlayHeader, inner)
+			continue
+		}
+		buf.WriteStringClipped(x+4, ry, r.key.Keys, overlayKey, keyW)
+		buf.WriteStringClipped(x+6+keyW, ry, r.key.Desc, Style{}, inner-keyW-4)
+	}
+}
+
+// drawWhichKey renders the continuations of prefix in columns along the
+// bottom of a w×h frame.
+func drawWhichKey(buf *Buffer, w, h int, prefix string, next []KeyHelp) {
+	colW := 0
+	for _, k := range next {
+		colW = max(colW, len([]rune(k.Keys))+3+len([]rune(k.Desc)))
+	}
+	colW = min(colW+2, max(w-4, 1))
+	cols := max((w-4)/colW, 1)
+	nrows := min((len(next)+cols-1)/cols, max(h/2-2, 1))
+	bh := nrows + 2
+	if w < 8 || bh > h {
+		return
+	}
+	y := h - bh
+	buf.FillRect(0, y, w, bh, EmptyCell())
+	buf.DrawBorder(0, y, w, bh, BorderRounded, overlayBorder)
+	buf.WriteString(2, y, " "+prefix+" ", overlayHeader)
+
+	for i, k := range next[:min(len(next), nrows*cols)] {
+		cx := 2 + (i/nrows)*colW
+		cy := y + 1 + i%nrows
+		n := buf.WriteStringClipped(cx, cy, k.Keys, overlayKey, colW)
+		n += buf.WriteStringClipped(cx+n, cy, " → ", overlayDim, colW-n)
+		buf.WriteStringClipped(cx+n, cy, k.Desc, Style{}, colW-n)
+	}
+}
+
+// =============================================================================
+// KeyHints
+// =============================================================================
+
+// KeyHintsC shows the described bindings that apply now. See KeyHints.
+type KeyHintsC struct {
+	app      *App
+	max      int
+	sep      string
+	keyStyle Style
+	style    Style
+}
+
+// KeyHints shows a one-line footer of described bindings, e.g.
+// "j down · k up · ? help". The focused component's keys come first, then
+// the current view's; hints that do not fit are dropped.
+func KeyHints(app *App) KeyHintsC {
+	return KeyHintsC{app: app, sep: " · ", keyStyle: Style{Attr: AttrBold}, style: Style{Attr: AttrDim}}
+}
+
+// Max limits the number of hints shown.
+func (k KeyHintsC) Max(n int) KeyHintsC {
+	k.max = n
+	return k
+}
+
+// Separator sets the text between hints.
+func (k KeyHintsC) Separator(s string) KeyHintsC {
+	k.sep = s
+	return k
+}
+
+// KeyStyle sets the style of the keys.
+func (k KeyHintsC) KeyStyle(s Style) KeyHintsC {
+	k.keyStyle = s
+	return k
+}
+
+// Style sets the style of descriptions and separators.
+func (k KeyHintsC) Style(s Style) KeyHintsC {
+	k.style = s
+	return k
+}
+
+func (k KeyHintsC) toTemplate() any {
+	return Widget(
+		func(availW int16) (int16, int16) { return availW, 1 },
+		func(buf *Buffer, x, y, w, h int16) {
+			cx, limit := int(x), int(x+w)
+			shown := 0
+			for _, kh := range k.app.ActiveKeys() {
+				if kh.Desc == "" {
+					continue
+				}
+				if k.max > 0 && shown == k.max {
+					break
+				}
+				width := len([]rune(kh.Keys)) + 1 + len([]rune(kh.Desc))
+				if shown > 0 {
+					width += len([]rune(k.sep))
+				}
+				if cx+width > limit {
+					break
+				}
+				if shown > 0 {
+					cx += buf.WriteString(cx, int(y), k.sep, k.style)
+				}
+				cx += buf.WriteString(cx, int(y), kh.Keys, k.keyStyle)
+				cx += buf.WriteString(cx, int(y), " "+kh.Desc, k.style)
+				shown++
+			}
+		},
+	)
+}
@@ -0,0 +1,159 @@
+package glyph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kungfusheep/riffkey"
+)
+
+// keyHelpApp returns an App without a terminal and a function that types
+// keys into it.
+func keyHelpApp() (*App, func(keys string)) {
+	router := riffkey.NewRouter()
+	a := &App{router: router, input: riffkey.NewInput(router), renderChan: make(chan struct{}, 1)}
+	return a, func(keys string) {
+		for _, k := range riffkey.ParsePattern(keys) {
+			a.input.Dispatch(k)
+		}
+	}
+}
+
+// overlay draws the key overlays into a blank w×h buffer.
+func overlay(a *App, w, h int) string {
+	buf := NewBuffer(w, h)
+	a.drawKeyOverlays(buf, w, h)
+	return buf.String()
+}
+
+func TestKeyHelpRegistry(t *testing.T) {
+	a, press := keyHelpApp()
+	quit := false
+	a.LeaderKey("<Space>").
+		Handle("q", func() { quit = true }).Describe("quit").
+		Handle("<leader>f", func() {}).Describe("find").Group("Files").
+		HandleNamed("go_top", "gg", func(riffkey.Match) {})
+	items := []string{"a", "b"}
+	a.wireBindings(Build(List(&items).BindNav("j", "k")), a.router)
+
+	got := map[string]KeyHelp{}
+	for _, k := range a.ActiveKeys() {
+		got[k.Pattern] = k
+	}
+	want := map[string]KeyHelp{
+		"q":         {Pattern: "q", Keys: "q", Desc: "quit"},
+		"<leader>f": {Pattern: "<leader>f", Keys: "space f", Desc: "find", Group: "Files"},
+		"gg":        {Pattern: "gg", Keys: "gg", Action: "go_top", Desc: "go top"},
+		"j":         {Pattern: "j", Keys: "j", Action: "list.down", Desc: "down", Group: "List"},
+	}
+	for p, w := range want {
+		if got[p] != w {
+			t.Errorf("%s = %+v, want %+v", p, got[p], w)
+		}
+	}
+
+	press("q")
+	if !quit {
+		t.Error("q handler not called")
+	}
+}
+
+func TestWhichKeyPopup(t *testing.T) {
+	a, press := keyHelpApp()
+	top := 0
+	a.WhichKey(true).
+		Handle("gg", func() { top++ }).Describe("top").
+		Handle("gd", func() {}).Describe("definition").
+		Handle("x", func() {}).Describe("delete")
+
+	if out := overlay(a, 40, 8); strings.TrimSpace(out) != "" {
+		t.Fatalf("popup with nothing pending:\n%s", out)
+	}
+
+	press("g")
+	out := overlay(a, 40, 8)
+	for _, want := range []string{" g ", "g → top", "d → definition"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("popup missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "delete") {
+		t.Errorf("popup lists a non-continuation:\n%s", out)
+	}
+
+	press("g")
+	if top != 1 {
+		t.Errorf("gg fired %d times", top)
+	}
+	if out := overlay(a, 40, 8); strings.TrimSpace(out) != "" {
+		t.Errorf("popup still shown:\n%s", out)
+	}
+}
+
+func TestShowHelp(t *testing.T) {
+	a, press := keyHelpApp()
+	a.Handle("q", func() {}).Describe("quit").
+		Handle("gd", func() {}).Describe("definition").Group("Code").
+		Handle("?", a.ShowHelp)
+
+	press("?")
+	if !a.HelpVisible() {
+		t.Fatal("help not shown")
+	}
+	out := overlay(a, 50, 12)
+	for _, want := range []string{"Keys", "General", "quit", "Code", "gd", "definition"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("help missing %q:\n%s", want, out)
+		}
+	}
+
+	// typing filters rather than firing bindings
+	press("def")
+	out = overlay(a, 50, 12)
+	if !strings.Contains(out, "/def") || !strings.Contains(out, "definition") || strings.Contains(out, "quit") {
+		t.Errorf("filtered help:\n%s", out)
+	}
+
+	press("<Esc>")
+	if a.HelpVisible() || a.input.Depth() != 1 {
+		t.Errorf("help still open, depth %d", a.input.Depth())
+	}
+}
+
+func TestKeyHintsFollowFocus(t *testing.T) {
+	a, press := keyHelpApp()
+	var name string
+	agree := false
+	a.Handle("?", a.ShowHelp).Describe("help")
+	a.wireBindings(Build(VBox(
+		Form.OnSubmit(func() {})(
+			Field("Name", Input(&name)),
+			Field("Agree", Checkbox(&agree, "")),
+		),
+	)), a.router)
+
+	hints := func() string {
+		buf := NewBuffer(80, 1)
+		Build(KeyHints(a)).Execute(buf, 80, 1)
+		return buf.GetLine(0)
+	}
+
+	// the name input is focused
+	if got := hints(); !strings.HasPrefix(got, "enter submit · tab next field") {
+		t.Errorf("input hints = %q", got)
+	}
+	press("<Tab>")
+	if got := hints(); !strings.Contains(got, "space toggle") {
+		t.Errorf("checkbox hints = %q", got)
+	}
+	press("<Escape>")
+	if got := hints(); !strings.HasPrefix(got, "? help") {
+		t.Errorf("view hints = %q", got)
+	}
+
+	buf := NewBuffer(80, 1)
+	Build(KeyHints(a).Max(1)).Execute(buf, 80, 1)
+	if got := strings.TrimSpace(buf.GetLine(0)); got != "? help" {
+		t.Errorf("Max(1) = %q", got)
+	}
+}
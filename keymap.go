@@ -0,0 +1,340 @@
+package glyph
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/kungfusheep/riffkey"
+)
+
+// Keymap overrides the key patterns of named actions, for every view and
+// per view. An action mapped to no patterns is unbound. See LoadKeymap.
+type Keymap struct {
+	Leader string                         // what <leader> stands for
+	Global map[string][]string            // action → patterns
+	Views  map[string]map[string][]string // view → action → patterns
+}
+
+// KeyConflict is a key sequence bound to more than one action in a view.
+type KeyConflict struct {
+	View    string // view or route name; "" for the app's own bindings
+	Keys    string
+	Actions []string
+}
+
+func (c KeyConflict) String() string {
+	s := c.Keys + " is bound to " + strings.Join(c.Actions, " and ")
+	if c.View != "" {
+		s += " in " + c.View
+	}
+	return s
+}
+
+// KeymapError reports problems found applying a keymap: actions it names
+// that nothing registered, and keys it binds to more than one action.
+type KeymapError struct {
+	Unknown   []string
+	Conflicts []KeyConflict
+}
+
+func (e *KeymapError) Error() string {
+	var problems []string
+	for _, name := range e.Unknown {
+		problems = append(problems, "unknown action "+name)
+	}
+	for _, c := range e.Conflicts {
+		problems = append(problems, c.String())
+	}
+	return "keymap: " + strings.Join(problems, "; ")
+}
+
+// boundAction records an action that took a key sequence on a router.
+type boundAction struct {
+	action string // action name, or the binding's description or pattern
+	view   string
+	custom bool // patterns came from the keymap
+}
+
+// LoadKeymap reads a keymap from a TOML file, or JSON if the name ends in
+// .json. A missing file gives an empty keymap.
+//
+//	leader = "<Space>"
+//
+//	[global]
+//	"list.down" = ["j", "<C-n>"]
+//	"list.up" = ["k", "<C-p>"]
+//	"focus.next" = "<C-j>"
+//	quit = ""                # unbind
+//
+//	[views.settings]
+//	"form.submit" = "<C-s>"
+//
+// Nested tables such as [global.list] with down = "j" are the same as the
+// dotted names.
+func LoadKeymap(path string) (*Keymap, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Keymap{}, nil
+		}
+		return nil, err
+	}
+	var raw map[string]any
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &raw)
+	} else {
+		_, err = toml.Decode(string(data), &raw)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("keymap %s: %w", path, err)
+	}
+	km, err := parseKeymap(raw)
+	if err != nil {
+		return nil, fmt.Errorf("keymap %s: %w", path, err)
+	}
+	return km, nil
+}
+
+// parseKeymap builds a Keymap from a decoded TOML or JSON document.
+func parseKeymap(raw map[string]any) (*Keymap, error) {
+	km := &Keymap{Global: map[string][]string{}, Views: map[string]map[string][]string{}}
+	for section, v := range raw {
+		switch section {
+		case "leader":
+			s, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("leader must be a string")
+			}
+			km.Leader = s
+		case "global":
+			m, ok := v.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("global must be a table")
+			}
+			if err := flattenActions("", m, km.Global); err != nil {
+				return nil, err
+			}
+		case "views":
+			views, ok := v.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("views must be a table")
+			}
+			for view, v := range views {
+				m, ok := v.(map[string]any)
+				if !ok {
+					return nil, fmt.Errorf("views.%s must be a table", view)
+				}
+				km.Views[view] = map[string][]string{}
+				if err := flattenActions("", m, km.Views[view]); err != nil {
+					return nil, err
+				}
+			}
+		default:
+			return nil, fmt.Errorf("unknown section %q", section)
+		}
+	}
+	return km, nil
+}
+
+// flattenActions collects action patterns from m, joining nested table
+// names with dots.
+func flattenActions(prefix string, m map[string]any, out map[string][]string) error {
+	for k, v := range m {
+		name := prefix + k
+		switch v := v.(type) {
+		case string:
+			out[name] = []string{}
+			if v != "" {
+				out[name] = append(out[name], v)
+			}
+		case []any:
+			out[name] = []string{}
+			for _, p := range v {
+				s, ok := p.(string)
+				if !ok {
+					return fmt.Errorf("%s: patterns must be strings", name)
+				}
+				if s != "" {
+					out[name] = append(out[name], s)
+				}
+			}
+		case map[string]any:
+			if err := flattenActions(name+".", v, out); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%s: want a pattern or list of patterns", name)
+		}
+	}
+	return nil
+}
+
+// patterns returns the patterns for action in view, and whether they come
+// from the keymap rather than def.
+func (km *Keymap) patterns(view, action, def string) ([]string, bool) {
+	if km != nil && action != "" {
+		if p, ok := km.Views[view][action]; ok {
+			return p, true
+		}
+		if p, ok := km.Global[action]; ok {
+			return p, true
+		}
+	}
+	if def == "" {
+		return nil, false
+	}
+	return []string{def}, false
+}
+
+// Keymap applies a keymap to the actions registered after it, so call it
+// before SetView, View, Route and Handle. Entries that can't apply are
+// skipped and reported through OnKeymapError and KeymapErr.
+//
+//	km, err := LoadKeymap(filepath.Join(configDir, "keys.toml"))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	app.Keymap(km)
+func (a *App) Keymap(km *Keymap) *App {
+	a.keymap = km
+	if km.Leader != "" {
+		a.leader = km.Leader
+	}
+	return a
+}
+
+// LoadKeymap loads a keymap file and applies it. See Keymap.
+func (a *App) LoadKeymap(path string) error {
+	km, err := LoadKeymap(path)
+	if err != nil {
+		return err
+	}
+	a.Keymap(km)
+	return nil
+}
+
+// OnKeymapError sets a callback that Run calls with the problems found
+// applying the keymap, once the views are registered and before the first
+// frame. The app still runs, so show them to the user, for example in a
+// status line.
+//
+//	app.OnKeymapError(func(err error) { status = err.Error() })
+func (a *App) OnKeymapError(fn func(error)) {
+	a.onKeymapError = fn
+}
+
+// KeymapErr returns the problems found applying the keymap, or nil. Run
+// reports the same error to the OnKeymapError callback.
+func (a *App) KeymapErr() error {
+	if a.keymap == nil {
+		return nil
+	}
+	e := &KeymapError{}
+	for name := range a.keymap.Global {
+		if !a.actions[name] {
+			e.Unknown = append(e.Unknown, name)
+		}
+	}
+	for view, m := range a.keymap.Views {
+		for name := range m {
+			if !a.actions[name] {
+				e.Unknown = append(e.Unknown, view+": "+name)
+			}
+		}
+	}
+	slices.Sort(e.Unknown)
+
+	for _, byKeys := range a.bound {
+		for _, keys := range slices.Sorted(maps.Keys(byKeys)) {
+			bound := byKeys[keys]
+			if len(bound) < 2 || !slices.ContainsFunc(bound, func(b boundAction) bool { return b.custom }) {
+				continue
+			}
+			c := KeyConflict{View: bound[0].view, Keys: keys}
+			for _, b := range bound {
+				c.Actions = append(c.Actions, b.action)
+			}
+			e.Conflicts = append(e.Conflicts, c)
+		}
+	}
+	slices.SortFunc(e.Conflicts, func(x, y KeyConflict) int {
+		return strings.Compare(x.View+" "+x.Keys, y.View+" "+y.Keys)
+	})
+
+	if len(e.Unknown) == 0 && len(e.Conflicts) == 0 {
+		return nil
+	}
+	return e
+}
+
+// bind registers h on r under the patterns the keymap gives action b.name
+// in view, or b.pattern, and records them for help and conflict checks.
+// With free set, keys already bound on r are left to their binding.
+// Returns the action the binding is recorded under, for Describe and Group.
+func (a *App) bind(r *riffkey.Router, view string, b binding, h riffkey.Handler, free bool) string {
+	patterns, custom := a.keymap.patterns(view, b.name, b.pattern)
+	if b.name != "" {
+		if a.actions == nil {
+			a.actions = make(map[string]bool)
+		}
+		a.actions[b.name] = true
+	}
+	action := cmp.Or(b.name, b.desc, b.pattern)
+
+	named := false
+	for _, p := range patterns {
+		keys := keyLabel(riffkey.ParsePattern(a.expandLeader(p)))
+		if free && slices.ContainsFunc(a.keyHelp[r], func(e keyEntry) bool { return e.Keys == keys }) {
+			continue
+		}
+		// the first key of a named action is also named on the router,
+		// for Router().Rebind
+		if b.name != "" && !named {
+			r.HandleNamed(b.name, a.expandLeader(p), h)
+			named = true
+		} else {
+			r.Handle(a.expandLeader(p), h)
+		}
+		a.noteKey(r, p, action, b, h)
+
+		if a.bound == nil {
+			a.bound = make(map[*riffkey.Router]map[string][]boundAction)
+		}
+		if a.bound[r] == nil {
+			a.bound[r] = make(map[string][]boundAction)
+		}
+		if !slices.ContainsFunc(a.bound[r][keys], func(o boundAction) bool { return o.action == action }) {
+			a.bound[r][keys] = append(a.bound[r][keys], boundAction{action: action, view: view, custom: custom})
+		}
+	}
+	// unbound actions can still be run from the command palette
+	if len(patterns) == 0 && b.name != "" {
+		a.addCommand(r, paletteCommand{
+			KeyHelp: KeyHelp{Action: b.name, Desc: b.desc, Group: b.group},
+			label:   b.name,
+			run:     h,
+		})
+	}
+	return action
+}
+
+// handler adapts a binding's handler to riffkey, requesting a render after
+// it runs. Returns nil for unsupported handler types.
+func (a *App) handler(h any) riffkey.Handler {
+	switch h := h.(type) {
+	case func(riffkey.Match):
+		return func(m riffkey.Match) { h(m); a.RequestRender() }
+	case func(any):
+		return func(_ riffkey.Match) { h(nil); a.RequestRender() }
+	case func():
+		return func(_ riffkey.Match) { h(); a.RequestRender() }
+	}
+	return nil
+}
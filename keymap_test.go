@@ -0,0 +1,219 @@
+package glyph
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeKeymap(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadKeymap(t *testing.T) {
+	toml := writeKeymap(t, "keys.toml", `
+leader = "<Space>"
+
+[global]
+"list.down" = ["<C-n>", "<Down>"]
+quit = ""
+
+[global.focus]
+next = "<C-j>"
+
+[views.settings]
+"list.up" = "<C-p>"
+`)
+	json := writeKeymap(t, "keys.json", `{
+	"leader": "<Space>",
+	"global": {"list.down": ["<C-n>", "<Down>"], "quit": "", "focus": {"next": "<C-j>"}},
+	"views": {"settings": {"list.up": "<C-p>"}}
+}`)
+	for _, path := range []string{toml, json} {
+		km, err := LoadKeymap(path)
+		if err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		if km.Leader != "<Space>" {
+			t.Errorf("%s: leader = %q", path, km.Leader)
+		}
+		if got := strings.Join(km.Global["list.down"], " "); got != "<C-n> <Down>" {
+			t.Errorf("%s: list.down = %q", path, got)
+		}
+		if p, ok := km.Global["quit"]; !ok || len(p) != 0 {
+			t.Errorf("%s: quit = %q, %v; want unbound", path, p, ok)
+		}
+		if got := km.Global["focus.next"]; len(got) != 1 || got[0] != "<C-j>" {
+			t.Errorf("%s: focus.next = %q", path, got)
+		}
+		if got := km.Views["settings"]["list.up"]; len(got) != 1 || got[0] != "<C-p>" {
+			t.Errorf("%s: settings list.up = %q", path, got)
+		}
+	}
+
+	if km, err := LoadKeymap(filepath.Join(t.TempDir(), "missing.toml")); err != nil || km == nil {
+		t.Errorf("missing file: %v, %v", km, err)
+	}
+	if _, err := LoadKeymap(writeKeymap(t, "bad.toml", "[keys]\nx = 1\n")); err == nil {
+		t.Error("unknown section accepted")
+	}
+	if _, err := LoadKeymap(writeKeymap(t, "bad.json", `{"global": {"x": 1}}`)); err == nil {
+		t.Error("numeric pattern accepted")
+	}
+}
+
+func TestKeymapRemapsActions(t *testing.T) {
+	a, press := keyHelpApp()
+	a.Keymap(&Keymap{Global: map[string][]string{
+		"list.down":  {"<C-n>"},
+		"list.up":    {},
+		"focus.next": {"<C-j>"},
+		"quit":       {"<C-q>"},
+	}})
+	quit := false
+	a.HandleNamed("quit", "q", func() { quit = true })
+
+	items := []string{"a", "b", "c"}
+	list := List(&items).BindVimNav()
+	var name, email string
+	tmpl := Build(VBox(
+		list,
+		Form(Field("Name", Input(&name)), Field("Email", Input(&email))),
+	))
+	a.wireBindings(tmpl, a.router)
+	tmpl.Execute(NewBuffer(40, 10), 40, 10) // the list learns its length
+
+	// the form's first field is focused; leave it to reach the list keys
+	press("<Escape>j")
+	if list.Index() != 0 {
+		t.Errorf("default j still moves the list")
+	}
+	press("<C-n><C-n>")
+	if list.Index() != 2 {
+		t.Errorf("index = %d after remapped down", list.Index())
+	}
+	press("k")
+	if list.Index() != 2 {
+		t.Errorf("unbound up still moves the list")
+	}
+
+	// focus cycling uses the remapped key, on the base router and in fields
+	press("<C-j>x")
+	if email != "x" {
+		t.Fatalf("email = %q", email)
+	}
+	press("<C-j>y")
+	if name != "y" {
+		t.Errorf("name = %q, email = %q", name, email)
+	}
+
+	press("<Escape>q")
+	if quit {
+		t.Error("default quit key still bound")
+	}
+	press("<C-q>")
+	if !quit {
+		t.Error("remapped quit not bound")
+	}
+	if err := a.KeymapErr(); err != nil {
+		t.Errorf("unexpected keymap error: %v", err)
+	}
+}
+
+func TestKeymapKeepsDescriptions(t *testing.T) {
+	a, _ := keyHelpApp()
+	a.Keymap(&Keymap{Global: map[string][]string{"quit": {"<C-q>", "Q"}, "save": {}}})
+	a.HandleNamed("quit", "q", func() {}).Describe("leave the app").Group("App")
+	a.Handle("r", func() {}).Describe("refresh")
+
+	descs := make(map[string]string)
+	for _, k := range a.ActiveKeys() {
+		descs[k.Keys] = k.Group + ": " + k.Desc
+	}
+	if descs["ctrl-q"] != "App: leave the app" || descs["Q"] != "App: leave the app" {
+		t.Errorf("remapped quit = %q, %q", descs["ctrl-q"], descs["Q"])
+	}
+	if _, ok := descs["q"]; ok {
+		t.Error("default quit key listed")
+	}
+	if descs["r"] != ": refresh" {
+		t.Errorf("r = %q", descs["r"])
+	}
+
+	// an unbound action keeps its description in the command palette
+	a.HandleNamed("save", "s", func() {}).Describe("write the file")
+	i := slices.IndexFunc(a.paletteCommands(), func(c paletteCommand) bool { return c.Action == "save" })
+	if i < 0 || a.paletteCommands()[i].Desc != "write the file" {
+		t.Errorf("unbound save not described in the palette")
+	}
+}
+
+func TestKeymapPerViewAndConflicts(t *testing.T) {
+	a, _ := keyHelpApp()
+	a.pool = NewBufferPool(20, 5)
+	a.Keymap(&Keymap{
+		Global: map[string][]string{"list.dwn": {"x"}},
+		Views: map[string]map[string][]string{
+			"logs": {"list.up": {"j"}},
+		},
+	})
+	items := []string{"a", "b"}
+	a.View("files", List(&items).BindNav("j", "k"))
+	a.View("logs", List(&items).BindNav("j", "k"))
+
+	var ke *KeymapError
+	if err := a.KeymapErr(); !errors.As(err, &ke) {
+		t.Fatalf("KeymapErr = %v", err)
+	}
+	if len(ke.Unknown) != 1 || ke.Unknown[0] != "list.dwn" {
+		t.Errorf("unknown = %q", ke.Unknown)
+	}
+	if len(ke.Conflicts) != 1 {
+		t.Fatalf("conflicts = %v", ke.Conflicts)
+	}
+	if got := ke.Conflicts[0].String(); got != "j is bound to list.down and list.up in logs" {
+		t.Errorf("conflict = %q", got)
+	}
+}
+
+func TestKeymapProblemsDontStopRun(t *testing.T) {
+	st := newStreamTerm(t, 10, 2)
+	st.app.Keymap(&Keymap{Global: map[string][]string{"list.dwn": {"x"}, "quit": {"Q"}}})
+	st.app.HandleNamed("quit", "q", st.app.Stop)
+	status := "hi"
+	st.app.SetView(Text(&status))
+	var reported error
+	st.app.OnKeymapError(func(err error) {
+		reported = err
+		status = "bad keys"
+	})
+	errs := make(chan error, 1)
+	go func() { errs <- st.app.Run() }()
+
+	// reported before the first frame
+	waitFor(t, "first frame", func() bool { return strings.HasPrefix(st.screen(), "bad keys") })
+	var ke *KeymapError
+	if !errors.As(reported, &ke) || len(ke.Unknown) != 1 || ke.Unknown[0] != "list.dwn" {
+		t.Errorf("reported = %v", reported)
+	}
+
+	// the valid entry still applies
+	st.keys.Write([]byte("Q"))
+	select {
+	case err := <-errs:
+		if err != nil {
+			t.Errorf("Run = %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("remapped quit didn't stop the app")
+	}
+}
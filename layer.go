@@ -36,6 +36,9 @@ type Layer struct {
 	// AlwaysRender causes Render to fire every frame, not just on width changes.
 	// Used by components that track external pointer mutations (e.g. TextViewC).
 	AlwaysRender bool
+
+	// search state, created by Search()
+	search *LayerSearch
 }
 
 // NewLayer creates a new empty layer.
@@ -72,6 +75,9 @@ func (l *Layer) updateMaxScroll() {
 		return
 	}
 	l.maxScroll = l.buffer.Height() - l.viewHeight
+	if l.search != nil && l.search.Active() {
+		l.maxScroll++ // so the last line can scroll clear of the status row
+	}
 	if l.maxScroll < 0 {
 		l.maxScroll = 0
 	}
@@ -195,6 +201,9 @@ func (l *Layer) blit(dst *Buffer, dstX, dstY, width, height int) {
 		return
 	}
 	dst.Blit(l.buffer, 0, l.scrollY, dstX, dstY, width, height)
+	if l.search != nil {
+		l.search.draw(dst, dstX, dstY, width, height)
+	}
 }
 
 // SetLine updates a single line in the layer buffer with styled spans.
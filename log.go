@@ -21,6 +21,20 @@ type LogC struct {
 	// key bindings
 	declaredBindings []binding
 
+	// ANSI mode: lines holds the stripped text, spans the styled rendering
+	ansi   bool
+	parser ansiParser
+	spans  [][]Span
+
+	// structured mode: entries holds parsed records (nil for plain lines)
+	structured bool
+	fieldMode  LogFieldMode
+	entries    []*logEntry
+
+	// filter applied when syncing to the layer (set by FilterLog)
+	query *logQuery
+	shown []int // lines written to the layer by the last sync, in order
+
 	// internal state
 	layer        *Layer
 	lines        []string
@@ -59,6 +73,26 @@ func (lv *LogC) AutoScroll(enabled bool) *LogC {
 	return lv
 }
 
+// ANSI interprets escape sequences in incoming lines (colours, attributes,
+// carriage-return redraws) instead of showing them as raw text.
+func (lv *LogC) ANSI() *LogC {
+	lv.ansi = true
+	return lv
+}
+
+// Structured parses JSON and logfmt lines into records rendered with level
+// colouring. Lines in neither format are shown as-is.
+func (lv *LogC) Structured() *LogC {
+	lv.structured = true
+	return lv
+}
+
+// Fields sets how structured records show their fields. Default is FieldsInline.
+func (lv *LogC) Fields(m LogFieldMode) *LogC {
+	lv.fieldMode = m
+	return lv
+}
+
 // Grow sets the flex grow factor. Accepts float32, float64, int, or *float32 for dynamic values.
 func (lv *LogC) Grow(g any) *LogC {
 	switch val := g.(type) {
@@ -136,8 +170,8 @@ func (lv *LogC) OnUpdate(f func()) *LogC {
 // BindNav registers key bindings for scrolling down/up by one line.
 func (lv *LogC) BindNav(down, up string) *LogC {
 	lv.declaredBindings = append(lv.declaredBindings,
-		binding{down, func() { lv.layer.ScrollDown(1) }},
-		binding{up, func() { lv.following = false; lv.layer.ScrollUp(1) }},
+		binding{pattern: down, handler: func() { lv.layer.ScrollDown(1) }, name: "log.scroll_down", desc: "scroll down", group: "Log"},
+		binding{pattern: up, handler: func() { lv.following = false; lv.layer.ScrollUp(1) }, name: "log.scroll_up", desc: "scroll up", group: "Log"},
 	)
 	return lv
 }
@@ -145,8 +179,8 @@ func (lv *LogC) BindNav(down, up string) *LogC {
 // BindPageNav registers key bindings for half-page scrolling.
 func (lv *LogC) BindPageNav(down, up string) *LogC {
 	lv.declaredBindings = append(lv.declaredBindings,
-		binding{down, func() { lv.layer.HalfPageDown() }},
-		binding{up, func() { lv.following = false; lv.layer.HalfPageUp() }},
+		binding{pattern: down, handler: func() { lv.layer.HalfPageDown() }, name: "log.half_page_down", desc: "half page down", group: "Log"},
+		binding{pattern: up, handler: func() { lv.following = false; lv.layer.HalfPageUp() }, name: "log.half_page_up", desc: "half page up", group: "Log"},
 	)
 	return lv
 }
@@ -154,8 +188,8 @@ func (lv *LogC) BindPageNav(down, up string) *LogC {
 // BindFirstLast registers key bindings for jumping to top/bottom.
 func (lv *LogC) BindFirstLast(first, last string) *LogC {
 	lv.declaredBindings = append(lv.declaredBindings,
-		binding{first, func() { lv.following = false; lv.layer.ScrollToTop() }},
-		binding{last, func() { lv.resume() }},
+		binding{pattern: first, handler: func() { lv.following = false; lv.layer.ScrollToTop() }, name: "log.top", desc: "top", group: "Log"},
+		binding{pattern: last, handler: func() { lv.resume() }, name: "log.follow", desc: "follow", group: "Log"},
 	)
 	return lv
 }
@@ -166,6 +200,77 @@ func (lv *LogC) BindVimNav() *LogC {
 	return lv.BindNav("j", "k").BindPageNav("<C-d>", "<C-u>").BindFirstLast("g", "G")
 }
 
+// BindFields registers a key that cycles structured field display between
+// inline, collapsed and expanded.
+func (lv *LogC) BindFields(key string) *LogC {
+	lv.declaredBindings = append(lv.declaredBindings, binding{pattern: key, handler: lv.cycleFields, name: "log.fields", desc: "cycle fields", group: "Log"})
+	return lv
+}
+
+// cycleFields advances the field display mode and re-renders.
+func (lv *LogC) cycleFields() {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+	lv.fieldMode = (lv.fieldMode + 1) % (FieldsExpanded + 1)
+	lv.syncToLayer()
+	if lv.following {
+		lv.layer.ScrollToEnd()
+	}
+}
+
+// BindExpand registers a key that expands or collapses the fields of the
+// record on the cursor line: the newest line while following, otherwise the
+// one at the top of the view. Other records keep the mode set by Fields or
+// BindFields.
+func (lv *LogC) BindExpand(key string) *LogC {
+	lv.declaredBindings = append(lv.declaredBindings, binding{pattern: key, handler: lv.toggleFields, name: "log.expand", desc: "expand fields", group: "Log"})
+	return lv
+}
+
+// toggleFields flips the field display of the record on the cursor line.
+func (lv *LogC) toggleFields() {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+	e := lv.entry(lv.cursorLine())
+	if e == nil || len(e.rec.Fields) == 0 {
+		return
+	}
+	e.toggled = !e.toggled
+	lv.syncToLayer()
+	if lv.following {
+		lv.layer.ScrollToEnd()
+	}
+}
+
+// cursorLine returns the buffered line the view is on: the newest shown
+// line while following, otherwise the one at the top of the view. Returns
+// -1 when nothing is shown.
+func (lv *LogC) cursorLine() int {
+	if len(lv.shown) == 0 {
+		return -1
+	}
+	if lv.following {
+		return lv.shown[len(lv.shown)-1]
+	}
+	top, y := lv.layer.ScrollY(), 0
+	for _, i := range lv.shown {
+		y += lv.lineRows(i)
+		if y > top {
+			return i
+		}
+	}
+	return lv.shown[len(lv.shown)-1]
+}
+
+// BindSearch registers key to open an in-place search prompt, with n/N to
+// move between matches. Jumping to a match stops following new lines.
+// Configure the query syntax and highlight via Layer().Search().
+func (lv *LogC) BindSearch(key string) *LogC {
+	lv.declaredBindings = append(lv.declaredBindings,
+		lv.layer.Search().bindings(key, func() { lv.following = false })...)
+	return lv
+}
+
 // bindings implements the bindable interface.
 func (lv *LogC) bindings() []binding {
 	return lv.declaredBindings
@@ -191,12 +296,12 @@ func (lv *LogC) readLoop() {
 
 		lv.mu.Lock()
 
-		lv.lines = append(lv.lines, line)
+		lv.appendLine(line)
 
 		// ring buffer: drop oldest if over limit
 		if lv.maxLines > 0 && len(lv.lines) > lv.maxLines {
 			dropped := len(lv.lines) - lv.maxLines
-			lv.lines = lv.lines[dropped:]
+			lv.dropLines(dropped)
 			// adjust scroll position to keep viewing same content
 			if !lv.following {
 				newScrollY := lv.layer.ScrollY() - dropped
@@ -230,19 +335,84 @@ func (lv *LogC) readLoop() {
 	}
 }
 
-// syncToLayer writes all buffered lines to the layer's buffer.
-func (lv *LogC) syncToLayer() {
-	if len(lv.lines) == 0 {
-		return
+// appendLine buffers a raw line, parsing escape sequences in ANSI mode and
+// records in structured mode. lines always holds the text filters match on.
+func (lv *LogC) appendLine(raw string) {
+	var spans []Span
+	text := raw
+	if lv.ansi {
+		spans, text = lv.parser.parse(raw)
+	}
+
+	var entry *logEntry
+	if lv.structured {
+		if rec, ok := ParseLogRecord(text); ok {
+			entry, text = newLogEntry(rec)
+		}
+	}
+
+	lv.lines = append(lv.lines, text)
+	if lv.ansi {
+		lv.spans = append(lv.spans, spans)
+	}
+	if lv.structured {
+		lv.entries = append(lv.entries, entry)
+	}
+}
+
+// dropLines discards the oldest n buffered lines.
+func (lv *LogC) dropLines(n int) {
+	lv.lines = lv.lines[n:]
+	if lv.ansi {
+		lv.spans = lv.spans[n:]
+	}
+	if lv.structured {
+		lv.entries = lv.entries[n:]
 	}
+}
 
-	// create exact-sized buffer (EnsureSize only grows, which breaks maxScroll after ring buffer truncates)
-	const bufferWidth = 500
-	buf := NewBuffer(bufferWidth, len(lv.lines))
-	for i, line := range lv.lines {
-		buf.WriteStringFast(0, i, line, Style{}, bufferWidth)
+// record returns the parsed record for line i, or nil.
+func (lv *LogC) record(i int) *LogRecord {
+	if e := lv.entry(i); e != nil {
+		return &e.rec
 	}
-	lv.layer.SetBuffer(buf)
+	return nil
+}
+
+// entry returns the structured entry for line i, or nil.
+func (lv *LogC) entry(i int) *logEntry {
+	if !lv.structured || i < 0 {
+		return nil
+	}
+	return lv.entries[i]
+}
+
+// lineRows returns how many buffer rows line i occupies.
+func (lv *LogC) lineRows(i int) int {
+	if e := lv.entry(i); e != nil {
+		return e.rows(e.mode(lv.fieldMode))
+	}
+	return 1
+}
+
+// writeLine renders buffered line i starting at row y of buf and returns
+// the number of rows written.
+func (lv *LogC) writeLine(buf *Buffer, y, i int) int {
+	if e := lv.entry(i); e != nil {
+		return e.write(buf, y, e.mode(lv.fieldMode))
+	}
+	if lv.ansi {
+		buf.WriteSpans(0, y, lv.spans[i], buf.Width())
+		return 1
+	}
+	buf.WriteStringFast(0, y, lv.lines[i], Style{}, buf.Width())
+	return 1
+}
+
+// syncToLayer writes all buffered lines that pass the filter (if any) to
+// the layer's buffer.
+func (lv *LogC) syncToLayer() {
+	lv.syncToLayerFiltered(lv.query)
 }
 
 // compileLogC compiles the Log component into the template.
@@ -0,0 +1,190 @@
+package glyph
+
+import (
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// TailReader follows a file the way tail -F does: it reads appended data
+// as it arrives, reopens the path when the file is rotated (a different
+// inode appears at the path) and starts over when the file is truncated.
+// Read blocks until data is available and only returns io.EOF after Close.
+//
+//	src := LogFile("/var/log/app.log").Last(100)
+//	defer src.Close()
+//	Log(src).Structured().BindVimNav()
+type TailReader struct {
+	path string
+	last int
+	poll time.Duration
+
+	file   *os.File
+	info   os.FileInfo
+	offset int64
+	opened bool // first open seeds from the last N lines, later opens read from the start
+
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// LogFile creates a TailReader for path. By default reading starts at the
+// end of the file; use Last to seed with existing lines. The file does not
+// need to exist yet.
+func LogFile(path string) *TailReader {
+	return &TailReader{
+		path:   path,
+		poll:   250 * time.Millisecond,
+		closed: make(chan struct{}),
+	}
+}
+
+// Last seeds the reader with the last n lines already in the file.
+func (t *TailReader) Last(n int) *TailReader {
+	t.last = n
+	return t
+}
+
+// Poll sets how often the file is checked for new data, rotation and
+// truncation once the end is reached. Default is 250ms.
+func (t *TailReader) Poll(d time.Duration) *TailReader {
+	t.poll = d
+	return t
+}
+
+// Close stops following. A blocked Read returns io.EOF.
+func (t *TailReader) Close() error {
+	t.closeOnce.Do(func() { close(t.closed) })
+	return nil
+}
+
+// Read implements io.Reader.
+func (t *TailReader) Read(p []byte) (int, error) {
+	for {
+		select {
+		case <-t.closed:
+			if t.file != nil {
+				t.file.Close()
+				t.file = nil
+			}
+			return 0, io.EOF
+		default:
+		}
+
+		if t.file == nil {
+			if err := t.open(); err != nil {
+				// a file created later is read from its start
+				t.opened = true
+				t.wait()
+				continue
+			}
+		}
+
+		n, err := t.file.Read(p)
+		if n > 0 {
+			t.offset += int64(n)
+			return n, nil
+		}
+		if err != nil && err != io.EOF {
+			// unreadable handle, try the path again
+			t.reset()
+			t.wait()
+			continue
+		}
+
+		// at end of file: look for rotation or truncation before waiting
+		fi, err := os.Stat(t.path)
+		switch {
+		case err != nil:
+			// rotated away and not recreated yet, keep the old handle
+		case !os.SameFile(fi, t.info):
+			t.reset()
+			continue
+		case fi.Size() < t.offset:
+			if _, err := t.file.Seek(0, io.SeekStart); err == nil {
+				t.offset = 0
+				continue
+			}
+		}
+		t.wait()
+	}
+}
+
+// open opens the path and positions the read offset.
+func (t *TailReader) open() error {
+	f, err := os.Open(t.path)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	var off int64
+	if !t.opened {
+		off = fi.Size()
+		if t.last > 0 {
+			off = lastLinesOffset(f, fi.Size(), t.last)
+		}
+	}
+	if _, err := f.Seek(off, io.SeekStart); err != nil {
+		f.Close()
+		return err
+	}
+
+	t.file, t.info, t.offset, t.opened = f, fi, off, true
+	return nil
+}
+
+// reset drops the current handle so the next Read reopens the path.
+func (t *TailReader) reset() {
+	if t.file != nil {
+		t.file.Close()
+	}
+	t.file = nil
+}
+
+func (t *TailReader) wait() {
+	select {
+	case <-t.closed:
+	case <-time.After(t.poll):
+	}
+}
+
+// lastLinesOffset returns the offset where the last n lines of f begin.
+// Reads backwards in chunks so large files aren't scanned from the start.
+func lastLinesOffset(f *os.File, size int64, n int) int64 {
+	const chunk = 4096
+	buf := make([]byte, chunk)
+	pos := size
+	newlines := 0
+
+	// a trailing newline terminates the last line rather than starting a new one
+	if size > 0 {
+		if _, err := f.ReadAt(buf[:1], size-1); err == nil && buf[0] == '\n' {
+			pos--
+		}
+	}
+
+	for pos > 0 {
+		start := max(pos-chunk, 0)
+		b := buf[:pos-start]
+		if _, err := f.ReadAt(b, start); err != nil && err != io.EOF {
+			return 0
+		}
+		for i := len(b) - 1; i >= 0; i-- {
+			if b[i] != '\n' {
+				continue
+			}
+			newlines++
+			if newlines == n {
+				return start + int64(i) + 1
+			}
+		}
+		pos = start
+	}
+	return 0
+}
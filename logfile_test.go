@@ -0,0 +1,159 @@
+package glyph
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// tailLines reads lines from a TailReader on a goroutine.
+func tailLines(t *testing.T, tr *TailReader) <-chan string {
+	t.Helper()
+	ch := make(chan string, 64)
+	go func() {
+		defer close(ch)
+		sc := bufio.NewScanner(tr)
+		for sc.Scan() {
+			ch <- sc.Text()
+		}
+	}()
+	t.Cleanup(func() { tr.Close() })
+	return ch
+}
+
+func expectLine(t *testing.T, ch <-chan string, want string) {
+	t.Helper()
+	select {
+	case got := <-ch:
+		if got != want {
+			t.Fatalf("got line %q, want %q", got, want)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for %q", want)
+	}
+}
+
+func appendFile(t *testing.T, path, s string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(s); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLogFileSeedsLastLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	appendFile(t, path, "one\ntwo\nthree\nfour\n")
+
+	ch := tailLines(t, LogFile(path).Last(2).Poll(5*time.Millisecond))
+	expectLine(t, ch, "three")
+	expectLine(t, ch, "four")
+
+	appendFile(t, path, "five\n")
+	expectLine(t, ch, "five")
+}
+
+func TestLogFileStartsAtEnd(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	appendFile(t, path, "old\n")
+
+	ch := tailLines(t, LogFile(path).Poll(5*time.Millisecond))
+	time.Sleep(20 * time.Millisecond)
+	appendFile(t, path, "new\n")
+	expectLine(t, ch, "new")
+}
+
+func TestLogFileWaitsForCreation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "later.log")
+	ch := tailLines(t, LogFile(path).Poll(5*time.Millisecond))
+
+	time.Sleep(20 * time.Millisecond)
+	appendFile(t, path, "hello\n")
+	expectLine(t, ch, "hello")
+}
+
+func TestLogFileTruncation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	appendFile(t, path, "a long first line\n")
+
+	ch := tailLines(t, LogFile(path).Last(1).Poll(5*time.Millisecond))
+	expectLine(t, ch, "a long first line")
+
+	if err := os.Truncate(path, 0); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	appendFile(t, path, "after\n")
+	expectLine(t, ch, "after")
+}
+
+func TestLogFileRotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	appendFile(t, path, "before\n")
+
+	ch := tailLines(t, LogFile(path).Last(1).Poll(5*time.Millisecond))
+	expectLine(t, ch, "before")
+
+	// logrotate-style: rename the old file, write the tail of it, then create a new one
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	appendFile(t, path+".1", "last of old\n")
+	expectLine(t, ch, "last of old")
+
+	appendFile(t, path, "first of new\n")
+	expectLine(t, ch, "first of new")
+}
+
+func TestLogFileClose(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	appendFile(t, path, "")
+	tr := LogFile(path).Poll(5 * time.Millisecond)
+	ch := tailLines(t, tr)
+
+	tr.Close()
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Fatal("expected no lines after close")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Read did not return after Close")
+	}
+}
+
+func TestLastLinesOffset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "f")
+	tests := []struct {
+		content string
+		n       int
+		want    int64
+	}{
+		{"a\nb\nc\n", 1, 4},
+		{"a\nb\nc\n", 2, 2},
+		{"a\nb\nc\n", 5, 0},
+		{"a\nb\nc", 1, 4},
+		{"", 3, 0},
+	}
+	for _, tt := range tests {
+		if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := lastLinesOffset(f, int64(len(tt.content)), tt.n)
+		f.Close()
+		if got != tt.want {
+			t.Errorf("lastLinesOffset(%q, %d) = %d, want %d", tt.content, tt.n, got, tt.want)
+		}
+	}
+}
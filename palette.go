@@ -0,0 +1,280 @@
+package glyph
+
+import (
+	"slices"
+
+	"github.com/kungfusheep/riffkey"
+)
+
+// maxRecentCommands is how many recently run commands the palette ranks
+// first.
+const maxRecentCommands = 20
+
+// maxPaletteRows is how many commands the palette shows at once.
+const maxPaletteRows = 12
+
+// paletteCommand is an action listed by the CommandPalette.
+type paletteCommand struct {
+	KeyHelp
+	label  string // identifies the command for recency
+	keys   []riffkey.Key
+	run    riffkey.Handler
+	prompt string       // asks for an argument first; see App.Command
+	arg    func(string) // takes the argument
+}
+
+// keylessCommand is a command with no key binding on a router: a named
+// action the keymap unbound, or one registered with Command.
+type keylessCommand struct {
+	router *riffkey.Router // nil for app-wide commands
+	paletteCommand
+}
+
+// palette is the state of the CommandPalette overlay.
+type palette struct {
+	commands []paletteCommand
+	filter   *Filter[paletteCommand]
+	query    string
+	cursor   int
+	selected int
+	scroll   int
+
+	chosen    *paletteCommand // waiting for its argument
+	arg       string
+	argCursor int
+}
+
+// Command registers a palette command that takes an argument. Choosing it
+// in the CommandPalette asks for the argument with prompt, then calls fn.
+// The name, with underscores as spaces, is its description.
+//
+//	app.Command("goto_line", "Line", func(s string) { ... })
+func (a *App) Command(name, prompt string, fn func(arg string)) *App {
+	a.addCommand(nil, paletteCommand{
+		KeyHelp: KeyHelp{Action: name, Desc: actionDesc(name)},
+		label:   name,
+		prompt:  prompt,
+		arg:     func(s string) { fn(s); a.RequestRender() },
+	})
+	return a
+}
+
+// addCommand records a keyless command, replacing one with the same label
+// on the same router.
+func (a *App) addCommand(r *riffkey.Router, c paletteCommand) {
+	i := slices.IndexFunc(a.commands, func(o keylessCommand) bool {
+		return o.router == r && o.label == c.label
+	})
+	if i >= 0 {
+		a.commands[i].paletteCommand = c
+		return
+	}
+	a.commands = append(a.commands, keylessCommand{router: r, paletteCommand: c})
+}
+
+// CommandPalette opens an overlay listing the named and described actions
+// of the current view and focused component with their keys. Typing fuzzy
+// filters it, recently run commands come first, Enter runs the selected
+// one as its key would, and Escape closes it.
+//
+//	app.Handle("<C-k>", app.CommandPalette).Describe("commands")
+func (a *App) CommandPalette() {
+	if a.palette != nil {
+		return
+	}
+	p := &palette{commands: a.paletteCommands()}
+	p.filter = NewFilter(&p.commands, func(c *paletteCommand) string {
+		return c.Desc + " " + c.Action + " " + c.Group
+	})
+
+	r := riffkey.NewRouter().NoCounts()
+	r.Handle("<Esc>", func(_ riffkey.Match) { a.HideCommandPalette() })
+	r.Handle("<Enter>", func(_ riffkey.Match) { a.choosePaletteCommand() })
+	r.Handle("<Down>", func(_ riffkey.Match) { p.move(1); a.RequestRender() })
+	r.Handle("<Up>", func(_ riffkey.Match) { p.move(-1); a.RequestRender() })
+	r.Handle("<C-n>", func(_ riffkey.Match) { p.move(1); a.RequestRender() })
+	r.Handle("<C-p>", func(_ riffkey.Match) { p.move(-1); a.RequestRender() })
+	query := riffkey.NewTextHandler(&p.query, &p.cursor)
+	query.OnChange = func(q string) {
+		p.filter.Update(q)
+		p.selected, p.scroll = 0, 0
+	}
+	arg := riffkey.NewTextHandler(&p.arg, &p.argCursor)
+	r.HandleUnmatched(func(k riffkey.Key) bool {
+		if p.chosen != nil {
+			arg.HandleKey(k)
+		} else {
+			query.HandleKey(k)
+		}
+		a.RequestRender()
+		return true
+	})
+
+	a.palette = p
+	a.input.Push(r)
+	a.RequestRender()
+}
+
+// HideCommandPalette closes the CommandPalette overlay.
+func (a *App) HideCommandPalette() {
+	if a.palette == nil {
+		return
+	}
+	a.palette = nil
+	a.input.Pop()
+	a.RequestRender()
+}
+
+// CommandPaletteVisible reports whether the CommandPalette overlay is open.
+func (a *App) CommandPaletteVisible() bool {
+	return a.palette != nil
+}
+
+// paletteCommands collects the runnable actions that apply now, recently
+// run ones first. Bindings of one action are merged, e.g. "j, down".
+func (a *App) paletteCommands() []paletteCommand {
+	routers := a.activeRouters()
+	var cmds []paletteCommand
+	seen := make(map[string]bool) // keys shadowed by an earlier router
+	for _, r := range routers {
+		for _, e := range a.keyHelp[r] {
+			if seen[e.Keys] {
+				continue
+			}
+			seen[e.Keys] = true
+			label := e.Action
+			if label == "" {
+				label = e.Desc
+			}
+			if label == "" || e.run == nil {
+				continue
+			}
+			if i := slices.IndexFunc(cmds, func(c paletteCommand) bool { return c.label == label }); i >= 0 {
+				cmds[i].Keys += ", " + e.Keys
+				continue
+			}
+			cmds = append(cmds, paletteCommand{KeyHelp: e.KeyHelp, label: label, keys: e.keys, run: e.run})
+		}
+	}
+	for _, c := range a.commands {
+		if c.router != nil && !slices.Contains(routers, c.router) {
+			continue
+		}
+		if !slices.ContainsFunc(cmds, func(o paletteCommand) bool { return o.label == c.label }) {
+			cmds = append(cmds, c.paletteCommand)
+		}
+	}
+
+	// stable: recent commands in recency order, the rest as registered
+	slices.SortStableFunc(cmds, func(x, y paletteCommand) int {
+		return recency(a.recentCommands, x.label) - recency(a.recentCommands, y.label)
+	})
+	return cmds
+}
+
+// recency ranks label by how recently it ran, most recent lowest.
+func recency(recent []string, label string) int {
+	if i := slices.Index(recent, label); i >= 0 {
+		return i
+	}
+	return len(recent)
+}
+
+// choosePaletteCommand runs the selected command, or asks for its
+// argument first.
+func (a *App) choosePaletteCommand() {
+	p := a.palette
+	if p.chosen != nil {
+		c, arg := *p.chosen, p.arg
+		a.HideCommandPalette()
+		a.noteRecentCommand(c.label)
+		c.arg(arg)
+		return
+	}
+	c := p.filter.Original(p.selected)
+	if c == nil {
+		return
+	}
+	if c.arg != nil {
+		p.chosen = c
+		a.RequestRender()
+		return
+	}
+	cmd := *c
+	a.HideCommandPalette()
+	a.noteRecentCommand(cmd.label)
+	// the handler the key runs, which requests a render
+	cmd.run(riffkey.Match{Keys: cmd.keys, Count: 1})
+}
+
+// noteRecentCommand moves label to the front of the recent commands.
+func (a *App) noteRecentCommand(label string) {
+	a.recentCommands = slices.DeleteFunc(a.recentCommands, func(s string) bool { return s == label })
+	a.recentCommands = slices.Insert(a.recentCommands, 0, label)
+	if len(a.recentCommands) > maxRecentCommands {
+		a.recentCommands = a.recentCommands[:maxRecentCommands]
+	}
+}
+
+// move moves the selection by delta, clamped to the filtered commands.
+func (p *palette) move(delta int) {
+	if p.chosen != nil {
+		return
+	}
+	p.selected = max(min(p.selected+delta, p.filter.Len()-1), 0)
+}
+
+// draw renders the palette box in the upper part of a w×h frame.
+func (p *palette) draw(buf *Buffer, w, h int) {
+	bw := min(w, 60)
+	if bw < 12 || h < 4 {
+		return
+	}
+	x, y := (w-bw)/2, min(h/6, 2)
+	inner := bw - 4
+
+	if p.chosen != nil {
+		buf.FillRect(x, y, bw, 3, EmptyCell())
+		buf.DrawBorder(x, y, bw, 3, BorderRounded, overlayBorder)
+		buf.WriteString(x+2, y, " "+p.chosen.Desc+" ", overlayHeader)
+		n := buf.WriteStringClipped(x+2, y+1, p.chosen.prompt+": ", overlayDim, inner)
+		buf.WriteStringClipped(x+2+n, y+1, p.arg, Style{}, inner-n)
+		return
+	}
+
+	n := p.filter.Len()
+	bh := min(max(n, 1)+3, h-y, maxPaletteRows+3)
+	buf.FillRect(x, y, bw, bh, EmptyCell())
+	buf.DrawBorder(x, y, bw, bh, BorderRounded, overlayBorder)
+	buf.WriteString(x+2, y, " Commands ", overlayHeader)
+	buf.WriteStringClipped(x+2, y+1, "> ", overlayDim, inner)
+	buf.WriteStringClipped(x+4, y+1, p.query, Style{}, inner-2)
+
+	visible := bh - 3
+	if n == 0 && visible > 0 {
+		buf.WriteStringClipped(x+2, y+2, "no matching commands", overlayDim, inner)
+		return
+	}
+	if p.selected < p.scroll {
+		p.scroll = p.selected
+	} else if p.selected >= p.scroll+visible {
+		p.scroll = p.selected - visible + 1
+	}
+	for i := p.scroll; i < min(p.scroll+visible, n); i++ {
+		c := p.filter.Items[i]
+		ry := y + 2 + i - p.scroll
+		desc, keys := Style{}, overlayKey
+		if i == p.selected {
+			desc.Attr = desc.Attr.With(AttrInverse)
+			keys.Attr = keys.Attr.With(AttrInverse)
+			buf.FillRect(x+1, ry, bw-2, 1, Cell{Rune: ' ', Style: desc})
+		}
+		label := c.Desc
+		if label == "" {
+			label = c.label
+		}
+		keyW := min(len([]rune(c.Keys)), inner/2)
+		buf.WriteStringClipped(x+2, ry, label, desc, inner-keyW-1)
+		buf.WriteStringClipped(x+2+inner-keyW, ry, c.Keys, keys, keyW)
+	}
+}
@@ -0,0 +1,94 @@
+package glyph
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCommandPalette(t *testing.T) {
+	a, press := keyHelpApp()
+	saved, quit := 0, 0
+	a.HandleNamed("save", "<C-s>", func() { saved++ }).
+		Handle("q", func() { quit++ }).Describe("quit").
+		Handle("x", func() {}). // undescribed, not listed
+		Handle("<C-k>", a.CommandPalette)
+	items := []string{"a", "b"}
+	a.wireBindings(Build(List(&items).BindNav("j", "k")), a.router)
+
+	press("<C-k>")
+	if !a.CommandPaletteVisible() {
+		t.Fatal("palette not shown")
+	}
+	out := overlay(a, 60, 16)
+	for _, want := range []string{"Commands", "save", "ctrl-s", "quit", "down"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("palette missing %q:\n%s", want, out)
+		}
+	}
+
+	// typing fuzzy filters rather than firing bindings
+	press("sve")
+	out = overlay(a, 60, 16)
+	if !strings.Contains(out, "save") || strings.Contains(out, "quit") {
+		t.Errorf("filtered palette:\n%s", out)
+	}
+
+	<-a.renderChan
+	press("<Enter>")
+	if saved != 1 || a.CommandPaletteVisible() || a.input.Depth() != 1 {
+		t.Fatalf("saved %d, visible %v, depth %d", saved, a.CommandPaletteVisible(), a.input.Depth())
+	}
+	select {
+	case <-a.renderChan:
+	default:
+		t.Error("running a command did not request a render")
+	}
+
+	// the recently run command comes first; Down then Enter runs the next
+	press("<C-k>")
+	if rows := a.palette.filter.Items; rows[0].Action != "save" {
+		t.Errorf("first command = %+v", rows[0].KeyHelp)
+	}
+	press("<Down><Enter>")
+	if quit != 1 {
+		t.Errorf("quit ran %d times", quit)
+	}
+	press("<C-k>")
+	if rows := a.palette.filter.Items; rows[0].Desc != "quit" || rows[1].Action != "save" {
+		t.Errorf("recent order = %q, %q", rows[0].Desc, rows[1].Desc)
+	}
+	press("<Esc>")
+	if a.CommandPaletteVisible() || saved != 1 || quit != 1 {
+		t.Error("Escape did not just close the palette")
+	}
+}
+
+func TestCommandPaletteArgumentsAndUnbound(t *testing.T) {
+	a, press := keyHelpApp()
+	a.Keymap(&Keymap{Global: map[string][]string{"refresh": {}}})
+	refreshed := false
+	var line string
+	a.HandleNamed("refresh", "r", func() { refreshed = true }).
+		Command("goto_line", "Line", func(s string) { line = s }).
+		Handle(":", a.CommandPalette)
+
+	press("r")
+	if refreshed {
+		t.Fatal("unbound action still on its key")
+	}
+
+	press(":refresh<Enter>")
+	if !refreshed {
+		t.Error("unbound action not run from the palette")
+	}
+
+	press(":goto<Enter>")
+	out := overlay(a, 60, 16)
+	if !strings.Contains(out, "goto line") || !strings.Contains(out, "Line: ") {
+		t.Errorf("argument prompt:\n%s", out)
+	}
+	press("42<Enter>")
+	if line != "42" || a.CommandPaletteVisible() {
+		t.Errorf("line = %q, visible %v", line, a.CommandPaletteVisible())
+	}
+}
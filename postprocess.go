@@ -6,20 +6,20 @@ import (
 	"time"
 )
 
-// resolveColor16 updates Color16 cells in the buffer to use the detected
-// terminal palette RGB values. Called once before effects run so all
-// colour math operates on the terminal's actual colours.
-func resolveColor16(buf *Buffer, w, h int) {
+// resolveColor16 updates Color16 cells in the buffer to use the terminal's
+// palette RGB values. Called once before effects run so all colour math
+// operates on the terminal's actual colours.
+func resolveColor16(buf *Buffer, w, h int, palette *[16][3]uint8) {
 	for y := range h {
 		base := y * buf.width
 		for x := range w {
 			c := &buf.cells[base+x]
 			if c.Style.FG.Mode == Color16 {
-				rgb := basic16RGB[c.Style.FG.Index&0xF]
+				rgb := palette[c.Style.FG.Index&0xF]
 				c.Style.FG.R, c.Style.FG.G, c.Style.FG.B = rgb[0], rgb[1], rgb[2]
 			}
 			if c.Style.BG.Mode == Color16 {
-				rgb := basic16RGB[c.Style.BG.Index&0xF]
+				rgb := palette[c.Style.BG.Index&0xF]
 				c.Style.BG.R, c.Style.BG.G, c.Style.BG.B = rgb[0], rgb[1], rgb[2]
 			}
 		}
@@ -40,6 +40,42 @@ type effectCompilable interface {
 	compileEffect(t *Template) Effect
 }
 
+// animatedEffect is implemented by effects that change with time.
+type animatedEffect interface {
+	animated() bool
+}
+
+// Animated marks an effect as changing with time, as SEPulse does, so the
+// App renders frames for it while it's in the view. Effects that read
+// PostContext.Time or Frame need it, or they only move when something else
+// renders.
+func Animated(e Effect) Effect { return timedEffect{e} }
+
+type timedEffect struct{ Effect }
+
+func (timedEffect) animated() bool { return true }
+
+func (a timedEffect) compileEffect(t *Template) Effect {
+	if ec, ok := a.Effect.(effectCompilable); ok {
+		return timedEffect{ec.compileEffect(t)}
+	}
+	return a
+}
+
+func isAnimated(e Effect) bool {
+	a, ok := e.(animatedEffect)
+	return ok && a.animated()
+}
+
+func anyAnimated(effects []Effect) bool {
+	for _, e := range effects {
+		if isAnimated(e) {
+			return true
+		}
+	}
+	return false
+}
+
 // funcEffect adapts a bare function to the Effect interface.
 // Returned by EachCell and used internally by blend/quantize wrappers.
 type funcEffect func(*Buffer, PostContext)
@@ -60,6 +96,21 @@ func ScreenEffect(pp ...Effect) ScreenEffectNode {
 	return ScreenEffectNode{Effects: pp}
 }
 
+// EffectNode runs post-processing passes over its child's bounds.
+type EffectNode struct {
+	Child   any
+	Effects []Effect
+}
+
+// WithEffect runs post-processing passes over child once it has drawn,
+// clipped to its bounds, as VBox.Effect does for a container. child can be
+// any node: a LayerView, a Table, a bordered card.
+//
+//	WithEffect(LayerView(logs).Grow(1), SEDesaturate().Strength(&logsDim))
+func WithEffect(child any, pp ...Effect) EffectNode {
+	return EffectNode{Child: child, Effects: pp}
+}
+
 // PostContext provides frame metadata to post-processing passes.
 type PostContext struct {
 	Width  int
@@ -72,6 +123,112 @@ type PostContext struct {
 	// Mode == ColorRGB when detected, ColorDefault when unknown.
 	DefaultFG Color
 	DefaultBG Color
+
+	// Bounds is where the buffer the pass gets sits on screen: the whole
+	// screen, or the node for a pass given to a container's Effect.
+	// Cell x, y is at Bounds.X+x, Bounds.Y+y.
+	Bounds Rect
+}
+
+// local moves ref from screen coordinates into the pass's own.
+func (ctx PostContext) local(ref *NodeRef) *NodeRef {
+	if ref == nil || (ctx.Bounds.X == 0 && ctx.Bounds.Y == 0) {
+		return ref
+	}
+	r := *ref
+	r.X -= ctx.Bounds.X
+	r.Y -= ctx.Bounds.Y
+	return &r
+}
+
+// compileEffects wires the dynamic properties of effects into t.
+func (t *Template) compileEffects(effects []Effect) []Effect {
+	out := make([]Effect, len(effects))
+	for i, eff := range effects {
+		if ec, ok := eff.(effectCompilable); ok {
+			eff = ec.compileEffect(t)
+		}
+		out[i] = eff
+	}
+	return out
+}
+
+// compileNodeEffects gives the op at idx passes to run over its bounds,
+// after any it already has.
+func (t *Template) compileNodeEffects(idx int16, effects []Effect) {
+	if len(effects) == 0 || idx < 0 {
+		return
+	}
+	op := &t.ops[idx]
+	if op.Dyn == nil {
+		op.Dyn = &OpDyn{}
+	}
+	if op.Dyn.Effects == nil {
+		op.Dyn.Effects = &nodeEffects{}
+	}
+	e := op.Dyn.Effects
+	e.fns = append(e.fns, t.compileEffects(effects)...)
+	e.animated = anyAnimated(e.fns)
+}
+
+// applyNodeEffects runs the passes of op, at x, y and w by h once its
+// margin is taken off, over what it drew.
+func (t *Template) applyNodeEffects(buf *Buffer, op *Op, x, y, w, h int16) {
+	if op.Kind == OpContainer {
+		x += op.Margin[3] // containers handle their margin themselves
+		y += op.Margin[0]
+	}
+	ctx, palette := PostContext{}, &basic16RGB
+	if t.app != nil {
+		ctx = t.app.frameCtx
+		if t.app.screen != nil {
+			palette = &t.app.screen.palette
+		}
+		t.app.nodeEffected = true
+	}
+	op.Dyn.Effects.apply(buf, Rect{X: int(x), Y: int(y), W: int(w), H: int(h)}, int(t.clipMaxY), ctx, palette)
+	if op.Dyn.Effects.animated {
+		t.evalRoot().tick(everyFrame)
+	}
+}
+
+// nodeEffects are the passes a node's Effect runs over its bounds, with
+// the buffer they run in.
+type nodeEffects struct {
+	fns      []Effect
+	animated bool // a pass changes with time
+	buf      *Buffer
+}
+
+// apply runs the passes over r of buf, clipped to buf and above maxY when
+// that isn't 0. The passes get a buffer of just those cells.
+func (e *nodeEffects) apply(buf *Buffer, r Rect, maxY int, ctx PostContext, palette *[16][3]uint8) {
+	x0, y0 := max(r.X, 0), max(r.Y, 0)
+	x1, y1 := min(r.X+r.W, buf.width), min(r.Y+r.H, buf.height)
+	if maxY > 0 {
+		y1 = min(y1, maxY)
+	}
+	w, h := x1-x0, y1-y0
+	if w <= 0 || h <= 0 {
+		return
+	}
+	if e.buf == nil || e.buf.width != w || e.buf.height != h {
+		e.buf = NewBuffer(w, h)
+	}
+	for y := range h {
+		copy(e.buf.cells[y*w:(y+1)*w], buf.cells[(y0+y)*buf.width+x0:])
+	}
+	resolveColor16(e.buf, w, h, palette)
+
+	ctx.Width, ctx.Height = w, h
+	ctx.Bounds = Rect{X: x0, Y: y0, W: w, H: h}
+	for _, eff := range e.fns {
+		eff.Apply(e.buf, ctx)
+	}
+	for y := range h {
+		copy(buf.cells[(y0+y)*buf.width+x0:(y0+y)*buf.width+x1], e.buf.cells[y*w:])
+		buf.dirtyRows[y0+y] = true
+	}
 }
 
 // EachCell wraps a per-cell transform into a Effect.
@@ -81,6 +238,8 @@ type PostContext struct {
 func EachCell(fn func(x, y int, cell Cell, ctx PostContext) Cell) Effect {
 	return funcEffect(func(buf *Buffer, ctx PostContext) {
 		qp := getCellPool()
+		qp.mu.Lock() // one frame at a time across apps
+		defer qp.mu.Unlock()
 		midX, midY := ctx.Width/2, ctx.Height/2
 
 		// set shared work (safe: written before wakeup send, read after recv)
@@ -115,6 +274,7 @@ func EachCell(fn func(x, y int, cell Cell, ctx PostContext) Cell) Effect {
 }
 
 type cellPool struct {
+	mu     sync.Mutex
 	fn     func(x, y int, cell Cell, ctx PostContext) Cell
 	buf    *Buffer
 	ctx    PostContext
@@ -275,6 +435,8 @@ func (b blendEffect) Apply(buf *Buffer, ctx PostContext) {
 	}
 }
 
+func (b blendEffect) animated() bool { return isAnimated(b.inner) }
+
 // WithBlend wraps any Effect with a blend mode. Snapshots the buffer
 // before the effect runs, then blends the effect's output with the original
 // using the specified mode. Works with any effect, plasma through multiply,
@@ -288,8 +450,12 @@ func WithBlend(mode BlendMode, pp Effect) Effect {
 // Use step=32 to reduce bytes/frame by ~40-50% with acceptable banding at typical terminal sizes.
 func WithQuantize(step uint8, pp Effect) Effect {
 	q := SEQuantize(step)
-	return funcEffect(func(buf *Buffer, ctx PostContext) {
+	var e Effect = funcEffect(func(buf *Buffer, ctx PostContext) {
 		pp.Apply(buf, ctx)
 		q.Apply(buf, ctx)
 	})
+	if isAnimated(pp) {
+		e = Animated(e)
+	}
+	return e
 }
@@ -1,8 +1,12 @@
 package glyph
 
 import (
+	"bytes"
+	"os"
+	"strings"
 	"testing"
 	"time"
+	"unicode"
 )
 
 func TestEachCell(t *testing.T) {
@@ -845,25 +849,76 @@ func TestParseOSC4Color(t *testing.T) {
 	}
 }
 
+// osc4Replier answers the colour queries written to the screen as a
+// terminal would, on the pty's master side.
+type osc4Replier struct {
+	master *os.File
+	reply  string
+}
+
+func (r osc4Replier) Write(p []byte) (int, error) {
+	if bytes.Contains(p, []byte("\x1b]4;")) {
+		r.master.WriteString(r.reply)
+	}
+	return len(p), nil
+}
+
+func TestQueryDefaultColorsPalette(t *testing.T) {
+	master, slave, err := openPTY()
+	if err != nil {
+		t.Skipf("no pty: %v", err)
+	}
+	t.Cleanup(func() { master.Close(); slave.Close() })
+
+	reply := "\x1b]10;rgb:eeee/eeee/eeee\x07\x1b]11;rgb:1111/1111/1111\x07" +
+		"\x1b]4;2;rgb:4e4e/d2d2/8e8e\x07"
+	s := &Screen{
+		front: NewBuffer(20, 4), back: NewBuffer(20, 4),
+		writer: osc4Replier{master, reply}, fd: int(slave.Fd()), width: 20, height: 4,
+		resizeChan: make(chan Size, 1), sigChan: make(chan os.Signal, 1),
+		lastStyle: DefaultStyle(), palette: basic16RGB,
+	}
+	if err := s.EnterRawMode(); err != nil {
+		t.Skipf("raw mode: %v", err)
+	}
+	t.Cleanup(func() { s.ExitRawMode() })
+	stdin := os.Stdin
+	os.Stdin = slave
+	t.Cleanup(func() { os.Stdin = stdin })
+	orig := basic16RGB
+	t.Cleanup(func() { basic16RGB = orig; refreshBasic16Vars() })
+
+	fg, bg := s.QueryDefaultColors()
+	if fg != RGB(238, 238, 238) || bg != RGB(17, 17, 17) {
+		t.Errorf("fg = %+v, bg = %+v", fg, bg)
+	}
+	if got := s.palette[2]; got != [3]uint8{78, 210, 142} {
+		t.Errorf("palette[2] = %v, want the reported green", got)
+	}
+	if s.palette[1] != ansi16RGB[1] {
+		t.Errorf("palette[1] = %v, want the default it wasn't sent", s.palette[1])
+	}
+	a := &App{screen: s}
+	if a.Palette() != s.palette {
+		t.Error("App.Palette doesn't report the screen's palette")
+	}
+	// the process terminal's palette drives the package colours too
+	if Green.R != 78 || Green.G != 210 || Green.B != 142 {
+		t.Errorf("Green = %+v, want the reported green", Green)
+	}
+}
+
 func TestRefreshBasic16Vars(t *testing.T) {
-	// save original
 	origGreen := basic16RGB[2]
-	defer func() {
-		basic16RGB[2] = origGreen
-		refreshBasic16Vars()
-	}()
+	defer func() { basic16RGB[2] = origGreen; refreshBasic16Vars() }()
 
-	// simulate OSC 4 detection changing green to a themed value
 	basic16RGB[2] = [3]uint8{78, 210, 142}
 	refreshBasic16Vars()
-
 	if Green.R != 78 || Green.G != 210 || Green.B != 142 {
-		t.Errorf("Green after refresh: got (%d,%d,%d), want (78,210,142)",
-			Green.R, Green.G, Green.B)
+		t.Errorf("Green = %+v, want RGB from updated table", Green)
 	}
 	if Green.Mode != Color16 || Green.Index != 2 {
-		t.Errorf("Green should stay Color16 index 2, got mode=%d index=%d",
-			Green.Mode, Green.Index)
+		t.Errorf("Green mode/index changed: %+v", Green)
 	}
 }
 
@@ -892,3 +947,83 @@ func TestResolveFG(t *testing.T) {
 		t.Errorf("resolveFG(explicit): should pass through, got (%d,%d,%d)", c.R, c.G, c.B)
 	}
 }
+
+func TestNodeEffectClipped(t *testing.T) {
+	var got PostContext
+	hash := funcEffect(func(buf *Buffer, ctx PostContext) {
+		got = ctx
+		for y := range ctx.Height {
+			for x := range ctx.Width {
+				buf.Set(x, y, Cell{Rune: '#'})
+			}
+		}
+	})
+	tmpl := Build(VBox(
+		Text("top"),
+		HBox(
+			VBox.Width(4)(Text("left")),
+			VBox.Width(3).Effect(hash)(Text("mid"), Text("mid")),
+			Text("right"),
+		),
+	))
+	buf := NewBuffer(12, 4)
+	tmpl.Execute(buf, 12, 4)
+
+	want := []string{"top", "left###right", "    ###", ""}
+	for y, line := range want {
+		if row := strings.TrimRight(buf.GetLine(y), " "); row != line {
+			t.Errorf("row %d = %q, want %q", y, row, line)
+		}
+	}
+	if b := (Rect{X: 4, Y: 1, W: 3, H: 2}); got.Bounds != b || got.Width != 3 || got.Height != 2 {
+		t.Errorf("ctx = %dx%d at %+v, want 3x2 at %+v", got.Width, got.Height, got.Bounds, b)
+	}
+	if len(tmpl.ScreenEffects()) != 0 {
+		t.Error("node effect collected as a screen effect")
+	}
+}
+
+func TestNodeEffectOnAnyNode(t *testing.T) {
+	upper := EachCell(func(_, _ int, c Cell, _ PostContext) Cell {
+		c.Rune = unicode.ToUpper(c.Rune)
+		return c
+	})
+	layer := NewLayer()
+	lb := NewBuffer(4, 2)
+	lb.WriteStringFast(0, 0, "ab", Style{}, 4)
+	lb.WriteStringFast(0, 1, "cd", Style{}, 4)
+	layer.SetBuffer(lb)
+	items := []string{"x", "y"}
+
+	tmpl := Build(VBox(
+		LayerView(layer).ViewHeight(2).Effect(upper),
+		WithEffect(Text("card"), upper),
+		Text("plain"),
+		ForEach(&items, func(s *string) any { return WithEffect(Text(s), upper) }),
+	))
+	buf := NewBuffer(8, 7)
+	tmpl.Execute(buf, 8, 7)
+
+	want := []string{"AB", "CD", "CARD", "plain", "X", "Y", ""}
+	for y, line := range want {
+		if row := strings.TrimRight(buf.GetLine(y), " "); row != line {
+			t.Errorf("row %d = %q, want %q", y, row, line)
+		}
+	}
+}
+
+func TestNodeEffectLocalRefs(t *testing.T) {
+	var ref NodeRef
+	tmpl := Build(HBox(
+		Text("ab"),
+		VBox.Effect(SEFocusDim(&ref))(HBox(Text("cd"), VBox.NodeRef(&ref)(Text("ef")))),
+	))
+	buf := NewBuffer(8, 1)
+	tmpl.Execute(buf, 8, 1)
+
+	for x, dim := range []bool{false, false, true, true, false, false} {
+		if got := buf.Get(x, 0).Style.Attr.Has(AttrDim); got != dim {
+			t.Errorf("cell %d (%c) dim = %v, want %v", x, buf.Get(x, 0).Rune, got, dim)
+		}
+	}
+}
@@ -165,6 +165,7 @@ func (t tintEffect) compileEffect(tmpl *Template) Effect {
 }
 
 func (t tintEffect) Apply(buf *Buffer, ctx PostContext) {
+	t.dodge = ctx.local(t.dodge)
 	s := t.strength.resolve()
 	EachCell(func(x, y int, c Cell, ectx PostContext) Cell {
 		if t.dodge != nil && inRect(x, y, t.dodge) {
@@ -208,6 +209,7 @@ func (v vignetteEffect) Dodge(ref *NodeRef) vignetteEffect { v.dodge = ref; retu
 func (v vignetteEffect) Smooth() vignetteEffect { v.quantize = false; return v }
 
 func (v vignetteEffect) Apply(buf *Buffer, ctx PostContext) {
+	v.focus, v.dodge = ctx.local(v.focus), ctx.local(v.dodge)
 	black := Color{Mode: ColorRGB}
 	var cx, cy float64
 	if v.focus != nil {
@@ -270,6 +272,7 @@ func (d desaturateEffect) compileEffect(tmpl *Template) Effect {
 func (d desaturateEffect) Dodge(ref *NodeRef) desaturateEffect { d.dodge = ref; return d }
 
 func (d desaturateEffect) Apply(buf *Buffer, ctx PostContext) {
+	d.dodge = ctx.local(d.dodge)
 	s := d.strength.resolve()
 	EachCell(func(x, y int, c Cell, ectx PostContext) Cell {
 		if d.dodge != nil && inRect(x, y, d.dodge) {
@@ -303,6 +306,7 @@ func (h contrastEffect) compileEffect(tmpl *Template) Effect {
 func (h contrastEffect) Dodge(ref *NodeRef) contrastEffect { h.dodge = ref; return h }
 
 func (h contrastEffect) Apply(buf *Buffer, ctx PostContext) {
+	h.dodge = ctx.local(h.dodge)
 	s := h.strength.resolve()
 	EachCell(func(x, y int, c Cell, ectx PostContext) Cell {
 		if h.dodge != nil && inRect(x, y, h.dodge) {
@@ -326,6 +330,7 @@ type focusDimEffect struct{ ref *NodeRef }
 func SEFocusDim(ref *NodeRef) focusDimEffect { return focusDimEffect{ref: ref} }
 
 func (f focusDimEffect) Apply(buf *Buffer, ctx PostContext) {
+	f.ref = ctx.local(f.ref)
 	rx, ry := f.ref.X, f.ref.Y
 	rw, rh := f.ref.W, f.ref.H
 
@@ -356,6 +361,8 @@ func (p pulseEffect) Speed(s any) pulseEffect { p.speed.set(s); return p }
 // Strength sets how much brightness dims at the trough (0.3 = subtle, 0.8 = dramatic).
 func (p pulseEffect) Strength(s any) pulseEffect { p.strength.set(s); return p }
 
+func (pulseEffect) animated() bool { return true }
+
 func (p pulseEffect) compileEffect(tmpl *Template) Effect {
 	p.speed.compileArmed(tmpl)
 	p.strength.compileArmed(tmpl)
@@ -451,7 +458,7 @@ func (d dropShadowEffect) Apply(buf *Buffer, ctx PostContext) {
 		return
 	}
 
-	ref := d.focus
+	ref := ctx.local(d.focus)
 	radius := float64(d.radius.resolve())
 	sx, sy := ref.X+d.offsetX, ref.Y+d.offsetY
 
@@ -527,7 +534,7 @@ func (g glowEffect) Apply(buf *Buffer, ctx PostContext) {
 		return
 	}
 
-	ref := g.focus
+	ref := ctx.local(g.focus)
 	radius := float64(g.radius.resolve())
 
 	for y := range ctx.Height {
@@ -610,6 +617,7 @@ func (b bloomEffect) compileEffect(tmpl *Template) Effect {
 func (b bloomEffect) Focus(ref *NodeRef) bloomEffect { b.focus = ref; return b }
 
 func (b bloomEffect) Apply(buf *Buffer, ctx PostContext) {
+	b.focus = ctx.local(b.focus)
 	bw, bh := ctx.Width, ctx.Height
 	// snapshot raw FG colours — do NOT resolve ColorDefault to terminal FG here.
 	// only cells with explicit colours (ColorRGB, Color16) should act as sources.
@@ -706,6 +714,7 @@ func SEMonochrome(tint Color) monochromeEffect { return monochromeEffect{tint: t
 func (m monochromeEffect) Dodge(ref *NodeRef) monochromeEffect { m.dodge = ref; return m }
 
 func (m monochromeEffect) Apply(buf *Buffer, ctx PostContext) {
+	m.dodge = ctx.local(m.dodge)
 	EachCell(func(x, y int, c Cell, ectx PostContext) Cell {
 		if m.dodge != nil && inRect(x, y, m.dodge) {
 			return c
@@ -750,6 +759,8 @@ func SEScreenShake(amplitude float64) screenShakeEffect {
 	return screenShakeEffect{amplitude: amplitude}
 }
 
+func (screenShakeEffect) animated() bool { return true }
+
 func (s screenShakeEffect) Apply(buf *Buffer, ctx PostContext) {
 	offset := int(math.Round(math.Sin(float64(ctx.Frame)*1.5) * s.amplitude))
 	if offset == 0 {
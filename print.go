@@ -0,0 +1,92 @@
+package glyph
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Println prints a line above an inline app's view, into the terminal's
+// scrollback, and redraws the view below it. Operands are separated by
+// spaces; Span and []Span operands keep their styles. Safe to call from
+// any goroutine.
+//
+//	app.Println(Span{Text: "✓", Style: Style{FG: Green}}, "built", pkg)
+//
+// Fullscreen apps have no scrollback to print into, so their lines are
+// printed when they exit.
+func (a *App) Println(args ...any) {
+	a.print(printSpans(args))
+}
+
+// Printf prints a formatted line above an inline app's view. See Println.
+func (a *App) Printf(format string, args ...any) {
+	a.print(printSpans([]any{strings.TrimSuffix(fmt.Sprintf(format, args...), "\n")}))
+}
+
+// print queues lines for the next frame to print.
+func (a *App) print(lines [][]Span) {
+	a.printMu.Lock()
+	a.printed = append(a.printed, lines...)
+	a.printMu.Unlock()
+	a.RequestRender()
+}
+
+// takePrinted returns the lines queued by Println and clears the queue.
+func (a *App) takePrinted() [][]Span {
+	a.printMu.Lock()
+	defer a.printMu.Unlock()
+	lines := a.printed
+	a.printed = nil
+	return lines
+}
+
+// hasPrinted reports whether Println lines are waiting to be printed.
+func (a *App) hasPrinted() bool {
+	a.printMu.Lock()
+	defer a.printMu.Unlock()
+	return len(a.printed) > 0
+}
+
+// printHeld writes lines still queued once the app has left the screen.
+func (a *App) printHeld() {
+	if lines := a.takePrinted(); len(lines) > 0 {
+		a.screen.printLines(lines)
+	}
+}
+
+// printSpans converts Println operands to lines of spans, splitting at
+// newlines.
+func printSpans(args []any) [][]Span {
+	var lines [][]Span
+	var line []Span
+	add := func(sp Span) {
+		for {
+			text, rest, more := strings.Cut(sp.Text, "\n")
+			if text != "" {
+				line = append(line, Span{Text: text, Style: sp.Style})
+			}
+			if !more {
+				return
+			}
+			lines = append(lines, line)
+			line = nil
+			sp.Text = rest
+		}
+	}
+	for i, arg := range args {
+		if i > 0 {
+			add(Span{Text: " "})
+		}
+		switch v := arg.(type) {
+		case Span:
+			add(v)
+		case []Span:
+			for _, sp := range v {
+				add(sp)
+			}
+		default:
+			add(Span{Text: fmt.Sprint(v)})
+		}
+	}
+	return append(lines, line)
+}
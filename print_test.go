@@ -0,0 +1,102 @@
+package glyph
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kungfusheep/riffkey"
+)
+
+// printApp returns an inline App drawing rows into a terminal emulator.
+func printApp(rows *[]string, w, h int) (*App, *vtScreen) {
+	vt := newVTScreen(w, h)
+	s := &Screen{
+		front: NewBuffer(w, h), back: NewBuffer(w, h),
+		writer: vt, width: w, height: h, inlineMode: true,
+		resizeChan: make(chan Size, 1), lastStyle: DefaultStyle(),
+	}
+	router := riffkey.NewRouter()
+	a := &App{screen: s, router: router, input: riffkey.NewInput(router), renderChan: make(chan struct{}, 1), inline: true}
+	a.SetView(VBox(ForEach(rows, func(r *string) any { return Text(r) })))
+	return a, vt
+}
+
+func TestPrintlnAboveInlineView(t *testing.T) {
+	rows := []string{"step 1", "step 2", "step 3"}
+	a, vt := printApp(&rows, 30, 8)
+	a.render()
+
+	// printed lines go above as the view shrinks beneath them
+	a.Println(Span{Text: "✓", Style: Style{FG: Green}}, "built", "pkg/foo")
+	a.Printf("%d tests\n", 12)
+	rows = rows[:1]
+	a.render()
+	want := []string{"✓ built pkg/foo", "12 tests", "step 1", "", "", "", "", ""}
+	if got := vtLines(vt); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("screen = %q, want %q", got, want)
+	}
+	if c := vt.grid.Get(0, 0); c.Style.FG != Green {
+		t.Errorf("span style = %+v", c.Style)
+	}
+
+	// and the view grows again below them
+	rows = append(rows, "step 2", "step 3")
+	a.Println("multi\nline")
+	a.render()
+	want = []string{"✓ built pkg/foo", "12 tests", "multi", "line", "step 1", "step 2", "step 3", ""}
+	if got := vtLines(vt); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("screen = %q, want %q", got, want)
+	}
+}
+
+func TestPrintlnConcurrent(t *testing.T) {
+	rows := []string{"working"}
+	a, vt := printApp(&rows, 30, 60)
+	a.render()
+
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a.Printf("line %d", i)
+			if i%10 == 0 {
+				a.render()
+			}
+		}()
+	}
+	wg.Wait()
+	a.render()
+
+	lines := vtLines(vt)
+	seen := make(map[string]bool)
+	for _, l := range lines[:50] {
+		seen[l] = true
+	}
+	for i := range 50 {
+		if !seen[fmt.Sprintf("line %d", i)] {
+			t.Errorf("line %d lost", i)
+		}
+	}
+	if lines[50] != "working" || lines[51] != "" {
+		t.Errorf("view = %q", lines[50:52])
+	}
+}
+
+func TestPrintlnHeldWhileFullscreen(t *testing.T) {
+	rows := []string{"view"}
+	a, vt := printApp(&rows, 30, 4)
+	a.inline = false
+	a.Println("after exit")
+	a.render()
+	if strings.Contains(strings.Join(vtLines(vt), "|"), "after exit") {
+		t.Fatal("printed over a fullscreen view")
+	}
+	vt.reset()
+	a.printHeld()
+	if got := vtLines(vt)[0]; got != "after exit" {
+		t.Errorf("held line = %q", got)
+	}
+}
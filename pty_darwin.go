@@ -0,0 +1,47 @@
+package glyph
+
+import (
+	"bytes"
+	"os"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// openPTY allocates a pseudo-terminal pair via /dev/ptmx.
+func openPTY() (master, slave *os.File, err error) {
+	master, err = os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	var name [128]byte
+	err = ptyControl(master, func(fd int) error {
+		if err := unix.IoctlSetInt(fd, unix.TIOCPTYGRANT, 0); err != nil {
+			return err
+		}
+		if err := unix.IoctlSetInt(fd, unix.TIOCPTYUNLK, 0); err != nil {
+			return err
+		}
+		_, _, errno := unix.Syscall(unix.SYS_IOCTL, uintptr(fd), uintptr(unix.TIOCPTYGNAME), uintptr(unsafe.Pointer(&name[0])))
+		if errno != 0 {
+			return errno
+		}
+		return nil
+	})
+	if err != nil {
+		master.Close()
+		return nil, nil, err
+	}
+	end := bytes.IndexByte(name[:], 0)
+	if end < 0 {
+		end = len(name)
+	}
+	path := string(name[:end])
+	slave, err = os.OpenFile(path, os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		master.Close()
+		return nil, nil, err
+	}
+	return master, slave, nil
+}
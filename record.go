@@ -0,0 +1,127 @@
+package glyph
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// recording writes a session as an asciicast v2 stream: a JSON header line,
+// then one [seconds, kind, data] line per event.
+type recording struct {
+	mu    sync.Mutex
+	w     io.Writer
+	start time.Time
+	input bool
+	err   error // the first write error; later events are dropped
+}
+
+// Record starts writing the session to w as an asciicast v2 recording, the
+// format asciinema plays. Every frame written to the terminal is recorded
+// with its time, along with resizes, and typed keys if RecordInput is set.
+// The next frame is drawn in full so the recording starts complete. Call
+// StopRecording to end it.
+//
+//	f, _ := os.Create("session.cast")
+//	app.Record(f).RecordInput(true)
+func (a *App) Record(w io.Writer) *App {
+	size := a.screen.Size()
+	r := &recording{w: w, start: time.Now()}
+	header, _ := json.Marshal(struct {
+		Version   int               `json:"version"`
+		Width     int               `json:"width"`
+		Height    int               `json:"height"`
+		Timestamp int64             `json:"timestamp"`
+		Env       map[string]string `json:"env"`
+	}{2, size.Width, size.Height, r.start.Unix(), map[string]string{"TERM": os.Getenv("TERM"), "SHELL": os.Getenv("SHELL")}})
+	r.write(append(header, '\n'))
+
+	if old := a.screen.recording.Swap(r); old != nil {
+		r.input = old.input
+	}
+	a.renderMu.Lock()
+	a.redrawFull = true
+	a.renderMu.Unlock()
+	a.RequestRender()
+	return a
+}
+
+// RecordInput sets whether the recording started by Record includes the
+// keys typed, as input events.
+func (a *App) RecordInput(on bool) *App {
+	if r := a.screen.recording.Load(); r != nil {
+		r.mu.Lock()
+		r.input = on
+		r.mu.Unlock()
+	}
+	return a
+}
+
+// StopRecording ends the recording started by Record and returns the
+// first error writing it.
+func (a *App) StopRecording() error {
+	r := a.screen.recording.Swap(nil)
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// event records data of kind "o" (output) or "i" (input).
+func (r *recording) event(kind string, data []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if kind == "i" && !r.input {
+		return
+	}
+	r.writeEvent(kind, string(data))
+}
+
+// resize records the terminal's new size.
+func (r *recording) resize(width, height int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.writeEvent("r", strconv.Itoa(width)+"x"+strconv.Itoa(height))
+}
+
+// writeEvent writes one event line. Called with mu held.
+func (r *recording) writeEvent(kind, data string) {
+	line := make([]byte, 0, len(data)+32)
+	line = append(line, '[')
+	line = strconv.AppendFloat(line, time.Since(r.start).Seconds(), 'f', 6, 64)
+	line = append(line, `, "`...)
+	line = append(line, kind...)
+	line = append(line, `", `...)
+	quoted, _ := json.Marshal(data)
+	line = append(line, quoted...)
+	line = append(line, "]\n"...)
+	r.write(line)
+}
+
+func (r *recording) write(p []byte) {
+	if r.err == nil {
+		_, r.err = r.w.Write(p)
+	}
+}
+
+// recordedInput passes the terminal's input through to the key reader,
+// recording it when the recording asks for input.
+type recordedInput struct {
+	r      io.Reader
+	screen *Screen
+}
+
+func (in recordedInput) Read(p []byte) (int, error) {
+	n, err := in.r.Read(p)
+	if n > 0 {
+		if r := in.screen.recording.Load(); r != nil {
+			r.event("i", p[:n])
+		}
+	}
+	return n, err
+}
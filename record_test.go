@@ -0,0 +1,110 @@
+package glyph
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kungfusheep/riffkey"
+)
+
+// castEvents decodes the event lines of an asciicast v2 recording.
+func castEvents(t *testing.T, cast string) (header map[string]any, events [][3]any) {
+	t.Helper()
+	lines := strings.Split(strings.TrimSuffix(cast, "\n"), "\n")
+	if err := json.Unmarshal([]byte(lines[0]), &header); err != nil {
+		t.Fatalf("header %q: %v", lines[0], err)
+	}
+	for _, l := range lines[1:] {
+		var e [3]any
+		if err := json.Unmarshal([]byte(l), &e); err != nil {
+			t.Fatalf("event %q: %v", l, err)
+		}
+		events = append(events, e)
+	}
+	return header, events
+}
+
+func TestRecord(t *testing.T) {
+	vt := newVTScreen(20, 3)
+	s := &Screen{
+		front: NewBuffer(20, 3), back: NewBuffer(20, 3),
+		writer: vt, width: 20, height: 3,
+		resizeChan: make(chan Size, 1), lastStyle: DefaultStyle(),
+	}
+	router := riffkey.NewRouter()
+	a := &App{screen: s, router: router, input: riffkey.NewInput(router), renderChan: make(chan struct{}, 1)}
+	msg := "hello"
+	a.SetView(Text(&msg))
+	a.render()
+
+	var cast bytes.Buffer
+	a.Record(&cast)
+	a.render() // drawn in full though unchanged
+	msg = "world"
+	a.render()
+	s.recording.Load().resize(30, 5)
+
+	// typed keys are only recorded when asked for
+	in := recordedInput{strings.NewReader("ab"), s}
+	in.Read(make([]byte, 1))
+	a.RecordInput(true)
+	in.Read(make([]byte, 1))
+
+	if err := a.StopRecording(); err != nil {
+		t.Fatal(err)
+	}
+	a.render()
+
+	header, events := castEvents(t, cast.String())
+	if header["version"] != 2.0 || header["width"] != 20.0 || header["height"] != 3.0 {
+		t.Errorf("header = %v", header)
+	}
+	var kinds, output []string
+	last := 0.0
+	for _, e := range events {
+		if e[0].(float64) < last {
+			t.Errorf("time went back: %v", e)
+		}
+		last = e[0].(float64)
+		kinds = append(kinds, e[1].(string))
+		if e[1] == "o" {
+			output = append(output, e[2].(string))
+		}
+	}
+	if got := strings.Join(kinds, ","); !strings.HasSuffix(got, "o,r,i") || strings.Count(got, "o") < 2 {
+		t.Errorf("events = %s", got)
+	}
+	frames := strings.Join(output, "")
+	if i := strings.Index(frames, "hello"); i < 0 || !strings.Contains(frames[i:], "wor") {
+		t.Errorf("frames = %q", output)
+	}
+	if n := len(events); events[n-2][2] != "30x5" || events[n-1][2] != "b" {
+		t.Errorf("resize and input = %v, %v", events[n-2], events[n-1])
+	}
+
+	// the recorded output redraws the screen
+	replay := newVTScreen(20, 3)
+	replay.Write([]byte(frames))
+	if got := replay.grid.GetLine(0); got != "world" {
+		t.Errorf("replayed line = %q", got)
+	}
+}
+
+type failWriter struct{}
+
+func (failWriter) Write(p []byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestRecordWriteError(t *testing.T) {
+	a, _ := printApp(&[]string{"x"}, 10, 2)
+	a.Record(failWriter{})
+	a.render()
+	if err := a.StopRecording(); err == nil || err.Error() != "disk full" {
+		t.Errorf("StopRecording = %v", err)
+	}
+	if err := a.StopRecording(); err != nil {
+		t.Errorf("second StopRecording = %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+// Package replay plays asciicast v2 recordings, such as those written by
+// glyph's App.Record, inside a glyph view.
+//
+//	cast, err := replay.Load("session.cast")
+//	player := replay.NewPlayer(cast).Bind(app)
+//	app.SetView(VBox(
+//		LayerView(player.Layer()).Grow(1),
+//		Text(player.Status),
+//	))
+//	player.Play()
+package replay
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Event is one entry of a recording.
+type Event struct {
+	Time time.Duration // since the recording started
+	Kind string        // "o" output, "i" input, "r" resize to Data ("80x24"), "m" marker
+	Data string
+}
+
+// Cast is a parsed asciicast v2 recording.
+type Cast struct {
+	Width, Height int // terminal size at the start
+	Timestamp     time.Time
+	Title         string
+	Events        []Event
+}
+
+// Duration returns the time of the last event.
+func (c *Cast) Duration() time.Duration {
+	if len(c.Events) == 0 {
+		return 0
+	}
+	return c.Events[len(c.Events)-1].Time
+}
+
+// Read parses an asciicast v2 recording.
+func Read(r io.Reader) (*Cast, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 64*1024*1024) // a full-screen frame is one line
+	if !sc.Scan() {
+		if err := sc.Err(); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("replay: empty recording")
+	}
+	var header struct {
+		Version   int    `json:"version"`
+		Width     int    `json:"width"`
+		Height    int    `json:"height"`
+		Timestamp int64  `json:"timestamp"`
+		Title     string `json:"title"`
+	}
+	if err := json.Unmarshal(sc.Bytes(), &header); err != nil {
+		return nil, fmt.Errorf("replay: header: %w", err)
+	}
+	if header.Version != 2 {
+		return nil, fmt.Errorf("replay: asciicast version %d, want 2", header.Version)
+	}
+	if header.Width <= 0 || header.Height <= 0 {
+		return nil, fmt.Errorf("replay: terminal size %dx%d", header.Width, header.Height)
+	}
+	c := &Cast{Width: header.Width, Height: header.Height, Title: header.Title}
+	if header.Timestamp != 0 {
+		c.Timestamp = time.Unix(header.Timestamp, 0)
+	}
+
+	for line := 2; sc.Scan(); line++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var fields []json.RawMessage
+		var e Event
+		var secs float64
+		err := json.Unmarshal(sc.Bytes(), &fields)
+		if err == nil && len(fields) != 3 {
+			err = fmt.Errorf("%d fields, want 3", len(fields))
+		}
+		if err == nil {
+			err = errors.Join(json.Unmarshal(fields[0], &secs), json.Unmarshal(fields[1], &e.Kind), json.Unmarshal(fields[2], &e.Data))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("replay: line %d: %w", line, err)
+		}
+		e.Time = time.Duration(secs * float64(time.Second))
+		c.Events = append(c.Events, e)
+	}
+	return c, sc.Err()
+}
+
+// Load reads the recording in the file at path.
+func Load(path string) (*Cast, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
@@ -0,0 +1,289 @@
+package replay
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kungfusheep/glyph"
+)
+
+// seekStep is how far the bound seek keys move.
+const seekStep = 5 * time.Second
+
+// Player plays a Cast into a Layer, through a terminal emulator. Show it
+// with glyph.LayerView(player.Layer()). Seeking back replays the recording
+// from the start.
+type Player struct {
+	cast  *Cast
+	layer *glyph.Layer
+
+	mu      sync.Mutex
+	emu     *glyph.Emulator
+	next    int           // the next event to apply
+	pos     time.Duration // position at base
+	base    time.Time     // when pos was taken; zero while paused
+	speed   float64
+	timer   *time.Timer
+	onFrame func()
+	now     func() time.Time
+}
+
+// NewPlayer returns a paused player at the start of c.
+func NewPlayer(c *Cast) *Player {
+	p := &Player{
+		cast:  c,
+		layer: glyph.NewLayer(),
+		emu:   glyph.NewEmulator(c.Width, c.Height),
+		speed: 1,
+		now:   time.Now,
+	}
+	p.layer.AlwaysRender = true
+	p.layer.Render = p.sync
+	return p
+}
+
+// Layer returns the layer the recording plays in.
+func (p *Player) Layer() *glyph.Layer { return p.layer }
+
+// OnFrame sets fn to be called when playback reaches new output, usually
+// to request a render. Bind sets it to the app's RequestRender.
+func (p *Player) OnFrame(fn func()) *Player {
+	p.mu.Lock()
+	p.onFrame = fn
+	p.mu.Unlock()
+	return p
+}
+
+// Bind renders the app as the recording plays and binds its keys:
+// space pauses and resumes, left and right seek 5s, + and - change the
+// speed, Home and End go to the start and end.
+func (p *Player) Bind(app *glyph.App) *Player {
+	p.OnFrame(app.RequestRender)
+	app.Handle("<Space>", p.Toggle).Describe("play/pause").
+		Handle("<Left>", func() { p.Seek(-seekStep) }).Describe("back 5s").
+		Handle("<Right>", func() { p.Seek(seekStep) }).Describe("forward 5s").
+		Handle("+", func() { p.SetSpeed(p.Speed() * 2) }).Describe("faster").
+		Handle("-", func() { p.SetSpeed(p.Speed() / 2) }).Describe("slower").
+		Handle("<Home>", func() { p.SeekTo(0) }).Describe("start").
+		Handle("<End>", func() { p.SeekTo(p.cast.Duration()) }).Describe("end")
+	return p
+}
+
+// Play starts or resumes playback, from the start if it had finished.
+func (p *Player) Play() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.base.IsZero() {
+		return
+	}
+	if p.pos >= p.cast.Duration() {
+		p.rewind()
+	}
+	p.base = p.now()
+	p.schedule()
+	p.frame()
+}
+
+// Pause stops playback at the current position.
+func (p *Player) Pause() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pause()
+	p.frame()
+}
+
+// Toggle pauses a playing recording and plays a paused one.
+func (p *Player) Toggle() {
+	if p.Playing() {
+		p.Pause()
+	} else {
+		p.Play()
+	}
+}
+
+// Playing reports whether the recording is playing.
+func (p *Player) Playing() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.advance()
+	return !p.base.IsZero()
+}
+
+// Position returns how far into the recording playback is.
+func (p *Player) Position() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.position()
+}
+
+// Duration returns the length of the recording.
+func (p *Player) Duration() time.Duration { return p.cast.Duration() }
+
+// Seek moves playback by d, back if negative.
+func (p *Player) Seek(d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.seekTo(p.position() + d)
+}
+
+// SeekTo moves playback to t into the recording.
+func (p *Player) SeekTo(t time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.seekTo(t)
+}
+
+// Speed returns the playback speed, 1 being real time.
+func (p *Player) Speed() float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.speed
+}
+
+// SetSpeed sets the playback speed, between 1/16 and 16 times real time.
+func (p *Player) SetSpeed(s float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	pos := p.position()
+	p.speed = min(max(s, 1.0/16), 16)
+	if !p.base.IsZero() {
+		p.pos, p.base = pos, p.now()
+		p.schedule()
+	}
+	p.frame()
+}
+
+// Status describes the playback state, e.g. "▶ 0:12 / 1:30  2x".
+func (p *Player) Status() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.advance()
+	icon := "⏸"
+	if !p.base.IsZero() {
+		icon = "▶"
+	}
+	speed := strconv.FormatFloat(p.speed, 'f', -1, 64)
+	return fmt.Sprintf("%s %s / %s  %sx", icon, clock(p.position()), clock(p.cast.Duration()), speed)
+}
+
+// clock formats d as m:ss.
+func clock(d time.Duration) string {
+	s := int(d / time.Second)
+	return fmt.Sprintf("%d:%02d", s/60, s%60)
+}
+
+// position returns the current position. Called with mu held.
+func (p *Player) position() time.Duration {
+	if p.base.IsZero() {
+		return p.pos
+	}
+	d := p.pos + time.Duration(float64(p.now().Sub(p.base))*p.speed)
+	return min(d, p.cast.Duration())
+}
+
+// advance applies the events up to the current position, pausing at the
+// end. It reports whether any were applied. Called with mu held.
+func (p *Player) advance() bool {
+	pos := p.position()
+	applied := p.next
+	for p.next < len(p.cast.Events) && p.cast.Events[p.next].Time <= pos {
+		p.apply(p.cast.Events[p.next])
+		p.next++
+	}
+	if !p.base.IsZero() && pos >= p.cast.Duration() {
+		p.pause()
+	}
+	return p.next != applied
+}
+
+func (p *Player) apply(e Event) {
+	switch e.Kind {
+	case "o":
+		p.emu.Write([]byte(e.Data))
+	case "r":
+		var cols, rows int
+		if _, err := fmt.Sscanf(strings.TrimSpace(e.Data), "%dx%d", &cols, &rows); err == nil && cols > 0 && rows > 0 {
+			p.emu.Resize(cols, rows)
+		}
+	}
+}
+
+// pause stops at the current position. Called with mu held.
+func (p *Player) pause() {
+	p.pos = p.position()
+	p.base = time.Time{}
+	if p.timer != nil {
+		p.timer.Stop()
+	}
+}
+
+// rewind resets the screen to the start. Called with mu held.
+func (p *Player) rewind() {
+	p.emu = glyph.NewEmulator(p.cast.Width, p.cast.Height)
+	p.next, p.pos = 0, 0
+}
+
+// seekTo moves to t, replaying from the start to go back. Called with mu
+// held.
+func (p *Player) seekTo(t time.Duration) {
+	t = min(max(t, 0), p.cast.Duration())
+	if p.next > 0 && t < p.cast.Events[p.next-1].Time {
+		p.rewind()
+	}
+	p.pos = t
+	if !p.base.IsZero() {
+		p.base = p.now()
+	}
+	p.advance()
+	p.schedule()
+	p.frame()
+}
+
+// schedule arms the timer for the next event while playing, or for a
+// second on, to move the clock in Status on. Called with mu held.
+func (p *Player) schedule() {
+	if p.timer != nil {
+		p.timer.Stop()
+	}
+	if p.base.IsZero() || p.next >= len(p.cast.Events) {
+		return
+	}
+	wait := time.Duration(float64(p.cast.Events[p.next].Time-p.position()) / p.speed)
+	p.timer = time.AfterFunc(min(max(wait, 0), time.Second), func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.advance()
+		p.frame()
+		p.schedule()
+	})
+}
+
+// frame reports new output. Called with mu held.
+func (p *Player) frame() {
+	if p.onFrame != nil {
+		go p.onFrame()
+	}
+}
+
+// sync is the layer's Render hook: it copies the emulator's screen into the
+// layer.
+func (p *Player) sync() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.advance()
+	src := p.emu.Buffer()
+	if buf := p.layer.Buffer(); buf == nil || buf.Width() != src.Width() || buf.Height() != src.Height() {
+		p.layer.SetBuffer(glyph.NewBuffer(src.Width(), src.Height()))
+	}
+	p.layer.Buffer().CopyFrom(src)
+	x, y, visible := p.emu.Cursor()
+	p.layer.SetCursor(x, y)
+	if visible {
+		p.layer.ShowCursor()
+	} else {
+		p.layer.HideCursor()
+	}
+}
@@ -0,0 +1,142 @@
+package replay
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testCast = `{"version": 2, "width": 10, "height": 2, "timestamp": 1700000000, "title": "demo"}
+[0.5, "o", "one"]
+[1.0, "i", "x"]
+[2.0, "o", "\r\u001b[Ktwo"]
+
+[3.5, "r", "12x3"]
+[4.0, "o", "\r\n\u001b[1mthree"]
+`
+
+func TestRead(t *testing.T) {
+	c, err := Read(strings.NewReader(testCast))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Width != 10 || c.Height != 2 || c.Title != "demo" || c.Timestamp.Unix() != 1700000000 {
+		t.Errorf("header = %+v", c)
+	}
+	if len(c.Events) != 5 || c.Events[2] != (Event{2 * time.Second, "o", "\r\x1b[Ktwo"}) {
+		t.Errorf("events = %+v", c.Events)
+	}
+	if c.Duration() != 4*time.Second {
+		t.Errorf("duration = %v", c.Duration())
+	}
+
+	for _, bad := range []string{
+		``,
+		`{"version": 1, "width": 80, "height": 24}`,
+		`{"version": 2, "width": 0, "height": 24}`,
+		"{\"version\": 2, \"width\": 8, \"height\": 2}\n[1, \"o\"]",
+		"{\"version\": 2, \"width\": 8, \"height\": 2}\n[\"1\", \"o\", \"x\"]",
+	} {
+		if _, err := Read(strings.NewReader(bad)); err == nil {
+			t.Errorf("Read(%q) succeeded", bad)
+		}
+	}
+}
+
+// testPlayer returns a player of testCast on a clock moved by advance.
+func testPlayer(t *testing.T) (p *Player, advance func(time.Duration), screen func() string) {
+	c, err := Read(strings.NewReader(testCast))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var now atomic.Int64 // read by the player's timer
+	p = NewPlayer(c)
+	p.now = func() time.Time { return time.Unix(0, now.Load()) }
+	t.Cleanup(p.Pause)
+	screen = func() string {
+		p.layer.Render()
+		buf := p.layer.Buffer()
+		var lines []string
+		for y := range buf.Height() {
+			lines = append(lines, buf.GetLine(y))
+		}
+		return strings.Join(lines, "|")
+	}
+	return p, func(d time.Duration) { now.Add(int64(d)) }, screen
+}
+
+func TestPlayer(t *testing.T) {
+	p, advance, screen := testPlayer(t)
+	if got := screen(); got != "|" {
+		t.Errorf("before play = %q", got)
+	}
+	p.Play()
+	advance(time.Second)
+	if got := screen(); got != "one|" {
+		t.Errorf("at 1s = %q", got)
+	}
+	if got := p.Status(); got != "▶ 0:01 / 0:04  1x" {
+		t.Errorf("status = %q", got)
+	}
+
+	// paused, the clock moving doesn't
+	p.Pause()
+	advance(10 * time.Second)
+	if got := screen(); got != "one|" || p.Position() != time.Second {
+		t.Errorf("paused = %q at %v", got, p.Position())
+	}
+
+	p.SetSpeed(2)
+	p.Play()
+	advance(time.Second) // 2s into the recording
+	if got := screen(); got != "two|" {
+		t.Errorf("at 2x = %q", got)
+	}
+
+	// seeking back replays from the start
+	p.Seek(-1500 * time.Millisecond)
+	if got := screen(); got != "one|" {
+		t.Errorf("after seeking back = %q", got)
+	}
+
+	// resizes follow the recording, and the end pauses
+	p.SeekTo(time.Hour)
+	if got := screen(); got != "two|three|" || p.layer.Buffer().Width() != 12 {
+		t.Errorf("at the end = %q, width %d", got, p.layer.Buffer().Width())
+	}
+	if p.Playing() || p.Position() != 4*time.Second {
+		t.Errorf("playing %v at %v", p.Playing(), p.Position())
+	}
+	if c := p.layer.Buffer().Get(0, 1); c.Rune != 't' || c.Style.Attr == 0 {
+		t.Errorf("styled cell = %+v", c)
+	}
+
+	// playing again restarts
+	p.Play()
+	advance(400 * time.Millisecond) // still at 2x
+	if got := screen(); got != "one|" {
+		t.Errorf("replayed = %q", got)
+	}
+}
+
+func TestPlayerFrames(t *testing.T) {
+	c, _ := Read(strings.NewReader(`{"version": 2, "width": 4, "height": 1}
+[0.01, "o", "a"]
+[0.02, "o", "b"]`))
+	frames := make(chan struct{}, 10)
+	p := NewPlayer(c).OnFrame(func() { frames <- struct{}{} })
+	p.Play()
+	deadline := time.After(2 * time.Second)
+	for p.Playing() {
+		select {
+		case <-frames:
+		case <-deadline:
+			t.Fatal("playback did not finish")
+		}
+	}
+	p.layer.Render()
+	if got := p.layer.Buffer().GetLine(0); got != "ab" {
+		t.Errorf("screen = %q", got)
+	}
+}
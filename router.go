@@ -0,0 +1,388 @@
+package glyph
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kungfusheep/riffkey"
+)
+
+// Params holds the values captured by a route's :name segments.
+type Params map[string]string
+
+// Get returns the named parameter, or "".
+func (p Params) Get(name string) string {
+	return p[name]
+}
+
+// Int returns the named parameter as an int, or 0 if it is not a number.
+// Declare the segment as :name<int> to only match numbers.
+func (p Params) Int(name string) int {
+	n, _ := strconv.Atoi(p[name])
+	return n
+}
+
+// route is a registered path pattern and its view.
+type route struct {
+	pattern  string
+	segments []string
+	title    func(Params) string
+	onEnter  func(Params)
+	onLeave  func()
+}
+
+// routeEntry is one visited location in the navigation history.
+type routeEntry struct {
+	route  *route
+	path   string
+	params Params
+}
+
+// Breadcrumb is one level of the current location. See App.Breadcrumbs.
+type Breadcrumb struct {
+	Title string
+	Path  string
+}
+
+// RouteBuilder configures a route. See App.Route.
+type RouteBuilder struct {
+	view  *ViewBuilder
+	route *route
+}
+
+// Route registers a view for a path pattern. Segments starting with a colon
+// capture parameters, and :name<int> only matches numbers. Navigate to a
+// path to show the view; Back and Forward move through the history.
+//
+//	app.Route("/pods", podList).Title("Pods")
+//	app.Route("/pods/:name", podDetail).
+//	    TitleFunc(func(p Params) string { return p.Get("name") }).
+//	    OnEnter(func(p Params) { loadPod(p.Get("name")) }).
+//	    OnLeave(cancelLoad)
+//	app.RunFrom("/pods")
+func (a *App) Route(pattern string, view any) *RouteBuilder {
+	r := &route{pattern: pattern, segments: splitPath(pattern)}
+	a.routes = append(a.routes, r)
+	return &RouteBuilder{view: a.View(pattern, view), route: r}
+}
+
+// Title sets the route's title, shown in breadcrumbs.
+func (rb *RouteBuilder) Title(title string) *RouteBuilder {
+	rb.route.title = func(Params) string { return title }
+	return rb
+}
+
+// TitleFunc sets a title computed from the route's parameters.
+func (rb *RouteBuilder) TitleFunc(fn func(Params) string) *RouteBuilder {
+	rb.route.title = fn
+	return rb
+}
+
+// OnEnter sets a callback that runs each time the route becomes active,
+// including via Back and Forward. Use it to load data for the params.
+func (rb *RouteBuilder) OnEnter(fn func(Params)) *RouteBuilder {
+	rb.route.onEnter = fn
+	return rb
+}
+
+// OnLeave sets a callback that runs when navigating away from the route.
+// Use it to cancel work started in OnEnter.
+func (rb *RouteBuilder) OnLeave(fn func()) *RouteBuilder {
+	rb.route.onLeave = fn
+	return rb
+}
+
+// Handle registers a key handler active while the route is shown.
+// Accepts func(riffkey.Match), func(any), or func().
+func (rb *RouteBuilder) Handle(pattern string, handler any) *RouteBuilder {
+	rb.view.Handle(pattern, handler)
+	return rb
+}
+
+// HandleNamed registers a key handler as a named action, which a Keymap
+// can rebind or unbind for this route.
+func (rb *RouteBuilder) HandleNamed(name, pattern string, handler any) *RouteBuilder {
+	rb.view.HandleNamed(name, pattern, handler)
+	return rb
+}
+
+// NoCounts disables vim-style count prefixes for this route.
+func (rb *RouteBuilder) NoCounts() *RouteBuilder {
+	rb.view.NoCounts()
+	return rb
+}
+
+// Router returns the route's key router for advanced configuration.
+func (rb *RouteBuilder) Router() *riffkey.Router {
+	return rb.view.router
+}
+
+// Navigate shows the route matching path and adds it to the history,
+// dropping any entries ahead of the current one.
+func (a *App) Navigate(path string) error {
+	e, ok := a.matchRoute(path)
+	if !ok {
+		return fmt.Errorf("no route matches %q", path)
+	}
+	if len(a.history) > 0 {
+		a.history = a.history[:a.historyPos+1]
+	}
+	a.history = append(a.history, e)
+	a.enterRoute(len(a.history) - 1)
+	return nil
+}
+
+// Replace shows the route matching path in place of the current history entry.
+func (a *App) Replace(path string) error {
+	e, ok := a.matchRoute(path)
+	if !ok {
+		return fmt.Errorf("no route matches %q", path)
+	}
+	a.leaveRoute()
+	if len(a.history) == 0 {
+		a.history = append(a.history, e)
+	} else {
+		a.history[a.historyPos] = e
+	}
+	a.showRoute(a.historyPos)
+	return nil
+}
+
+// Forward returns to the entry left by Back. Reports whether there was one.
+func (a *App) Forward() bool {
+	if a.historyPos+1 >= len(a.history) {
+		return false
+	}
+	a.enterRoute(a.historyPos + 1)
+	return true
+}
+
+// CanGoBack reports whether Back has a previous route to return to.
+func (a *App) CanGoBack() bool {
+	return a.historyPos > 0
+}
+
+// Path returns the current route's path, or "" if no route is active.
+func (a *App) Path() string {
+	if e := a.currentEntry(); e != nil {
+		return e.path
+	}
+	return ""
+}
+
+// Params returns the current route's parameters.
+func (a *App) Params() Params {
+	if e := a.currentEntry(); e != nil {
+		return e.params
+	}
+	return nil
+}
+
+// Title returns the current route's title, or its path if it has none.
+func (a *App) Title() string {
+	if e := a.currentEntry(); e != nil {
+		return e.title()
+	}
+	return ""
+}
+
+// Breadcrumbs returns a crumb for each prefix of the current path that
+// matches a route, from the root to the current location. "/" is the
+// first when it's a route.
+func (a *App) Breadcrumbs() []Breadcrumb {
+	e := a.currentEntry()
+	if e == nil {
+		return nil
+	}
+	var crumbs []Breadcrumb
+	segs := splitPath(e.path)
+	for i := 0; i <= len(segs); i++ {
+		prefix := "/" + strings.Join(segs[:i], "/")
+		if pe, ok := a.matchRoute(prefix); ok {
+			crumbs = append(crumbs, Breadcrumb{Title: pe.title(), Path: prefix})
+		}
+	}
+	return crumbs
+}
+
+// NavTransition plays an effect over each route change. fn receives a
+// progress pointer that runs from 1 down to 0 over d; effects such as
+// SEDissolve show the new view at 0.
+//
+//	app.NavTransition(func(p *float64) Effect { return SEDissolve(p) }, 300*time.Millisecond)
+func (a *App) NavTransition(fn func(progress *float64) Effect, d time.Duration) *App {
+	a.navEffect = fn(&a.navProgress)
+	a.navDuration = d
+	return a
+}
+
+func (a *App) currentEntry() *routeEntry {
+	if len(a.history) == 0 {
+		return nil
+	}
+	return &a.history[a.historyPos]
+}
+
+// enterRoute makes history entry i current, running the leave and enter hooks.
+func (a *App) enterRoute(i int) {
+	a.leaveRoute()
+	a.showRoute(i)
+}
+
+// leaveRoute runs the current route's OnLeave hook.
+func (a *App) leaveRoute() {
+	if cur := a.currentEntry(); cur != nil && cur.route.onLeave != nil {
+		cur.route.onLeave()
+	}
+}
+
+// showRoute activates history entry i and runs its OnEnter hook.
+func (a *App) showRoute(i int) {
+	a.historyPos = i
+	e := &a.history[i]
+	a.currentView = e.route.pattern
+	a.input.SetRouter(a.viewRouters[e.route.pattern])
+	if e.route.onEnter != nil {
+		e.route.onEnter(e.params)
+	}
+	a.startNavTransition()
+	a.RequestRender()
+}
+
+// startNavTransition restarts navProgress at 1. render winds it down to 0
+// over navDuration, with the frame clock running until it gets there.
+func (a *App) startNavTransition() {
+	if a.navEffect == nil || a.navDuration <= 0 {
+		return
+	}
+	a.renderMu.Lock()
+	a.navStart = time.Now()
+	a.navProgress = 1
+	a.renderMu.Unlock()
+}
+
+// stepNavTransition sets navProgress for a frame drawn at now. Called
+// under renderMu.
+func (a *App) stepNavTransition(now time.Time) {
+	if a.navStart.IsZero() {
+		return
+	}
+	p := 1 - float64(now.Sub(a.navStart))/float64(a.navDuration)
+	if p <= 0 {
+		p = 0
+		a.navStart = time.Time{}
+	}
+	a.navProgress = p
+}
+
+// matchRoute finds the first route matching path.
+func (a *App) matchRoute(path string) (routeEntry, bool) {
+	segs := splitPath(path)
+	for _, r := range a.routes {
+		if params, ok := r.match(segs); ok {
+			return routeEntry{route: r, path: "/" + strings.Join(segs, "/"), params: params}, true
+		}
+	}
+	return routeEntry{}, false
+}
+
+// match reports whether segs fit the route, capturing its parameters.
+func (r *route) match(segs []string) (Params, bool) {
+	if len(segs) != len(r.segments) {
+		return nil, false
+	}
+	var params Params
+	for i, pat := range r.segments {
+		name, ok := strings.CutPrefix(pat, ":")
+		if !ok {
+			if pat != segs[i] {
+				return nil, false
+			}
+			continue
+		}
+		if n, isInt := strings.CutSuffix(name, "<int>"); isInt {
+			if _, err := strconv.Atoi(segs[i]); err != nil {
+				return nil, false
+			}
+			name = n
+		}
+		if params == nil {
+			params = Params{}
+		}
+		params[name] = segs[i]
+	}
+	return params, true
+}
+
+func (e *routeEntry) title() string {
+	if e.route.title != nil {
+		return e.route.title(e.params)
+	}
+	return e.path
+}
+
+// splitPath splits a path into its non-empty segments.
+func splitPath(path string) []string {
+	var segs []string
+	for s := range strings.SplitSeq(path, "/") {
+		if s != "" {
+			segs = append(segs, s)
+		}
+	}
+	return segs
+}
+
+// BreadcrumbsC shows the App's current location. See Breadcrumbs.
+type BreadcrumbsC struct {
+	app         *App
+	sep         string
+	style       Style
+	activeStyle Style
+}
+
+// Breadcrumbs shows the titles of the current route and its parents,
+// e.g. "Pods › web-1".
+func Breadcrumbs(app *App) BreadcrumbsC {
+	return BreadcrumbsC{app: app, sep: " › ", style: Style{Attr: AttrDim}, activeStyle: Style{Attr: AttrBold}}
+}
+
+// Separator sets the text between crumbs.
+func (b BreadcrumbsC) Separator(s string) BreadcrumbsC {
+	b.sep = s
+	return b
+}
+
+// Style sets the style of parent crumbs and separators.
+func (b BreadcrumbsC) Style(s Style) BreadcrumbsC {
+	b.style = s
+	return b
+}
+
+// ActiveStyle sets the style of the current crumb.
+func (b BreadcrumbsC) ActiveStyle(s Style) BreadcrumbsC {
+	b.activeStyle = s
+	return b
+}
+
+func (b BreadcrumbsC) toTemplate() any {
+	return Widget(
+		func(availW int16) (int16, int16) { return availW, 1 },
+		func(buf *Buffer, x, y, w, h int16) {
+			crumbs := b.app.Breadcrumbs()
+			cx := int(x)
+			limit := int(x + w)
+			for i, c := range crumbs {
+				st := b.style
+				if i == len(crumbs)-1 {
+					st = b.activeStyle
+				}
+				if i > 0 {
+					cx += buf.WriteStringClipped(cx, int(y), b.sep, b.style, limit-cx)
+				}
+				cx += buf.WriteStringClipped(cx, int(y), c.Title, st, limit-cx)
+			}
+		},
+	)
+}
@@ -0,0 +1,187 @@
+package glyph
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kungfusheep/riffkey"
+)
+
+// routerApp returns an App with no terminal, enough to register and
+// navigate routes.
+func routerApp() *App {
+	router := riffkey.NewRouter()
+	return &App{
+		router:     router,
+		input:      riffkey.NewInput(router),
+		renderChan: make(chan struct{}, 1),
+		pool:       NewBufferPool(40, 5),
+	}
+}
+
+func TestRouteMatch(t *testing.T) {
+	r := &route{segments: splitPath("/pods/:name/logs/:line<int>")}
+	p, ok := r.match(splitPath("/pods/web-1/logs/42"))
+	if !ok || p.Get("name") != "web-1" || p.Int("line") != 42 {
+		t.Errorf("params = %v, ok = %v", p, ok)
+	}
+	if _, ok := r.match(splitPath("/pods/web-1/logs/tail")); ok {
+		t.Error("<int> segment matched a word")
+	}
+	if _, ok := r.match(splitPath("/pods/web-1")); ok {
+		t.Error("matched a shorter path")
+	}
+}
+
+func TestNavigateHistoryAndHooks(t *testing.T) {
+	a := routerApp()
+	var log []string
+	a.Route("/", Text("home")).Title("Home")
+	a.Route("/pods", Text("pods")).Title("Pods").
+		OnEnter(func(Params) { log = append(log, "enter pods") }).
+		OnLeave(func() { log = append(log, "leave pods") })
+	a.Route("/pods/:name", Text("pod")).
+		TitleFunc(func(p Params) string { return p.Get("name") }).
+		OnEnter(func(p Params) { log = append(log, "enter "+p.Get("name")) }).
+		OnLeave(func() { log = append(log, "leave pod") })
+
+	if err := a.Navigate("/nowhere"); err == nil {
+		t.Error("expected no-match error")
+	}
+	for _, p := range []string{"/", "/pods", "/pods/web-1"} {
+		if err := a.Navigate(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if a.Path() != "/pods/web-1" || a.Title() != "web-1" || a.currentView != "/pods/:name" {
+		t.Errorf("path = %q title = %q view = %q", a.Path(), a.Title(), a.currentView)
+	}
+	var crumbs []string
+	for _, c := range a.Breadcrumbs() {
+		crumbs = append(crumbs, c.Title)
+	}
+	if got := strings.Join(crumbs, " > "); got != "Home > Pods > web-1" {
+		t.Errorf("breadcrumbs = %q", got)
+	}
+
+	a.Back()
+	if a.Path() != "/pods" || !a.CanGoBack() {
+		t.Errorf("after back: %q", a.Path())
+	}
+	if !a.Forward() || a.Params().Get("name") != "web-1" {
+		t.Errorf("after forward: %q", a.Path())
+	}
+	if a.Forward() {
+		t.Error("forward past the end")
+	}
+
+	// navigating after Back drops the forward entries
+	a.Back()
+	a.Navigate("/pods/db-0")
+	if a.Forward() {
+		t.Error("forward history should be cleared")
+	}
+
+	want := "enter pods,leave pods,enter web-1,leave pod,enter pods,leave pods,enter web-1,leave pod,enter pods,leave pods,enter db-0"
+	if got := strings.Join(log, ","); got != want {
+		t.Errorf("hooks:\n got %s\nwant %s", got, want)
+	}
+
+	// the route's router takes input
+	if a.input.Current() != a.viewRouters["/pods/:name"] {
+		t.Error("route router not active")
+	}
+}
+
+func TestBackPopsModalBeforeHistory(t *testing.T) {
+	a := routerApp()
+	a.Route("/", Text("home"))
+	a.Route("/pods", Text("pods"))
+	a.Navigate("/")
+	a.Navigate("/pods")
+
+	modal := riffkey.NewRouter()
+	a.Push(modal)
+	a.Back()
+	if a.input.Depth() != 1 || a.Path() != "/pods" {
+		t.Fatalf("depth = %d path = %q, want the modal closed on /pods", a.input.Depth(), a.Path())
+	}
+	a.Back()
+	if a.Path() != "/" {
+		t.Errorf("path = %q, want the previous route", a.Path())
+	}
+}
+
+func TestBreadcrumbsStartAtRoot(t *testing.T) {
+	a := routerApp()
+	a.Route("/", Text("home")).Title("Home")
+	a.Route("/pods/:name", Text("pod")).TitleFunc(func(p Params) string { return p.Get("name") })
+
+	for path, want := range map[string][]Breadcrumb{
+		"/":        {{Title: "Home", Path: "/"}},
+		"/pods/db": {{Title: "Home", Path: "/"}, {Title: "db", Path: "/pods/db"}},
+	} {
+		if err := a.Navigate(path); err != nil {
+			t.Fatal(err)
+		}
+		if got := a.Breadcrumbs(); !slices.Equal(got, want) {
+			t.Errorf("%s: breadcrumbs = %+v, want %+v", path, got, want)
+		}
+	}
+}
+
+func TestBreadcrumbsRender(t *testing.T) {
+	a := routerApp()
+	a.Route("/pods", Text("pods")).Title("Pods")
+	a.Route("/pods/:name", Text("pod")).TitleFunc(func(p Params) string { return p.Get("name") })
+	a.Navigate("/pods/api")
+
+	buf := NewBuffer(20, 1)
+	Build(VBox(Breadcrumbs(a).Separator(" / "))).Execute(buf, 20, 1)
+	if got := strings.TrimRight(buf.GetLine(0), " "); got != "Pods / api" {
+		t.Errorf("breadcrumbs = %q", got)
+	}
+	if c := buf.Get(7, 0); c.Style.Attr&AttrBold == 0 {
+		t.Error("current crumb should be bold")
+	}
+}
+
+func TestNavTransition(t *testing.T) {
+	st := newStreamTerm(t, 10, 1)
+	a := st.app
+	a.Route("/a", Text("a"))
+	a.Route("/b", Text("b"))
+	a.NavTransition(func(p *float64) Effect { return SEDissolve(p) }, 30*time.Millisecond)
+	errs := make(chan error, 1)
+	go func() { errs <- a.Run() }()
+
+	a.Navigate("/a")
+	a.renderMu.Lock()
+	start := a.navProgress
+	a.renderMu.Unlock()
+	if start != 1 {
+		t.Fatalf("progress = %v, want 1 at start", start)
+	}
+	// the frame clock winds it down, then stops
+	waitFor(t, "transition to finish", func() bool {
+		a.renderMu.Lock()
+		defer a.renderMu.Unlock()
+		return a.navProgress == 0 && a.clockEvery == 0
+	})
+	waitFor(t, "new view", func() bool { return strings.HasPrefix(st.screen(), "a") })
+	a.Stop()
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+
+	// Replace leaves the old route, not the new one
+	left := ""
+	a.Route("/c", Text("c")).OnLeave(func() { left = "c" })
+	a.Navigate("/c")
+	a.Replace("/b")
+	if left != "c" || a.Path() != "/b" || !a.CanGoBack() {
+		t.Errorf("left = %q path = %q", left, a.Path())
+	}
+}
@@ -6,8 +6,11 @@ import (
 	"io"
 	"os"
 	"os/signal"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"syscall"
+	"unicode/utf8"
 
 	"github.com/mattn/go-runewidth"
 	"golang.org/x/sys/unix"
@@ -32,14 +35,31 @@ type Screen struct {
 	resizeChan chan Size
 	sigChan    chan os.Signal
 
+	// Streams (see NewAppWithIO): no termios or signals, and the size comes
+	// from sizeFn and resizes. nil sizeFn means the process's terminal.
+	sizeFn      func() Size
+	resizes     <-chan Size
+	stopResizes chan struct{}
+
 	// Rendering state
 	lastStyle  Style        // Last style we emitted (for optimization)
 	buf        bytes.Buffer // Reusable buffer for building output
 	forceRGB   bool         // emit all colours as true color RGB
 	syncOutput bool         // wrap frames with DEC sync output markers (\e[?2026h/l)
+	palette    [16][3]uint8 // the terminal's basic 16 colours, for effects
+	stats      FlushStats   // from the last Flush, guarded by mu
+	noRepeat   bool         // the terminal lacks REP
+
+	// Flush scratch
+	rowDiff             []int    // first changed column of each row, -1 if none
+	backHash, frontHash []uint64 // row hashes, for finding scrolled rows
+	hashed              bool     // frontHash is up to date with front
+	offsets             []int    // scroll distances to try
 
 	// Synchronization - protects buffer access during resize
 	mu sync.Mutex
+
+	recording atomic.Pointer[recording] // set by App.Record
 }
 
 // Size represents dimensions.
@@ -72,11 +92,39 @@ func NewScreen(w io.Writer) (*Screen, error) {
 		resizeChan: make(chan Size, 1),
 		sigChan:    make(chan os.Signal, 1),
 		lastStyle:  DefaultStyle(),
+		palette:    basic16RGB,
+		noRepeat:   !repeatSupported(),
 	}
 
 	return s, nil
 }
 
+// newStreamScreen creates a screen writing to out, a terminal elsewhere
+// such as an SSH session's, which reports its size through size and
+// resize.
+func newStreamScreen(out io.Writer, size func() Size, resize <-chan Size) *Screen {
+	sz := size()
+	return &Screen{
+		front:      NewBuffer(sz.Width, sz.Height),
+		back:       NewBuffer(sz.Width, sz.Height),
+		writer:     out,
+		fd:         -1,
+		width:      sz.Width,
+		height:     sz.Height,
+		resizeChan: make(chan Size, 1),
+		lastStyle:  DefaultStyle(),
+		palette:    ansi16RGB,
+		sizeFn:     size,
+		resizes:    resize,
+	}
+}
+
+// repeatSupported reports whether the terminal understands REP, which all
+// but a few xterm-alikes do.
+func repeatSupported() bool {
+	return os.Getenv("TERM") != "linux" && os.Getenv("TERM_PROGRAM") != "Apple_Terminal"
+}
+
 // getTerminalSize returns the current terminal dimensions.
 func getTerminalSize(fd int) (int, int, error) {
 	ws, err := unix.IoctlGetWinsize(fd, unix.TIOCGWINSZ)
@@ -88,17 +136,19 @@ func getTerminalSize(fd int) (int, int, error) {
 
 // Size returns the current screen dimensions.
 func (s *Screen) Size() Size {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	return Size{Width: s.width, Height: s.height}
 }
 
 // Width returns the screen width.
 func (s *Screen) Width() int {
-	return s.width
+	return s.Size().Width
 }
 
 // Height returns the screen height.
 func (s *Screen) Height() int {
-	return s.height
+	return s.Size().Height
 }
 
 // Buffer returns the back buffer for drawing.
@@ -116,6 +166,33 @@ func (s *Screen) EnterRawMode() error {
 	if s.inRawMode {
 		return nil
 	}
+	if err := s.takeTerminal(); err != nil {
+		return err
+	}
+	s.inRawMode = true
+
+	// Enter alternate screen, hide cursor, enable bracketed paste
+	s.writeString("\x1b[?1049h") // Enter alternate screen
+	s.writeString("\x1b[2J")     // Clear screen (ensures front buffer matches actual screen)
+	s.writeString("\x1b[H")      // Move cursor to home position
+	s.writeString("\x1b[?25l")   // Hide cursor
+	s.writeString("\x1b[?2004h") // Enable bracketed paste mode
+	s.writeString("\x1b[?1004h") // Report focus changes
+	s.syncOutput = true           // wrap frames with synchronized output (reduces tearing)
+
+	return nil
+}
+
+// takeTerminal puts the terminal into raw mode and starts following its
+// size. Streams have no termios; their size comes from resizes.
+func (s *Screen) takeTerminal() error {
+	if s.sizeFn != nil {
+		if s.resizes != nil {
+			s.stopResizes = make(chan struct{})
+			go s.handleResizes(s.stopResizes)
+		}
+		return nil
+	}
 
 	termios, err := unix.IoctlGetTermios(s.fd, ioctlGetTermios)
 	if err != nil {
@@ -123,7 +200,39 @@ func (s *Screen) EnterRawMode() error {
 	}
 	s.origTermios = termios
 
-	raw := *termios
+	if err := s.setRaw(); err != nil {
+		return err
+	}
+
+	// Start listening for resize signals
+	signal.Notify(s.sigChan, syscall.SIGWINCH)
+	go s.handleSignals()
+	return nil
+}
+
+// restoreTerminal undoes takeTerminal.
+func (s *Screen) restoreTerminal() error {
+	if s.sizeFn != nil {
+		if s.stopResizes != nil {
+			close(s.stopResizes)
+			s.stopResizes = nil
+		}
+		return nil
+	}
+
+	signal.Stop(s.sigChan)
+
+	if s.origTermios != nil {
+		if err := unix.IoctlSetTermios(s.fd, ioctlSetTermios, s.origTermios); err != nil {
+			return fmt.Errorf("failed to restore termios: %w", err)
+		}
+	}
+	return nil
+}
+
+// setRaw puts the terminal into raw mode, starting from origTermios.
+func (s *Screen) setRaw() error {
+	raw := *s.origTermios
 	// Input flags: disable break, CR to NL, parity, strip, flow control
 	raw.Iflag &^= unix.BRKINT | unix.ICRNL | unix.INPCK | unix.ISTRIP | unix.IXON
 	// Output flags: disable post processing
@@ -139,21 +248,6 @@ func (s *Screen) EnterRawMode() error {
 	if err := unix.IoctlSetTermios(s.fd, ioctlSetTermios, &raw); err != nil {
 		return fmt.Errorf("failed to set raw mode: %w", err)
 	}
-
-	s.inRawMode = true
-
-	// Start listening for resize signals
-	signal.Notify(s.sigChan, syscall.SIGWINCH)
-	go s.handleSignals()
-
-	// Enter alternate screen, hide cursor, enable bracketed paste
-	s.writeString("\x1b[?1049h") // Enter alternate screen
-	s.writeString("\x1b[2J")     // Clear screen (ensures front buffer matches actual screen)
-	s.writeString("\x1b[H")      // Move cursor to home position
-	s.writeString("\x1b[?25l")   // Hide cursor
-	s.writeString("\x1b[?2004h") // Enable bracketed paste mode
-	s.syncOutput = true           // wrap frames with synchronized output (reduces tearing)
-
 	return nil
 }
 
@@ -164,16 +258,13 @@ func (s *Screen) ExitRawMode() error {
 	}
 
 	// Disable bracketed paste, show cursor, exit alternate screen
+	s.writeString("\x1b[?1004l") // Stop reporting focus changes
 	s.writeString("\x1b[?2004l") // Disable bracketed paste mode
 	s.writeString("\x1b[?25h")   // Show cursor
 	s.writeString("\x1b[?1049l") // Exit alternate screen
 
-	signal.Stop(s.sigChan)
-
-	if s.origTermios != nil {
-		if err := unix.IoctlSetTermios(s.fd, ioctlSetTermios, s.origTermios); err != nil {
-			return fmt.Errorf("failed to restore termios: %w", err)
-		}
+	if err := s.restoreTerminal(); err != nil {
+		return err
 	}
 
 	s.inRawMode = false
@@ -187,37 +278,13 @@ func (s *Screen) EnterInlineMode() error {
 	if s.inRawMode {
 		return nil
 	}
-
-	termios, err := unix.IoctlGetTermios(s.fd, ioctlGetTermios)
-	if err != nil {
-		return fmt.Errorf("failed to get termios: %w", err)
-	}
-	s.origTermios = termios
-
-	raw := *termios
-	// Input flags: disable break, CR to NL, parity, strip, flow control
-	raw.Iflag &^= unix.BRKINT | unix.ICRNL | unix.INPCK | unix.ISTRIP | unix.IXON
-	// Output flags: disable post processing
-	raw.Oflag &^= unix.OPOST
-	// Control flags: set 8 bit chars
-	raw.Cflag |= unix.CS8
-	// Local flags: disable echo, canonical mode, signals, extended input
-	raw.Lflag &^= unix.ECHO | unix.ICANON | unix.ISIG | unix.IEXTEN
-	// Control chars: min bytes = 1, timeout = 0
-	raw.Cc[unix.VMIN] = 1
-	raw.Cc[unix.VTIME] = 0
-
-	if err := unix.IoctlSetTermios(s.fd, ioctlSetTermios, &raw); err != nil {
-		return fmt.Errorf("failed to set raw mode: %w", err)
+	if err := s.takeTerminal(); err != nil {
+		return err
 	}
 
 	s.inRawMode = true
 	s.inlineMode = true
 
-	// Start listening for resize signals
-	signal.Notify(s.sigChan, syscall.SIGWINCH)
-	go s.handleSignals()
-
 	// NO alternate screen switch for inline mode
 	// Keep cursor visible
 
@@ -234,21 +301,7 @@ func (s *Screen) ExitInlineMode(linesUsed int, clear bool) error {
 
 	// After FlushInline, cursor is at start of our content (row 0 of inline area)
 	if clear && linesUsed > 0 {
-		// Build all clear commands into a single write
-		var clearBuf bytes.Buffer
-		for i := 0; i < linesUsed; i++ {
-			clearBuf.WriteString("\r\x1b[2K") // Start of line, clear entire line
-			if i < linesUsed-1 {
-				clearBuf.WriteString("\x1b[1B") // Move down to next line
-			}
-		}
-		// Move back to first line
-		if linesUsed > 1 {
-			clearBuf.WriteString(fmt.Sprintf("\x1b[%dA", linesUsed-1))
-		}
-		clearBuf.WriteString("\r")      // Ensure at start of line
-		clearBuf.WriteString("\x1b[0m") // Reset style
-		s.writer.Write(clearBuf.Bytes())
+		s.clearInline(linesUsed)
 	} else if linesUsed > 0 {
 		// Move cursor below content
 		var moveBuf bytes.Buffer
@@ -257,22 +310,95 @@ func (s *Screen) ExitInlineMode(linesUsed int, clear bool) error {
 		}
 		moveBuf.WriteString("\r\n")    // New line after content
 		moveBuf.WriteString("\x1b[0m") // Reset style
-		s.writer.Write(moveBuf.Bytes())
+		s.write(moveBuf.Bytes())
 	} else {
 		// Reset style
 		s.writeString("\x1b[0m")
 	}
 
+	if err := s.restoreTerminal(); err != nil {
+		return err
+	}
+
+	s.inRawMode = false
+	s.inlineMode = false
+	return nil
+}
+
+// clearInline clears the linesUsed lines of inline content, leaving the
+// cursor at the start of the first.
+func (s *Screen) clearInline(linesUsed int) {
+	// Build all clear commands into a single write
+	var clearBuf bytes.Buffer
+	for i := 0; i < linesUsed; i++ {
+		clearBuf.WriteString("\r\x1b[2K") // Start of line, clear entire line
+		if i < linesUsed-1 {
+			clearBuf.WriteString("\x1b[1B") // Move down to next line
+		}
+	}
+	// Move back to first line
+	if linesUsed > 1 {
+		clearBuf.WriteString(fmt.Sprintf("\x1b[%dA", linesUsed-1))
+	}
+	clearBuf.WriteString("\r")      // Ensure at start of line
+	clearBuf.WriteString("\x1b[0m") // Reset style
+	s.write(clearBuf.Bytes())
+}
+
+// release hands the terminal back in its original mode, for a suspended
+// app or a child process: the main screen for fullscreen apps, and the
+// inline content (linesUsed lines) cleared for inline ones.
+func (s *Screen) release(linesUsed int) error {
+	if !s.inRawMode {
+		return nil
+	}
+	if s.inlineMode {
+		if linesUsed > 0 {
+			s.clearInline(linesUsed)
+		}
+	} else {
+		s.writeString("\x1b[?1004l") // Stop reporting focus changes
+		s.writeString("\x1b[?2004l") // Disable bracketed paste mode
+		s.writeString("\x1b[?25h")   // Show cursor
+		s.writeString("\x1b[?1049l") // Exit alternate screen
+	}
+
+	if s.sizeFn != nil {
+		return nil // a stream keeps following resizes
+	}
+
+	// the terminal may resize while released; catch up in reclaim
 	signal.Stop(s.sigChan)
 
-	if s.origTermios != nil {
-		if err := unix.IoctlSetTermios(s.fd, ioctlSetTermios, s.origTermios); err != nil {
-			return fmt.Errorf("failed to restore termios: %w", err)
+	if err := unix.IoctlSetTermios(s.fd, ioctlSetTermios, s.origTermios); err != nil {
+		return fmt.Errorf("failed to restore termios: %w", err)
+	}
+	return nil
+}
+
+// reclaim takes the terminal back after release. The caller redraws in
+// full: the alternate screen comes back cleared, and inline content starts
+// again at the cursor.
+func (s *Screen) reclaim() error {
+	if !s.inRawMode {
+		return nil
+	}
+	if s.sizeFn == nil {
+		if err := s.setRaw(); err != nil {
+			return err
 		}
+		signal.Notify(s.sigChan, syscall.SIGWINCH)
 	}
 
-	s.inRawMode = false
-	s.inlineMode = false
+	if !s.inlineMode {
+		s.writeString("\x1b[?1049h") // Enter alternate screen
+		s.writeString("\x1b[2J")     // Clear screen
+		s.writeString("\x1b[H")      // Move cursor to home position
+		s.writeString("\x1b[?25l")   // Hide cursor
+		s.writeString("\x1b[?2004h") // Enable bracketed paste mode
+		s.writeString("\x1b[?1004h") // Report focus changes
+	}
+	s.checkSize()
 	return nil
 }
 
@@ -284,43 +410,96 @@ func (s *Screen) IsInlineMode() bool {
 // handleSignals processes OS signals.
 func (s *Screen) handleSignals() {
 	for range s.sigChan {
-		width, height, err := getTerminalSize(s.fd)
-		if err != nil {
-			continue
-		}
-		if width != s.width || height != s.height {
-			s.mu.Lock()
-			s.width = width
-			s.height = height
-			s.front.Resize(width, height)
-			s.back.Resize(width, height)
-			// Clear BOTH buffers to avoid stale content
-			s.front.Clear()
-			s.back.Clear()
-			// Clear the actual terminal screen
-			s.writeString("\x1b[2J")
-			s.mu.Unlock()
-			// Non-blocking send (outside lock to avoid potential deadlock)
-			select {
-			case s.resizeChan <- Size{Width: width, Height: height}:
-			default:
+		s.checkSize()
+	}
+}
+
+// handleResizes follows a stream's resizes until stop is closed.
+func (s *Screen) handleResizes(stop <-chan struct{}) {
+	for {
+		select {
+		case size, ok := <-s.resizes:
+			if !ok {
+				return
 			}
+			s.setSize(size.Width, size.Height)
+		case <-stop:
+			return
+		}
+	}
+}
+
+// checkSize picks up a change in the terminal's size.
+func (s *Screen) checkSize() {
+	if s.sizeFn != nil {
+		size := s.sizeFn()
+		s.setSize(size.Width, size.Height)
+		return
+	}
+	width, height, err := getTerminalSize(s.fd)
+	if err != nil {
+		return
+	}
+	s.setSize(width, height)
+}
+
+// setSize resizes the screen to width×height, clearing it.
+func (s *Screen) setSize(width, height int) {
+	if width <= 0 || height <= 0 {
+		return
+	}
+	if width != s.width || height != s.height {
+		s.mu.Lock()
+		s.width = width
+		s.height = height
+		s.front.Resize(width, height)
+		s.back.Resize(width, height)
+		s.hashed = false
+		// Clear BOTH buffers to avoid stale content
+		s.front.Clear()
+		s.back.Clear()
+		if r := s.recording.Load(); r != nil {
+			r.resize(width, height)
+		}
+		// Clear the actual terminal screen
+		s.writeString("\x1b[2J")
+		s.mu.Unlock()
+		// Non-blocking send (outside lock to avoid potential deadlock)
+		select {
+		case s.resizeChan <- Size{Width: width, Height: height}:
+		default:
 		}
 	}
 }
 
 // FlushStats holds statistics from the last flush.
 type FlushStats struct {
-	DirtyRows   int
-	ChangedRows int
+	DirtyRows    int
+	ChangedRows  int
+	ScrolledRows int // rows moved by scrolling instead of redrawn
+	BytesWritten int // bytes of the frame, before cursor updates
+	BytesSaved   int // bytes scrolling, erasing and repeating saved over writing every changed cell, estimated
 }
 
-// lastFlushStats holds stats from the most recent flush.
-var lastFlushStats FlushStats
+// lastFlushed is the screen flushed most recently, for GetFlushStats.
+var lastFlushed atomic.Pointer[Screen]
+
+// FlushStats returns stats from the screen's last flush.
+func (s *Screen) FlushStats() FlushStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stats
+}
 
-// GetFlushStats returns stats from the last flush.
+// GetFlushStats returns stats from the last flush of any screen.
+//
+// Deprecated: use App.FlushStats or Screen.FlushStats, which are per
+// screen.
 func GetFlushStats() FlushStats {
-	return lastFlushStats
+	if s := lastFlushed.Load(); s != nil {
+		return s.FlushStats()
+	}
+	return FlushStats{}
 }
 
 // debugFlush enables detailed flush debugging via TUI_DEBUG_FLUSH env var
@@ -329,6 +508,10 @@ var debugFlush = os.Getenv("TUI_DEBUG_FLUSH") != ""
 // Flush renders the back buffer to the terminal using per-cell diff.
 // Only cells that actually changed are written, with cursor positioning for each run.
 // Uses dirty row tracking to skip rows that haven't been modified.
+//
+// Rows that moved up or down, as in a scrolling log, are scrolled into
+// place (DECSTBM with SU/SD) rather than redrawn; runs of blanks are erased
+// (EL, ECH) and runs of one character repeated (REP).
 func (s *Screen) Flush() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -338,8 +521,18 @@ func (s *Screen) Flush() {
 		s.buf.WriteString("\x1b[?2026h") // begin synchronized update
 	}
 
-	dirtyCount := 0
-	changedCount := 0
+	stats := FlushStats{}
+	changed := s.diffRows()
+	hashed := changed > 0 && !s.inlineMode && s.back.width == s.width && s.front.width == s.width
+	if hashed {
+		s.hashRows()
+		if changed > 1 {
+			s.scrollRows(&stats)
+		}
+	} else if changed > 0 {
+		s.hashed = false
+	}
+
 	cursorX, cursorY := -1, -1
 	positionCount := 0
 
@@ -348,12 +541,16 @@ func (s *Screen) Flush() {
 		if !s.back.RowDirty(y) {
 			continue
 		}
-		dirtyCount++
+		stats.DirtyRows++
+
+		if s.rowDiff[y] < 0 {
+			continue // dirty, but the same
+		}
 
 		rowChanged := false
 		backBase := y * s.back.width
 		frontBase := y * s.front.width
-		for x := 0; x < s.width; x++ {
+		for x := s.rowDiff[y]; x < s.width; x++ {
 			idx := backBase + x
 			backCell := s.back.cells[idx]
 			if backCell == s.front.cells[frontBase+x] {
@@ -369,7 +566,7 @@ func (s *Screen) Flush() {
 			// Cell changed - need to write it
 			if !rowChanged {
 				rowChanged = true
-				changedCount++
+				stats.ChangedRows++
 			}
 
 			// Position cursor if not already there
@@ -380,11 +577,22 @@ func (s *Screen) Flush() {
 						x, y, cursorX, cursorY, backCell.Rune, backCell.Rune, rw)
 				}
 				positionCount++
-				s.buf.WriteString("\x1b[")
-				s.writeIntToBuf(y + 1)
-				s.buf.WriteByte(';')
-				s.writeIntToBuf(x + 1)
-				s.buf.WriteByte('H')
+				s.writeCursorPos(x, y)
+			}
+
+			// runs of one cell are erased or repeated
+			if run := s.runLength(idx, x); run > 1 {
+				if n, saved, erased := s.writeRun(backCell, x, y, run, s.front.cells[frontBase+x:frontBase+x+run]); n > 0 {
+					copy(s.front.cells[frontBase+x:frontBase+x+n], s.back.cells[idx:idx+n])
+					stats.BytesSaved += saved
+					cursorX = x + n
+					if erased {
+						cursorX = x // erasing leaves the cursor in place
+					}
+					cursorY = y
+					x += n - 1
+					continue
+				}
 			}
 
 			s.writeCell(&s.buf, backCell)
@@ -405,33 +613,335 @@ func (s *Screen) Flush() {
 
 	if debugFlush {
 		fmt.Fprintf(os.Stderr, "Flush: %d dirty rows, %d changed rows, %d cursor positions, buf size %d\n",
-			dirtyCount, changedCount, positionCount, s.buf.Len())
+			stats.DirtyRows, stats.ChangedRows, positionCount, s.buf.Len())
 	}
 
 	// Reset style at end if we have changes
-	if changedCount > 0 {
+	if stats.ChangedRows > 0 || stats.ScrolledRows > 0 {
 		s.buf.WriteString("\x1b[0m")
 		s.lastStyle = DefaultStyle()
 	}
 	// Note: Don't write here - let FlushBuffer() do it so we can batch cursor ops
 
+	// the rows drawn now show the back buffer
+	if hashed {
+		for y, x := range s.rowDiff {
+			if x >= 0 {
+				s.frontHash[y] = s.backHash[y]
+			}
+		}
+	}
+
 	// Clear dirty flags for next frame
 	s.back.ClearDirtyFlags()
 
 	// Record stats
-	lastFlushStats = FlushStats{DirtyRows: dirtyCount, ChangedRows: changedCount}
+	stats.BytesWritten = s.buf.Len()
+	s.stats = stats
+	lastFlushed.Store(s)
+}
+
+// diffRows finds where each dirty row first differs from what the terminal
+// shows, -1 for rows that don't, and returns how many do.
+func (s *Screen) diffRows() int {
+	if len(s.rowDiff) != s.height {
+		s.rowDiff = make([]int, s.height)
+	}
+	changed := 0
+	for y := range s.height {
+		s.rowDiff[y] = -1
+		if s.back.RowDirty(y) {
+			if s.rowDiff[y] = s.firstDiff(y); s.rowDiff[y] >= 0 {
+				changed++
+			}
+		}
+	}
+	return changed
+}
+
+// hashRows hashes the changed rows of the back buffer. The front's hashes
+// are kept from the last Flush, so unchanged rows cost nothing.
+func (s *Screen) hashRows() {
+	h, w := s.height, s.width
+	if !s.hashed || len(s.frontHash) != h {
+		s.backHash, s.frontHash = make([]uint64, h), make([]uint64, h)
+		for y := range h {
+			s.frontHash[y] = hashRow(s.front.cells[y*w : (y+1)*w])
+		}
+		s.hashed = true
+	}
+	for y := range h {
+		s.backHash[y] = s.frontHash[y]
+		if s.rowDiff[y] >= 0 {
+			s.backHash[y] = hashRow(s.back.cells[y*w : (y+1)*w])
+		}
+	}
+}
+
+// firstDiff returns the first column where row y of the back and front
+// buffers differ, or -1.
+func (s *Screen) firstDiff(y int) int {
+	back := s.back.cells[y*s.back.width:][:s.width]
+	front := s.front.cells[y*s.front.width:][:s.width]
+	for x := range back {
+		if back[x] != front[x] {
+			return x
+		}
+	}
+	return -1
+}
+
+// scrollRows finds a run of rows that moved up or down since the last
+// frame, as when a log scrolls, and scrolls the terminal to match so they
+// aren't redrawn. The rows uncovered are left to the diff.
+func (s *Screen) scrollRows(stats *FlushStats) {
+	h, w := s.height, s.width
+	blank := hashRow(emptyCells(w)[:w])
+
+	// a changed row with content now shown d rows away suggests the
+	// terminal scroll by d
+	s.offsets = s.offsets[:0]
+	for y, x := range s.rowDiff {
+		if x < 0 || s.backHash[y] == blank {
+			continue
+		}
+		for src, fh := range s.frontHash {
+			if fh == s.backHash[y] && src != y && !slices.Contains(s.offsets, src-y) {
+				s.offsets = append(s.offsets, src-y)
+			}
+		}
+	}
+
+	// the longest run of back rows found d rows down (up > 0) or up in
+	// the front, counting the changed rows with content
+	var best struct{ y0, y1, d, rows int }
+	for _, d := range s.offsets {
+		y0, rows := -1, 0
+		for y := max(0, -d); y <= min(h, h-d); y++ {
+			if y < min(h, h-d) && s.backHash[y] == s.frontHash[y+d] {
+				if y0 < 0 {
+					y0, rows = y, 0
+				}
+				if s.rowDiff[y] >= 0 && s.backHash[y] != blank {
+					rows++
+				}
+				continue
+			}
+			if y0 >= 0 && rows > best.rows {
+				best.y0, best.y1, best.d, best.rows = y0, y-1, d, rows
+			}
+			y0 = -1
+		}
+	}
+	if best.rows == 0 {
+		return
+	}
+
+	// hashes only suggest; the rows must match
+	d := best.d
+	for y := best.y0; y <= best.y1; y++ {
+		if !slices.Equal(s.back.cells[y*w:(y+1)*w], s.front.cells[(y+d)*w:(y+d+1)*w]) {
+			return
+		}
+	}
+
+	// the region scrolled: the run and the rows it comes from
+	top, bot, n := best.y0, best.y1+d, d
+	if d < 0 {
+		top, bot, n = best.y0+d, best.y1, -d
+	}
+
+	// the run would otherwise be drawn over what is there now: about a
+	// byte a cell from each row's first change
+	plain := 0
+	for y := best.y0; y <= best.y1; y++ {
+		if s.rowDiff[y] >= 0 {
+			plain += w - s.rowDiff[y]
+		}
+	}
+	region := top > 0 || bot < h-1
+	cost := 4 + 3 + digits(n) // SGR reset, SU or SD
+	if region {
+		cost += 4 + digits(top+1) + digits(bot+1) + 3 // DECSTBM, and its reset
+	}
+	if plain <= cost {
+		return
+	}
+	start := s.buf.Len()
+	if !s.lastStyle.Equal(DefaultStyle()) {
+		s.buf.WriteString("\x1b[0m") // scrolled-in rows take the current background
+		s.lastStyle = DefaultStyle()
+	}
+	if region {
+		s.buf.WriteString("\x1b[")
+		s.writeIntToBuf(top + 1)
+		s.buf.WriteByte(';')
+		s.writeIntToBuf(bot + 1)
+		s.buf.WriteByte('r')
+	}
+	s.buf.WriteString("\x1b[")
+	s.writeIntToBuf(n)
+	if d > 0 {
+		s.buf.WriteByte('S')
+	} else {
+		s.buf.WriteByte('T')
+	}
+	if region {
+		s.buf.WriteString("\x1b[r")
+	}
+	stats.ScrolledRows = best.y1 - best.y0 + 1
+	stats.BytesSaved += plain - (s.buf.Len() - start)
+
+	// move the front rows as the terminal did, and diff the region again
+	front, hashes := s.front.cells, s.frontHash
+	if d > 0 {
+		copy(front[top*w:(bot+1-n)*w], front[(top+n)*w:(bot+1)*w])
+		copy(front[(bot+1-n)*w:(bot+1)*w], emptyCells(n*w))
+		copy(hashes[top:bot+1-n], hashes[top+n:bot+1])
+		for y := bot + 1 - n; y <= bot; y++ {
+			hashes[y] = blank
+		}
+	} else {
+		copy(front[(top+n)*w:(bot+1)*w], front[top*w:(bot+1-n)*w])
+		copy(front[top*w:(top+n)*w], emptyCells(n*w))
+		copy(hashes[top+n:bot+1], hashes[top:bot+1-n])
+		for y := top; y < top+n; y++ {
+			hashes[y] = blank
+		}
+	}
+	for y := top; y <= bot; y++ {
+		s.back.dirtyRows[y] = true
+		s.rowDiff[y] = s.firstDiff(y)
+	}
+}
+
+// hashRow hashes a row of cells by what they show.
+func hashRow(cells []Cell) uint64 {
+	const prime = 1099511628211
+	h := uint64(14695981039346656037)
+	color := func(c Color) uint64 {
+		return uint64(c.Mode)<<32 | uint64(c.R)<<24 | uint64(c.G)<<16 | uint64(c.B)<<8 | uint64(c.Index)
+	}
+	for i := range cells {
+		c := &cells[i]
+		h = (h ^ uint64(c.Rune)<<40 ^ uint64(c.Style.Attr)<<32 ^ color(c.Style.FG)) * prime
+		h = (h ^ color(c.Style.BG)) * prime
+	}
+	return h
+}
+
+// writeCursorPos moves the cursor to x, y (CUP).
+func (s *Screen) writeCursorPos(x, y int) {
+	s.buf.WriteString("\x1b[")
+	s.writeIntToBuf(y + 1)
+	s.buf.WriteByte(';')
+	s.writeIntToBuf(x + 1)
+	s.buf.WriteByte('H')
+}
+
+// cursorPosLen is the length of the CUP sequence writeCursorPos writes.
+func cursorPosLen(x, y int) int {
+	return 4 + digits(y+1) + digits(x+1)
+}
+
+func digits(n int) int {
+	d := 1
+	for n >= 10 {
+		n /= 10
+		d++
+	}
+	return d
+}
+
+// runLength returns how many cells from back cell idx, at column x, are the
+// same as it.
+func (s *Screen) runLength(idx, x int) int {
+	cells := s.back.cells
+	c := cells[idx]
+	n := 1
+	for x+n < s.width && cells[idx+n] == c {
+		n++
+	}
+	return n
+}
+
+// blankAttrs are the attributes that show on a blank cell, which erasing
+// doesn't reproduce.
+const blankAttrs = AttrUnderline | AttrInverse | AttrStrikethrough
+
+// writeRun writes run copies of c, at x, y where the cursor is, with one
+// sequence when that is shorter: blanks are erased to the end of the line
+// (EL) or by count (ECH), both in the current background, and other
+// characters written once then repeated (REP). old is what the terminal
+// shows there now. It returns how many cells it wrote, 0 if it left them
+// to be written one by one, the bytes that saved, and whether it erased,
+// which leaves the cursor where it was.
+func (s *Screen) writeRun(c Cell, x, y, run int, old []Cell) (n, saved int, erased bool) {
+	// writing one by one costs the changed cells; unchanged ones between
+	// them are skipped with a cursor move, which we count as nothing
+	plain := 0
+	size := utf8.RuneLen(c.Rune)
+	for i := range old {
+		if old[i] != c {
+			plain += size
+		}
+	}
+
+	if c.Rune == ' ' && c.Style.Attr&blankAttrs == 0 {
+		seq := 3 // EL
+		toEnd := x+run == s.width
+		if !toEnd {
+			// ECH, and moving past the run afterwards
+			seq = 3 + digits(run) + cursorPosLen(x+run, y)
+		}
+		if plain <= seq {
+			return 0, 0, false
+		}
+		if !c.Style.Equal(s.lastStyle) {
+			s.writeStyle(&s.buf, c.Style)
+			s.lastStyle = c.Style
+		}
+		if toEnd {
+			s.buf.WriteString("\x1b[K")
+		} else {
+			s.buf.WriteString("\x1b[")
+			s.writeIntToBuf(run)
+			s.buf.WriteByte('X')
+		}
+		return run, plain - seq, true
+	}
+
+	if s.noRepeat || c.Rune < 0x20 || (c.Rune >= 0x7f && c.Rune < 0xa0) ||
+		(c.Rune >= 0x1100 && runewidth.RuneWidth(c.Rune) != 1) {
+		return 0, 0, false
+	}
+	seq := size + 3 + digits(run-1) // the character, then REP
+	if plain <= seq {
+		return 0, 0, false
+	}
+	s.writeCell(&s.buf, c)
+	s.buf.WriteString("\x1b[")
+	s.writeIntToBuf(run - 1)
+	s.buf.WriteByte('b')
+	return run, plain - seq, false
 }
 
 // writeIntToBuf writes an integer to the buffer without allocation.
 func (s *Screen) writeIntToBuf(n int) {
+	writeInt(&s.buf, n)
+}
+
+// writeInt writes an integer to buf without allocation.
+func writeInt(buf *bytes.Buffer, n int) {
 	var scratch [10]byte
-	s.buf.Write(appendInt(scratch[:0], n))
+	buf.Write(appendInt(scratch[:0], n))
 }
 
 // FlushFull does a complete redraw without diffing.
 func (s *Screen) FlushFull() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.hashed = false
 
 	s.buf.Reset()
 	if s.syncOutput {
@@ -459,7 +969,8 @@ func (s *Screen) FlushFull() {
 	if s.syncOutput {
 		s.buf.WriteString("\x1b[?2026l")
 	}
-	s.writer.Write(s.buf.Bytes())
+	s.write(s.buf.Bytes())
+	s.buf.Reset() // so a following FlushBuffer sends only what is added
 }
 
 // FlushInline renders the buffer for inline mode (no alternate screen).
@@ -469,11 +980,26 @@ func (s *Screen) FlushFull() {
 // stale content does not remain on screen when the view shrinks.
 // Returns the number of lines rendered for cleanup tracking.
 func (s *Screen) FlushInline(height, prevLines int) int {
+	return s.flushInline(nil, height, prevLines)
+}
+
+// flushInline is FlushInline, first printing the lines above into the
+// terminal's normal flow in place of the old content. The frame follows
+// them in the same write, so nothing flickers.
+func (s *Screen) flushInline(above [][]Span, height, prevLines int) int {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.hashed = false
 
 	s.buf.Reset()
 
+	if len(above) > 0 {
+		s.buf.WriteString("\x1b[?2026h")
+		s.buf.WriteString("\r\x1b[J") // clear from the content's first line down
+		s.writeLines(&s.buf, above)
+		prevLines = 0 // nothing left below to clear
+	}
+
 	linesRendered := 0
 	for y := 0; y < height && y < s.height; y++ {
 		// Move to start of line, clear to end of line
@@ -513,13 +1039,37 @@ func (s *Screen) FlushInline(height, prevLines int) int {
 		s.buf.WriteString(fmt.Sprintf("\x1b[%dA", totalLines-1))
 	}
 	s.buf.WriteString("\r")
+	if len(above) > 0 {
+		s.buf.WriteString("\x1b[?2026l")
+	}
 
-	s.writer.Write(s.buf.Bytes())
+	s.write(s.buf.Bytes())
 	s.back.ClearDirtyFlags()
 
 	return linesRendered
 }
 
+// writeLines writes styled lines, each ending in a newline.
+func (s *Screen) writeLines(buf *bytes.Buffer, lines [][]Span) {
+	for _, line := range lines {
+		for _, sp := range line {
+			s.writeStyle(buf, sp.Style)
+			buf.WriteString(sp.Text)
+		}
+		buf.WriteString("\x1b[0m\r\n")
+	}
+	s.lastStyle = DefaultStyle()
+}
+
+// printLines writes styled lines at the cursor, outside of any frame.
+func (s *Screen) printLines(lines [][]Span) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.buf.Reset()
+	s.writeLines(&s.buf, lines)
+	s.write(s.buf.Bytes())
+}
+
 // writeCell writes a cell's style and rune to the buffer.
 func (s *Screen) writeCell(buf *bytes.Buffer, cell Cell) {
 	// Only emit style changes
@@ -589,10 +1139,10 @@ func (s *Screen) writeColor(buf *bytes.Buffer, c Color, fg bool) {
 		if c.Index >= 8 {
 			base += 60
 			buf.WriteByte(';')
-			s.writeIntToBuf(base + int(c.Index-8))
+			writeInt(buf, base+int(c.Index-8))
 		} else {
 			buf.WriteByte(';')
-			s.writeIntToBuf(base + int(c.Index))
+			writeInt(buf, base+int(c.Index))
 		}
 	case Color256:
 		if fg {
@@ -600,7 +1150,7 @@ func (s *Screen) writeColor(buf *bytes.Buffer, c Color, fg bool) {
 		} else {
 			buf.WriteString(";48;5;")
 		}
-		s.writeIntToBuf(int(c.Index))
+		writeInt(buf, int(c.Index))
 	case ColorRGB:
 		// True color
 		if fg {
@@ -608,17 +1158,28 @@ func (s *Screen) writeColor(buf *bytes.Buffer, c Color, fg bool) {
 		} else {
 			buf.WriteString(";48;2;")
 		}
-		s.writeIntToBuf(int(c.R))
+		writeInt(buf, int(c.R))
 		buf.WriteByte(';')
-		s.writeIntToBuf(int(c.G))
+		writeInt(buf, int(c.G))
 		buf.WriteByte(';')
-		s.writeIntToBuf(int(c.B))
+		writeInt(buf, int(c.B))
 	}
 }
 
 // writeString is a helper to write a string directly to the terminal.
 func (s *Screen) writeString(str string) {
 	io.WriteString(s.writer, str)
+	if r := s.recording.Load(); r != nil {
+		r.event("o", []byte(str))
+	}
+}
+
+// write sends p to the terminal, and to the recording if there is one.
+func (s *Screen) write(p []byte) {
+	s.writer.Write(p)
+	if r := s.recording.Load(); r != nil {
+		r.event("o", p)
+	}
 }
 
 // Clear clears the back buffer.
@@ -646,7 +1207,7 @@ func (s *Screen) MoveCursor(x, y int) {
 	b = append(b, ';')
 	b = appendInt(b, x+1)
 	b = append(b, 'H')
-	s.writer.Write(b)
+	s.write(b)
 }
 
 // BufferCursor writes cursor positioning and visibility to the internal buffer.
@@ -700,7 +1261,7 @@ func (s *Screen) FlushBuffer() {
 		if s.syncOutput {
 			s.buf.WriteString("\x1b[?2026l") // end synchronized update
 		}
-		s.writer.Write(s.buf.Bytes())
+		s.write(s.buf.Bytes())
 	}
 }
 
@@ -725,7 +1286,7 @@ func (s *Screen) SetCursorShape(shape CursorShape) {
 	b = append(b, "\x1b["...)
 	b = appendInt(b, int(shape))
 	b = append(b, " q"...)
-	s.writer.Write(b)
+	s.write(b)
 }
 
 // appendInt appends an integer to a byte slice without allocation.
@@ -754,8 +1315,18 @@ func appendInt(b []byte, n int) []byte {
 // Returns zero-value Colors on failure (unsupported terminal, timeout, etc.)
 // Callers should check Mode != ColorDefault.
 func (s *Screen) QueryDefaultColors() (fg, bg Color) {
-	if !s.inRawMode {
-		return
+	fg, bg, palette := s.queryColors()
+	s.setPalette(palette)
+	return fg, bg
+}
+
+// queryColors does QueryDefaultColors' terminal round trip without changing
+// the screen, so it can run while a frame renders. palette is the screen's
+// own with whichever colours the terminal reported.
+func (s *Screen) queryColors() (fg, bg Color, palette [16][3]uint8) {
+	palette = s.palette
+	if !s.inRawMode || s.sizeFn != nil {
+		return // a stream's replies would arrive as keys
 	}
 
 	// temporarily set non-blocking read with 100ms timeout
@@ -813,17 +1384,27 @@ func (s *Screen) QueryDefaultColors() (fg, bg Color) {
 	fg = parseOSCColor(data, '0') // OSC 10
 	bg = parseOSCColor(data, '1') // OSC 11
 
-	// parse OSC 4 palette responses and update basic16RGB
+	// parse OSC 4 palette responses
 	for i := range 16 {
 		if c := parseOSC4Color(data, i); c.Mode == ColorRGB {
-			basic16RGB[i] = [3]uint8{c.R, c.G, c.B}
+			palette[i] = [3]uint8{c.R, c.G, c.B}
 		}
 	}
-	refreshBasic16Vars()
 
 	return
 }
 
+// setPalette sets the screen's basic 16 colours. the process's terminal also
+// sets the package colours.
+func (s *Screen) setPalette(palette [16][3]uint8) {
+	s.palette = palette
+	if s.sizeFn != nil {
+		return
+	}
+	basic16RGB = palette
+	refreshBasic16Vars()
+}
+
 // parseOSC4Color extracts an RGB colour from an OSC 4 palette response.
 // response format: \x1b]4;N;rgb:rrrr/gggg/bbbb\x1b\\ or \x07
 func parseOSC4Color(data []byte, index int) Color {
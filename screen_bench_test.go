@@ -2,6 +2,7 @@ package glyph
 
 import (
 	"bytes"
+	"fmt"
 	"testing"
 )
 
@@ -218,7 +219,7 @@ func BenchmarkResolveColor16Pass(b *testing.B) {
 	b.ResetTimer()
 	b.ReportAllocs()
 	for i := 0; i < b.N; i++ {
-		resolveColor16(buf, 120, 40)
+		resolveColor16(buf, 120, 40, &basic16RGB)
 	}
 }
 
@@ -243,7 +244,7 @@ func BenchmarkVignetteTransition(b *testing.B) {
 			renderBuf.Set(x, y, Cell{Rune: 'A', Style: style})
 		}
 	}
-	resolveColor16(renderBuf, 120, 40)
+	resolveColor16(renderBuf, 120, 40, &basic16RGB)
 	SEVignette().Strength(1.0).Apply(renderBuf, PostContext{Width: 120, Height: 40})
 
 	b.ResetTimer()
@@ -281,7 +282,7 @@ func BenchmarkVignetteSteadyState(b *testing.B) {
 			renderBuf.Set(x, y, Cell{Rune: 'A', Style: style})
 		}
 	}
-	resolveColor16(renderBuf, 120, 40)
+	resolveColor16(renderBuf, 120, 40, &basic16RGB)
 	SEVignette().Strength(1.0).Apply(renderBuf, PostContext{Width: 120, Height: 40})
 
 	// prime both buffers with the same vignette output (steady state)
@@ -607,3 +608,77 @@ func BenchmarkEffectDispatchInterface(b *testing.B) {
 		pp.Apply(buf, ctx)
 	}
 }
+
+// benchScreen returns a 120×40 screen writing to a byte counter.
+func benchScreen() (*Screen, *mockWriter) {
+	w := &mockWriter{}
+	return &Screen{
+		width:  120,
+		height: 40,
+		back:   NewBuffer(120, 40),
+		front:  NewBuffer(120, 40),
+		writer: w,
+	}, w
+}
+
+// benchFlush flushes each frame draw makes, reporting the bytes written
+// and saved per frame.
+func benchFlush(b *testing.B, s *Screen, w *mockWriter, draw func(frame int)) {
+	draw(0)
+	s.Flush()
+	s.FlushBuffer()
+	b.ResetTimer()
+	b.ReportAllocs()
+	written, saved := 0, 0
+	for i := 0; i < b.N; i++ {
+		draw(i + 1)
+		w.n = 0
+		s.Flush()
+		s.FlushBuffer()
+		written += w.n
+		saved += s.stats.BytesSaved
+	}
+	b.ReportMetric(float64(written)/float64(b.N), "bytes/op")
+	b.ReportMetric(float64(saved)/float64(b.N), "saved-bytes/op")
+}
+
+// BenchmarkFlushLogScroll flushes a log scrolling by a line a frame,
+// between a header and a status line.
+func BenchmarkFlushLogScroll(b *testing.B) {
+	s, w := benchScreen()
+	text := Style{FG: Green}
+	benchFlush(b, s, w, func(frame int) {
+		s.back.WriteString(0, 0, "build log", Style{Attr: AttrBold})
+		for y := 1; y < 39; y++ {
+			s.back.WriteString(0, y, fmt.Sprintf("%6d  compiling package %-40d done in %3dms", frame+y, frame+y, (frame+y)%997), text)
+		}
+		s.back.WriteString(0, 39, fmt.Sprintf("%-120d", frame), Style{BG: Blue})
+	})
+}
+
+// BenchmarkFlushBlankRuns flushes panels of background colour that swap
+// places, as when a selection moves.
+func BenchmarkFlushBlankRuns(b *testing.B) {
+	s, w := benchScreen()
+	benchFlush(b, s, w, func(frame int) {
+		for y := range 40 {
+			bg := Style{BG: Black}
+			if (y/4+frame)%2 == 0 {
+				bg = Style{BG: Blue}
+			}
+			s.back.FillRect(0, y, 120, 1, Cell{Rune: ' ', Style: bg})
+			s.back.WriteString(2, y, "item", bg)
+		}
+	})
+}
+
+// BenchmarkFlushRepeatedRuns flushes borders and bars that change colour.
+func BenchmarkFlushRepeatedRuns(b *testing.B) {
+	s, w := benchScreen()
+	colors := []Color{Red, Green, Yellow}
+	benchFlush(b, s, w, func(frame int) {
+		for y := range 40 {
+			s.back.FillRect(0, y, 120, 1, Cell{Rune: '━', Style: Style{FG: colors[(y+frame)%3]}})
+		}
+	})
+}
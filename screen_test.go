@@ -3,6 +3,7 @@ package glyph
 import (
 	"bytes"
 	"fmt"
+	"math/rand/v2"
 	"strings"
 	"testing"
 )
@@ -86,3 +87,188 @@ func TestFlushInline(t *testing.T) {
 		}
 	})
 }
+
+// flushTo flushes s into vt, as a terminal showing s would receive it.
+func flushTo(s *Screen, out *bytes.Buffer, vt *vtScreen) string {
+	out.Reset()
+	s.Flush()
+	s.FlushBuffer()
+	vt.Write(out.Bytes())
+	return out.String()
+}
+
+// sameOnScreen reports where vt differs from buf in what shows: blanks
+// only by background.
+func sameOnScreen(vt *vtScreen, buf *Buffer) error {
+	for y := range buf.Height() {
+		for x := range buf.Width() {
+			want, got := buf.Get(x, y), vt.grid.Get(x, y)
+			if want.Rune != got.Rune || want.Style.BG != got.Style.BG {
+				return fmt.Errorf("(%d,%d) = %q %+v, want %q %+v", x, y, got.Rune, got.Style, want.Rune, want.Style)
+			}
+			visible := want.Style.Attr
+			if want.Rune == ' ' {
+				visible &= blankAttrs
+			} else if want.Style.FG != got.Style.FG {
+				return fmt.Errorf("(%d,%d) %q fg = %+v, want %+v", x, y, got.Rune, got.Style.FG, want.Style.FG)
+			}
+			if got.Style.Attr&visible != visible {
+				return fmt.Errorf("(%d,%d) %q attr = %v, want %v", x, y, got.Rune, got.Style.Attr, want.Style.Attr)
+			}
+		}
+	}
+	return nil
+}
+
+func TestFlushScrollsMovedRows(t *testing.T) {
+	s, out := newTestScreen(30, 8)
+	vt := newVTScreen(30, 8)
+	status := Style{FG: Black, BG: Cyan}
+	lines := []string{}
+	draw := func() {
+		s.back.Clear()
+		for y, l := range lines[max(len(lines)-6, 0):] {
+			s.back.WriteString(1, y+1, l, Style{FG: Green})
+		}
+		s.back.WriteString(0, 0, "log", Style{Attr: AttrBold})
+		s.back.WriteString(0, 7, fmt.Sprintf("%-30s", fmt.Sprintf("%d lines", len(lines))), status)
+	}
+	for i := range 6 {
+		lines = append(lines, fmt.Sprintf("line %d of the log, with text", i))
+	}
+	draw()
+	flushTo(s, out, vt)
+
+	lines = append(lines, "line 6 of the log, with text")
+	draw()
+	got := flushTo(s, out, vt)
+	if err := sameOnScreen(vt, s.back); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got, "\x1b[2;7r\x1b[1S\x1b[r") {
+		t.Errorf("log rows not scrolled: %q", got)
+	}
+	if strings.Contains(got, "line 5") {
+		t.Errorf("scrolled row redrawn: %q", got)
+	}
+	stats := s.FlushStats()
+	if stats.ScrolledRows != 5 || stats.BytesSaved <= 0 || stats.BytesWritten != len(got) {
+		t.Errorf("stats = %+v, %d bytes written", stats, len(got))
+	}
+
+	// scrolling back down
+	lines = lines[:len(lines)-1]
+	draw()
+	got = flushTo(s, out, vt)
+	if err := sameOnScreen(vt, s.back); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got, "\x1b[1T") {
+		t.Errorf("log rows not scrolled down: %q", got)
+	}
+}
+
+func TestFlushErasesAndRepeats(t *testing.T) {
+	s, out := newTestScreen(40, 3)
+	vt := newVTScreen(40, 3)
+	fill := func(r rune, style Style) {
+		for y := range 3 {
+			for x := range 40 {
+				s.back.Set(x, y, Cell{Rune: r, Style: style})
+			}
+		}
+	}
+	fill('x', DefaultStyle())
+	flushTo(s, out, vt)
+
+	// blanks to the row's end are erased, and before a cell by count
+	blue := Style{BG: Blue}
+	fill(' ', blue)
+	s.back.WriteString(30, 1, "end", DefaultStyle())
+	got := flushTo(s, out, vt)
+	if err := sameOnScreen(vt, s.back); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(got, "\x1b[K") != 3 || !strings.Contains(got, "\x1b[30X") || strings.Contains(got, "     ") {
+		t.Errorf("blanks = %q", got)
+	}
+
+	// underlined blanks don't erase; they and other runs repeat
+	fill('─', Style{FG: Red})
+	s.back.FillRect(0, 1, 40, 1, Cell{Rune: ' ', Style: Style{Attr: AttrUnderline}})
+	got = flushTo(s, out, vt)
+	if err := sameOnScreen(vt, s.back); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(got, "\x1b[39b") != 3 || strings.Count(got, "─") != 2 {
+		t.Errorf("repeats = %q", got)
+	}
+
+	// without REP, runs are written out
+	s.noRepeat = true
+	fill('=', DefaultStyle())
+	got = flushTo(s, out, vt)
+	if err := sameOnScreen(vt, s.back); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(got, "b") || strings.Count(got, "=") != 120 {
+		t.Errorf("without REP = %q", got)
+	}
+}
+
+func TestFlushMatchesTerminal(t *testing.T) {
+	// random frames of scrolling, shifting, blank and repeated content
+	// must leave the terminal showing exactly the frame
+	rng := rand.New(rand.NewPCG(1, 2))
+	s, out := newTestScreen(24, 10)
+	vt := newVTScreen(24, 10)
+	styles := []Style{DefaultStyle(), {FG: Red}, {BG: Blue}, {FG: Yellow, Attr: AttrBold}, {Attr: AttrUnderline}, {BG: Green, Attr: AttrInverse}}
+	row := func() []Cell {
+		cells := make([]Cell, 24)
+		for x := 0; x < 24; {
+			n := 1 + rng.IntN(8)
+			c := Cell{Rune: []rune{' ', ' ', 'a', 'b', '─', '█'}[rng.IntN(6)], Style: styles[rng.IntN(len(styles))]}
+			for ; n > 0 && x < 24; n-- {
+				cells[x] = c
+				x++
+			}
+		}
+		return cells
+	}
+	rows := make([][]Cell, 10)
+	for y := range rows {
+		rows[y] = row()
+	}
+	for frame := range 300 {
+		switch rng.IntN(5) {
+		case 0: // a region scrolls up or down
+			top := rng.IntN(9)
+			bot := top + 1 + rng.IntN(9-top)
+			if rng.IntN(2) == 0 {
+				copy(rows[top:bot], rows[top+1:bot+1])
+				rows[bot] = row()
+			} else {
+				copy(rows[top+1:bot+1], rows[top:bot])
+				rows[top] = row()
+			}
+		case 1: // a few rows change
+			for range 1 + rng.IntN(3) {
+				rows[rng.IntN(10)] = row()
+			}
+		case 2: // one cell changes
+			rows[rng.IntN(10)][rng.IntN(24)] = row()[0]
+		case 3: // rows swap
+			i, j := rng.IntN(10), rng.IntN(10)
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+		for y, cells := range rows {
+			for x, c := range cells {
+				s.back.Set(x, y, c)
+			}
+		}
+		flushTo(s, out, vt)
+		if err := sameOnScreen(vt, s.back); err != nil {
+			t.Fatalf("frame %d: %v", frame, err)
+		}
+	}
+}
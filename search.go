@@ -0,0 +1,463 @@
+package glyph
+
+import (
+	"regexp"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/junegunn/fzf/src/util"
+	"github.com/kungfusheep/riffkey"
+	"github.com/mattn/go-runewidth"
+)
+
+// ============================================================================
+// In-place search for layers (less-style "/", n, N)
+// ============================================================================
+
+// SearchMode selects how search queries are interpreted.
+type SearchMode uint8
+
+const (
+	SearchRegex SearchMode = iota // regular expression, case-insensitive unless the query has uppercase
+	SearchFzf                     // fzf query syntax, one match per line
+)
+
+// LayerSearch holds the search state of a Layer: the query, the matches
+// found in the layer's buffer and the current match. While a query is active
+// the bottom row of the viewport shows the prompt and a "3/41" counter.
+// Obtain it with Layer.Search; components wire it up through BindSearch.
+type LayerSearch struct {
+	layer        *Layer
+	mode         SearchMode
+	matchStyle   Style
+	currentStyle Style
+
+	query     string
+	cursor    int
+	prompting bool
+
+	// state restored when the prompt is cancelled
+	prevQuery   string
+	prevScrollY int
+
+	re      *regexp.Regexp
+	fzf     FzfQuery
+	matches []searchMatch
+	current int // index into matches, -1 when none selected
+
+	// buffer the matches were computed against
+	scanned *Buffer
+	dirty   bool
+
+	// row scratch
+	text []byte
+	cols []int
+
+	// wiring (set by App.wireBindings)
+	router *riffkey.Router
+	push   func(*riffkey.Router)
+	pop    func()
+	render func()
+}
+
+type searchMatch struct {
+	y, x, w int
+}
+
+// Search returns the layer's search state, creating it on first use.
+func (l *Layer) Search() *LayerSearch {
+	if l.search == nil {
+		l.search = &LayerSearch{
+			layer:        l,
+			matchStyle:   Style{FG: Black, BG: Yellow},
+			currentStyle: Style{FG: Black, BG: BrightYellow, Attr: AttrBold},
+			current:      -1,
+		}
+	}
+	return l.search
+}
+
+// Mode sets how queries are interpreted. Default is SearchRegex.
+func (s *LayerSearch) Mode(m SearchMode) *LayerSearch {
+	s.mode = m
+	s.compile()
+	return s
+}
+
+// MatchStyle sets the highlight for matches. Only colours are applied;
+// the underlying text keeps its attributes.
+func (s *LayerSearch) MatchStyle(st Style) *LayerSearch {
+	s.matchStyle = st
+	return s
+}
+
+// CurrentStyle sets the highlight for the selected match.
+func (s *LayerSearch) CurrentStyle(st Style) *LayerSearch {
+	s.currentStyle = st
+	return s
+}
+
+// Query returns the active query.
+func (s *LayerSearch) Query() string {
+	return s.query
+}
+
+// Active reports whether a query is set or the prompt is open.
+func (s *LayerSearch) Active() bool {
+	return s.query != "" || s.prompting
+}
+
+// Prompting reports whether the query prompt is open.
+func (s *LayerSearch) Prompting() bool {
+	return s.prompting
+}
+
+// Count returns the number of matches in the layer.
+func (s *LayerSearch) Count() int {
+	s.refresh()
+	return len(s.matches)
+}
+
+// Current returns the 1-based position of the selected match, or 0 if none.
+func (s *LayerSearch) Current() int {
+	s.refresh()
+	return s.current + 1
+}
+
+// SetQuery runs a search and jumps to the first match at or below the top
+// of the viewport, the way incremental search does while typing.
+func (s *LayerSearch) SetQuery(q string) {
+	s.query = q
+	s.cursor = len(q)
+	s.compile()
+	s.current = -1
+	s.refresh()
+	s.jumpFrom(s.layer.scrollY, 0)
+}
+
+// Clear removes the query and all highlights.
+func (s *LayerSearch) Clear() {
+	s.query = ""
+	s.cursor = 0
+	s.compile()
+	s.matches = s.matches[:0]
+	s.current = -1
+	s.layer.updateMaxScroll() // the status row no longer needs a line
+}
+
+// Next selects the next match, wrapping at the end, and scrolls to it.
+func (s *LayerSearch) Next() {
+	s.refresh()
+	if len(s.matches) == 0 {
+		return
+	}
+	if s.current < 0 {
+		s.jumpFrom(s.layer.scrollY, 0)
+		return
+	}
+	s.current = (s.current + 1) % len(s.matches)
+	s.reveal()
+}
+
+// Prev selects the previous match, wrapping at the start, and scrolls to it.
+func (s *LayerSearch) Prev() {
+	s.refresh()
+	if len(s.matches) == 0 {
+		return
+	}
+	if s.current < 0 {
+		s.jumpFrom(s.layer.scrollY, 0)
+	}
+	s.current = (s.current - 1 + len(s.matches)) % len(s.matches)
+	s.reveal()
+}
+
+// Refresh forces matches to be recomputed. Only needed when the layer's
+// buffer is modified in place; replacing it with SetBuffer is detected.
+func (s *LayerSearch) Refresh() {
+	s.dirty = true
+}
+
+// bindings returns the key bindings for a search key plus n/N.
+// onJump runs after navigation (e.g. so a Log stops following).
+func (s *LayerSearch) bindings(key string, onJump func()) []binding {
+	jump := func(f func()) func() {
+		return func() {
+			f()
+			if onJump != nil && s.current >= 0 {
+				onJump()
+			}
+		}
+	}
+	return []binding{
+		{pattern: key, handler: s.open, name: "search.open", desc: "search", group: "Search"},
+		{pattern: "n", handler: jump(s.Next), name: "search.next", desc: "next match", group: "Search"},
+		{pattern: "N", handler: jump(s.Prev), name: "search.prev", desc: "previous match", group: "Search"},
+	}
+}
+
+// open shows the query prompt and pushes a text-input router.
+func (s *LayerSearch) open() {
+	s.prevQuery = s.query
+	s.prevScrollY = s.layer.scrollY
+	s.prompting = true
+	s.query = ""
+	s.cursor = 0
+	s.compile()
+	s.current = -1
+	s.dirty = true
+
+	if s.push == nil {
+		return
+	}
+	if s.router == nil {
+		s.router = riffkey.NewRouter()
+		s.router.Handle("<Enter>", func(_ riffkey.Match) { s.commit(); s.requestRender() })
+		s.router.Handle("<Escape>", func(_ riffkey.Match) { s.cancel(); s.requestRender() })
+		th := riffkey.NewTextHandler(&s.query, &s.cursor)
+		th.OnChange = func(q string) { s.SetQuery(q); s.requestRender() }
+		s.router.HandleUnmatched(th.HandleKey)
+		s.router.NoCounts()
+	}
+	s.push(s.router)
+}
+
+// commit closes the prompt, keeping the query. An empty query clears the search.
+func (s *LayerSearch) commit() {
+	s.prompting = false
+	if s.query == "" {
+		s.Clear()
+	}
+	if s.pop != nil {
+		s.pop()
+	}
+}
+
+// cancel closes the prompt and restores the previous query and scroll.
+func (s *LayerSearch) cancel() {
+	s.prompting = false
+	s.query = s.prevQuery
+	s.cursor = len(s.query)
+	s.compile()
+	s.current = -1
+	s.dirty = true
+	s.layer.updateMaxScroll()
+	s.layer.ScrollTo(s.prevScrollY)
+	if s.pop != nil {
+		s.pop()
+	}
+}
+
+func (s *LayerSearch) requestRender() {
+	if s.render != nil {
+		s.render()
+	}
+}
+
+// compile prepares the matcher for the current query and mode.
+func (s *LayerSearch) compile() {
+	s.dirty = true
+	s.re = nil
+	s.fzf = FzfQuery{}
+	if s.query == "" {
+		return
+	}
+	if s.mode == SearchFzf {
+		s.fzf = ParseFzfQuery(s.query)
+		return
+	}
+	flags := ""
+	if !hasUppercase(s.query) {
+		flags = "(?i)"
+	}
+	re, err := regexp.Compile(flags + s.query)
+	if err != nil {
+		// half-typed patterns like "foo(" search literally
+		re = regexp.MustCompile(flags + regexp.QuoteMeta(s.query))
+	}
+	s.re = re
+}
+
+// refresh rescans the buffer when it or the query changed. The selected
+// match is kept, or moved to the nearest match after it.
+func (s *LayerSearch) refresh() {
+	buf := s.layer.buffer
+	if !s.dirty && buf == s.scanned {
+		return
+	}
+	s.dirty = false
+	s.scanned = buf
+
+	var prev searchMatch
+	hadCurrent := s.current >= 0 && s.current < len(s.matches)
+	if hadCurrent {
+		prev = s.matches[s.current]
+	}
+
+	s.matches = s.matches[:0]
+	s.current = -1
+	if buf == nil || (s.re == nil && s.fzf.Empty()) {
+		return
+	}
+
+	slab := fzfSlabs.Get().(*util.Slab)
+	defer fzfSlabs.Put(slab)
+	for y := 0; y < buf.height; y++ {
+		text := s.rowText(buf, y)
+		if s.re != nil {
+			for _, m := range s.re.FindAllStringIndex(text, -1) {
+				if m[1] == m[0] {
+					continue
+				}
+				a := utf8.RuneCountInString(text[:m[0]])
+				b := a + utf8.RuneCountInString(text[m[0]:m[1]])
+				s.matches = append(s.matches, searchMatch{y: y, x: s.cols[a], w: s.cols[b] - s.cols[a]})
+			}
+			continue
+		}
+		ranges, ok := s.fzf.matchRanges(text, slab)
+		if !ok {
+			continue
+		}
+		if len(ranges) == 0 {
+			// negation-only queries match the whole line
+			ranges = [][2]int{{0, len(s.cols) - 1}}
+		}
+		lo, hi := ranges[0][0], ranges[0][1]
+		for _, r := range ranges[1:] {
+			lo, hi = min(lo, r[0]), max(hi, r[1])
+		}
+		s.matches = append(s.matches, searchMatch{y: y, x: s.cols[lo], w: max(s.cols[hi]-s.cols[lo], 1)})
+	}
+
+	if hadCurrent {
+		for i, m := range s.matches {
+			if m.y > prev.y || (m.y == prev.y && m.x >= prev.x) {
+				s.current = i
+				break
+			}
+		}
+	}
+}
+
+// rowText returns the trimmed text of row y. s.cols maps each rune index to
+// its column, with a trailing sentinel for the column after the last rune.
+func (s *LayerSearch) rowText(buf *Buffer, y int) string {
+	s.text = s.text[:0]
+	s.cols = s.cols[:0]
+	runes, endCol := 0, 0
+	trimRunes, trimBytes := 0, 0
+	base := y * buf.width
+	for x := 0; x < buf.width; x++ {
+		r := buf.cells[base+x].Rune
+		if r == 0 {
+			continue // wide-character continuation
+		}
+		s.text = utf8.AppendRune(s.text, r)
+		s.cols = append(s.cols, x)
+		runes++
+		if r != ' ' {
+			trimRunes, trimBytes = runes, len(s.text)
+			endCol = x + max(runewidth.RuneWidth(r), 1)
+		}
+	}
+	s.text = s.text[:trimBytes]
+	s.cols = append(s.cols[:trimRunes], endCol)
+	return string(s.text)
+}
+
+// jumpFrom selects the first match at or after (y, x) and scrolls to it.
+func (s *LayerSearch) jumpFrom(y, x int) {
+	if len(s.matches) == 0 {
+		s.current = -1
+		return
+	}
+	s.current = 0
+	for i, m := range s.matches {
+		if m.y > y || (m.y == y && m.x >= x) {
+			s.current = i
+			break
+		}
+	}
+	s.reveal()
+}
+
+// reveal scrolls the layer so the current match is visible above the
+// status row: up one line when the status row covers it, or centred when
+// it is off screen.
+func (s *LayerSearch) reveal() {
+	if s.current < 0 {
+		return
+	}
+	l := s.layer
+	l.updateMaxScroll()
+	rows := max(l.viewHeight-1, 1)
+	y := s.matches[s.current].y
+	switch {
+	case y == l.scrollY+rows:
+		l.ScrollTo(l.scrollY + 1)
+	case y < l.scrollY || y > l.scrollY+rows:
+		l.ScrollTo(y - rows/2)
+	}
+}
+
+// draw highlights visible matches and renders the status row.
+// Called after the layer has been blitted to dst.
+func (s *LayerSearch) draw(dst *Buffer, dstX, dstY, width, height int) {
+	if !s.Active() || width <= 0 || height <= 0 {
+		return
+	}
+	s.refresh()
+
+	top := s.layer.scrollY
+	for i, m := range s.matches {
+		row := m.y - top
+		if row < 0 {
+			continue
+		}
+		if row >= height {
+			break
+		}
+		st := s.matchStyle
+		if i == s.current {
+			st = s.currentStyle
+		}
+		for x := m.x; x < m.x+m.w && x < width; x++ {
+			c := dst.Get(dstX+x, dstY+row)
+			c.Style.FG, c.Style.BG = st.FG, st.BG
+			c.Style.Attr |= st.Attr
+			dst.SetFast(dstX+x, dstY+row, c)
+		}
+	}
+
+	// status row: "/query" on the left, "3/41" on the right
+	y := dstY + height - 1
+	for x := 0; x < width; x++ {
+		dst.SetFast(dstX+x, y, EmptyCell())
+	}
+	prompt := "/" + s.query
+	dst.WriteStringFast(dstX, y, prompt, Style{}, width)
+	if s.prompting {
+		cx := 1 + utf8.RuneCountInString(s.query[:min(s.cursor, len(s.query))])
+		if cx < width {
+			c := dst.Get(dstX+cx, y)
+			c.Style.Attr |= AttrInverse
+			dst.SetFast(dstX+cx, y, c)
+		}
+	}
+	status := s.status()
+	if sx := width - len(status); sx > utf8.RuneCountInString(prompt)+1 {
+		dst.WriteStringFast(dstX+sx, y, status, Style{FG: BrightBlack}, len(status))
+	}
+}
+
+// status returns the counter text, e.g. "3/41" or "no matches".
+func (s *LayerSearch) status() string {
+	if s.query == "" {
+		return ""
+	}
+	if len(s.matches) == 0 {
+		return "no matches"
+	}
+	return strconv.Itoa(s.current+1) + "/" + strconv.Itoa(len(s.matches))
+}
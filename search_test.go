@@ -0,0 +1,277 @@
+package glyph
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func searchLayer(lines ...string) *Layer {
+	buf := NewBuffer(40, len(lines))
+	for i, line := range lines {
+		buf.WriteStringFast(0, i, line, Style{}, 40)
+	}
+	l := NewLayer()
+	l.SetViewport(40, 4)
+	l.SetBuffer(buf)
+	return l
+}
+
+func TestLayerSearchRegex(t *testing.T) {
+	var lines []string
+	for i := range 20 {
+		if i%5 == 0 {
+			lines = append(lines, fmt.Sprintf("line %d ERROR boom", i))
+		} else {
+			lines = append(lines, fmt.Sprintf("line %d ok", i))
+		}
+	}
+	l := searchLayer(lines...)
+	s := l.Search()
+
+	s.SetQuery("error")
+	if s.Count() != 4 {
+		t.Fatalf("expected 4 matches (case-insensitive), got %d", s.Count())
+	}
+	if s.Current() != 1 {
+		t.Errorf("expected first match selected, got %d", s.Current())
+	}
+
+	s.Next()
+	s.Next()
+	if s.Current() != 3 {
+		t.Errorf("expected match 3, got %d", s.Current())
+	}
+	// match 3 is on row 10; must be visible above the status row
+	if y := 10; y < l.ScrollY() || y >= l.ScrollY()+l.ViewportHeight()-1 {
+		t.Errorf("row %d not visible at scrollY %d", y, l.ScrollY())
+	}
+
+	s.Next()
+	s.Next()
+	if s.Current() != 1 {
+		t.Errorf("expected wrap to match 1, got %d", s.Current())
+	}
+	s.Prev()
+	if s.Current() != 4 {
+		t.Errorf("expected wrap back to match 4, got %d", s.Current())
+	}
+
+	// uppercase makes the search case-sensitive
+	s.SetQuery("Error")
+	if s.Count() != 0 {
+		t.Errorf("expected no case-sensitive matches, got %d", s.Count())
+	}
+
+	// invalid regex falls back to a literal search
+	s.SetQuery("boom(")
+	if s.Count() != 0 {
+		t.Errorf("expected literal fallback with no matches, got %d", s.Count())
+	}
+	s.SetQuery(`line \d+ ok`)
+	if s.Count() != 16 {
+		t.Errorf("expected 16 regex matches, got %d", s.Count())
+	}
+}
+
+func TestLayerSearchMultipleMatchesPerLine(t *testing.T) {
+	l := searchLayer("ab ab ab", "xx", "ab")
+	s := l.Search()
+	s.SetQuery("ab")
+	if s.Count() != 4 {
+		t.Fatalf("expected 4 matches, got %d", s.Count())
+	}
+	if m := s.matches[1]; m.y != 0 || m.x != 3 || m.w != 2 {
+		t.Errorf("match 2 = %+v", m)
+	}
+}
+
+func TestLayerSearchWideCharacters(t *testing.T) {
+	buf := NewBuffer(20, 1)
+	buf.WriteSpans(0, 0, []Span{{Text: "日本 test"}}, 20)
+	l := NewLayer()
+	l.SetViewport(20, 2)
+	l.SetBuffer(buf)
+
+	s := l.Search()
+	s.SetQuery("test")
+	if s.Count() != 1 {
+		t.Fatalf("expected 1 match, got %d", s.Count())
+	}
+	if m := s.matches[0]; m.x != 5 || m.w != 4 {
+		t.Errorf("expected match at col 5 width 4, got %+v", m)
+	}
+}
+
+func TestLayerSearchFzf(t *testing.T) {
+	l := searchLayer("connection refused", "all good", "conn reset by peer")
+	s := l.Search().Mode(SearchFzf)
+	s.SetQuery("'conn !reset")
+	if s.Count() != 1 {
+		t.Fatalf("expected 1 match, got %d", s.Count())
+	}
+	if m := s.matches[0]; m.y != 0 || m.x != 0 || m.w != 4 {
+		t.Errorf("match = %+v", m)
+	}
+}
+
+func TestLayerSearchDraw(t *testing.T) {
+	l := searchLayer("foo bar", "bar foo", "nothing")
+	s := l.Search()
+	s.SetQuery("foo")
+
+	dst := NewBuffer(20, 4)
+	l.blit(dst, 0, 0, 20, 4)
+
+	if got := dst.Get(0, 0).Style.BG; got != s.currentStyle.BG {
+		t.Errorf("current match bg = %+v", got)
+	}
+	if got := dst.Get(4, 1).Style.BG; got != s.matchStyle.BG {
+		t.Errorf("other match bg = %+v", got)
+	}
+	if got := dst.Get(0, 1).Style.BG; got != (Color{}) {
+		t.Errorf("non-match should not be highlighted, got %+v", got)
+	}
+	status := dst.GetLine(3)
+	if !strings.HasPrefix(status, "/foo") || !strings.HasSuffix(status, "1/2") {
+		t.Errorf("status row = %q", status)
+	}
+
+	s.SetQuery("zzz")
+	l.blit(dst, 0, 0, 20, 4)
+	if status := dst.GetLine(3); !strings.HasSuffix(status, "no matches") {
+		t.Errorf("status row = %q", status)
+	}
+
+	s.Clear()
+	dst = NewBuffer(20, 4)
+	l.blit(dst, 0, 0, 20, 4)
+	if got := dst.GetLine(3); got != "" {
+		t.Errorf("cleared search should not draw a status row, got %q", got)
+	}
+}
+
+func TestLayerSearchStatusRowDoesNotCoverMatch(t *testing.T) {
+	// a match on the row the status covers scrolls up one line
+	l := searchLayer("a", "b", "c", "target", "d", "e", "f", "g")
+	s := l.Search()
+	s.SetQuery("target")
+	if l.ScrollY() != 1 {
+		t.Errorf("scrollY = %d, want 1", l.ScrollY())
+	}
+
+	// the last line can scroll clear of the status row too
+	l = searchLayer("a", "b", "c", "target")
+	s = l.Search()
+	s.SetQuery("target")
+	dst := NewBuffer(20, 4)
+	l.blit(dst, 0, 0, 20, 4)
+	if got := dst.GetLine(2); got != "target" {
+		t.Errorf("row above the status = %q", got)
+	}
+	if got := dst.GetLine(3); !strings.HasPrefix(got, "/target") {
+		t.Errorf("status row = %q", got)
+	}
+
+	s.Clear()
+	if l.MaxScroll() != 0 || l.ScrollY() != 0 {
+		t.Errorf("after clearing maxScroll = %d scrollY = %d, want 0", l.MaxScroll(), l.ScrollY())
+	}
+
+	// and cancelling a prompt opened with no search gives the line back
+	s.open()
+	s.SetQuery("target")
+	s.cancel()
+	if l.MaxScroll() != 0 || l.ScrollY() != 0 {
+		t.Errorf("after cancelling maxScroll = %d scrollY = %d, want 0", l.MaxScroll(), l.ScrollY())
+	}
+}
+
+func TestLayerSearchPromptCancelRestores(t *testing.T) {
+	l := searchLayer("a", "b", "c", "d", "e", "f", "target")
+	s := l.Search()
+	s.SetQuery("c")
+	l.ScrollTo(1)
+
+	s.open()
+	if !s.Prompting() || s.Query() != "" {
+		t.Fatalf("expected an empty prompt, got prompting=%v query=%q", s.Prompting(), s.Query())
+	}
+	s.SetQuery("target")
+	if l.ScrollY() == 1 {
+		t.Error("expected incremental search to scroll to the match")
+	}
+	s.cancel()
+	if s.Prompting() || s.Query() != "c" || l.ScrollY() != 1 {
+		t.Errorf("cancel should restore: prompting=%v query=%q scrollY=%d", s.Prompting(), s.Query(), l.ScrollY())
+	}
+
+	s.open()
+	s.commit()
+	if s.Active() {
+		t.Error("committing an empty query should clear the search")
+	}
+}
+
+func TestLogSearchWhileStreaming(t *testing.T) {
+	pr, pw := io.Pipe()
+	lv := Log(pr).BindSearch("/")
+	tmpl := Build(VBox(lv))
+	if len(tmpl.pendingSearches) != 1 {
+		t.Fatalf("expected search to be collected for wiring, got %d", len(tmpl.pendingSearches))
+	}
+	if len(lv.bindings()) != 3 {
+		t.Fatalf("expected /, n and N bindings, got %d", len(lv.bindings()))
+	}
+
+	pw.Write([]byte("warn one\ninfo\nwarn two\n"))
+	time.Sleep(50 * time.Millisecond)
+
+	lv.mu.Lock()
+	lv.layer.SetViewport(40, 2)
+	s := lv.layer.Search()
+	s.SetQuery("warn")
+	count := s.Count()
+	lv.mu.Unlock()
+	if count != 2 {
+		t.Fatalf("expected 2 matches, got %d", count)
+	}
+
+	// n stops following so streaming doesn't yank the view away
+	for _, b := range lv.bindings() {
+		if b.pattern == "n" {
+			lv.mu.Lock()
+			b.handler.(func())()
+			lv.mu.Unlock()
+		}
+	}
+	if lv.following {
+		t.Error("expected jumping to a match to stop following")
+	}
+
+	pw.Write([]byte("warn three\n"))
+	time.Sleep(50 * time.Millisecond)
+	pw.Close()
+
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+	if s.Count() != 3 {
+		t.Errorf("expected matches to include streamed line, got %d", s.Count())
+	}
+	if s.Current() != 2 {
+		t.Errorf("expected selection to stay on match 2, got %d", s.Current())
+	}
+}
+
+func TestLayerViewBindSearch(t *testing.T) {
+	l := searchLayer("x")
+	tmpl := Build(VBox(LayerView(l).BindSearch("/")))
+	if len(tmpl.pendingBindings) != 3 {
+		t.Errorf("expected 3 bindings, got %d", len(tmpl.pendingBindings))
+	}
+	if len(tmpl.pendingSearches) != 1 {
+		t.Errorf("expected 1 pending search, got %d", len(tmpl.pendingSearches))
+	}
+}
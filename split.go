@@ -0,0 +1,443 @@
+package glyph
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"unsafe"
+)
+
+// SplitDir is the direction panes are arranged in a Split.
+type SplitDir uint8
+
+const (
+	Horizontal SplitDir = iota // panes side by side with a vertical divider
+	Vertical                   // panes stacked with a horizontal divider
+)
+
+// SplitPane identifies one pane of a Split.
+type SplitPane uint8
+
+const (
+	SplitFirst  SplitPane = iota + 1 // the first (left or top) pane
+	SplitSecond                      // the second (right or bottom) pane
+)
+
+// MarshalText encodes the pane as "first" or "second".
+func (p SplitPane) MarshalText() ([]byte, error) {
+	switch p {
+	case SplitFirst:
+		return []byte("first"), nil
+	case SplitSecond:
+		return []byte("second"), nil
+	}
+	return []byte(""), nil
+}
+
+// UnmarshalText decodes "first" or "second".
+func (p *SplitPane) UnmarshalText(b []byte) error {
+	switch string(b) {
+	case "first":
+		*p = SplitFirst
+	case "second":
+		*p = SplitSecond
+	case "":
+		*p = 0
+	default:
+		return fmt.Errorf("unknown split pane %q", b)
+	}
+	return nil
+}
+
+// SplitState is the persisted layout of a Split and the splits nested
+// directly in its panes.
+type SplitState struct {
+	Ratio  float64     `json:"ratio"`
+	Hidden SplitPane   `json:"hidden,omitempty"`
+	First  *SplitState `json:"first,omitempty"`
+	Second *SplitState `json:"second,omitempty"`
+}
+
+// SplitC is a two-pane container with a resizable divider. The divider is
+// a VRule or HRule that merges with surrounding borders. Panes keep their
+// share of the space as the terminal resizes, subject to minimum sizes, and
+// either pane can be collapsed so the other fills the split.
+//
+//	editor := Split(Vertical, code, Log(out)).Ratio(0.75)
+//	layout := Split(Horizontal, sidebar, editor).
+//		Ratio(0.25).MinSize(12, 20).
+//		BindResize("<C-h>", "<C-l>").
+//		BindCollapse("<C-b>", SplitFirst)
+type SplitC struct {
+	dir        SplitDir
+	a, b       any
+	ratio      float64
+	minA, minB int16
+	step       int16
+	hidden     SplitPane
+	space      int16  // cells shared by the panes at the last layout
+	name       string // namespaces the action names of its key bindings
+
+	// container
+	border       BorderStyle
+	borderFG     *Color
+	title        string
+	dividerStyle Style
+	grow         float32
+	margin       [4]int16
+	flexGrowPtr  *float32
+	flexGrowCond conditionNode
+
+	declaredBindings []binding
+}
+
+// Split creates a split container. Horizontal places a and b side by side,
+// Vertical stacks them. The space is shared equally until Ratio is set.
+func Split(dir SplitDir, a, b any) *SplitC {
+	return &SplitC{
+		dir:   dir,
+		a:     a,
+		b:     b,
+		ratio: 0.5,
+		step:  1,
+		grow:  1,
+	}
+}
+
+// Ratio sets the share of the space given to the first pane (0.0-1.0).
+func (s *SplitC) Ratio(r float64) *SplitC {
+	s.ratio = max(0, min(r, 1))
+	return s
+}
+
+// MinSize sets the minimum width (Horizontal) or height (Vertical) of
+// each pane. Resizing stops at these limits.
+func (s *SplitC) MinSize(a, b int) *SplitC {
+	s.minA, s.minB = int16(a), int16(b)
+	return s
+}
+
+// Step sets how many cells the divider moves per resize key. Default is 1.
+func (s *SplitC) Step(n int) *SplitC {
+	s.step = int16(max(n, 1))
+	return s
+}
+
+// Name namespaces the split's key binding actions, so "split.grow" becomes
+// "split.<name>.grow". Give each split that binds keys its own name when
+// splits nest, so keymaps and help can tell them apart.
+//
+//	Split(Horizontal, tree, Split(Vertical, code, term).Name("editor").BindResize("<C-k>", "<C-j>")).
+//		Name("main").BindResize("<C-h>", "<C-l>")
+func (s *SplitC) Name(name string) *SplitC {
+	s.name = name
+	return s
+}
+
+// Border draws a border around the split. The divider joins it with
+// junction characters.
+func (s *SplitC) Border(b BorderStyle) *SplitC {
+	s.border = b
+	return s
+}
+
+// BorderFG sets the border foreground color.
+func (s *SplitC) BorderFG(c Color) *SplitC {
+	s.borderFG = &c
+	return s
+}
+
+// Title sets the border title.
+func (s *SplitC) Title(t string) *SplitC {
+	s.title = t
+	return s
+}
+
+// DividerStyle sets the divider's style.
+func (s *SplitC) DividerStyle(st Style) *SplitC {
+	s.dividerStyle = st
+	return s
+}
+
+// Grow sets the flex grow factor. Default is 1 so a split fills its parent.
+// Accepts float32, float64, int, or *float32 for dynamic values.
+func (s *SplitC) Grow(g any) *SplitC {
+	switch val := g.(type) {
+	case float32:
+		s.grow = val
+	case float64:
+		s.grow = float32(val)
+	case int:
+		s.grow = float32(val)
+	case *float32:
+		s.flexGrowPtr = val
+	case conditionNode:
+		s.flexGrowCond = val
+	}
+	return s
+}
+
+// Margin sets uniform margin on all sides.
+func (s *SplitC) Margin(all int16) *SplitC {
+	s.margin = [4]int16{all, all, all, all}
+	return s
+}
+
+// MarginVH sets vertical and horizontal margin.
+func (s *SplitC) MarginVH(v, h int16) *SplitC {
+	s.margin = [4]int16{v, h, v, h}
+	return s
+}
+
+// MarginTRBL sets individual margins for top, right, bottom, left.
+func (s *SplitC) MarginTRBL(t, r, b, l int16) *SplitC {
+	s.margin = [4]int16{t, r, b, l}
+	return s
+}
+
+// Resize moves the divider by n cells; positive values grow the first pane.
+// A collapsed pane is restored first.
+func (s *SplitC) Resize(n int) {
+	s.hidden = 0
+	if s.space <= 0 {
+		// not laid out yet: treat the split as 100 cells
+		s.Ratio(s.ratio + float64(n)/100)
+		return
+	}
+	a := int16(math.Round(s.ratio*float64(s.space))) + int16(n)
+	a = s.clamp(a, s.space)
+	s.ratio = float64(a) / float64(s.space)
+}
+
+// Collapse hides pane p so the other pane fills the split.
+func (s *SplitC) Collapse(p SplitPane) { s.hidden = p }
+
+// Maximize hides the pane other than p.
+func (s *SplitC) Maximize(p SplitPane) {
+	switch p {
+	case SplitFirst:
+		s.hidden = SplitSecond
+	case SplitSecond:
+		s.hidden = SplitFirst
+	}
+}
+
+// Restore shows both panes again.
+func (s *SplitC) Restore() { s.hidden = 0 }
+
+// Hidden returns the collapsed pane, or 0 when both are shown.
+func (s *SplitC) Hidden() SplitPane { return s.hidden }
+
+// BindResize registers keys that move the divider towards the first pane
+// (shrink) and towards the second (grow).
+func (s *SplitC) BindResize(shrink, grow string) *SplitC {
+	s.declaredBindings = append(s.declaredBindings,
+		binding{pattern: shrink, handler: func() { s.Resize(-int(s.step)) }, name: "split.shrink", desc: "shrink pane", group: "Split"},
+		binding{pattern: grow, handler: func() { s.Resize(int(s.step)) }, name: "split.grow", desc: "grow pane", group: "Split"},
+	)
+	return s
+}
+
+// BindCollapse registers key to toggle collapsing pane p.
+func (s *SplitC) BindCollapse(key string, p SplitPane) *SplitC {
+	s.declaredBindings = append(s.declaredBindings, binding{pattern: key, handler: func() {
+		if s.hidden == p {
+			s.Restore()
+		} else {
+			s.Collapse(p)
+		}
+	}, name: "split.collapse", desc: "collapse pane", group: "Split"})
+	return s
+}
+
+// BindMaximize registers key to toggle maximising pane p.
+func (s *SplitC) BindMaximize(key string, p SplitPane) *SplitC {
+	s.declaredBindings = append(s.declaredBindings, binding{pattern: key, handler: func() {
+		if s.hidden != 0 && s.hidden != p {
+			s.Restore()
+		} else {
+			s.Maximize(p)
+		}
+	}, name: "split.maximize", desc: "maximize pane", group: "Split"})
+	return s
+}
+
+func (s *SplitC) bindings() []binding {
+	if s.name == "" {
+		return s.declaredBindings
+	}
+	binds := slices.Clone(s.declaredBindings)
+	for i := range binds {
+		binds[i].name = "split." + s.name + strings.TrimPrefix(binds[i].name, "split")
+	}
+	return binds
+}
+
+// State returns the current layout, including splits passed directly as panes.
+func (s *SplitC) State() SplitState {
+	st := SplitState{Ratio: s.ratio, Hidden: s.hidden}
+	if a, ok := s.a.(*SplitC); ok {
+		as := a.State()
+		st.First = &as
+	}
+	if b, ok := s.b.(*SplitC); ok {
+		bs := b.State()
+		st.Second = &bs
+	}
+	return st
+}
+
+// SetState restores a layout saved with State. Nested states are applied to
+// splits passed directly as panes and ignored otherwise.
+func (s *SplitC) SetState(st SplitState) {
+	s.Ratio(st.Ratio)
+	s.hidden = st.Hidden
+	if a, ok := s.a.(*SplitC); ok && st.First != nil {
+		a.SetState(*st.First)
+	}
+	if b, ok := s.b.(*SplitC); ok && st.Second != nil {
+		b.SetState(*st.Second)
+	}
+}
+
+// MarshalJSON encodes the split's State.
+func (s *SplitC) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.State())
+}
+
+// UnmarshalJSON decodes a State and applies it.
+func (s *SplitC) UnmarshalJSON(b []byte) error {
+	var st SplitState
+	if err := json.Unmarshal(b, &st); err != nil {
+		return err
+	}
+	s.SetState(st)
+	return nil
+}
+
+// clamp limits the first pane's size to the minimums within space.
+func (s *SplitC) clamp(a, space int16) int16 {
+	a = min(a, space-s.minB)
+	a = max(a, s.minA)
+	return max(0, min(a, space))
+}
+
+// sizes divides avail cells into the first pane, divider and second pane.
+func (s *SplitC) sizes(avail int16) (a, div, b int16) {
+	switch s.hidden {
+	case SplitFirst:
+		return 0, 0, avail
+	case SplitSecond:
+		return avail, 0, 0
+	}
+	space := avail - 1
+	if space <= 0 {
+		return 0, max(avail, 0), 0
+	}
+	s.space = space
+	a = s.clamp(int16(math.Round(s.ratio*float64(space))), space)
+	return a, 1, space - a
+}
+
+// hides reports whether child i of the split's container op is hidden.
+// The first pane is always the first child; the divider is the only rule.
+func (s *SplitC) hides(op *Op, i int16, child *Op) bool {
+	switch {
+	case s.hidden == 0:
+		return false
+	case child.Kind == OpVRule || child.Kind == OpHRule:
+		return true
+	case i == op.ChildStart:
+		return s.hidden == SplitFirst
+	}
+	return s.hidden == SplitSecond
+}
+
+// layoutWidths sets pane and divider widths for a Horizontal split.
+func (s *SplitC) layoutWidths(t *Template, idx int16, op *Op, availW int16) {
+	a, div, b := s.sizes(availW)
+	sizes := [3]int16{a, div, b}
+	k := 0
+	for i := op.ChildStart; i < op.ChildEnd && k < 3; i++ {
+		if t.ops[i].Parent != idx {
+			continue
+		}
+		t.geom[i].W = sizes[k]
+		k++
+	}
+}
+
+// layoutHeights sets pane and divider heights and positions for a Vertical split.
+func (s *SplitC) layoutHeights(t *Template, idx int16, op *Op, availH int16) {
+	a, div, b := s.sizes(availH)
+	sizes := [3]int16{a, div, b}
+	y := op.Margin[0]
+	if op.Border.Horizontal != 0 {
+		y++
+	}
+	k := 0
+	for i := op.ChildStart; i < op.ChildEnd && k < 3; i++ {
+		if t.ops[i].Parent != idx {
+			continue
+		}
+		t.geom[i].H = sizes[k]
+		t.geom[i].LocalY = y
+		y += sizes[k]
+		k++
+	}
+}
+
+// compileSplitC compiles the split as an HBox or VBox of two panes around a
+// divider rule; the container's layout defers pane sizes to the split.
+// Dividers only merge with a border on an enclosing VBox, so a bordered
+// Horizontal split wraps its HBox in one.
+func (t *Template) compileSplitC(v *SplitC, parent int16, depth int, elemBase unsafe.Pointer, elemSize uintptr) int16 {
+	if v.dir == Vertical {
+		idx := t.compileVBoxC(VBoxC{
+			children:     []any{VBox.Grow(1)(v.a), HRule().Extend().Style(v.dividerStyle), VBox.Grow(1)(v.b)},
+			border:       v.border,
+			borderFG:     v.borderFG,
+			title:        v.title,
+			flexGrow:     v.grow,
+			flexGrowPtr:  v.flexGrowPtr,
+			flexGrowCond: v.flexGrowCond,
+			margin:       v.margin,
+		}, parent, depth, elemBase, elemSize)
+		t.ops[idx].Ext = v
+		return idx
+	}
+
+	row := HBoxC{
+		children: []any{VBox.Grow(1)(v.a), VRule().Extend().Style(v.dividerStyle), VBox.Grow(1)(v.b)},
+		flexGrow: 1,
+	}
+	if v.border.Horizontal == 0 {
+		row.flexGrow = v.grow
+		row.flexGrowPtr = v.flexGrowPtr
+		row.flexGrowCond = v.flexGrowCond
+		row.margin = v.margin
+		idx := t.compileHBoxC(row, parent, depth, elemBase, elemSize)
+		t.ops[idx].Ext = v
+		return idx
+	}
+
+	idx := t.compileVBoxC(VBoxC{
+		children:     []any{splitRow{row, v}},
+		border:       v.border,
+		borderFG:     v.borderFG,
+		title:        v.title,
+		flexGrow:     v.grow,
+		flexGrowPtr:  v.flexGrowPtr,
+		flexGrowCond: v.flexGrowCond,
+		margin:       v.margin,
+	}, parent, depth, elemBase, elemSize)
+	return idx
+}
+
+// splitRow is the inner row of a bordered Horizontal split.
+type splitRow struct {
+	row   HBoxC
+	split *SplitC
+}
@@ -0,0 +1,228 @@
+package glyph
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func renderSplit(view any, w, h int) *Buffer {
+	buf := NewBuffer(w, h)
+	Build(view).Execute(buf, int16(w), int16(h))
+	return buf
+}
+
+func dividerX(buf *Buffer, y int) int {
+	for x := 0; x < buf.Width(); x++ {
+		if buf.Get(x, y).Rune == '│' {
+			return x
+		}
+	}
+	return -1
+}
+
+func TestSplitHorizontalRatio(t *testing.T) {
+	s := Split(Horizontal, Text("left"), Text("right")).Ratio(0.3)
+	buf := renderSplit(s, 41, 5)
+
+	// 40 cells shared: 12 | 28
+	for y := 0; y < 5; y++ {
+		if x := dividerX(buf, y); x != 12 {
+			t.Fatalf("row %d: divider at %d, want 12\n%s", y, x, buf.StringTrimmed())
+		}
+	}
+	if got := buf.GetLine(0); !strings.HasPrefix(got, "left") || !strings.Contains(got, "│right") {
+		t.Errorf("row 0 = %q", got)
+	}
+}
+
+func TestSplitVerticalRatio(t *testing.T) {
+	s := Split(Vertical, Text("top"), Text("bottom")).Ratio(0.25)
+	buf := renderSplit(s, 20, 9)
+
+	// 8 rows shared: 2 above the divider, 6 below
+	if got := buf.GetLine(0); got != "top" {
+		t.Errorf("row 0 = %q", got)
+	}
+	if got := buf.Get(0, 2).Rune; got != '─' {
+		t.Errorf("divider row 2 = %q\n%s", got, buf.StringTrimmed())
+	}
+	if got := buf.GetLine(3); got != "bottom" {
+		t.Errorf("row 3 = %q", got)
+	}
+}
+
+func TestSplitMinSize(t *testing.T) {
+	s := Split(Horizontal, Text("a"), Text("b")).Ratio(0.1).MinSize(10, 5)
+	buf := renderSplit(s, 31, 2)
+	if x := dividerX(buf, 0); x != 10 {
+		t.Errorf("divider at %d, want 10 (min first pane)", x)
+	}
+
+	s.Ratio(0.95)
+	buf = renderSplit(s, 31, 2)
+	if x := dividerX(buf, 0); x != 25 {
+		t.Errorf("divider at %d, want 25 (min second pane)", x)
+	}
+}
+
+func TestSplitResizeBindings(t *testing.T) {
+	s := Split(Horizontal, Text("a"), Text("b")).Step(2).BindResize("<", ">")
+	tmpl := Build(s)
+	buf := NewBuffer(21, 2)
+	tmpl.Execute(buf, 21, 2)
+	if x := dividerX(buf, 0); x != 10 {
+		t.Fatalf("divider at %d, want 10", x)
+	}
+
+	b := s.bindings()
+	if len(b) != 2 || b[0].pattern != "<" || b[1].pattern != ">" {
+		t.Fatalf("bindings = %+v", b)
+	}
+	b[1].handler.(func())()
+	b[1].handler.(func())()
+	buf.Clear()
+	tmpl.Execute(buf, 21, 2)
+	if x := dividerX(buf, 0); x != 14 {
+		t.Errorf("after grow: divider at %d, want 14", x)
+	}
+
+	for range 10 {
+		b[0].handler.(func())()
+	}
+	buf.Clear()
+	tmpl.Execute(buf, 21, 2)
+	if x := dividerX(buf, 0); x != 0 {
+		t.Errorf("after shrink: divider at %d, want 0", x)
+	}
+}
+
+func TestSplitNamedBindings(t *testing.T) {
+	a, press := keyHelpApp()
+	a.Keymap(&Keymap{Global: map[string][]string{"split.editor.grow": {"g"}}})
+	inner := Split(Vertical, Text("code"), Text("log")).Name("editor").BindResize("-", "+")
+	outer := Split(Horizontal, Text("tree"), inner).Name("main").BindResize("<", ">")
+	a.wireBindings(Build(outer), a.router)
+
+	press("+")
+	if inner.State().Ratio != 0.5 {
+		t.Error("default key still bound after remap")
+	}
+	press("g")
+	if inner.State().Ratio <= 0.5 || outer.State().Ratio != 0.5 {
+		t.Errorf("ratios = %v / %v, want only the inner split grown", outer.State().Ratio, inner.State().Ratio)
+	}
+	press(">")
+	if outer.State().Ratio <= 0.5 {
+		t.Error("outer split didn't keep its own keys")
+	}
+
+	actions := make(map[string]string)
+	for _, k := range a.ActiveKeys() {
+		actions[k.Keys] = k.Action
+	}
+	if actions["g"] != "split.editor.grow" || actions[">"] != "split.main.grow" {
+		t.Errorf("actions = %v", actions)
+	}
+	if err := a.KeymapErr(); err != nil {
+		t.Errorf("keymap error: %v", err)
+	}
+}
+
+func TestSplitCollapseMaximize(t *testing.T) {
+	s := Split(Horizontal, Text("aaa"), Text("bbb")).
+		BindCollapse("c", SplitFirst).
+		BindMaximize("m", SplitFirst)
+	tmpl := Build(s)
+	buf := NewBuffer(20, 2)
+
+	b := s.bindings()
+	b[0].handler.(func())()
+	if s.Hidden() != SplitFirst {
+		t.Fatalf("hidden = %v, want first", s.Hidden())
+	}
+	tmpl.Execute(buf, 20, 2)
+	if got := buf.GetLine(0); got != "bbb" {
+		t.Errorf("collapsed first: row 0 = %q", got)
+	}
+
+	b[0].handler.(func())()
+	if s.Hidden() != 0 {
+		t.Fatalf("collapse toggle did not restore")
+	}
+
+	b[1].handler.(func())()
+	buf.Clear()
+	tmpl.Execute(buf, 20, 2)
+	if got := buf.GetLine(0); got != "aaa" {
+		t.Errorf("maximised first: row 0 = %q", got)
+	}
+	b[1].handler.(func())()
+	if s.Hidden() != 0 {
+		t.Errorf("maximise toggle did not restore")
+	}
+
+	// resizing brings a collapsed pane back
+	s.Collapse(SplitSecond)
+	s.Resize(1)
+	if s.Hidden() != 0 {
+		t.Errorf("Resize did not restore collapsed pane")
+	}
+}
+
+func TestSplitBorderJunctions(t *testing.T) {
+	s := Split(Horizontal, Text("a"), Text("b")).Border(BorderSingle)
+	buf := renderSplit(s, 12, 4)
+
+	// 10 inner cells, 9 shared: divider after 5 cells (+1 border)
+	if got := buf.Get(6, 0).Rune; got != '┬' {
+		t.Errorf("top junction = %q\n%s", got, buf.StringTrimmed())
+	}
+	if got := buf.Get(6, 3).Rune; got != '┴' {
+		t.Errorf("bottom junction = %q\n%s", got, buf.StringTrimmed())
+	}
+
+	nested := Split(Horizontal, Text("a"), Split(Vertical, Text("b"), Text("c"))).Border(BorderSingle)
+	buf = renderSplit(nested, 12, 7)
+	if got := buf.Get(6, 3).Rune; got != '├' {
+		t.Errorf("inner divider junction = %q\n%s", got, buf.StringTrimmed())
+	}
+}
+
+func TestSplitNestedState(t *testing.T) {
+	inner := Split(Vertical, Text("code"), Text("log")).Ratio(0.75)
+	outer := Split(Horizontal, Text("tree"), inner).Ratio(0.25)
+	inner.Collapse(SplitSecond)
+
+	data, err := json.Marshal(outer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"ratio":0.25,"second":{"ratio":0.75,"hidden":"second"}}`
+	if string(data) != want {
+		t.Fatalf("json = %s, want %s", data, want)
+	}
+
+	inner2 := Split(Vertical, Text("code"), Text("log"))
+	outer2 := Split(Horizontal, Text("tree"), inner2)
+	if err := json.Unmarshal(data, outer2); err != nil {
+		t.Fatal(err)
+	}
+	if outer2.State().Ratio != 0.25 || inner2.State().Ratio != 0.75 || inner2.Hidden() != SplitSecond {
+		t.Errorf("restored state = %+v / %+v", outer2.State(), inner2.State())
+	}
+
+	if err := json.Unmarshal([]byte(`{"ratio":0.5,"hidden":"middle"}`), outer2); err == nil {
+		t.Error("expected error for unknown pane")
+	}
+
+	// nested render: tree | code over log
+	inner.Restore()
+	buf := renderSplit(outer, 41, 9)
+	if x := dividerX(buf, 0); x != 10 {
+		t.Errorf("outer divider at %d, want 10", x)
+	}
+	if got := buf.Get(11, 6).Rune; got != '─' {
+		t.Errorf("inner divider missing at row 6:\n%s", buf.StringTrimmed())
+	}
+}
@@ -0,0 +1,435 @@
+package glyph
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/junegunn/fzf/src/util"
+	"github.com/mattn/go-runewidth"
+)
+
+// ============================================================================
+// Structured log records (JSON and logfmt)
+// ============================================================================
+
+// LogRecord is a parsed structured log line.
+type LogRecord struct {
+	Time    string
+	Level   string // normalised: trace, debug, info, warn, error, fatal
+	Message string
+	Fields  []LogField // remaining fields in source order
+}
+
+// LogField is a single key/value pair from a structured log line.
+// Non-string JSON values keep their JSON encoding.
+type LogField struct {
+	Key   string
+	Value string
+}
+
+// LogFieldMode controls how a structured Log shows a record's fields.
+type LogFieldMode uint8
+
+const (
+	FieldsInline    LogFieldMode = iota // key=value pairs after the message
+	FieldsCollapsed                     // a "+N fields" marker after the message
+	FieldsExpanded                      // one indented row per field below the message
+)
+
+// logKey is which of a record's extracted values a key names.
+type logKey uint8
+
+const (
+	keyOther logKey = iota
+	keyLevel
+	keyMessage
+	keyTime
+)
+
+// logKeys holds the lowercase key names for a record's level, message and
+// time. parsing and lookups both go through it.
+var logKeys = map[string]logKey{
+	"level": keyLevel, "lvl": keyLevel, "severity": keyLevel, "loglevel": keyLevel,
+	"msg": keyMessage, "message": keyMessage,
+	"time": keyTime, "ts": keyTime, "timestamp": keyTime, "@timestamp": keyTime, "t": keyTime,
+}
+
+// Field returns the value of the named field and whether it exists.
+// level, msg and time, and their other spellings, resolve to the record's
+// extracted values.
+func (r *LogRecord) Field(key string) (string, bool) {
+	switch logKeys[strings.ToLower(key)] {
+	case keyLevel:
+		return r.Level, r.Level != ""
+	case keyMessage:
+		return r.Message, r.Message != ""
+	case keyTime:
+		return r.Time, r.Time != ""
+	}
+	for _, f := range r.Fields {
+		if strings.EqualFold(f.Key, key) {
+			return f.Value, true
+		}
+	}
+	return "", false
+}
+
+// ParseLogRecord parses a JSON object or logfmt line. Level, time and message
+// are taken from their common key names (level/lvl/severity/loglevel,
+// time/ts/timestamp/@timestamp/t, msg/message). Returns false for lines that are neither format.
+func ParseLogRecord(line string) (LogRecord, bool) {
+	s := strings.TrimSpace(line)
+	if strings.HasPrefix(s, "{") {
+		return parseJSONRecord(s)
+	}
+	return parseLogfmtRecord(s)
+}
+
+func parseJSONRecord(s string) (LogRecord, bool) {
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return LogRecord{}, false
+	}
+	var rec LogRecord
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return LogRecord{}, false
+		}
+		key, _ := tok.(string)
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return LogRecord{}, false
+		}
+		var val string
+		if len(raw) > 0 && raw[0] == '"' {
+			json.Unmarshal(raw, &val)
+		} else {
+			var buf bytes.Buffer
+			if json.Compact(&buf, raw) == nil {
+				val = buf.String()
+			} else {
+				val = string(raw)
+			}
+		}
+		rec.set(key, val)
+	}
+	return rec, true
+}
+
+func parseLogfmtRecord(s string) (LogRecord, bool) {
+	var rec LogRecord
+	known := false
+	for i := 0; i < len(s); {
+		for i < len(s) && s[i] == ' ' {
+			i++
+		}
+		if i >= len(s) {
+			break
+		}
+		start := i
+		for i < len(s) && s[i] != '=' && s[i] != ' ' {
+			i++
+		}
+		key := s[start:i]
+		if key == "" {
+			return LogRecord{}, false
+		}
+		if i >= len(s) || s[i] != '=' {
+			// bare word: not logfmt
+			return LogRecord{}, false
+		}
+		i++ // '='
+
+		var val string
+		if i < len(s) && s[i] == '"' {
+			j := i + 1
+			for j < len(s) && s[j] != '"' {
+				if s[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(s) {
+				return LogRecord{}, false
+			}
+			uq, err := strconv.Unquote(s[i : j+1])
+			if err != nil {
+				uq = s[i+1 : j]
+			}
+			val = uq
+			i = j + 1
+		} else {
+			start := i
+			for i < len(s) && s[i] != ' ' {
+				i++
+			}
+			val = s[start:i]
+		}
+		if rec.set(key, val) {
+			known = true
+		}
+	}
+	// plain text that happens to contain "a=b" shouldn't be treated as structured
+	return rec, known
+}
+
+// set stores a key/value pair, returning true if it was a well-known key.
+func (r *LogRecord) set(key, val string) bool {
+	switch logKeys[strings.ToLower(key)] {
+	case keyLevel:
+		if r.Level == "" {
+			r.Level = normLevel(val)
+			return true
+		}
+	case keyMessage:
+		if r.Message == "" {
+			r.Message = val
+			return true
+		}
+	case keyTime:
+		if r.Time == "" {
+			r.Time = val
+			return true
+		}
+	}
+	r.Fields = append(r.Fields, LogField{Key: key, Value: val})
+	return false
+}
+
+// normLevel maps level spellings (and bunyan/pino numeric levels) onto
+// trace, debug, info, warn, error and fatal.
+func normLevel(s string) string {
+	l := strings.ToLower(strings.TrimSpace(s))
+	switch l {
+	case "10", "trc", "trace":
+		return "trace"
+	case "20", "dbg", "debug":
+		return "debug"
+	case "30", "inf", "info", "information", "notice":
+		return "info"
+	case "40", "wrn", "warn", "warning":
+		return "warn"
+	case "50", "err", "eror", "error":
+		return "error"
+	case "60", "ftl", "fatal", "panic", "crit", "critical", "alert", "emerg", "emergency":
+		return "fatal"
+	}
+	return l
+}
+
+// levelStyle returns the colour used for a normalised level.
+func levelStyle(level string) Style {
+	switch level {
+	case "trace":
+		return Style{FG: BrightBlack}
+	case "debug":
+		return Style{FG: Cyan}
+	case "info":
+		return Style{FG: Green}
+	case "warn":
+		return Style{FG: Yellow, Attr: AttrBold}
+	case "error":
+		return Style{FG: Red, Attr: AttrBold}
+	case "fatal":
+		return Style{FG: White, BG: Red, Attr: AttrBold}
+	}
+	return Style{}
+}
+
+// displayTime shortens RFC 3339 timestamps to the time of day.
+func displayTime(s string) string {
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t.Format("15:04:05.000")
+	}
+	return s
+}
+
+// logEntry is a parsed record with its rendered head (time, level, message)
+// and inline fields, cached so re-syncing the layer doesn't re-render.
+type logEntry struct {
+	rec       LogRecord
+	spans     []Span // head spans followed by inline field spans
+	head      int    // number of head spans
+	headWidth int    // display width of the head spans
+	toggled   bool   // expanded (or collapsed) against the log's field mode
+}
+
+// newLogEntry renders rec and returns the entry and its plain inline text.
+func newLogEntry(rec LogRecord) (*logEntry, string) {
+	dim := Style{FG: BrightBlack}
+	e := &logEntry{rec: rec}
+	if rec.Time != "" {
+		e.spans = append(e.spans, Span{Text: displayTime(rec.Time) + " ", Style: dim})
+	}
+	if rec.Level != "" {
+		lvl := strings.ToUpper(rec.Level)
+		if len(lvl) < 5 {
+			lvl += strings.Repeat(" ", 5-len(lvl))
+		}
+		e.spans = append(e.spans, Span{Text: lvl, Style: levelStyle(rec.Level)}, Span{Text: " "})
+	}
+	if rec.Message != "" {
+		e.spans = append(e.spans, Span{Text: rec.Message})
+	}
+	e.head = len(e.spans)
+	for _, sp := range e.spans {
+		e.headWidth += runewidth.StringWidth(sp.Text)
+	}
+	for _, f := range rec.Fields {
+		e.spans = append(e.spans,
+			Span{Text: "  " + f.Key + "=", Style: dim},
+			Span{Text: f.Value},
+		)
+	}
+
+	var sb strings.Builder
+	for _, sp := range e.spans {
+		sb.WriteString(sp.Text)
+	}
+	return e, sb.String()
+}
+
+// mode returns how the entry shows its fields when the log's mode is m. A
+// toggled entry is expanded, or collapsed if m already expands.
+func (e *logEntry) mode(m LogFieldMode) LogFieldMode {
+	switch {
+	case !e.toggled:
+		return m
+	case m == FieldsExpanded:
+		return FieldsCollapsed
+	}
+	return FieldsExpanded
+}
+
+// rows returns how many buffer rows the entry occupies in mode m.
+func (e *logEntry) rows(m LogFieldMode) int {
+	if m == FieldsExpanded {
+		return 1 + len(e.rec.Fields)
+	}
+	return 1
+}
+
+// write renders the entry at row y and returns the rows used.
+func (e *logEntry) write(buf *Buffer, y int, m LogFieldMode) int {
+	w := buf.Width()
+	switch m {
+	case FieldsCollapsed:
+		buf.WriteSpans(0, y, e.spans[:e.head], w)
+		if n := len(e.rec.Fields); n > 0 {
+			label := "  +" + strconv.Itoa(n) + " field"
+			if n > 1 {
+				label += "s"
+			}
+			buf.WriteStringFast(e.headWidth, y, label, Style{FG: BrightBlack}, w-e.headWidth)
+		}
+		return 1
+	case FieldsExpanded:
+		buf.WriteSpans(0, y, e.spans[:e.head], w)
+		for i, f := range e.rec.Fields {
+			buf.WriteSpans(0, y+1+i, []Span{
+				{Text: "    " + f.Key + ": ", Style: Style{FG: BrightBlack}},
+				{Text: f.Value},
+			}, w)
+		}
+		return 1 + len(e.rec.Fields)
+	}
+	buf.WriteSpans(0, y, e.spans, w)
+	return 1
+}
+
+// ============================================================================
+// Field-scoped log queries
+// ============================================================================
+
+// logQuery is an fzf query plus field terms such as "level:error" or
+// "!user:bob". Field terms apply to structured records and are ANDed with
+// the text query.
+type logQuery struct {
+	text   FzfQuery
+	fields []fieldTerm
+}
+
+type fieldTerm struct {
+	key, value string
+	negated    bool
+}
+
+// parseLogQuery splits raw into field terms and an fzf query. Field terms
+// are only recognised when fields is true (structured logs).
+func parseLogQuery(raw string, fields bool) logQuery {
+	if !fields {
+		return logQuery{text: ParseFzfQuery(raw)}
+	}
+	var q logQuery
+	var rest []string
+	for _, tok := range strings.Fields(raw) {
+		if ft, ok := parseFieldTerm(tok); ok {
+			q.fields = append(q.fields, ft)
+			continue
+		}
+		rest = append(rest, tok)
+	}
+	q.text = ParseFzfQuery(strings.Join(rest, " "))
+	return q
+}
+
+// parseFieldTerm recognises [!]key:value where key is an identifier.
+func parseFieldTerm(tok string) (fieldTerm, bool) {
+	var ft fieldTerm
+	if strings.HasPrefix(tok, "!") {
+		ft.negated = true
+		tok = tok[1:]
+	}
+	i := strings.IndexByte(tok, ':')
+	if i <= 0 || i == len(tok)-1 {
+		return fieldTerm{}, false
+	}
+	for j := 0; j < i; j++ {
+		c := tok[j]
+		if !(c == '_' || c == '.' || c == '-' || c == '@' ||
+			(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')) {
+			return fieldTerm{}, false
+		}
+	}
+	ft.key = tok[:i]
+	ft.value = strings.ToLower(tok[i+1:])
+	if strings.HasPrefix(ft.value, "//") {
+		return fieldTerm{}, false // a URL, not a field term
+	}
+	return ft, true
+}
+
+// Empty reports whether the query has no terms.
+func (q *logQuery) Empty() bool {
+	return q.text.Empty() && len(q.fields) == 0
+}
+
+// matches reports whether a line (and its record, if structured) passes.
+func (q *logQuery) matches(line string, rec *LogRecord, slab *util.Slab) bool {
+	for _, ft := range q.fields {
+		if ft.match(rec) == ft.negated {
+			return false
+		}
+	}
+	_, ok := q.text.score(line, slab)
+	return ok
+}
+
+func (ft fieldTerm) match(rec *LogRecord) bool {
+	if rec == nil {
+		return false
+	}
+	v, ok := rec.Field(ft.key)
+	if !ok {
+		return false
+	}
+	if logKeys[strings.ToLower(ft.key)] == keyLevel {
+		return strings.HasPrefix(v, normLevel(ft.value))
+	}
+	return strings.Contains(strings.ToLower(v), ft.value)
+}
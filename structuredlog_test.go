@@ -0,0 +1,234 @@
+package glyph
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/junegunn/fzf/src/util"
+)
+
+func TestParseLogRecordJSON(t *testing.T) {
+	rec, ok := ParseLogRecord(`{"time":"2024-05-01T12:30:45.123Z","level":"WARNING","msg":"disk almost full","pct":91.5,"host":"db-1","tags":["a","b"]}`)
+	if !ok {
+		t.Fatal("expected JSON line to parse")
+	}
+	if rec.Level != "warn" || rec.Message != "disk almost full" || rec.Time != "2024-05-01T12:30:45.123Z" {
+		t.Errorf("unexpected record: %+v", rec)
+	}
+	want := []LogField{{"pct", "91.5"}, {"host", "db-1"}, {"tags", `["a","b"]`}}
+	if len(rec.Fields) != len(want) {
+		t.Fatalf("fields = %+v", rec.Fields)
+	}
+	for i, f := range want {
+		if rec.Fields[i] != f {
+			t.Errorf("field %d = %+v, want %+v", i, rec.Fields[i], f)
+		}
+	}
+}
+
+func TestParseLogRecordNumericLevel(t *testing.T) {
+	rec, ok := ParseLogRecord(`{"level":50,"msg":"boom"}`)
+	if !ok || rec.Level != "error" {
+		t.Errorf("expected pino level 50 to map to error, got %+v", rec)
+	}
+}
+
+func TestParseLogRecordLogfmt(t *testing.T) {
+	rec, ok := ParseLogRecord(`ts=2024-05-01T12:00:00Z lvl=err msg="connection refused" addr=10.0.0.1:5432 retry=3`)
+	if !ok {
+		t.Fatal("expected logfmt line to parse")
+	}
+	if rec.Level != "error" || rec.Message != "connection refused" {
+		t.Errorf("unexpected record: %+v", rec)
+	}
+	if v, ok := rec.Field("addr"); !ok || v != "10.0.0.1:5432" {
+		t.Errorf("addr = %q", v)
+	}
+	if v, ok := rec.Field("level"); !ok || v != "error" {
+		t.Errorf("level alias = %q", v)
+	}
+
+	// every key set recognises, Field and field terms do too
+	rec, _ = ParseLogRecord(`t=12:00 loglevel=warn msg=x`)
+	if v, ok := rec.Field("loglevel"); !ok || v != "warn" {
+		t.Errorf("loglevel = %q", v)
+	}
+	if v, ok := rec.Field("t"); !ok || v != "12:00" {
+		t.Errorf("t = %q", v)
+	}
+	if q := parseLogQuery("loglevel:warning", true); !q.matches("x", &rec, util.MakeSlab(100*1024, 2048)) {
+		t.Error("loglevel:warning should match a warn record")
+	}
+}
+
+func TestParseLogRecordRejectsPlainText(t *testing.T) {
+	for _, line := range []string{
+		"plain old log line",
+		"x=1 y=2",
+		"{not json",
+		`msg="unterminated`,
+	} {
+		if _, ok := ParseLogRecord(line); ok {
+			t.Errorf("expected %q not to parse as a record", line)
+		}
+	}
+}
+
+func TestLogEntryRender(t *testing.T) {
+	rec, _ := ParseLogRecord(`{"time":"2024-05-01T12:30:45.123Z","level":"error","msg":"failed","user":"bob","code":500}`)
+	e, plain := newLogEntry(rec)
+	if plain != "12:30:45.123 ERROR failed  user=bob  code=500" {
+		t.Errorf("plain = %q", plain)
+	}
+
+	buf := NewBuffer(60, 3)
+	if n := e.write(buf, 0, FieldsInline); n != 1 {
+		t.Errorf("inline rows = %d", n)
+	}
+	if got := buf.Get(13, 0).Style.FG; got != Red {
+		t.Errorf("level colour = %+v", got)
+	}
+
+	buf = NewBuffer(60, 3)
+	e.write(buf, 0, FieldsCollapsed)
+	if got := buf.GetLine(0); got != "12:30:45.123 ERROR failed  +2 fields" {
+		t.Errorf("collapsed = %q", got)
+	}
+
+	buf = NewBuffer(60, 3)
+	if n := e.write(buf, 0, FieldsExpanded); n != 3 {
+		t.Errorf("expanded rows = %d", n)
+	}
+	if got := buf.GetLine(2); got != "    code: 500" {
+		t.Errorf("expanded field row = %q", got)
+	}
+}
+
+func TestLogQueryFieldTerms(t *testing.T) {
+	errRec, _ := ParseLogRecord(`level=error msg="db down" user=bob`)
+	infoRec, _ := ParseLogRecord(`level=info msg="db up" user=alice`)
+
+	tests := []struct {
+		query   string
+		errLine bool
+		infLine bool
+	}{
+		{"level:error", true, false},
+		{"level:err", true, false},
+		{"level:warning", false, false},
+		{"!level:error", false, true},
+		{"user:ali", false, true},
+		{"level:info db", false, true},
+		{"missing:x", false, false},
+		{"http://x", false, false}, // not a field term, fuzzy text
+	}
+	slab := util.MakeSlab(100*1024, 2048)
+	for _, tt := range tests {
+		q := parseLogQuery(tt.query, true)
+		if got := q.matches("db down", &errRec, slab); got != tt.errLine {
+			t.Errorf("%q on error line = %v, want %v", tt.query, got, tt.errLine)
+		}
+		if got := q.matches("db up", &infoRec, slab); got != tt.infLine {
+			t.Errorf("%q on info line = %v, want %v", tt.query, got, tt.infLine)
+		}
+	}
+
+	// without structured mode the whole query is fzf text
+	if q := parseLogQuery("level:error", false); len(q.fields) != 0 {
+		t.Error("field terms should only be parsed for structured logs")
+	}
+}
+
+func TestLogStructured(t *testing.T) {
+	input := `{"level":"info","msg":"started","port":8080}` + "\nnot structured\n"
+	lv := Log(strings.NewReader(input)).Structured().Fields(FieldsExpanded)
+	Build(VBox(lv))
+	time.Sleep(50 * time.Millisecond)
+
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+	buf := lv.Layer().Buffer()
+	if buf.Height() != 3 {
+		t.Fatalf("expected 3 rows (record, field, plain), got %d", buf.Height())
+	}
+	if got := buf.GetLine(0); got != "INFO  started" {
+		t.Errorf("row 0 = %q", got)
+	}
+	if got := buf.GetLine(1); got != "    port: 8080" {
+		t.Errorf("row 1 = %q", got)
+	}
+	if got := buf.GetLine(2); got != "not structured" {
+		t.Errorf("row 2 = %q", got)
+	}
+}
+
+func TestFilterLogFieldQuery(t *testing.T) {
+	input := "level=error msg=\"db down\"\nlevel=info msg=\"db up\"\nplain db line\n"
+	fl := FilterLog(strings.NewReader(input)).Structured().Fields(FieldsCollapsed)
+	Build(VBox(fl))
+	time.Sleep(50 * time.Millisecond)
+
+	fl.input.SetValue("level:error")
+	fl.updateFilter()
+	buf := fl.Layer().Buffer()
+	if buf.Height() != 1 || buf.GetLine(0) != "ERROR db down" {
+		t.Errorf("expected only the error record, got %d rows: %q", buf.Height(), buf.GetLine(0))
+	}
+
+	fl.input.SetValue("db")
+	fl.updateFilter()
+	if h := fl.Layer().Buffer().Height(); h != 3 {
+		t.Errorf("expected text query to match all 3 lines, got %d", h)
+	}
+
+	// field display toggles keep the filter applied
+	fl.log.cycleFields()
+	if fl.log.fieldMode != FieldsExpanded {
+		t.Errorf("expected cycle to expanded, got %d", fl.log.fieldMode)
+	}
+	if h := fl.Layer().Buffer().Height(); h != 3 {
+		t.Errorf("expected filter to survive a field toggle, got %d rows", h)
+	}
+}
+
+func TestLogExpandCursorRecord(t *testing.T) {
+	input := "level=info msg=a port=1\nlevel=info msg=b port=2\nlevel=info msg=c port=3\n"
+	lv := Log(strings.NewReader(input)).Structured().Fields(FieldsCollapsed)
+	Build(VBox(lv))
+	time.Sleep(50 * time.Millisecond)
+
+	lines := func() []string {
+		buf := lv.Layer().Buffer()
+		var out []string
+		for y := range buf.Height() {
+			out = append(out, strings.TrimRight(buf.GetLine(y), " "))
+		}
+		return out
+	}
+
+	// following: the newest record expands, the others stay collapsed
+	lv.toggleFields()
+	want := []string{"INFO  a  +1 field", "INFO  b  +1 field", "INFO  c", "    port: 3"}
+	if got := lines(); !slices.Equal(got, want) {
+		t.Errorf("expanded newest = %q", got)
+	}
+
+	// scrolled away: the record at the top of the view toggles
+	lv.following = false
+	lv.layer.SetViewport(40, 2)
+	lv.layer.ScrollTo(1)
+	lv.toggleFields()
+	want = []string{"INFO  a  +1 field", "INFO  b", "    port: 2", "INFO  c", "    port: 3"}
+	if got := lines(); !slices.Equal(got, want) {
+		t.Errorf("expanded top = %q", got)
+	}
+
+	// toggled records collapse again under a global expanded mode
+	lv.cycleFields()
+	want = []string{"INFO  a", "    port: 1", "INFO  b  +1 field", "INFO  c  +1 field"}
+	if got := lines(); !slices.Equal(got, want) {
+		t.Errorf("global expanded = %q", got)
+	}
+}
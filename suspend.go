@@ -0,0 +1,144 @@
+package glyph
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+
+	"github.com/kungfusheep/riffkey"
+	"golang.org/x/sys/unix"
+)
+
+// Suspend hands the terminal back to the shell and stops the app, as
+// Ctrl-Z does for other programs. When the shell resumes it (fg), the
+// terminal goes back into raw mode and the screen is redrawn in full.
+//
+// Ctrl-Z is bound to Suspend as the "suspend" action on views that don't
+// bind it themselves. Call it from a key handler. Apps from NewAppWithIO
+// have no job to stop, and Suspend does nothing.
+func (a *App) Suspend() {
+	if a.screen == nil || a.nonInteractive || a.stream != nil {
+		return
+	}
+	cont := make(chan os.Signal, 1)
+	signal.Notify(cont, syscall.SIGCONT)
+	defer signal.Stop(cont)
+
+	if err := a.releaseTerminal(); err != nil {
+		return
+	}
+	// stop the whole job with the default action, then wait to be resumed
+	signal.Reset(syscall.SIGTSTP)
+	if err := unix.Kill(0, unix.SIGTSTP); err == nil {
+		<-cont
+	}
+	if a.jobSigs != nil {
+		signal.Notify(a.jobSigs, syscall.SIGTSTP)
+	}
+	a.reclaimTerminal()
+}
+
+// Exec runs cmd in the app's terminal, e.g. $EDITOR, a pager or a shell,
+// and returns once it exits and the app is redrawn. The terminal is handed
+// over as for Suspend; Stdin, Stdout and Stderr default to the app's.
+// Call it from a key handler, so the app isn't reading input meanwhile.
+// For an app from NewAppWithIO, Stdout and Stderr default to its output
+// and Stdin to nothing, since its input is still being read.
+//
+//	app.Handle("e", func() {
+//	    cmd := exec.Command(cmp.Or(os.Getenv("EDITOR"), "vi"), path)
+//	    if err := app.Exec(cmd); err != nil {
+//	        status = err.Error()
+//	    }
+//	})
+func (a *App) Exec(cmd *exec.Cmd) error {
+	if a.stream != nil {
+		if cmd.Stdout == nil {
+			cmd.Stdout = a.screen.writer
+		}
+		if cmd.Stderr == nil {
+			cmd.Stderr = a.screen.writer
+		}
+		if err := a.releaseTerminal(); err != nil {
+			return err
+		}
+		err := cmd.Run()
+		return errors.Join(err, a.reclaimTerminal())
+	}
+	if cmd.Stdin == nil {
+		cmd.Stdin = os.Stdin
+	}
+	if cmd.Stdout == nil {
+		cmd.Stdout = os.Stdout
+	}
+	if cmd.Stderr == nil {
+		cmd.Stderr = os.Stderr
+	}
+	if a.screen == nil || a.nonInteractive {
+		return cmd.Run()
+	}
+
+	// Ctrl-C in the child's terminal reaches the app too
+	ints := make(chan os.Signal, 1)
+	signal.Notify(ints, os.Interrupt)
+	defer signal.Stop(ints)
+
+	if err := a.releaseTerminal(); err != nil {
+		return err
+	}
+	err := cmd.Run()
+	return errors.Join(err, a.reclaimTerminal())
+}
+
+// releaseTerminal stops rendering and hands the terminal back.
+func (a *App) releaseTerminal() error {
+	a.renderMu.Lock()
+	defer a.renderMu.Unlock()
+	a.released.Store(true)
+	if err := a.screen.release(a.linesUsed); err != nil {
+		a.released.Store(false)
+		return err
+	}
+	a.linesUsed = 0
+	return nil
+}
+
+// reclaimTerminal takes the terminal back and redraws in full.
+func (a *App) reclaimTerminal() error {
+	a.renderMu.Lock()
+	err := a.screen.reclaim()
+	a.released.Store(false)
+	a.redrawFull = true
+	a.renderMu.Unlock()
+	a.RequestRender()
+	return err
+}
+
+// handleJobSignals suspends the app on SIGTSTP sent from outside it.
+func (a *App) handleJobSignals(sigs <-chan os.Signal) {
+	for range sigs {
+		if a.released.Load() {
+			// a child has the terminal and is stopping: stop with it
+			unix.Kill(os.Getpid(), unix.SIGSTOP)
+			continue
+		}
+		a.Suspend()
+	}
+}
+
+// bindSuspend binds Ctrl-Z to Suspend on the app's routers that leave it
+// free.
+func (a *App) bindSuspend() {
+	a.bindSuspendOn(a.router, "")
+	for name, r := range a.viewRouters {
+		a.bindSuspendOn(r, name)
+	}
+}
+
+// bindSuspendOn binds Ctrl-Z to Suspend on r, unless r binds it already.
+func (a *App) bindSuspendOn(r *riffkey.Router, view string) {
+	a.bind(r, view, binding{pattern: "<C-z>", name: "suspend", desc: "suspend"},
+		func(_ riffkey.Match) { a.Suspend() }, true)
+}
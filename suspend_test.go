@@ -0,0 +1,122 @@
+package glyph
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/kungfusheep/riffkey"
+	"golang.org/x/sys/unix"
+)
+
+// ptyApp returns an App whose screen is a pty in raw mode, writing frames
+// to out, the pty, and a function reporting whether it is in raw mode.
+func ptyApp(t *testing.T, inline bool) (*App, *bytes.Buffer, *os.File, func() bool) {
+	t.Helper()
+	master, slave, err := openPTY()
+	if err != nil {
+		t.Skipf("no pty: %v", err)
+	}
+	t.Cleanup(func() { master.Close(); slave.Close() })
+	unix.IoctlSetWinsize(int(slave.Fd()), unix.TIOCSWINSZ, &unix.Winsize{Row: 4, Col: 20})
+
+	out := &bytes.Buffer{}
+	s := &Screen{
+		front: NewBuffer(20, 4), back: NewBuffer(20, 4),
+		writer: out, fd: int(slave.Fd()), width: 20, height: 4,
+		resizeChan: make(chan Size, 1), sigChan: make(chan os.Signal, 1),
+		lastStyle: DefaultStyle(),
+	}
+	if inline {
+		err = s.EnterInlineMode()
+	} else {
+		err = s.EnterRawMode()
+	}
+	if err != nil {
+		t.Skipf("raw mode: %v", err)
+	}
+	t.Cleanup(func() { s.ExitRawMode() })
+
+	router := riffkey.NewRouter()
+	a := &App{screen: s, router: router, input: riffkey.NewInput(router), renderChan: make(chan struct{}, 1), inline: inline}
+	a.SetView(Text("hello"))
+
+	raw := func() bool {
+		tio, err := unix.IoctlGetTermios(s.fd, ioctlGetTermios)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tio.Lflag&unix.ICANON == 0
+	}
+	return a, out, slave, raw
+}
+
+func TestReleaseAndReclaimTerminal(t *testing.T) {
+	a, out, _, raw := ptyApp(t, false)
+	a.render()
+	if !raw() || !strings.Contains(out.String(), "hello") {
+		t.Fatalf("not rendering in raw mode: %q", out)
+	}
+
+	out.Reset()
+	if err := a.releaseTerminal(); err != nil {
+		t.Fatal(err)
+	}
+	if raw() || !strings.Contains(out.String(), "\x1b[?1049l") {
+		t.Errorf("terminal not handed back: raw %v, %q", raw(), out)
+	}
+	out.Reset()
+	a.render()
+	if out.Len() != 0 {
+		t.Errorf("rendered while released: %q", out)
+	}
+
+	if err := a.reclaimTerminal(); err != nil {
+		t.Fatal(err)
+	}
+	a.render()
+	if !raw() || !strings.Contains(out.String(), "\x1b[?1049h") {
+		t.Errorf("terminal not reclaimed: raw %v, %q", raw(), out)
+	}
+	// the unchanged frame is drawn again in full
+	if !strings.Contains(out.String(), "hello") {
+		t.Errorf("no full redraw after reclaim: %q", out)
+	}
+}
+
+func TestExec(t *testing.T) {
+	for _, inline := range []bool{false, true} {
+		a, out, tty, raw := ptyApp(t, inline)
+		a.render()
+
+		// the child gets the terminal in its original mode
+		var stty bytes.Buffer
+		cmd := exec.Command("stty", "-a")
+		cmd.Stdin, cmd.Stdout = tty, &stty
+		if err := a.Exec(cmd); err != nil {
+			t.Skipf("stty: %v", err)
+		}
+		if !regexp.MustCompile(`(^|\s)icanon`).MatchString(stty.String()) {
+			t.Errorf("inline %v: child saw raw mode:\n%s", inline, stty.String())
+		}
+		if !raw() {
+			t.Errorf("inline %v: raw mode not restored", inline)
+		}
+
+		out.Reset()
+		a.render()
+		if !strings.Contains(out.String(), "hello") {
+			t.Errorf("inline %v: not redrawn after Exec: %q", inline, out)
+		}
+
+		err := a.Exec(exec.Command("sh", "-c", "exit 3"))
+		var exit *exec.ExitError
+		if !errors.As(err, &exit) || exit.ExitCode() != 3 {
+			t.Errorf("inline %v: Exec = %v", inline, err)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"reflect"
 	"strconv"
+	"slices"
 	"strings"
 	"sync/atomic"
 	"time"
@@ -35,7 +36,7 @@ type Renderer interface {
 
 // forEachCompiler is implemented by generic ForEach types to compile themselves
 type forEachCompiler interface {
-	compileTo(t *Template, parent int16, depth int) int16
+	compileTo(t *Template, parent int16, depth int, nested bool) int16
 }
 
 // listCompiler is implemented by generic List types to compile themselves
@@ -127,19 +128,22 @@ type Template struct {
 	// Declarative bindings collected during compile, wired during setup
 	pendingBindings     []binding
 	pendingTIB          *textInputBinding
-	pendingLogs         []*LogC       // Logs that need app.RequestRender wiring
-	pendingFocusManager *FocusManager // Focus manager for multi-input routing
+	pendingLogs         []*LogC        // Logs that need app.RequestRender wiring
+	pendingSearches     []*LayerSearch // Layer searches that need input stack wiring
+	pendingTerminals    []*TerminalC   // Terminals that need input stack and render wiring
+	pendingAsync        []*asyncCheck  // Async validators that need render wiring
+	pendingWizards      []*WizardC     // Wizards whose step forms need input wiring
+	pendingFocusManager *FocusManager  // Focus manager for multi-input routing
 
 	// per-frame evaluators — conditions, animations, etc. run at start of Execute
 	evals []func()
 
 	// frame timing — single timestamp per frame, shared by all animations
 	frameTime time.Time
+	clock     func() time.Time // nil for time.Now
 	animating bool
-
-	// animation ticker — runs at ~60fps only while animations are active
-	animTicker    *time.Ticker
-	requestRender func()
+	tickEvery time.Duration    // shortest frame interval a node drawn this frame wants
+	staggers  map[*stagger]int // tweens of each Stagger compiled so far
 
 	// root points to the outermost template so sub-templates (If branches,
 	// Overlays, ForEach) register evaluators where Execute actually runs them.
@@ -178,7 +182,7 @@ func (t *Template) collectTextInputBinding(node any) {
 }
 
 func (t *Template) collectFocusManager(node any) {
-	// check if InputC or FilterLogC has a manager
+	// check if a control or form has a manager
 	switch v := node.(type) {
 	case *InputC:
 		if v.manager != nil && t.pendingFocusManager == nil {
@@ -188,6 +192,14 @@ func (t *Template) collectFocusManager(node any) {
 		if v.manager != nil && t.pendingFocusManager == nil {
 			t.pendingFocusManager = v.manager
 		}
+	case *FormC:
+		if t.pendingFocusManager == nil {
+			t.pendingFocusManager = v.fm
+		}
+	case managedControl:
+		if fm := v.focusManager(); fm != nil && t.pendingFocusManager == nil {
+			t.pendingFocusManager = fm
+		}
 	}
 }
 
@@ -249,7 +261,8 @@ type OpDyn struct {
 	Gap          *int8
 	Fill         *Color
 	Opacity      *float64
-	OpacityArmed *bool // set true by render to signal From tween activation
+	OpacityArmed *bool        // set true by render to signal From tween activation
+	Effects      *nodeEffects // passes run over the container once it's drawn
 }
 
 // resolver methods — inlinable nil-check + deref, zero cost when Dyn is nil
@@ -588,247 +601,47 @@ func anyToStyle(v any) Style {
 	return Style{}
 }
 
+// SetClock sets where Execute reads the time for tweens, springs,
+// keyframes and spinners, in place of time.Now. Stepping a fake clock
+// between frames makes animation deterministic in tests.
+func (t *Template) SetClock(now func() time.Time) {
+	t.clock = now
+}
+
 // Animating returns true if any tween is currently in progress.
 // check this after Execute to determine if another frame is needed.
 func (t *Template) Animating() bool { return t.animating }
 
-// compileTween resolves a tweenNode's target to a typed pointer, allocates
-// interpolation storage, and registers a per-frame evaluator that watches the
-// target and lerps toward it. all tweens in a frame share t.frameTime.
-
-func (t *Template) compileTweenInt16(tw tweenNode) *int16 {
-	root := t.evalRoot()
-	watchPtr := t.resolveTweenTargetInt16(tw.getTarget())
-	storage := new(int16)
-	*storage = *watchPtr
-	dur := tw.getTweenDuration()
-	ease := tw.getTweenEasing()
-
-	lastTarget := *watchPtr
-	startVal := float64(*watchPtr)
-	var startTime time.Time
+// everyFrame asks tick for frames as often as the App's frame rate allows.
+const everyFrame time.Duration = 1
 
-	needsFirstFrame := false
-	if from := tw.getTweenFrom(); from != nil {
-		*storage = anyToInt16(from)
-		startVal = float64(*storage)
-		needsFirstFrame = true
+// tick asks for a frame at least every d while the node asking is drawn.
+// Call it on the root template during render.
+func (t *Template) tick(d time.Duration) {
+	if t.tickEvery == 0 || d < t.tickEvery {
+		t.tickEvery = d
 	}
-
-	root.evals = append(root.evals, func() {
-		target := *watchPtr
-		now := root.frameTime
-		if needsFirstFrame {
-			startVal = float64(*storage)
-			lastTarget = target
-			startTime = now
-			needsFirstFrame = false
-		} else if target != lastTarget {
-			startVal = float64(*storage)
-			lastTarget = target
-			startTime = now
-		}
-		if startTime.IsZero() {
-			return
-		}
-		elapsed := now.Sub(startTime)
-		if elapsed >= dur {
-			*storage = target
-			startTime = time.Time{}
-			return
-		}
-		progress := float64(elapsed) / float64(dur)
-		if ease != nil {
-			progress = ease(progress)
-		}
-		*storage = int16(startVal + progress*(float64(target)-startVal))
-		root.animating = true
-	})
-	return storage
 }
 
-func (t *Template) compileTweenFloat32(tw tweenNode) *float32 {
-	root := t.evalRoot()
-	watchPtr := t.resolveTweenTargetFloat32(tw.getTarget())
-	storage := new(float32)
-	*storage = *watchPtr
-	dur := tw.getTweenDuration()
-	ease := tw.getTweenEasing()
-
-	lastTarget := *watchPtr
-	startVal := float64(*watchPtr)
-	var startTime time.Time
-	needsFirstFrame := false
+// compileTween* resolve a tweenNode's target to a typed pointer and hand it
+// to compileTween, which animates storage toward it each frame.
 
-	if from := tw.getTweenFrom(); from != nil {
-		*storage = anyToFloat32(from)
-		startVal = float64(*storage)
-		needsFirstFrame = true
-	}
+func (t *Template) compileTweenInt16(tw tweenNode) *int16 {
+	return compileTween(t, tw, t.resolveTweenTargetInt16(tw.getTarget()), int16Values, nil)
+}
 
-	root.evals = append(root.evals, func() {
-		target := *watchPtr
-		now := root.frameTime
-		if needsFirstFrame {
-			startVal = float64(*storage)
-			lastTarget = target
-			startTime = now
-			needsFirstFrame = false
-		} else if target != lastTarget {
-			startVal = float64(*storage)
-			lastTarget = target
-			startTime = now
-		}
-		if startTime.IsZero() {
-			return
-		}
-		elapsed := now.Sub(startTime)
-		if elapsed >= dur {
-			*storage = target
-			startTime = time.Time{}
-			return
-		}
-		progress := float64(elapsed) / float64(dur)
-		if ease != nil {
-			progress = ease(progress)
-		}
-		*storage = float32(startVal + progress*(float64(target)-startVal))
-		root.animating = true
-	})
-	return storage
+func (t *Template) compileTweenFloat32(tw tweenNode) *float32 {
+	return compileTween(t, tw, t.resolveTweenTargetFloat32(tw.getTarget()), float32Values, nil)
 }
 
+// armed gates tweens of screen effects: when set, render sets *armed each
+// frame the effect is drawn, and From tweens restart each time it reappears.
 func (t *Template) compileTweenFloat64(tw tweenNode, armed *bool) *float64 {
-	root := t.evalRoot()
-	watchPtr := t.resolveTweenTargetFloat64(tw.getTarget())
-	storage := new(float64)
-	*storage = *watchPtr
-	dur := tw.getTweenDuration()
-	ease := tw.getTweenEasing()
-
-	lastTarget := *watchPtr
-	startVal := *watchPtr
-	var startTime time.Time
-	needsFirstFrame := false
-
-	var fromVal float64
-	if from := tw.getTweenFrom(); from != nil {
-		fromVal = anyToFloat64(from)
-		*storage = fromVal
-		startVal = fromVal
-		needsFirstFrame = true
-	}
-
-	// tracks whether resolve() was called last frame (effect was active)
-	wasActive := armed == nil // nil armed = always active (non-effect tweens)
-
-	root.evals = append(root.evals, func() {
-		target := *watchPtr
-		now := root.frameTime
-
-		// activation gating: From tweens in screen effects wait for resolve()
-		if armed != nil {
-			active := *armed
-			*armed = false // reset each frame; resolve() re-sets if still active
-
-			if !active {
-				wasActive = false
-				*storage = fromVal // reset so stale target doesn't flash on re-open
-				return
-			}
-
-			if !wasActive {
-				// inactive → active transition: (re)start From animation
-				wasActive = true
-				*storage = fromVal
-				startVal = fromVal
-				lastTarget = target
-				startTime = now
-				needsFirstFrame = false
-				goto interpolate
-			}
-		}
-
-		if needsFirstFrame {
-			startVal = *storage
-			lastTarget = target
-			startTime = now
-			needsFirstFrame = false
-		} else if target != lastTarget {
-			startVal = *storage
-			lastTarget = target
-			startTime = now
-		}
-
-	interpolate:
-		if startTime.IsZero() {
-			return
-		}
-		elapsed := now.Sub(startTime)
-		if elapsed >= dur {
-			*storage = target
-			startTime = time.Time{}
-			return
-		}
-		progress := float64(elapsed) / float64(dur)
-		if ease != nil {
-			progress = ease(progress)
-		}
-		*storage = startVal + progress*(target-startVal)
-		root.animating = true
-	})
-	return storage
+	return compileTween(t, tw, t.resolveTweenTargetFloat64(tw.getTarget()), float64Values, armed)
 }
 
 func (t *Template) compileTweenInt8(tw tweenNode) *int8 {
-	root := t.evalRoot()
-	watchPtr := t.resolveTweenTargetInt8(tw.getTarget())
-	storage := new(int8)
-	*storage = *watchPtr
-	dur := tw.getTweenDuration()
-	ease := tw.getTweenEasing()
-
-	lastTarget := *watchPtr
-	startVal := float64(*watchPtr)
-	var startTime time.Time
-
-	needsFirstFrame := false
-	if from := tw.getTweenFrom(); from != nil {
-		*storage = anyToInt8(from)
-		startVal = float64(*storage)
-		needsFirstFrame = true
-	}
-
-	root.evals = append(root.evals, func() {
-		target := *watchPtr
-		now := root.frameTime
-		if needsFirstFrame {
-			startVal = float64(*storage)
-			lastTarget = target
-			startTime = now
-			needsFirstFrame = false
-		} else if target != lastTarget {
-			startVal = float64(*storage)
-			lastTarget = target
-			startTime = now
-		}
-		if startTime.IsZero() {
-			return
-		}
-		elapsed := now.Sub(startTime)
-		if elapsed >= dur {
-			*storage = target
-			startTime = time.Time{}
-			return
-		}
-		progress := float64(elapsed) / float64(dur)
-		if ease != nil {
-			progress = ease(progress)
-		}
-		*storage = int8(startVal + progress*(float64(target)-startVal))
-		root.animating = true
-	})
-	return storage
+	return compileTween(t, tw, t.resolveTweenTargetInt8(tw.getTarget()), int8Values, nil)
 }
 
 // resolve tween targets — unwrap conditionNode or pointer, same as properties
@@ -882,109 +695,11 @@ func (t *Template) resolveTweenTargetInt8(target any) *int8 {
 }
 
 func (t *Template) compileTweenColor(tw tweenNode) *Color {
-	root := t.evalRoot()
-	watchPtr := t.resolveTweenTargetColor(tw.getTarget())
-	storage := new(Color)
-	*storage = *watchPtr
-	dur := tw.getTweenDuration()
-	ease := tw.getTweenEasing()
-
-	lastTarget := *watchPtr
-	startVal := *watchPtr
-	var startTime time.Time
-
-	needsFirstFrame := false
-	if from := tw.getTweenFrom(); from != nil {
-		if c, ok := from.(Color); ok {
-			*storage = c
-			startVal = c
-			needsFirstFrame = true
-		}
-	}
-
-	root.evals = append(root.evals, func() {
-		target := *watchPtr
-		now := root.frameTime
-		if needsFirstFrame {
-			startVal = *storage
-			lastTarget = target
-			startTime = now
-			needsFirstFrame = false
-		} else if target != lastTarget {
-			startVal = *storage
-			lastTarget = target
-			startTime = now
-		}
-		if startTime.IsZero() {
-			return
-		}
-		elapsed := now.Sub(startTime)
-		if elapsed >= dur {
-			*storage = target
-			startTime = time.Time{}
-			return
-		}
-		progress := float64(elapsed) / float64(dur)
-		if ease != nil {
-			progress = ease(progress)
-		}
-		*storage = lerpColor(startVal, target, progress)
-		root.animating = true
-	})
-	return storage
+	return compileTween(t, tw, t.resolveTweenTargetColor(tw.getTarget()), colorValues, nil)
 }
 
 func (t *Template) compileTweenStyle(tw tweenNode) *Style {
-	root := t.evalRoot()
-	watchPtr := t.resolveTweenTargetStyle(tw.getTarget())
-	storage := new(Style)
-	*storage = *watchPtr
-	dur := tw.getTweenDuration()
-	ease := tw.getTweenEasing()
-
-	lastTarget := *watchPtr
-	startVal := *watchPtr
-	var startTime time.Time
-
-	needsFirstFrame := false
-	if from := tw.getTweenFrom(); from != nil {
-		if s, ok := from.(Style); ok {
-			*storage = s
-			startVal = s
-			needsFirstFrame = true
-		}
-	}
-
-	root.evals = append(root.evals, func() {
-		target := *watchPtr
-		now := root.frameTime
-		if needsFirstFrame {
-			startVal = *storage
-			lastTarget = target
-			startTime = now
-			needsFirstFrame = false
-		} else if target != lastTarget {
-			startVal = *storage
-			lastTarget = target
-			startTime = now
-		}
-		if startTime.IsZero() {
-			return
-		}
-		elapsed := now.Sub(startTime)
-		if elapsed >= dur {
-			*storage = target
-			startTime = time.Time{}
-			return
-		}
-		progress := float64(elapsed) / float64(dur)
-		if ease != nil {
-			progress = ease(progress)
-		}
-		*storage = lerpStyle(startVal, target, progress)
-		root.animating = true
-	})
-	return storage
+	return compileTween(t, tw, t.resolveTweenTargetStyle(tw.getTarget()), styleValues, nil)
 }
 
 func (t *Template) resolveTweenTargetColor(target any) *Color {
@@ -1081,7 +796,8 @@ type opForEach struct {
 	iterTmpl *Template
 	slicePtr unsafe.Pointer
 	elemSize uintptr
-	geoms    []Geom // per-item geometry, reused across frames
+	geoms    []Geom             // per-item geometry, reused across frames
+	trans    *forEachTransition // rows animating in and out, nil without a Transition
 }
 
 type opSwitch struct {
@@ -1231,10 +947,24 @@ type opCounter struct {
 type opSpinner struct {
 	framePtr *int
 	frames   []string
+	interval time.Duration // per frame, when framePtr is nil
 	style    Style
 	stylePtr *Style
 }
 
+// frame returns the frame to show: *framePtr, or for a spinner that
+// animates itself the one the time is at, asking root for frames.
+func (s *opSpinner) frame(root *Template) string {
+	n := 0
+	if s.framePtr != nil {
+		n = *s.framePtr
+	} else {
+		root.tick(s.interval)
+		n = int(root.frameTime.UnixNano() / int64(s.interval))
+	}
+	return s.frames[n%len(s.frames)]
+}
+
 type opRule struct {
 	char        rune
 	style       Style
@@ -1261,6 +991,7 @@ type opScrollbar struct {
 
 type opTabs struct {
 	labels        []string
+	labelsPtr     *[]string // dynamic labels, overrides labels
 	selectedPtr   *int
 	styleType     TabsStyle
 	gap           int
@@ -1268,6 +999,14 @@ type opTabs struct {
 	inactiveStyle Style
 }
 
+// items returns the current tab labels.
+func (e *opTabs) items() []string {
+	if e.labelsPtr != nil {
+		return *e.labelsPtr
+	}
+	return e.labels
+}
+
 type opTreeView struct {
 	root          *TreeNode
 	showRoot      bool
@@ -1391,6 +1130,9 @@ const (
 	OpTextInput    // Single-line text input (data in Ext)
 	OpOverlay      // Floating overlay/modal (data in Ext)
 	OpScreenEffect // Full-screen post-processing effect (data in Ext)
+	OpTransition   // If or Switch animating between branches (data in Ext)
+	OpCanvas       // Sub-cell drawing surface (data in Ext)
+	OpChart        // Line, bar, histogram, scatter or heatmap chart (data in Ext)
 )
 
 // Build compiles a declarative UI tree into a Template ready for Execute.
@@ -1494,13 +1236,12 @@ func (t *Template) compile(node any, parent int16, depth int, elemBase unsafe.Po
 	case OverlayNode:
 		return t.compileOverlay(v, parent, depth)
 	case ScreenEffectNode:
-		for i, eff := range v.Effects {
-			if ec, ok := eff.(effectCompilable); ok {
-				v.Effects[i] = ec.compileEffect(t)
-			}
-		}
-		ext := &opScreenEffect{fns: v.Effects}
+		ext := &opScreenEffect{fns: t.compileEffects(v.Effects)}
 		return t.addOp(Op{Kind: OpScreenEffect, Parent: parent, Ext: ext}, depth)
+	case EffectNode:
+		idx := t.compile(v.Child, parent, depth, elemBase, elemSize)
+		t.compileNodeEffects(idx, v.Effects)
+		return idx
 	case Component:
 		return t.compile(v.Build(), parent, depth, elemBase, elemSize)
 
@@ -1526,9 +1267,22 @@ func (t *Template) compile(node any, parent int16, depth int, elemBase unsafe.Po
 		return t.compileCounterC(v, parent, depth)
 	case SparklineC:
 		return t.compileSparklineC(v, parent, depth)
+	case CanvasC:
+		return t.compileCanvasC(v, parent, depth)
+	case LineChartC:
+		return t.compileChart(v.spec, parent, depth)
+	case BarChartC:
+		return t.compileChart(v.spec, parent, depth)
+	case HistogramC:
+		return t.compileChart(v.spec, parent, depth)
+	case ScatterC:
+		return t.compileChart(v.spec, parent, depth)
+	case HeatmapC:
+		return t.compileChart(v.spec, parent, depth)
 	case JumpC:
 		return t.compileJumpC(v, parent, depth, elemBase, elemSize)
 	case LayerViewC:
+		t.collectBindings(v)
 		return t.compileLayerViewC(v, parent, depth)
 	case OverlayC:
 		return t.compileOverlayC(v, parent, depth)
@@ -1558,19 +1312,46 @@ func (t *Template) compile(node any, parent int16, depth int, elemBase unsafe.Po
 	case *FilterLogC:
 		t.collectFocusManager(v)
 		return t.compileFilterLogC(v, parent, depth)
+	case *TerminalC:
+		t.collectBindings(v)
+		return t.compileTerminalC(v, parent, depth)
+	case *FormC:
+		if v.onSubmitAsync != nil {
+			root := t.evalRoot()
+			root.evals = append(root.evals, v.syncStatus)
+		}
+		t.collectBindings(v)
+		t.collectTextInputBinding(v)
+		t.collectFocusManager(v)
+		return t.compile(v.toTemplate(), parent, depth, elemBase, elemSize)
+	case *WizardC:
+		root := t.evalRoot()
+		root.pendingWizards = append(root.pendingWizards, v)
+		idx := t.compile(v.toTemplate(), parent, depth, elemBase, elemSize)
+		// after the steps so the wizard's keys win on the base router
+		t.collectBindings(v)
+		return idx
+	case *SplitC:
+		t.collectBindings(v)
+		return t.compileSplitC(v, parent, depth, elemBase, elemSize)
+	case splitRow:
+		idx := t.compileHBoxC(v.row, parent, depth, elemBase, elemSize)
+		t.ops[idx].Ext = v.split
+		return idx
 	case Custom:
 		return t.compileCustom(v, parent, depth)
 	}
 
 	// Check for ForEachC[T] via interface
 	if fe, ok := node.(forEachCompiler); ok {
-		return fe.compileTo(t, parent, depth)
+		return fe.compileTo(t, parent, depth, elemBase != nil)
 	}
 
 	// Check for compound components that produce a template subtree
 	if tc, ok := node.(templateTree); ok {
 		t.collectBindings(node)
 		t.collectTextInputBinding(node)
+		t.collectFocusManager(node)
 		return t.compile(tc.toTemplate(), parent, depth, elemBase, elemSize)
 	}
 
@@ -2035,6 +1816,12 @@ func (t *Template) compileCondition(cond conditionNode, parent int16, depth int,
 		condNode: cond,
 	}
 
+	// branches inside ForEach items share one template, so can't animate apart
+	var trans *opTransition
+	if tn, ok := cond.(transitionNode); ok && tn.getTransition() != nil && elemBase == nil {
+		trans = newOpTransition(tn.getTransition(), 2)
+	}
+
 	// Compile then branch as sub-template
 	if cond.getThen() != nil {
 		thenTmpl := &Template{
@@ -2045,7 +1832,7 @@ func (t *Template) compileCondition(cond conditionNode, parent int16, depth int,
 		for i := range thenTmpl.byDepth {
 			thenTmpl.byDepth[i] = make([]int16, 0, 4)
 		}
-		thenTmpl.compile(cond.getThen(), -1, 0, elemBase, elemSize)
+		thenTmpl.compile(trans.fading(0, cond.getThen()), -1, 0, elemBase, elemSize)
 		if thenTmpl.maxDepth >= 0 {
 			thenTmpl.byDepth = thenTmpl.byDepth[:thenTmpl.maxDepth+1]
 		}
@@ -2064,7 +1851,7 @@ func (t *Template) compileCondition(cond conditionNode, parent int16, depth int,
 		for i := range elseTmpl.byDepth {
 			elseTmpl.byDepth[i] = make([]int16, 0, 4)
 		}
-		elseTmpl.compile(cond.getElse(), -1, 0, elemBase, elemSize)
+		elseTmpl.compile(trans.fading(1, cond.getElse()), -1, 0, elemBase, elemSize)
 		if elseTmpl.maxDepth >= 0 {
 			elseTmpl.byDepth = elseTmpl.byDepth[:elseTmpl.maxDepth+1]
 		}
@@ -2073,6 +1860,16 @@ func (t *Template) compileCondition(cond conditionNode, parent int16, depth int,
 		t.pendingBindings = append(t.pendingBindings, elseTmpl.pendingBindings...)
 	}
 
+	if trans != nil {
+		t.compileTransition(trans, []*Template{ext.thenTmpl, ext.elseTmpl}, func() int {
+			if ext.evalStatic() {
+				return 0
+			}
+			return 1
+		})
+		return t.addOp(Op{Kind: OpTransition, Parent: parent, Ext: trans}, depth)
+	}
+
 	return t.addOp(Op{
 		Kind:   OpIf,
 		Parent: parent,
@@ -2094,8 +1891,13 @@ func (t *Template) compileSwitch(sw switchNodeInterface, parent int16, depth int
 		node: sw,
 	}
 
-	// Compile each case branch
 	caseNodes := sw.getCaseNodes()
+	var trans *opTransition
+	if tn, ok := sw.(transitionNode); ok && tn.getTransition() != nil && elemBase == nil {
+		trans = newOpTransition(tn.getTransition(), len(caseNodes)+1)
+	}
+
+	// Compile each case branch
 	ext.cases = make([]*Template, len(caseNodes))
 	for i, caseNode := range caseNodes {
 		if caseNode != nil {
@@ -2107,7 +1909,7 @@ func (t *Template) compileSwitch(sw switchNodeInterface, parent int16, depth int
 			for j := range caseTmpl.byDepth {
 				caseTmpl.byDepth[j] = make([]int16, 0, 4)
 			}
-			caseTmpl.compile(caseNode, -1, 0, elemBase, elemSize)
+			caseTmpl.compile(trans.fading(i, caseNode), -1, 0, elemBase, elemSize)
 			if caseTmpl.maxDepth >= 0 {
 				caseTmpl.byDepth = caseTmpl.byDepth[:caseTmpl.maxDepth+1]
 			}
@@ -2127,7 +1929,7 @@ func (t *Template) compileSwitch(sw switchNodeInterface, parent int16, depth int
 		for i := range defTmpl.byDepth {
 			defTmpl.byDepth[i] = make([]int16, 0, 4)
 		}
-		defTmpl.compile(defNode, -1, 0, elemBase, elemSize)
+		defTmpl.compile(trans.fading(len(caseNodes), defNode), -1, 0, elemBase, elemSize)
 		if defTmpl.maxDepth >= 0 {
 			defTmpl.byDepth = defTmpl.byDepth[:defTmpl.maxDepth+1]
 		}
@@ -2136,6 +1938,17 @@ func (t *Template) compileSwitch(sw switchNodeInterface, parent int16, depth int
 		t.pendingBindings = append(t.pendingBindings, defTmpl.pendingBindings...)
 	}
 
+	if trans != nil {
+		branches := append(slices.Clip(ext.cases), ext.def)
+		t.compileTransition(trans, branches, func() int {
+			if i := sw.getMatchIndex(); i >= 0 {
+				return i
+			}
+			return len(ext.cases)
+		})
+		return t.addOp(Op{Kind: OpTransition, Parent: parent, Ext: trans}, depth)
+	}
+
 	return t.addOp(Op{
 		Kind:   OpSwitch,
 		Parent: parent,
@@ -2143,7 +1956,7 @@ func (t *Template) compileSwitch(sw switchNodeInterface, parent int16, depth int
 	}, depth)
 }
 
-func (t *Template) compileForEach(items any, render any, parent int16, depth int) int16 {
+func (t *Template) compileForEach(items any, render any, trans *transition, parent int16, depth int) int16 {
 	// Analyze slice
 	sliceRV := reflect.ValueOf(items)
 	if sliceRV.Kind() != reflect.Ptr {
@@ -2173,6 +1986,11 @@ func (t *Template) compileForEach(items any, render any, parent int16, depth int
 
 	// Call render to get template structure
 	templateResult := renderRV.Call([]reflect.Value{dummyElem})[0].Interface()
+	var ft *forEachTransition
+	if trans != nil {
+		ft = t.compileForEachTransition(trans, sliceType, slicePtr, elemSize)
+		templateResult = trans.fading(templateResult, &ft.opacity)
+	}
 
 	// Compile iteration template
 	iterTmpl := &Template{
@@ -2189,14 +2007,16 @@ func (t *Template) compileForEach(items any, render any, parent int16, depth int
 	}
 	iterTmpl.geom = make([]Geom, len(iterTmpl.ops))
 
+	ext := &opForEach{
+		iterTmpl: iterTmpl,
+		slicePtr: slicePtr,
+		elemSize: elemSize,
+		trans:    ft,
+	}
 	op := Op{
 		Kind:   OpForEach,
 		Parent: parent,
-		Ext: &opForEach{
-			iterTmpl: iterTmpl,
-			slicePtr: slicePtr,
-			elemSize: elemSize,
-		},
+		Ext:    ext,
 	}
 
 	return t.addOp(op, depth)
@@ -2284,6 +2104,7 @@ func (t *Template) compileVBoxC(v VBoxC, parent int16, depth int, elemBase unsaf
 		val := v.opacity.val
 		t.ops[idx].Dyn.Opacity = &val
 	}
+	t.compileNodeEffects(idx, v.effects)
 	return idx
 }
 
@@ -2365,6 +2186,7 @@ func (t *Template) compileHBoxC(v HBoxC, parent int16, depth int, elemBase unsaf
 		val := v.opacity.val
 		t.ops[idx].Dyn.Opacity = &val
 	}
+	t.compileNodeEffects(idx, v.effects)
 	return idx
 }
 
@@ -2543,7 +2365,11 @@ func (t *Template) compileSpinnerC(v SpinnerC, parent int16, depth int) int16 {
 	if frames == nil {
 		frames = SpinnerBraille
 	}
-	ext := &opSpinner{framePtr: v.frame, frames: frames, style: v.style}
+	interval := v.interval
+	if interval <= 0 {
+		interval = spinInterval
+	}
+	ext := &opSpinner{framePtr: v.frame, frames: frames, interval: interval, style: v.style}
 	ext.stylePtr = t.compileStyleDyn(v.style, v.styleDyn, v.fgDyn, v.bgDyn)
 	return t.addOp(Op{
 		Kind:   OpSpinner,
@@ -2669,6 +2495,37 @@ func (t *Template) compileSparklineC(v SparklineC, parent int16, depth int) int1
 	return idx
 }
 
+func (t *Template) compileCanvasC(v CanvasC, parent int16, depth int) int16 {
+	ext := &opCanvas{draw: v.draw, mode: v.mode, style: v.style}
+	ext.stylePtr = t.compileStyleDyn(v.style, v.styleDyn, v.fgDyn, v.bgDyn)
+	for _, p := range v.watches {
+		ext.watches = append(ext.watches, newCanvasWatch(p))
+	}
+
+	idx := t.addOp(Op{
+		Kind:   OpCanvas,
+		Parent: parent,
+		Width:  v.width,
+		Height: v.height,
+		Margin: v.style.margin,
+		Ext:    ext,
+	}, depth)
+	if v.widthPtr != nil || v.heightPtr != nil || v.widthCond != nil || v.heightCond != nil {
+		t.ops[idx].Dyn = &OpDyn{}
+		if v.widthCond != nil {
+			t.ops[idx].Dyn.Width = t.compileDynInt16(v.widthCond)
+		} else if v.widthPtr != nil {
+			t.ops[idx].Dyn.Width = v.widthPtr
+		}
+		if v.heightCond != nil {
+			t.ops[idx].Dyn.Height = t.compileDynInt16(v.heightCond)
+		} else if v.heightPtr != nil {
+			t.ops[idx].Dyn.Height = v.heightPtr
+		}
+	}
+	return idx
+}
+
 func (t *Template) compileJumpC(v JumpC, parent int16, depth int, elemBase unsafe.Pointer, elemSize uintptr) int16 {
 	ext := &opJump{onSelect: v.onSelect, style: v.style}
 	idx := t.addOp(Op{
@@ -2688,6 +2545,10 @@ func (t *Template) compileJumpC(v JumpC, parent int16, depth int, elemBase unsaf
 }
 
 func (t *Template) compileLayerViewC(v LayerViewC, parent int16, depth int) int16 {
+	if v.layer != nil && v.layer.search != nil {
+		root := t.evalRoot()
+		root.pendingSearches = append(root.pendingSearches, v.layer.search)
+	}
 	ext := &opLayer{ptr: v.layer, width: v.viewWidth, height: v.viewHeight}
 	idx := t.addOp(Op{
 		Kind:     OpLayer,
@@ -2707,6 +2568,7 @@ func (t *Template) compileLayerViewC(v LayerViewC, parent int16, depth int) int1
 		}
 		t.ops[idx].Dyn.FlexGrow = v.flexGrowPtr
 	}
+	t.compileNodeEffects(idx, v.effects)
 	return idx
 }
 
@@ -2747,6 +2609,7 @@ func (t *Template) compileOverlayC(v OverlayC, parent int16, depth int) int16 {
 func (t *Template) compileTabsC(v TabsC, parent int16, depth int) int16 {
 	ext := &opTabs{
 		labels:        v.labels,
+		labelsPtr:     v.labelsPtr,
 		selectedPtr:   v.selected,
 		styleType:     v.tabStyle,
 		gap:           int(v.gap),
@@ -3174,8 +3037,16 @@ func (t *Template) compileRadioC(v *RadioC, parent int16, depth int) int16 {
 
 func (t *Template) compileInputC(v *InputC, parent int16, depth int) int16 {
 	// Convert to TextInput and compile
-	ti := v.toTextInput()
-	idx := t.compile(ti, parent, depth, nil, 0)
+	var node any = v.toTextInput()
+	if v.async != nil && v.async.fn != nil {
+		root := t.evalRoot()
+		root.pendingAsync = append(root.pendingAsync, v.async)
+		// before the If below so it sees this frame's state
+		root.evals = append(root.evals, v.async.sync)
+		// pending indicator beside the field
+		node = HBox.Gap(1)(node, If(&v.async.spinning).Then(Spinner(nil)))
+	}
+	idx := t.compile(node, parent, depth, nil, 0)
 	if v.widthCond != nil {
 		if t.ops[idx].Dyn == nil {
 			t.ops[idx].Dyn = &OpDyn{}
@@ -3197,24 +3068,19 @@ func (t *Template) Execute(buf *Buffer, screenW, screenH int16) {
 	t.pendingScreenEffects = t.pendingScreenEffects[:0]
 
 	// Phase 0: Evaluate reactive bindings (conditions, animations)
-	t.frameTime = time.Now()
+	if t.clock != nil {
+		t.frameTime = t.clock()
+	} else {
+		t.frameTime = time.Now()
+	}
 	t.animating = false
-	for _, eval := range t.evals {
-		eval()
+	// by index, so evals added along the way (a ForEach row starting to
+	// move) run this frame too
+	for i := 0; i < len(t.evals); i++ {
+		t.evals[i]()
 	}
 
-	// manage animation ticker — start at ~60fps when animating, stop when settled
-	if t.animating && t.animTicker == nil && t.requestRender != nil {
-		t.animTicker = time.NewTicker(16 * time.Millisecond)
-		go func() {
-			for range t.animTicker.C {
-				t.requestRender()
-			}
-		}()
-	} else if !t.animating && t.animTicker != nil {
-		t.animTicker.Stop()
-		t.animTicker = nil
-	}
+	t.tickEvery = 0
 
 	// Phase 1: Width distribution (top → down)
 	t.distributeWidths(screenW, nil)
@@ -3379,6 +3245,12 @@ func (t *Template) setOpWidth(op *Op, geom *Geom, availW int16, elemBase unsafe.
 			}
 		}
 
+	case OpCanvas, OpChart:
+		geom.W = op.width()
+		if geom.W == 0 {
+			geom.W = availW
+		}
+
 	case OpHRule:
 		geom.W = 0 // fill available
 
@@ -3405,8 +3277,9 @@ func (t *Template) setOpWidth(op *Op, geom *Geom, availW int16, elemBase unsafe.
 
 	case OpTabs:
 		ext := op.Ext.(*opTabs)
+		labels := ext.items()
 		totalW := 0
-		for i, label := range ext.labels {
+		for i, label := range labels {
 			labelW := utf8.RuneCountInString(label)
 			switch ext.styleType {
 			case TabsStyleBox:
@@ -3415,7 +3288,7 @@ func (t *Template) setOpWidth(op *Op, geom *Geom, availW int16, elemBase unsafe.
 				labelW += 2
 			}
 			totalW += labelW
-			if i < len(ext.labels)-1 {
+			if i < len(labels)-1 {
 				totalW += ext.gap
 			}
 		}
@@ -3506,6 +3379,9 @@ func (t *Template) setOpWidth(op *Op, geom *Geom, availW int16, elemBase unsafe.
 			geom.W = 0
 		}
 
+	case OpTransition:
+		geom.W = op.Ext.(*opTransition).width(availW)
+
 	case OpContainer:
 		if w := op.width(); w > 0 {
 			geom.W = w
@@ -3570,6 +3446,13 @@ func (t *Template) getIfContentOp(childOp *Op, elemBase unsafe.Pointer) *Op {
 
 // distributeHBoxChildWidths sets widths for children of a HBox using two-pass flex.
 func (t *Template) distributeHBoxChildWidths(idx int16, op *Op, availW int16, elemBase unsafe.Pointer) {
+	// Split panes take their widths from the split ratio, not from flex
+	if sp, ok := op.Ext.(*SplitC); ok {
+		sp.layoutWidths(t, idx, op, availW)
+		t.annotateHRuleExtensions(idx, op, availW)
+		return
+	}
+
 	// Pass 1: Set widths for non-flex children, collect flex children
 	// Containers without explicit width/flex are treated as implicit flex (share remaining space)
 	// OpIf is transparent - we look at its content's properties
@@ -3605,8 +3488,8 @@ func (t *Template) distributeHBoxChildWidths(idx int16, op *Op, availW int16, el
 			totalFlex += fg
 			flexChildren = append(flexChildren, i)
 			flexGrowValues = append(flexGrowValues, fg)
-		} else if !effectiveOp.ContentSized && (effectiveOp.Kind == OpContainer || effectiveOp.Kind == OpJump) && effectiveOp.width() == 0 && effectiveOp.percentWidth() == 0 {
-			// Container/Jump without explicit width or fixed-content children - implicit flex
+		} else if !effectiveOp.ContentSized && (effectiveOp.Kind == OpContainer || effectiveOp.Kind == OpJump || effectiveOp.Kind == OpChart) && effectiveOp.width() == 0 && effectiveOp.percentWidth() == 0 {
+			// Container/Jump/Chart without explicit width or fixed-content children - implicit flex
 			implicitFlexChildren = append(implicitFlexChildren, i)
 		} else {
 			// Non-flex child with explicit or content-based width
@@ -3968,12 +3851,22 @@ func (t *Template) layout(_ int16) {
 					geom.H = 1
 				}
 
-			case OpSparkline:
+			case OpSparkline, OpCanvas:
 				geom.H = op.height()
 				if geom.H <= 0 {
 					geom.H = 1
 				}
 
+			case OpChart:
+				geom.H = op.height()
+				if geom.H <= 0 {
+					if op.flexGrow() > 0 {
+						geom.H = 1
+					} else {
+						geom.H = chartDefaultHeight
+					}
+				}
+
 			case OpHRule:
 				geom.H = 1
 
@@ -4127,6 +4020,9 @@ func (t *Template) layout(_ int16) {
 					geom.H = switchTmpl.Height()
 				}
 
+			case OpTransition:
+				geom.H = op.Ext.(*opTransition).layout(geom.W)
+
 			case OpLayout:
 				t.layoutCustom(idx, op, geom)
 
@@ -4468,8 +4364,8 @@ func (t *Template) stretchRowChildren(idx int16, op *Op) {
 		}
 		childGeom := &t.geom[i]
 
-		// Stretch containers, layers, and VRule to fill height (unless they have explicit height)
-		if childOp.Kind == OpContainer || childOp.Kind == OpLayer || childOp.Kind == OpVRule {
+		// Stretch containers, layers, charts and VRule to fill height (unless they have explicit height)
+		if childOp.Kind == OpContainer || childOp.Kind == OpLayer || childOp.Kind == OpChart || childOp.Kind == OpVRule {
 			if childOp.height() == 0 && childGeom.H < availH {
 				childGeom.H = availH
 			}
@@ -4544,6 +4440,16 @@ func (t *Template) distributeFlexInCol(idx int16, op *Op, rootH int16) {
 		}
 	}
 
+	// Split panes take their heights from the split ratio, not from flex
+	if sp, ok := op.Ext.(*SplitC); ok {
+		sp.layoutHeights(t, idx, op, availH)
+		geom.H = availH
+		if op.Border.Horizontal != 0 {
+			geom.H += 2
+		}
+		return
+	}
+
 	// Calculate used height and total flex grow (reuse scratch slices)
 	var usedH int16
 	var totalFlex float32
@@ -4561,7 +4467,7 @@ func (t *Template) distributeFlexInCol(idx int16, op *Op, rootH int16) {
 		childGeom := &t.geom[i]
 
 		// Check for direct flex child (container, layer or spacer)
-		if fg := childOp.flexGrow(); (childOp.Kind == OpContainer || childOp.Kind == OpLayer || childOp.Kind == OpSpacer) && fg > 0 {
+		if fg := childOp.flexGrow(); (childOp.Kind == OpContainer || childOp.Kind == OpLayer || childOp.Kind == OpSpacer || childOp.Kind == OpChart) && fg > 0 {
 			totalFlex += fg
 			flexChildren = append(flexChildren, i)
 			flexGrowValues = append(flexGrowValues, fg)
@@ -4759,6 +4665,9 @@ func (t *Template) layoutForEach(_ int16, op *Op, availW int16) (totalH, maxW in
 	if feExt.iterTmpl == nil || feExt.slicePtr == nil {
 		return 0, 0
 	}
+	if feExt.trans != nil {
+		return t.layoutForEachTransition(feExt, availW)
+	}
 
 	sliceHdr := *(*sliceHeader)(feExt.slicePtr)
 	if sliceHdr.Len == 0 {
@@ -4994,6 +4903,12 @@ func (t *Template) renderOp(buf *Buffer, idx int16, globalX, globalY, maxW int16
 	case OpSparkline:
 		op.Ext.(*opSparkline).render(t, buf, absX, absY, contentW, geom.H)
 
+	case OpCanvas:
+		op.Ext.(*opCanvas).render(t, buf, absX, absY, contentW, contentH)
+
+	case OpChart:
+		op.Ext.(*opChart).render(t, buf, absX, absY, contentW, contentH)
+
 	case OpHRule:
 		ext := op.Ext.(*opRule)
 		width := int(maxW)
@@ -5094,9 +5009,8 @@ func (t *Template) renderOp(buf *Buffer, idx int16, globalX, globalY, maxW int16
 
 	case OpSpinner:
 		ext := op.Ext.(*opSpinner)
-		if ext.framePtr != nil && len(ext.frames) > 0 {
-			frameIdx := *ext.framePtr % len(ext.frames)
-			frame := ext.frames[frameIdx]
+		if len(ext.frames) > 0 {
+			frame := ext.frame(t.evalRoot())
 			baseStyle := ext.style
 			if ext.stylePtr != nil {
 				baseStyle = *ext.stylePtr
@@ -5268,11 +5182,15 @@ func (t *Template) renderOp(buf *Buffer, idx int16, globalX, globalY, maxW int16
 
 		// Render children with this container's position as their origin
 		// children's LocalX/Y already include margin+border offsets from layoutContainer
+		split, _ := op.Ext.(*SplitC)
 		for i := op.ChildStart; i < op.ChildEnd; i++ {
 			childOp := &t.ops[i]
 			if childOp.Parent != idx {
 				continue
 			}
+			if split != nil && split.hides(op, i, childOp) {
+				continue
+			}
 			t.renderOp(buf, i, absX, absY, contentW)
 		}
 
@@ -5313,40 +5231,25 @@ func (t *Template) renderOp(buf *Buffer, idx int16, globalX, globalY, maxW int16
 		ifExt := op.Ext.(*opIf)
 		condTrue := ifExt.evalStatic()
 		if ifExt.thenTmpl != nil && condTrue {
-			ifExt.thenTmpl.app = t.app
-			ifExt.thenTmpl.inheritedStyle = t.inheritedStyle // propagate inherited style
-			ifExt.thenTmpl.inheritedFill = t.inheritedFill   // propagate inherited fill
-			ifExt.thenTmpl.clipMaxY = t.clipMaxY             // propagate vertical clip
-			ifExt.thenTmpl.elemBase = t.elemBase             // propagate for offset-based text inside branch templates
-			ifExt.thenTmpl.pendingOverlays = ifExt.thenTmpl.pendingOverlays[:0]
-			ifExt.thenTmpl.pendingScreenEffects = ifExt.thenTmpl.pendingScreenEffects[:0]
-			ifExt.thenTmpl.render(buf, absX, absY, geom.W)
-			t.pendingOverlays = append(t.pendingOverlays, ifExt.thenTmpl.pendingOverlays...)
-			t.pendingScreenEffects = append(t.pendingScreenEffects, ifExt.thenTmpl.pendingScreenEffects...)
+			t.renderBranch(buf, ifExt.thenTmpl, absX, absY, geom.W, t.clipMaxY)
 		} else if ifExt.elseTmpl != nil && !condTrue {
-			ifExt.elseTmpl.app = t.app
-			ifExt.elseTmpl.inheritedStyle = t.inheritedStyle // propagate inherited style
-			ifExt.elseTmpl.inheritedFill = t.inheritedFill   // propagate inherited fill
-			ifExt.elseTmpl.clipMaxY = t.clipMaxY             // propagate vertical clip
-			ifExt.elseTmpl.elemBase = t.elemBase             // propagate for offset-based text inside branch templates
-			ifExt.elseTmpl.pendingOverlays = ifExt.elseTmpl.pendingOverlays[:0]
-			ifExt.elseTmpl.pendingScreenEffects = ifExt.elseTmpl.pendingScreenEffects[:0]
-			ifExt.elseTmpl.render(buf, absX, absY, geom.W)
-			t.pendingOverlays = append(t.pendingOverlays, ifExt.elseTmpl.pendingOverlays...)
-			t.pendingScreenEffects = append(t.pendingScreenEffects, ifExt.elseTmpl.pendingScreenEffects...)
+			t.renderBranch(buf, ifExt.elseTmpl, absX, absY, geom.W, t.clipMaxY)
 		}
 
+	case OpTransition:
+		t.renderTransition(buf, op.Ext.(*opTransition), absX, absY, geom.W, geom.H)
+
 	case OpForEach:
 		// Render each item using iterGeoms for positioning
 		feExt := op.Ext.(*opForEach)
 		if feExt.iterTmpl == nil || feExt.slicePtr == nil {
-			return
+			break
 		}
-		sliceHdr := *(*sliceHeader)(feExt.slicePtr)
-		if sliceHdr.Len == 0 {
-			return
+		if feExt.trans != nil {
+			t.renderForEachTransition(buf, feExt, absX, absY)
+			break
 		}
-
+		sliceHdr := *(*sliceHeader)(feExt.slicePtr)
 		for i := 0; i < sliceHdr.Len && i < len(feExt.geoms); i++ {
 			itemGeom := &feExt.geoms[i]
 			itemAbsX := absX + itemGeom.LocalX
@@ -5374,6 +5277,12 @@ func (t *Template) renderOp(buf *Buffer, idx int16, globalX, globalY, maxW int16
 			tmpl.render(buf, absX, absY, geom.W)
 		}
 	}
+
+	// effects on this node run over what it drew, before anything drawn
+	// later such as overlays
+	if op.Dyn != nil && op.Dyn.Effects != nil {
+		t.applyNodeEffects(buf, op, absX, absY, contentW, contentH)
+	}
 }
 
 // renderSubTemplate renders a sub-template (for ForEach) with element-bound data.
@@ -5505,6 +5414,12 @@ func (sub *Template) renderSubOp(buf *Buffer, idx int16, globalX, globalY, maxW
 	case OpSparkline:
 		op.Ext.(*opSparkline).render(sub, buf, absX, absY, contentW, geom.H)
 
+	case OpCanvas:
+		op.Ext.(*opCanvas).render(sub, buf, absX, absY, contentW, contentH)
+
+	case OpChart:
+		op.Ext.(*opChart).render(sub, buf, absX, absY, contentW, contentH)
+
 	case OpHRule:
 		ext := op.Ext.(*opRule)
 		width := int(maxW)
@@ -5550,9 +5465,8 @@ func (sub *Template) renderSubOp(buf *Buffer, idx int16, globalX, globalY, maxW
 
 	case OpSpinner:
 		ext := op.Ext.(*opSpinner)
-		if ext.framePtr != nil && len(ext.frames) > 0 {
-			frameIdx := *ext.framePtr % len(ext.frames)
-			frame := ext.frames[frameIdx]
+		if len(ext.frames) > 0 {
+			frame := ext.frame(sub.evalRoot())
 			spinBaseStyle := ext.style
 			if ext.stylePtr != nil {
 				spinBaseStyle = *ext.stylePtr
@@ -5707,14 +5621,45 @@ func (sub *Template) renderSubOp(buf *Buffer, idx int16, globalX, globalY, maxW
 
 		// Recurse into children with this container's position as their origin
 		// children's LocalX/Y already include margin+border offsets
+		split, _ := op.Ext.(*SplitC)
 		for i := op.ChildStart; i < op.ChildEnd; i++ {
 			childOp := &sub.ops[i]
 			if childOp.Parent != idx {
 				continue
 			}
+			if split != nil && split.hides(op, i, childOp) {
+				continue
+			}
 			sub.renderSubOp(buf, i, absX, absY, contentW, elemBase)
 		}
 
+		// apply opacity: lerp all cells toward fill/BG
+		if op.Dyn != nil && op.Dyn.Opacity != nil {
+			if op.Dyn.OpacityArmed != nil {
+				*op.Dyn.OpacityArmed = true
+			}
+			opacity := *op.Dyn.Opacity
+			if opacity < 1.0 {
+				fade := 1.0 - opacity
+				bg := fillColor
+				if bg.Mode == ColorDefault {
+					bg = Color{Mode: ColorRGB} // fade toward black if no fill
+				}
+				for y := int(boxY); y < int(boxY+boxH); y++ {
+					for x := int(boxX); x < int(boxX+boxW); x++ {
+						c := buf.Get(x, y)
+						if c.Style.FG.Mode != ColorDefault {
+							c.Style.FG = LerpColor(c.Style.FG, bg, fade)
+						}
+						if c.Style.BG.Mode != ColorDefault {
+							c.Style.BG = LerpColor(c.Style.BG, bg, fade)
+						}
+						buf.Set(x, y, c)
+					}
+				}
+			}
+		}
+
 		// Restore inherited style and fill
 		sub.inheritedStyle = oldInheritedStyle
 		sub.inheritedFill = oldInheritedFill
@@ -5761,6 +5706,10 @@ func (sub *Template) renderSubOp(buf *Buffer, idx int16, globalX, globalY, maxW
 			sub.renderSubTemplate(buf, tmpl, absX, absY, geom.W, elemBase)
 		}
 	}
+
+	if op.Dyn != nil && op.Dyn.Effects != nil {
+		sub.applyNodeEffects(buf, op, absX, absY, contentW, contentH)
+	}
 }
 
 // renderSelectionList renders a selection list with marker and windowing.
@@ -6293,7 +6242,7 @@ func (t *Template) renderTabs(buf *Buffer, op *Op, geom *Geom, absX, absY int16)
 	x := int(absX)
 	y := int(absY)
 
-	for i, label := range ext.labels {
+	for i, label := range ext.items() {
 		isSelected := i == selectedIdx
 		style := t.effectiveStyle(ext.inactiveStyle)
 		if isSelected {
@@ -6816,6 +6765,7 @@ func opKindName(k OpKind) string {
 		OpHRule: "HRule", OpVRule: "VRule", OpSpacer: "Spacer",
 		OpSpinner: "Spinner", OpScrollbar: "Scrollbar", OpTabs: "Tabs", OpTreeView: "TreeView",
 		OpJump: "Jump", OpTextInput: "TextInput", OpOverlay: "Overlay", OpScreenEffect: "ScreenEffect",
+		OpTransition: "Transition", OpCanvas: "Canvas", OpChart: "Chart",
 	}
 	if name, ok := names[k]; ok {
 		return name
@@ -5276,6 +5276,199 @@ func TestAnimate(t *testing.T) {
 	})
 }
 
+// fakeClock is a frame clock tests step by hand.
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time       { return c.now }
+func (c *fakeClock) Step(d time.Duration) { c.now = c.now.Add(d) }
+func newFakeClock(tmpl *Template) *fakeClock {
+	c := &fakeClock{now: time.Unix(0, 0)}
+	tmpl.SetClock(c.Now)
+	return c
+}
+
+func TestAnimateSpring(t *testing.T) {
+	t.Run("keeps velocity when retargeted", func(t *testing.T) {
+		target := int16(5)
+		tmpl := Build(VBox(VBox.Height(Animate.Spring(170, 20)(&target))(Text("A"))))
+		clock := newFakeClock(tmpl)
+		buf := NewBuffer(10, 200)
+		tmpl.Execute(buf, 10, 200)
+
+		target = 100
+		tmpl.Execute(buf, 10, 200)
+		for range 5 {
+			clock.Step(16 * time.Millisecond)
+			tmpl.Execute(buf, 10, 200)
+		}
+		mid := tmpl.geom[1].H
+		if mid <= 5 || mid >= 100 {
+			t.Fatalf("mid-flight height = %d", mid)
+		}
+
+		// a curve would turn back at once; the spring carries on up first
+		target = 5
+		clock.Step(16 * time.Millisecond)
+		tmpl.Execute(buf, 10, 200)
+		if h := tmpl.geom[1].H; h <= mid {
+			t.Errorf("after retarget height = %d, want above %d", h, mid)
+		}
+
+		for range 200 {
+			clock.Step(16 * time.Millisecond)
+			tmpl.Execute(buf, 10, 200)
+		}
+		if h := tmpl.geom[1].H; h != 5 || tmpl.Animating() {
+			t.Errorf("settled height = %d, animating %v", h, tmpl.Animating())
+		}
+	})
+
+	t.Run("underdamped overshoots", func(t *testing.T) {
+		target := int16(10)
+		tmpl := Build(VBox.Height(Animate.Spring(300, 4)(&target))(Text("A")))
+		clock := newFakeClock(tmpl)
+		buf := NewBuffer(10, 200)
+		tmpl.Execute(buf, 10, 200)
+
+		target = 100
+		var peak int16
+		for range 100 {
+			clock.Step(16 * time.Millisecond)
+			tmpl.Execute(buf, 10, 200)
+			peak = max(peak, tmpl.geom[0].H)
+		}
+		if peak <= 100 {
+			t.Errorf("peak = %d, want past 100", peak)
+		}
+	})
+
+	t.Run("color settles on target", func(t *testing.T) {
+		fg := RGB(0, 0, 0)
+		tmpl := Build(VBox(Text("hi").FG(Animate.Spring(170, 26)(&fg))))
+		clock := newFakeClock(tmpl)
+		buf := NewBuffer(10, 1)
+		tmpl.Execute(buf, 10, 1)
+
+		fg = RGB(200, 100, 50)
+		clock.Step(50 * time.Millisecond)
+		tmpl.Execute(buf, 10, 1)
+		clock.Step(50 * time.Millisecond)
+		tmpl.Execute(buf, 10, 1)
+		if c := buf.Get(0, 0).Style.FG; c.R == 0 || c.R >= 200 {
+			t.Errorf("mid-flight FG = %+v", c)
+		}
+		for range 100 {
+			clock.Step(16 * time.Millisecond)
+			tmpl.Execute(buf, 10, 1)
+		}
+		if c := buf.Get(0, 0).Style.FG; c != fg {
+			t.Errorf("settled FG = %+v, want %+v", c, fg)
+		}
+	})
+}
+
+func TestAnimateKeyframes(t *testing.T) {
+	tmpl := Build(VBox.Height(Animate.Duration(100*time.Millisecond).Keyframes(2, 22, 12))(Text("A")))
+	clock := newFakeClock(tmpl)
+	buf := NewBuffer(10, 30)
+
+	for i, want := range []int16{2, 12, 22, 17, 12} {
+		tmpl.Execute(buf, 10, 30)
+		if h := tmpl.geom[0].H; h != want {
+			t.Errorf("at %dms height = %d, want %d", i*25, h, want)
+		}
+		clock.Step(25 * time.Millisecond)
+	}
+	if tmpl.Animating() {
+		t.Error("still animating after the last keyframe")
+	}
+}
+
+func TestTimeline(t *testing.T) {
+	t.Run("then waits for the step before", func(t *testing.T) {
+		tl := NewTimeline()
+		first := tl.Then(Animate.Duration(100 * time.Millisecond).From(int16(2)))
+		second := tl.Then(Animate.Duration(100 * time.Millisecond).From(int16(2)))
+		with := tl.With(Animate.Duration(50 * time.Millisecond).From(int16(2)))
+		tmpl := Build(VBox(
+			VBox.Height(first(int16(12)))(Text("a")),
+			VBox.Height(second(int16(12)))(Text("b")),
+			VBox.Height(with(int16(12)))(Text("c")),
+		))
+		clock := newFakeClock(tmpl)
+		buf := NewBuffer(10, 40)
+
+		heights := func() [3]int16 { return [3]int16{tmpl.geom[1].H, tmpl.geom[3].H, tmpl.geom[5].H} }
+		for _, want := range [][3]int16{{2, 2, 2}, {7, 2, 2}, {12, 2, 2}, {12, 7, 12}, {12, 12, 12}} {
+			tmpl.Execute(buf, 10, 40)
+			if got := heights(); got != want {
+				t.Errorf("at %v heights = %v, want %v", clock.now.Sub(time.Unix(0, 0)), got, want)
+			}
+			clock.Step(50 * time.Millisecond)
+		}
+		if !tl.Done() {
+			t.Error("timeline not done")
+		}
+	})
+
+	t.Run("rebuild starts over", func(t *testing.T) {
+		tl := NewTimeline()
+		first := tl.Then(Animate.Duration(100 * time.Millisecond).From(int16(2)))
+		second := tl.Then(Animate.Duration(100 * time.Millisecond).From(int16(2)))
+		view := func() any {
+			return VBox(
+				VBox.Height(first(int16(12)))(Text("a")),
+				VBox.Height(second(int16(12)))(Text("b")),
+			)
+		}
+		old := Build(view())
+		newFakeClock(old)
+		old.Execute(NewBuffer(10, 40), 10, 40)
+
+		// the old template's tweens never finish, and mustn't hold this one up
+		tmpl := Build(view())
+		clock := newFakeClock(tmpl)
+		buf := NewBuffer(10, 40)
+		heights := func() [2]int16 { return [2]int16{tmpl.geom[1].H, tmpl.geom[3].H} }
+		for _, want := range [][2]int16{{2, 2}, {7, 2}, {12, 2}, {12, 7}, {12, 12}} {
+			tmpl.Execute(buf, 10, 40)
+			if got := heights(); got != want {
+				t.Errorf("at %v heights = %v, want %v", clock.now.Sub(time.Unix(0, 0)), got, want)
+			}
+			clock.Step(50 * time.Millisecond)
+		}
+		if !tl.Done() {
+			t.Error("timeline not done")
+		}
+	})
+
+	t.Run("stagger", func(t *testing.T) {
+		in := Animate.Duration(100 * time.Millisecond).From(int16(2)).Stagger(50 * time.Millisecond)
+		view := func() any {
+			return VBox(
+				VBox.Height(in(int16(12)))(Text("a")),
+				VBox.Height(in(int16(12)))(Text("b")),
+				VBox.Height(in(int16(12)))(Text("c")),
+			)
+		}
+		// each build counts its own tweens, so a rebuild staggers the same
+		for range 2 {
+			tmpl := Build(view())
+			clock := newFakeClock(tmpl)
+			buf := NewBuffer(10, 40)
+
+			heights := func() [3]int16 { return [3]int16{tmpl.geom[1].H, tmpl.geom[3].H, tmpl.geom[5].H} }
+			for _, want := range [][3]int16{{2, 2, 2}, {7, 2, 2}, {12, 7, 2}, {12, 12, 7}, {12, 12, 12}} {
+				tmpl.Execute(buf, 10, 40)
+				if got := heights(); got != want {
+					t.Errorf("at %v heights = %v, want %v", clock.now.Sub(time.Unix(0, 0)), got, want)
+				}
+				clock.Step(50 * time.Millisecond)
+			}
+		}
+	})
+}
+
 func TestOpacity(t *testing.T) {
 	t.Run("static opacity", func(t *testing.T) {
 		tmpl := Build(
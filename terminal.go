@@ -0,0 +1,563 @@
+package glyph
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/kungfusheep/riffkey"
+	"golang.org/x/sys/unix"
+)
+
+// TerminalC hosts a child process in a pseudo-terminal and renders its
+// screen through a Layer. The process starts on first layout, sized to the
+// component's viewport, and the PTY is resized whenever the layout changes.
+//
+// Keys reach the child only while the terminal is focused: BindFocus (or
+// Focus) pushes a router that forwards every key, and the release key
+// (default Ctrl-G) hands input back to the app.
+//
+//	term := Terminal(exec.Command("htop")).
+//		BindFocus("<C-t>").
+//		OnExit(func(err error) { app.Stop() })
+type TerminalC struct {
+	cmd *exec.Cmd
+
+	// layout
+	grow         float32
+	margin       [4]int16
+	flexGrowPtr  *float32
+	flexGrowCond conditionNode
+
+	declaredBindings []binding
+	releaseKey       string
+	onExit           func(error)
+
+	// wired by the app
+	push   func(*riffkey.Router)
+	pop    func()
+	render func()
+
+	layer   *Layer
+	vt      *vtScreen
+	mu      sync.Mutex
+	pty     *os.File
+	started sync.Once
+	router  *riffkey.Router
+	focused bool
+	exited  bool
+	err     error
+	done    chan struct{}
+}
+
+// errTerminalNotRunning is returned when writing to a terminal whose process
+// hasn't started yet or has exited.
+var errTerminalNotRunning = errors.New("terminal: process not running")
+
+// Terminal creates a terminal pane that runs cmd in a PTY. cmd's Stdin,
+// Stdout, Stderr and controlling terminal are set to the PTY; TERM is set
+// to xterm-256color unless cmd.Env already provides one.
+func Terminal(cmd *exec.Cmd) *TerminalC {
+	tc := &TerminalC{
+		cmd:        cmd,
+		releaseKey: "<C-g>",
+		layer:      NewLayer(),
+		vt:         newVTScreen(80, 24),
+		done:       make(chan struct{}),
+	}
+	tc.layer.AlwaysRender = true
+	tc.layer.Render = tc.sync
+	return tc
+}
+
+// Grow sets the flex grow factor so the terminal expands to fill available space.
+// Accepts float32, float64, int, or *float32 for dynamic values.
+func (tc *TerminalC) Grow(g any) *TerminalC {
+	switch val := g.(type) {
+	case float32:
+		tc.grow = val
+	case float64:
+		tc.grow = float32(val)
+	case int:
+		tc.grow = float32(val)
+	case *float32:
+		tc.flexGrowPtr = val
+	case conditionNode:
+		tc.flexGrowCond = val
+	}
+	return tc
+}
+
+// Margin sets uniform margin on all sides.
+func (tc *TerminalC) Margin(all int16) *TerminalC {
+	tc.margin = [4]int16{all, all, all, all}
+	return tc
+}
+
+// MarginVH sets vertical and horizontal margin.
+func (tc *TerminalC) MarginVH(v, h int16) *TerminalC {
+	tc.margin = [4]int16{v, h, v, h}
+	return tc
+}
+
+// MarginTRBL sets individual margins for top, right, bottom, left.
+func (tc *TerminalC) MarginTRBL(t, r, b, l int16) *TerminalC {
+	tc.margin = [4]int16{t, r, b, l}
+	return tc
+}
+
+// Layer returns the layer the terminal screen is rendered into.
+func (tc *TerminalC) Layer() *Layer { return tc.layer }
+
+// ReleaseKey sets the key that returns input to the app while focused.
+// Default is "<C-g>".
+func (tc *TerminalC) ReleaseKey(key string) *TerminalC {
+	tc.releaseKey = key
+	return tc
+}
+
+// BindFocus registers key to focus the terminal.
+func (tc *TerminalC) BindFocus(key string) *TerminalC {
+	tc.declaredBindings = append(tc.declaredBindings, binding{pattern: key, handler: tc.Focus, name: "terminal.focus", desc: "focus terminal", group: "Terminal"})
+	return tc
+}
+
+// OnExit registers a callback for when the process exits. err is the
+// result of cmd.Wait, or the start error if the process never ran.
+// Called from a background goroutine.
+func (tc *TerminalC) OnExit(fn func(err error)) *TerminalC {
+	tc.onExit = fn
+	return tc
+}
+
+func (tc *TerminalC) bindings() []binding { return tc.declaredBindings }
+
+// Done is closed once the process has exited.
+func (tc *TerminalC) Done() <-chan struct{} { return tc.done }
+
+// Err returns the process's exit error once Done is closed.
+func (tc *TerminalC) Err() error {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	return tc.err
+}
+
+// Title returns the window title last set by the child (OSC 0 or 2).
+func (tc *TerminalC) Title() string {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	return tc.vt.title
+}
+
+// Focused reports whether keys are being forwarded to the child.
+func (tc *TerminalC) Focused() bool {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	return tc.focused
+}
+
+// Focus starts forwarding keys to the child until the release key is
+// pressed. Once the process has exited, the next key releases focus.
+func (tc *TerminalC) Focus() {
+	tc.mu.Lock()
+	if tc.focused {
+		tc.mu.Unlock()
+		return
+	}
+	tc.focused = true
+	tc.mu.Unlock()
+
+	if tc.push == nil {
+		return
+	}
+	if tc.router == nil {
+		tc.router = riffkey.NewRouter().NoCounts()
+		tc.router.Handle(tc.releaseKey, func(_ riffkey.Match) { tc.Blur() })
+		tc.router.HandleUnmatched(func(k riffkey.Key) bool {
+			if tc.Exited() {
+				tc.Blur()
+				return true
+			}
+			tc.SendKey(k)
+			return true
+		})
+	}
+	tc.push(tc.router)
+	tc.requestRender()
+}
+
+// Blur stops forwarding keys and returns input to the app.
+func (tc *TerminalC) Blur() {
+	tc.mu.Lock()
+	if !tc.focused {
+		tc.mu.Unlock()
+		return
+	}
+	tc.focused = false
+	tc.mu.Unlock()
+
+	if tc.pop != nil && tc.router != nil {
+		tc.pop()
+	}
+	tc.requestRender()
+}
+
+// Exited reports whether the process has exited.
+func (tc *TerminalC) Exited() bool {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	return tc.exited
+}
+
+// Write sends raw input to the child, as if typed.
+func (tc *TerminalC) Write(p []byte) (int, error) {
+	tc.mu.Lock()
+	pty, exited := tc.pty, tc.exited
+	tc.mu.Unlock()
+	if pty == nil || exited {
+		return 0, errTerminalNotRunning
+	}
+	return pty.Write(p)
+}
+
+// SendKey encodes k the way an xterm would and sends it to the child.
+func (tc *TerminalC) SendKey(k riffkey.Key) {
+	tc.mu.Lock()
+	appCursor, paste := tc.vt.appCursor, tc.vt.bracketedPaste
+	tc.mu.Unlock()
+	if b := encodeKey(k, appCursor, paste); len(b) > 0 {
+		tc.Write(b)
+	}
+}
+
+// Close kills the process and releases the PTY.
+func (tc *TerminalC) Close() error {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	if tc.cmd.Process != nil && !tc.exited {
+		tc.cmd.Process.Kill()
+	}
+	if tc.pty != nil {
+		return tc.pty.Close()
+	}
+	return nil
+}
+
+func (tc *TerminalC) requestRender() {
+	if tc.render != nil {
+		tc.render()
+	}
+}
+
+// sync is the layer's Render hook: it starts the process on first layout,
+// follows viewport size changes and copies the screen into the layer.
+func (tc *TerminalC) sync() {
+	w, h := tc.layer.ViewportWidth(), tc.layer.ViewportHeight()
+	if w <= 0 || h <= 0 {
+		return
+	}
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	if w != tc.vt.cols || h != tc.vt.rows {
+		tc.vt.resize(w, h)
+		if tc.pty != nil {
+			setPTYSize(tc.pty, w, h)
+		}
+	}
+	tc.started.Do(func() {
+		if err := tc.start(w, h); err != nil {
+			tc.vt.Write([]byte("terminal: " + err.Error()))
+			tc.exit(err)
+			if tc.onExit != nil {
+				go tc.onExit(err)
+			}
+		}
+	})
+
+	if buf := tc.layer.Buffer(); buf == nil || buf.Width() != w || buf.Height() != h {
+		tc.layer.SetBuffer(NewBuffer(w, h))
+		tc.vt.dirty = true
+	}
+	if tc.vt.dirty {
+		tc.layer.Buffer().CopyFrom(tc.vt.grid)
+		tc.vt.dirty = false
+	}
+
+	tc.layer.SetCursor(tc.vt.cx, tc.vt.cy)
+	tc.layer.SetCursorStyle(tc.vt.cursorShape)
+	if tc.focused && tc.vt.cursorVisible && !tc.exited {
+		tc.layer.ShowCursor()
+	} else {
+		tc.layer.HideCursor()
+	}
+}
+
+// start launches the child on a new PTY. Called with mu held.
+func (tc *TerminalC) start(cols, rows int) error {
+	master, slave, err := openPTY()
+	if err != nil {
+		return err
+	}
+	defer slave.Close()
+	if err := setPTYSize(master, cols, rows); err != nil {
+		master.Close()
+		return err
+	}
+
+	cmd := tc.cmd
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = slave, slave, slave
+	cmd.Env = withTerm(cmd.Env)
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setsid = true
+	cmd.SysProcAttr.Setctty = true
+	cmd.SysProcAttr.Ctty = 0 // fd 0 in the child, which is the slave
+	if err := cmd.Start(); err != nil {
+		master.Close()
+		return err
+	}
+	tc.pty = master
+
+	output := make(chan struct{})
+	go tc.readLoop(master, output)
+	go tc.wait(output)
+	return nil
+}
+
+// readLoop feeds PTY output into the screen until the slave side closes.
+func (tc *TerminalC) readLoop(master *os.File, done chan struct{}) {
+	defer close(done)
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := master.Read(buf)
+		if n > 0 {
+			tc.mu.Lock()
+			tc.vt.Write(buf[:n])
+			replies := tc.vt.takeReplies()
+			tc.mu.Unlock()
+			if len(replies) > 0 {
+				master.Write(replies)
+			}
+			tc.requestRender()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// wait reaps the process and surfaces its exit once its output is drained.
+func (tc *TerminalC) wait(output chan struct{}) {
+	err := tc.cmd.Wait()
+	// a background grandchild may hold the PTY open; don't wait on it forever
+	select {
+	case <-output:
+	case <-time.After(250 * time.Millisecond):
+	}
+	tc.mu.Lock()
+	tc.exit(err)
+	tc.mu.Unlock()
+	if tc.onExit != nil {
+		tc.onExit(err)
+	}
+	tc.requestRender()
+}
+
+// exit records the exit error. Called with mu held.
+func (tc *TerminalC) exit(err error) {
+	tc.exited = true
+	tc.err = err
+	close(tc.done)
+}
+
+// withTerm returns env (or the process environment when nil) with
+// TERM=xterm-256color added if no TERM is set.
+func withTerm(env []string) []string {
+	if env == nil {
+		env = os.Environ()
+		for i, kv := range env {
+			if strings.HasPrefix(kv, "TERM=") {
+				env[i] = "TERM=xterm-256color"
+				return env
+			}
+		}
+	} else {
+		for _, kv := range env {
+			if strings.HasPrefix(kv, "TERM=") {
+				return env
+			}
+		}
+	}
+	return append(env, "TERM=xterm-256color")
+}
+
+// ptyControl runs fn with the file's descriptor without switching it to
+// blocking mode, so reads stay interruptible by Close.
+func ptyControl(f *os.File, fn func(fd int) error) error {
+	rc, err := f.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var ferr error
+	if err := rc.Control(func(fd uintptr) { ferr = fn(int(fd)) }); err != nil {
+		return err
+	}
+	return ferr
+}
+
+// setPTYSize sets the PTY window size; the kernel signals SIGWINCH to the child.
+func setPTYSize(f *os.File, cols, rows int) error {
+	return ptyControl(f, func(fd int) error {
+		return unix.IoctlSetWinsize(fd, unix.TIOCSWINSZ, &unix.Winsize{Col: uint16(cols), Row: uint16(rows)})
+	})
+}
+
+// encodeKey converts a key to the bytes an xterm sends for it.
+func encodeKey(k riffkey.Key, appCursor, bracketedPaste bool) []byte {
+	if k.IsPaste() {
+		if bracketedPaste {
+			return []byte("\x1b[200~" + k.Paste + "\x1b[201~")
+		}
+		return []byte(k.Paste)
+	}
+
+	var out []byte
+	if k.Mod&riffkey.ModAlt != 0 {
+		out = append(out, 0x1b)
+	}
+	ctrl := k.Mod&riffkey.ModCtrl != 0
+
+	if k.Special == riffkey.SpecialNone {
+		r := k.Rune
+		if ctrl {
+			switch {
+			case r >= 'a' && r <= 'z':
+				return append(out, byte(r-'a'+1))
+			case r >= '@' && r <= '_':
+				return append(out, byte(r-'@'))
+			case r == ' ':
+				return append(out, 0)
+			case r == '?':
+				return append(out, 0x7f)
+			}
+		}
+		if r == 0 {
+			return nil
+		}
+		return append(out, string(r)...)
+	}
+
+	// xterm modifier parameter: 1 + shift(1) + alt(2) + ctrl(4)
+	mod := 1
+	if k.Mod&riffkey.ModShift != 0 {
+		mod++
+	}
+	if k.Mod&riffkey.ModAlt != 0 {
+		mod += 2
+	}
+	if ctrl {
+		mod += 4
+	}
+	if mod > 1 {
+		out = out[:0] // modifiers are encoded in the sequence instead
+	}
+
+	cursor := func(c byte) []byte {
+		switch {
+		case mod > 1:
+			return append(out, "\x1b[1;"+strconv.Itoa(mod)+string(c)...)
+		case appCursor:
+			return append(out, 0x1b, 'O', c)
+		}
+		return append(out, 0x1b, '[', c)
+	}
+	tilde := func(n int) []byte {
+		if mod > 1 {
+			return append(out, "\x1b["+strconv.Itoa(n)+";"+strconv.Itoa(mod)+"~"...)
+		}
+		return append(out, "\x1b["+strconv.Itoa(n)+"~"...)
+	}
+
+	switch k.Special {
+	case riffkey.SpecialEscape:
+		return append(out, 0x1b)
+	case riffkey.SpecialEnter:
+		return append(out, '\r')
+	case riffkey.SpecialTab:
+		if k.Mod&riffkey.ModShift != 0 {
+			return append(out[:0], "\x1b[Z"...)
+		}
+		return append(out, '\t')
+	case riffkey.SpecialSpace:
+		if ctrl {
+			return append(out[:0], 0)
+		}
+		return append(out, ' ')
+	case riffkey.SpecialBackspace:
+		if ctrl {
+			return append(out[:0], '\b')
+		}
+		return append(out, 0x7f)
+	case riffkey.SpecialUp:
+		return cursor('A')
+	case riffkey.SpecialDown:
+		return cursor('B')
+	case riffkey.SpecialRight:
+		return cursor('C')
+	case riffkey.SpecialLeft:
+		return cursor('D')
+	case riffkey.SpecialHome:
+		return cursor('H')
+	case riffkey.SpecialEnd:
+		return cursor('F')
+	case riffkey.SpecialInsert:
+		return tilde(2)
+	case riffkey.SpecialDelete:
+		return tilde(3)
+	case riffkey.SpecialPageUp:
+		return tilde(5)
+	case riffkey.SpecialPageDown:
+		return tilde(6)
+	case riffkey.SpecialF1, riffkey.SpecialF2, riffkey.SpecialF3, riffkey.SpecialF4:
+		c := byte('P' + k.Special - riffkey.SpecialF1)
+		if mod > 1 {
+			return append(out, "\x1b[1;"+strconv.Itoa(mod)+string(c)...)
+		}
+		return append(out, 0x1b, 'O', c)
+	case riffkey.SpecialF5, riffkey.SpecialF6, riffkey.SpecialF7, riffkey.SpecialF8,
+		riffkey.SpecialF9, riffkey.SpecialF10, riffkey.SpecialF11, riffkey.SpecialF12:
+		return tilde(fkeyCodes[k.Special-riffkey.SpecialF5])
+	}
+	return nil
+}
+
+// fkeyCodes are the CSI ~ codes for F5 through F12.
+var fkeyCodes = [8]int{15, 17, 18, 19, 20, 21, 23, 24}
+
+// compileTerminalC compiles the terminal as a LayerView over its layer.
+func (t *Template) compileTerminalC(v *TerminalC, parent int16, depth int) int16 {
+	if v.render == nil {
+		root := t.evalRoot()
+		root.pendingTerminals = append(root.pendingTerminals, v)
+	}
+
+	var layerView LayerViewC
+	if v.flexGrowCond != nil {
+		layerView = LayerView(v.layer).Grow(v.flexGrowCond)
+	} else if v.flexGrowPtr != nil {
+		layerView = LayerView(v.layer).Grow(v.flexGrowPtr)
+	} else {
+		layerView = LayerView(v.layer).Grow(v.grow)
+	}
+	if v.margin != [4]int16{} {
+		layerView = layerView.MarginTRBL(v.margin[0], v.margin[1], v.margin[2], v.margin[3])
+	}
+	return t.compileLayerViewC(layerView, parent, depth)
+}
@@ -0,0 +1,241 @@
+package glyph
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kungfusheep/riffkey"
+)
+
+func vtLines(s *vtScreen) []string {
+	lines := make([]string, s.rows)
+	for y := range lines {
+		lines[y] = s.grid.GetLine(y)
+	}
+	return lines
+}
+
+func TestVTCursorMovementAndErase(t *testing.T) {
+	s := newVTScreen(10, 3)
+	s.Write([]byte("hello\r\nworld"))
+	s.Write([]byte("\x1b[1;3H")) // row 1, col 3
+	s.Write([]byte("X\x1b[K"))
+	s.Write([]byte("\x1b[2;2H\x1b[1K"))
+
+	if got := vtLines(s); got[0] != "heX" || got[1] != "  rld" {
+		t.Errorf("screen = %q", got)
+	}
+	if s.cx != 1 || s.cy != 1 {
+		t.Errorf("cursor = %d,%d", s.cx, s.cy)
+	}
+}
+
+func TestVTWrapAndScroll(t *testing.T) {
+	s := newVTScreen(4, 2)
+	s.Write([]byte("abcdefgh"))
+	if got := vtLines(s); got[0] != "abcd" || got[1] != "efgh" {
+		t.Errorf("wrap = %q", got)
+	}
+	// the pending wrap scrolls only when another glyph arrives
+	s.Write([]byte("i"))
+	if got := vtLines(s); got[0] != "efgh" || got[1] != "i" {
+		t.Errorf("scroll = %q", got)
+	}
+}
+
+func TestVTScrollRegion(t *testing.T) {
+	s := newVTScreen(5, 4)
+	s.Write([]byte("head\r\none\r\ntwo\r\nfoot"))
+	s.Write([]byte("\x1b[2;3r"))      // rows 2-3 scroll
+	s.Write([]byte("\x1b[3;1H\nnew")) // LF on the region's bottom row
+	want := []string{"head", "two", "new", "foot"}
+	for i, line := range vtLines(s) {
+		if line != want[i] {
+			t.Errorf("row %d = %q, want %q", i, line, want[i])
+		}
+	}
+
+	// reverse index at the region top scrolls it down
+	s.Write([]byte("\x1b[2;1H\x1bM"))
+	if got := vtLines(s); got[1] != "" || got[2] != "two" || got[3] != "foot" {
+		t.Errorf("reverse index = %q", got)
+	}
+}
+
+func TestVTInsertDeleteLines(t *testing.T) {
+	s := newVTScreen(3, 3)
+	s.Write([]byte("a\r\nb\r\nc\x1b[2;1H\x1b[L"))
+	if got := vtLines(s); got[0] != "a" || got[1] != "" || got[2] != "b" {
+		t.Errorf("insert line = %q", got)
+	}
+	s.Write([]byte("\x1b[M"))
+	if got := vtLines(s); got[1] != "b" || got[2] != "" {
+		t.Errorf("delete line = %q", got)
+	}
+	s.Write([]byte("\x1b[1;1Hxyz\x1b[1;1H\x1b[P"))
+	if got := s.grid.GetLine(0); got != "yz" {
+		t.Errorf("delete char = %q", got)
+	}
+	s.Write([]byte("\x1b[2@"))
+	if got := s.grid.GetLine(0); got != "  y" {
+		t.Errorf("insert char = %q", got)
+	}
+}
+
+func TestVTSGRAndSplitSequences(t *testing.T) {
+	s := newVTScreen(10, 1)
+	s.Write([]byte("\x1b[1;3"))
+	s.Write([]byte("1mR\x1b[0mn"))
+	if c := s.grid.Get(0, 0); c.Rune != 'R' || c.Style.FG != Red || c.Style.Attr&AttrBold == 0 {
+		t.Errorf("styled cell = %+v", c)
+	}
+	if c := s.grid.Get(1, 0); c.Style != (Style{}) {
+		t.Errorf("reset cell = %+v", c)
+	}
+
+	// UTF-8 split across writes, and a wide rune occupying two cells
+	b := []byte("é日")
+	s.Write(b[:1])
+	s.Write(b[1:3])
+	s.Write(b[3:])
+	if got := s.grid.GetLine(0); got != "Rné日" {
+		t.Errorf("line = %q", got)
+	}
+	if s.cx != 5 {
+		t.Errorf("cursor x = %d, want 5", s.cx)
+	}
+}
+
+func TestVTAlternateScreen(t *testing.T) {
+	s := newVTScreen(10, 2)
+	s.Write([]byte("shell$ "))
+	s.Write([]byte("\x1b[?1049h\x1b[Hfull"))
+	if got := s.grid.GetLine(0); got != "full" {
+		t.Errorf("alt screen = %q", got)
+	}
+	s.Write([]byte("\x1b[?1049l"))
+	if got := s.grid.GetLine(0); got != "shell$" {
+		t.Errorf("main screen not restored: %q", got)
+	}
+	if s.cx != 7 || s.cy != 0 {
+		t.Errorf("cursor not restored: %d,%d", s.cx, s.cy)
+	}
+}
+
+func TestVTRepliesAndModes(t *testing.T) {
+	s := newVTScreen(10, 5)
+	s.Write([]byte("\x1b[3;4H\x1b[6n\x1b[?1h\x1b[?25l\x1b]2;my title\x07\x1b(0q\x1b(B"))
+	if got := string(s.takeReplies()); got != "\x1b[3;4R" {
+		t.Errorf("replies = %q", got)
+	}
+	if got := s.takeReplies(); got != nil {
+		t.Errorf("replies after take = %q", got)
+	}
+	if !s.appCursor || s.cursorVisible || s.title != "my title" {
+		t.Errorf("modes: appCursor=%v cursorVisible=%v title=%q", s.appCursor, s.cursorVisible, s.title)
+	}
+	if c := s.grid.Get(3, 2); c.Rune != '─' {
+		t.Errorf("line drawing = %q", c.Rune)
+	}
+}
+
+func TestVTResizeKeepsCursorLine(t *testing.T) {
+	s := newVTScreen(10, 4)
+	s.Write([]byte("1\r\n2\r\n3\r\n4"))
+	s.resize(5, 2)
+	if got := vtLines(s); got[0] != "3" || got[1] != "4" {
+		t.Errorf("after shrink = %q", got)
+	}
+	if s.cy != 1 || s.bot != 1 {
+		t.Errorf("cursor row %d, region bottom %d", s.cy, s.bot)
+	}
+}
+
+func TestEncodeKey(t *testing.T) {
+	tests := []struct {
+		key       riffkey.Key
+		appCursor bool
+		want      string
+	}{
+		{riffkey.Key{Rune: 'x'}, false, "x"},
+		{riffkey.Key{Rune: 'c', Mod: riffkey.ModCtrl}, false, "\x03"},
+		{riffkey.Key{Rune: 'b', Mod: riffkey.ModAlt}, false, "\x1bb"},
+		{riffkey.Key{Special: riffkey.SpecialEnter}, false, "\r"},
+		{riffkey.Key{Special: riffkey.SpecialBackspace}, false, "\x7f"},
+		{riffkey.Key{Special: riffkey.SpecialUp}, false, "\x1b[A"},
+		{riffkey.Key{Special: riffkey.SpecialUp}, true, "\x1bOA"},
+		{riffkey.Key{Special: riffkey.SpecialRight, Mod: riffkey.ModCtrl}, true, "\x1b[1;5C"},
+		{riffkey.Key{Special: riffkey.SpecialTab, Mod: riffkey.ModShift}, false, "\x1b[Z"},
+		{riffkey.Key{Special: riffkey.SpecialDelete}, false, "\x1b[3~"},
+		{riffkey.Key{Special: riffkey.SpecialF1}, false, "\x1bOP"},
+		{riffkey.Key{Special: riffkey.SpecialF12}, false, "\x1b[24~"},
+	}
+	for _, tt := range tests {
+		if got := string(encodeKey(tt.key, tt.appCursor, false)); got != tt.want {
+			t.Errorf("encodeKey(%v, %v) = %q, want %q", tt.key, tt.appCursor, got, tt.want)
+		}
+	}
+	paste := riffkey.Key{Paste: "hi"}
+	if got := string(encodeKey(paste, false, true)); got != "\x1b[200~hi\x1b[201~" {
+		t.Errorf("bracketed paste = %q", got)
+	}
+}
+
+func TestTerminalRunsProcess(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	var exitErr error
+	exited := make(chan struct{})
+	term := Terminal(exec.Command("sh", "-c", `printf 'size %s\n' "$(stty size)"; read line; printf '\033[31mgot %s' "$line"`)).
+		OnExit(func(err error) { exitErr = err; close(exited) })
+	defer term.Close()
+
+	tmpl := Build(VBox(term.Grow(1)))
+	if len(tmpl.pendingTerminals) != 1 {
+		t.Fatalf("expected terminal to be collected for wiring")
+	}
+	buf := NewBuffer(30, 5)
+	tmpl.Execute(buf, 30, 5)
+
+	waitFor := func(want string) {
+		t.Helper()
+		deadline := time.Now().Add(3 * time.Second)
+		for time.Now().Before(deadline) {
+			buf.Clear()
+			tmpl.Execute(buf, 30, 5)
+			if strings.Contains(buf.String(), want) {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("timed out waiting for %q, screen:\n%s", want, buf.String())
+	}
+
+	waitFor("size 5 30")
+	if _, err := term.Write([]byte("ping\r")); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-exited:
+	case <-time.After(3 * time.Second):
+		t.Fatal("exit not surfaced")
+	}
+	waitFor("got ping")
+	if exitErr != nil {
+		t.Errorf("exit error = %v", exitErr)
+	}
+	if _, err := term.Write([]byte("x")); err == nil {
+		t.Error("expected writes after exit to fail")
+	}
+	for x := range 30 {
+		if c := buf.Get(x, 2); c.Rune == 'g' {
+			if c.Style.FG != Red {
+				t.Errorf("expected SGR colour from child, got %+v", c.Style)
+			}
+			break
+		}
+	}
+}
@@ -12,6 +12,7 @@ type TextViewC struct {
 	declaredBindings []binding
 	flexGrowPtr      *float32
 	flexGrowCond     conditionNode
+	ansi             bool
 }
 
 // TextView creates a scrollable multi-line text display with word wrapping.
@@ -62,14 +63,22 @@ func (tv *TextViewC) MarginTRBL(t, r, b, l int16) *TextViewC {
 	return tv
 }
 
+// ANSI interprets escape sequences in the content (colours, attributes,
+// carriage-return redraws) instead of showing them as raw text.
+func (tv *TextViewC) ANSI() *TextViewC {
+	tv.ansi = true
+	tv.lastWidth = 0
+	return tv
+}
+
 // Layer returns the underlying layer for external scroll wiring.
 func (tv *TextViewC) Layer() *Layer { return tv.layer }
 
 // BindScroll registers keys for line-by-line scrolling.
 func (tv *TextViewC) BindScroll(down, up string) *TextViewC {
 	tv.declaredBindings = append(tv.declaredBindings,
-		binding{pattern: down, handler: func() { tv.layer.ScrollDown(1) }},
-		binding{pattern: up, handler: func() { tv.layer.ScrollUp(1) }},
+		binding{pattern: down, handler: func() { tv.layer.ScrollDown(1) }, name: "text.scroll_down", desc: "scroll down", group: "Text"},
+		binding{pattern: up, handler: func() { tv.layer.ScrollUp(1) }, name: "text.scroll_up", desc: "scroll up", group: "Text"},
 	)
 	return tv
 }
@@ -77,12 +86,19 @@ func (tv *TextViewC) BindScroll(down, up string) *TextViewC {
 // BindPageScroll registers keys for half-page scrolling.
 func (tv *TextViewC) BindPageScroll(down, up string) *TextViewC {
 	tv.declaredBindings = append(tv.declaredBindings,
-		binding{pattern: down, handler: func() { tv.layer.HalfPageDown() }},
-		binding{pattern: up, handler: func() { tv.layer.HalfPageUp() }},
+		binding{pattern: down, handler: func() { tv.layer.HalfPageDown() }, name: "text.half_page_down", desc: "half page down", group: "Text"},
+		binding{pattern: up, handler: func() { tv.layer.HalfPageUp() }, name: "text.half_page_up", desc: "half page up", group: "Text"},
 	)
 	return tv
 }
 
+// BindSearch registers key to open an in-place search prompt, with n/N to
+// move between matches. Configure via Layer().Search().
+func (tv *TextViewC) BindSearch(key string) *TextViewC {
+	tv.declaredBindings = append(tv.declaredBindings, tv.layer.Search().bindings(key, nil)...)
+	return tv
+}
+
 func (tv *TextViewC) bindings() []binding { return tv.declaredBindings }
 
 func (tv *TextViewC) sync() {
@@ -97,6 +113,11 @@ func (tv *TextViewC) sync() {
 	tv.lastContent = c
 	tv.lastWidth = w
 
+	if tv.ansi {
+		tv.syncANSI(c, w)
+		return
+	}
+
 	lines := wrapText(c, w)
 	if len(lines) == 0 {
 		lines = []string{""}
@@ -109,6 +130,17 @@ func (tv *TextViewC) sync() {
 	tv.layer.SetBuffer(buf)
 }
 
+// syncANSI is the escape-aware variant of sync.
+func (tv *TextViewC) syncANSI(c string, w int) {
+	lines := wrapANSI(c, w)
+	h := max(len(lines), 1, tv.layer.ViewportHeight())
+	buf := NewBuffer(w, h)
+	for i, spans := range lines {
+		buf.WriteSpans(0, i, spans, w)
+	}
+	tv.layer.SetBuffer(buf)
+}
+
 func (t *Template) compileTextViewC(v *TextViewC, parent int16, depth int) int16 {
 	var layerView LayerViewC
 	if v.flexGrowCond != nil {
@@ -0,0 +1,117 @@
+package glyph
+
+import "time"
+
+// Timeline sequences tweens across properties and effects. Each Then adds
+// a step that starts once every tween of the step before has finished,
+// and With adds tweens to the last step, to run alongside it. The first
+// step starts on the first frame drawn.
+//
+//	intro := NewTimeline()
+//	fade := intro.Then(Animate.Duration(300 * time.Millisecond).From(0.0))
+//	grow := intro.With(Animate.Spring(170, 26).From(int16(0)))
+//	rows := intro.Then(Animate.Duration(150 * time.Millisecond).From(0.0).Stagger(40 * time.Millisecond))
+//
+//	VBox.Opacity(fade(1.0)).Height(grow(int16(10)))(
+//	    VBox.Opacity(rows(1.0))(Text("one")),
+//	    VBox.Opacity(rows(1.0))(Text("two")),
+//	)
+type Timeline struct {
+	steps []*timelineStep
+	start time.Time
+	root  *Template // the template whose tweens the steps track
+}
+
+type timelineStep struct {
+	tl       *Timeline
+	prev     *timelineStep
+	done     []bool // per tween compiled into the step
+	finished time.Time
+}
+
+// NewTimeline creates an empty timeline.
+func NewTimeline() *Timeline {
+	return &Timeline{}
+}
+
+// Then returns f with its tweens in a new step, after the last.
+func (tl *Timeline) Then(f AnimateFn) AnimateFn {
+	step := &timelineStep{tl: tl}
+	if n := len(tl.steps); n > 0 {
+		step.prev = tl.steps[n-1]
+	}
+	tl.steps = append(tl.steps, step)
+	return step.add(f)
+}
+
+// With returns f with its tweens in the last step, running alongside it.
+func (tl *Timeline) With(f AnimateFn) AnimateFn {
+	if len(tl.steps) == 0 {
+		return tl.Then(f)
+	}
+	return tl.steps[len(tl.steps)-1].add(f)
+}
+
+// Done reports whether every tween on the timeline has finished.
+func (tl *Timeline) Done() bool {
+	for _, s := range tl.steps {
+		for _, done := range s.done {
+			if !done {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (s *timelineStep) add(f AnimateFn) AnimateFn {
+	return func(target any) *tween {
+		tw := f(target)
+		tw.step = s
+		return tw
+	}
+}
+
+// join registers a tween root compiled with the step and returns its
+// index. compiling into another template starts the timeline over, so
+// tweens of a template that was replaced don't hold it up.
+func (s *timelineStep) join(root *Template) int {
+	if s.tl.root != root {
+		s.tl.root = root
+		s.tl.start = time.Time{}
+		for _, step := range s.tl.steps {
+			step.done, step.finished = nil, time.Time{}
+		}
+	}
+	s.done = append(s.done, false)
+	return len(s.done) - 1
+}
+
+// opensAt returns when the step started, or false if it hasn't yet.
+func (s *timelineStep) opensAt(now time.Time) (time.Time, bool) {
+	if s.prev == nil {
+		if s.tl.start.IsZero() {
+			s.tl.start = now
+		}
+		return s.tl.start, true
+	}
+	return s.prev.finishedAt(now)
+}
+
+// finishedAt returns when every tween of the step had finished, or false
+// if some haven't yet.
+func (s *timelineStep) finishedAt(now time.Time) (time.Time, bool) {
+	if !s.finished.IsZero() {
+		return s.finished, true
+	}
+	if _, ok := s.opensAt(now); !ok {
+		return time.Time{}, false
+	}
+	for _, done := range s.done {
+		if !done {
+			return time.Time{}, false
+		}
+	}
+	s.finished = now
+	return now, true
+}
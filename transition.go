@@ -0,0 +1,508 @@
+package glyph
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"time"
+	"unsafe"
+)
+
+// TransitionKind is how a subtree enters and leaves when an If, Switch or
+// ForEach with a Transition changes what it shows.
+type TransitionKind uint8
+
+const (
+	Fade      TransitionKind = iota // fades in and out, as Opacity does
+	SlideLeft                       // slides in from the right and out to the left
+	SlideDown                       // slides down into view, growing its height
+	Expand                          // is revealed from the top, growing its height
+)
+
+// transition is what .Transition configures on an If, Switch or ForEach.
+type transition struct {
+	kind     TransitionKind
+	duration time.Duration
+	ease     func(float64) float64
+}
+
+// transitionNode is implemented by nodes that take a .Transition.
+type transitionNode interface {
+	getTransition() *transition
+}
+
+// grows reports whether the kind animates height too, so what's around a
+// subtree moves smoothly rather than jumping when it comes or goes.
+func (tr *transition) grows() bool {
+	return tr.kind == SlideDown || tr.kind == Expand
+}
+
+// slot returns the rows a subtree h tall takes when a of the way in.
+func (tr *transition) slot(h int16, a float64) int16 {
+	if !tr.grows() {
+		return h
+	}
+	return int16(math.Round(float64(h) * a))
+}
+
+// fading wraps node for Fade in a VBox whose Opacity reads *o, so it fades
+// the way any container does. Other kinds are moved rather than faded, so
+// node is returned as it is.
+func (tr *transition) fading(node any, o *float64) any {
+	if tr.kind != Fade || node == nil {
+		return node
+	}
+	*o = 1
+	return VBox.Opacity(o)(node)
+}
+
+// opTransition is an If or Switch whose branches animate in and out. Each
+// branch's visibility is a tween toward 1 while it's the one shown and
+// toward 0 otherwise, so the one leaving keeps rendering until it's gone.
+type opTransition struct {
+	*transition
+	branches []*Template // nil where a branch is empty
+	active   func() int  // branch the condition picks, -1 for none
+	cur      int         // branch shown this frame
+	shown    []float64   // tween targets, 1 for the branch shown
+	amount   []*float64  // how far in each branch is
+	opacity  []float64   // each branch's Opacity, for Fade
+	heights  []int16     // full height of each visible branch this frame
+	scratch  *Buffer
+}
+
+func newOpTransition(tr *transition, n int) *opTransition {
+	return &opTransition{
+		transition: tr,
+		shown:      make([]float64, n),
+		amount:     make([]*float64, n),
+		opacity:    make([]float64, n),
+		heights:    make([]int16, n),
+	}
+}
+
+// fading wraps branch i's node to fade; see transition.fading. A nil
+// receiver, for an If or Switch without a Transition, returns node.
+func (tr *opTransition) fading(i int, node any) any {
+	if tr == nil {
+		return node
+	}
+	return tr.transition.fading(node, &tr.opacity[i])
+}
+
+// compileTransition wires the tweens for an If or Switch with a transition.
+func (t *Template) compileTransition(ext *opTransition, branches []*Template, active func() int) {
+	ext.branches = branches
+	ext.active = active
+	pick := func() {
+		ext.cur = ext.active()
+		if ext.cur >= 0 && ext.branches[ext.cur] == nil {
+			ext.cur = -1
+		}
+		clear(ext.shown)
+		if ext.cur >= 0 {
+			ext.shown[ext.cur] = 1
+		}
+	}
+	pick() // what shows at first shows without animating in
+
+	// targets move before the tweens registered below read them
+	root := t.evalRoot()
+	root.evals = append(root.evals, pick)
+	tween := Animate.Duration(ext.duration).Ease(ext.ease)
+	for i := range branches {
+		ext.amount[i] = t.compileTweenFloat64(tween(&ext.shown[i]), nil)
+	}
+}
+
+func (tr *opTransition) visible(i int) bool {
+	return tr.branches[i] != nil && (i == tr.cur || *tr.amount[i] > 0)
+}
+
+// width returns the widest visible branch given availW.
+func (tr *opTransition) width(availW int16) int16 {
+	var w int16
+	for i, b := range tr.branches {
+		if !tr.visible(i) {
+			continue
+		}
+		bw := b.computeIntrinsicWidth(0)
+		if bw <= 0 {
+			b.distributeWidths(availW, nil)
+			if len(b.geom) > 0 {
+				bw = b.geom[0].W
+			}
+		}
+		w = max(w, bw)
+	}
+	return w
+}
+
+// layout lays out the visible branches and returns the height they take:
+// the tallest for kinds that overlap them, or their slots stacked for
+// kinds that grow.
+func (tr *opTransition) layout(w int16) int16 {
+	var h int16
+	for i, b := range tr.branches {
+		tr.heights[i] = 0
+		if !tr.visible(i) {
+			continue
+		}
+		b.distributeWidths(w, nil)
+		b.layout(0)
+		tr.heights[i] = b.Height()
+		if tr.grows() {
+			h += tr.slot(tr.heights[i], *tr.amount[i])
+		} else {
+			h = max(h, tr.heights[i])
+		}
+	}
+	return h
+}
+
+// renderTransition draws the branches of an If or Switch. Fading branches
+// are drawn in place through their Opacity; moving ones through a scratch
+// buffer so they can be moved and clipped.
+func (t *Template) renderTransition(buf *Buffer, tr *opTransition, x, y, w, h int16) {
+	moving, n := false, 0
+	var tallest int16
+	for i := range tr.branches {
+		if tr.visible(i) {
+			n++
+			moving = moving || *tr.amount[i] != tr.shown[i]
+			tallest = max(tallest, tr.heights[i])
+		}
+	}
+	if !moving {
+		if tr.cur >= 0 {
+			tr.opacity[tr.cur] = 1
+			t.renderBranch(buf, tr.branches[tr.cur], x, y, w, t.clipMaxY)
+		}
+		return
+	}
+
+	if tr.kind == Fade {
+		for i, b := range tr.branches {
+			if !tr.visible(i) {
+				continue
+			}
+			a := *tr.amount[i]
+			if n > 1 {
+				a = max(0, 2*a-1) // cross-fade through the fill
+			}
+			if a <= 0 {
+				continue
+			}
+			tr.opacity[i] = a
+			t.renderBranch(buf, b, x, y, w, t.clipMaxY)
+		}
+		return
+	}
+
+	tr.scratch = scratchBuffer(tr.scratch, int(w), int(tallest))
+	slotY := y
+	for i, b := range tr.branches {
+		if !tr.visible(i) {
+			continue
+		}
+		a := *tr.amount[i]
+		slotH := h
+		if tr.grows() {
+			slotH = tr.slot(tr.heights[i], a)
+		}
+		tr.scratch.Clear()
+		t.renderBranch(tr.scratch, b, 0, 0, w, 0)
+		t.blitTransition(buf, tr.scratch, tr.transition, a, i == tr.cur, int(x), int(slotY), int(w), int(slotH), int(tr.heights[i]))
+		if tr.grows() {
+			slotY += slotH
+		}
+	}
+}
+
+// renderBranch draws an If or Switch branch, passing down what it inherits.
+func (t *Template) renderBranch(buf *Buffer, b *Template, x, y, w, clipMaxY int16) {
+	b.app = t.app
+	b.inheritedStyle = t.inheritedStyle
+	b.inheritedFill = t.inheritedFill
+	b.clipMaxY = clipMaxY
+	b.elemBase = t.elemBase // for offset-based text inside branch templates
+	b.pendingOverlays = b.pendingOverlays[:0]
+	b.pendingScreenEffects = b.pendingScreenEffects[:0]
+	b.render(buf, x, y, w)
+	t.pendingOverlays = append(t.pendingOverlays, b.pendingOverlays...)
+	t.pendingScreenEffects = append(t.pendingScreenEffects, b.pendingScreenEffects...)
+}
+
+// scratchBuffer returns buf if it's w wide and at least h tall, or a new
+// buffer that is.
+func scratchBuffer(buf *Buffer, w, h int) *Buffer {
+	if buf == nil || buf.width != w || buf.height < h {
+		return NewBuffer(w, max(h, 1))
+	}
+	return buf
+}
+
+// blitTransition copies a subtree h tall drawn into src onto buf, placed
+// for being a of the way in within the w by slotH slot at x, y. Cells the
+// subtree left empty aren't copied, so what's behind shows through.
+func (t *Template) blitTransition(buf, src *Buffer, tr *transition, a float64, entering bool, x, y, w, slotH, h int) {
+	dx, dy := 0, 0
+	switch tr.kind {
+	case SlideLeft:
+		dx = int(math.Round((1 - a) * float64(w)))
+		if !entering {
+			dx = -dx
+		}
+	case SlideDown:
+		dy = slotH - h
+	}
+	maxY := min(y+slotH, buf.height)
+	if t.clipMaxY > 0 {
+		maxY = min(maxY, int(t.clipMaxY))
+	}
+	empty := EmptyCell()
+	for sy := range min(h, src.height) {
+		ty := y + dy + sy
+		if ty < max(y, 0) {
+			continue
+		}
+		if ty >= maxY {
+			break
+		}
+		for sx := range min(w, src.width) {
+			tx := x + dx + sx
+			if tx < max(x, 0) || tx >= min(x+w, buf.width) {
+				continue
+			}
+			if c := src.cells[sy*src.width+sx]; !c.Equal(empty) {
+				buf.Set(tx, ty, c)
+			}
+		}
+	}
+}
+
+// forEachTransition tracks the rows of a ForEach as items come and go.
+// Items are told apart by their bytes: when the count changes, rows are
+// matched from the front and back and what's between them has left or
+// arrived. When the count stays the same items are taken as edited in
+// place.
+type forEachTransition struct {
+	*transition
+	tmpl      *Template // where row tweens are compiled
+	tween     AnimateFn
+	sliceType reflect.Type
+	prev      reflect.Value // the items last frame, to diff against
+	rows      []transitionRow
+	next      []transitionRow
+	idle      [2][]*rowTween // tweens no row is using, by where they settled
+	opacity   float64        // Opacity of the row being drawn, for Fade
+	primed    bool
+	scratch   *Buffer
+}
+
+// rowTween is a tween a row moves in or out by. Once the row settles it's
+// kept for the next row to move, so tweens are compiled only as many as
+// move at once.
+type rowTween struct {
+	shown  float64  // tween target, 1 arriving and 0 leaving
+	amount *float64 // how far in the row is
+}
+
+type transitionRow struct {
+	index int            // in the slice, -1 once the item has left
+	elem  unsafe.Pointer // the item, in the slice or in gone
+	gone  reflect.Value  // copy of an item that's left, while it animates out
+	tw    *rowTween      // nil once settled
+	h     int16          // full height this frame
+}
+
+// compileForEachTransition sets up the row tracking for a ForEach with a
+// transition. The diff runs as an eval, so rows start moving before the
+// tweens they move by are evaluated.
+func (t *Template) compileForEachTransition(tr *transition, sliceType reflect.Type, slicePtr unsafe.Pointer, elemSize uintptr) *forEachTransition {
+	ft := &forEachTransition{
+		transition: tr,
+		tmpl:       t,
+		tween:      Animate.Duration(tr.duration).Ease(tr.ease),
+		sliceType:  sliceType,
+	}
+	root := t.evalRoot()
+	root.evals = append(root.evals, func() { ft.update(slicePtr, elemSize) })
+	return ft
+}
+
+// move returns a tween going from from to shown, reusing one settled at
+// from if there is one. A new tween's eval is added after this one's, so
+// it still runs this frame.
+func (ft *forEachTransition) move(from, shown float64) *rowTween {
+	idle := &ft.idle[int(from)]
+	if n := len(*idle); n > 0 {
+		rt := (*idle)[n-1]
+		*idle = (*idle)[:n-1]
+		rt.shown = shown
+		return rt
+	}
+	rt := &rowTween{shown: from}
+	rt.amount = ft.tmpl.compileTweenFloat64(ft.tween(&rt.shown), nil)
+	rt.shown = shown
+	return rt
+}
+
+// update diffs the slice against last frame, starting rows moving in and
+// out, and points rows at their items.
+func (ft *forEachTransition) update(slicePtr unsafe.Pointer, elemSize uintptr) {
+	items := reflect.NewAt(ft.sliceType, slicePtr).Elem()
+	n := items.Len()
+	if !ft.primed {
+		ft.primed = true
+		for i := range n {
+			ft.rows = append(ft.rows, transitionRow{index: i})
+		}
+	} else if ft.prev.Len() != n {
+		ft.diff(items, elemSize)
+	}
+
+	if !ft.prev.IsValid() || ft.prev.Cap() < n {
+		ft.prev = reflect.MakeSlice(ft.sliceType, n, n)
+	}
+	ft.prev = ft.prev.Slice(0, n)
+	reflect.Copy(ft.prev, items)
+
+	for i := range ft.rows {
+		if r := &ft.rows[i]; r.index >= 0 {
+			r.elem = unsafe.Add(items.UnsafePointer(), uintptr(r.index)*elemSize)
+		}
+	}
+}
+
+// diff turns rows of items no longer in the slice into rows moving out,
+// and adds rows moving in for items new to it.
+func (ft *forEachTransition) diff(items reflect.Value, elemSize uintptr) {
+	old, n := ft.prev.Len(), items.Len()
+	item := func(v reflect.Value, i int) []byte {
+		return unsafe.Slice((*byte)(unsafe.Add(v.UnsafePointer(), uintptr(i)*elemSize)), elemSize)
+	}
+	same := func(i, j int) bool { return bytes.Equal(item(ft.prev, i), item(items, j)) }
+	front := 0
+	for front < old && front < n && same(front, front) {
+		front++
+	}
+	back := 0
+	for back < old-front && back < n-front && same(old-1-back, n-1-back) {
+		back++
+	}
+
+	next, arrived := ft.next[:0], false
+	arrive := func() {
+		for i := front; i < n-back; i++ {
+			next = append(next, transitionRow{index: i, tw: ft.move(0, 1)})
+		}
+		arrived = true
+	}
+	for _, r := range ft.rows {
+		switch {
+		case r.index < 0 || r.index < front:
+		case r.index >= old-back:
+			if !arrived {
+				arrive()
+			}
+			r.index += n - old
+		default:
+			r.gone = reflect.New(ft.sliceType.Elem())
+			r.gone.Elem().Set(ft.prev.Index(r.index))
+			r.elem = r.gone.UnsafePointer()
+			r.index = -1
+			if r.tw == nil {
+				r.tw = ft.move(1, 0)
+			} else {
+				r.tw.shown = 0 // turns back from wherever it's got to
+			}
+		}
+		next = append(next, r)
+	}
+	if !arrived {
+		arrive()
+	}
+	ft.rows, ft.next = next, ft.rows[:0]
+}
+
+// settle lets go of the tweens of rows that have got where they were
+// going, and of the rows that have left. It returns whether any row is
+// still moving.
+func (ft *forEachTransition) settle() bool {
+	moving := false
+	rows := ft.rows[:0]
+	for _, r := range ft.rows {
+		if r.tw != nil && *r.tw.amount == r.tw.shown {
+			ft.idle[int(r.tw.shown)] = append(ft.idle[int(r.tw.shown)], r.tw)
+			r.tw = nil
+			if r.index < 0 {
+				continue // gone
+			}
+		}
+		moving = moving || r.tw != nil
+		rows = append(rows, r)
+	}
+	ft.rows = rows
+	return moving
+}
+
+// layoutForEachTransition lays out a ForEach with a transition, rows
+// moving in and out included.
+func (t *Template) layoutForEachTransition(feExt *opForEach, availW int16) (totalH, maxW int16) {
+	ft := feExt.trans
+	if ft.settle() {
+		t.evalRoot().animating = true
+	}
+
+	if cap(feExt.geoms) < len(ft.rows) {
+		feExt.geoms = make([]Geom, len(ft.rows))
+	}
+	feExt.geoms = feExt.geoms[:len(ft.rows)]
+
+	cursor := int16(0)
+	for i := range ft.rows {
+		r := &ft.rows[i]
+		feExt.iterTmpl.elemBase = r.elem
+		feExt.iterTmpl.distributeWidths(availW, r.elem)
+		feExt.iterTmpl.layout(0)
+		r.h = feExt.iterTmpl.Height()
+		h := r.h
+		if r.tw != nil {
+			h = ft.slot(r.h, *r.tw.amount)
+		}
+		feExt.geoms[i] = Geom{LocalY: cursor, W: availW, H: h}
+		cursor += h
+		maxW = availW
+	}
+	return cursor, maxW
+}
+
+// renderForEachTransition draws the rows of a ForEach with a transition.
+// Fading rows are drawn in place through their Opacity, moving ones
+// through a scratch buffer.
+func (t *Template) renderForEachTransition(buf *Buffer, feExt *opForEach, absX, absY int16) {
+	ft := feExt.trans
+	for i := 0; i < len(ft.rows) && i < len(feExt.geoms); i++ {
+		r := &ft.rows[i]
+		g := &feExt.geoms[i]
+		x, y := absX+g.LocalX, absY+g.LocalY
+		if r.tw == nil || ft.kind == Fade {
+			ft.opacity = 1
+			if r.tw != nil {
+				ft.opacity = *r.tw.amount
+			}
+			if ft.opacity > 0 {
+				t.renderSubTemplate(buf, feExt.iterTmpl, x, y, g.W, r.elem)
+			}
+			continue
+		}
+		ft.scratch = scratchBuffer(ft.scratch, int(g.W), int(r.h))
+		ft.scratch.Clear()
+		clipMaxY := t.clipMaxY
+		t.clipMaxY = 0
+		t.renderSubTemplate(ft.scratch, feExt.iterTmpl, 0, 0, g.W, r.elem)
+		t.clipMaxY = clipMaxY
+		t.blitTransition(buf, ft.scratch, ft.transition, *r.tw.amount, r.index >= 0, int(x), int(y), int(g.W), int(g.H), int(r.h))
+	}
+}
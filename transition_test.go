@@ -0,0 +1,201 @@
+package glyph
+
+import (
+	"testing"
+	"time"
+)
+
+// frame clears buf and renders a frame of tmpl into it.
+func frame(tmpl *Template, buf *Buffer) string {
+	buf.Clear()
+	w, h := buf.Size()
+	tmpl.Execute(buf, int16(w), int16(h))
+	return buf.StringTrimmed()
+}
+
+func TestTransitionExpand(t *testing.T) {
+	show := false
+	tmpl := Build(VBox(
+		Text("top"),
+		If(&show).Then(VBox(Text("a"), Text("b"))).Transition(Expand, 30*time.Millisecond, nil),
+		Text("end"),
+	))
+	buf := NewBuffer(10, 5)
+	if got := frame(tmpl, buf); got != "top\nend" {
+		t.Fatalf("hidden = %q", got)
+	}
+
+	show = true
+	if got := frame(tmpl, buf); got != "top\nend" || !tmpl.Animating() {
+		t.Errorf("first frame in = %q, animating %v", got, tmpl.Animating())
+	}
+	time.Sleep(50 * time.Millisecond)
+	if got := frame(tmpl, buf); got != "top\na\nb\nend" || tmpl.Animating() {
+		t.Errorf("settled = %q, animating %v", got, tmpl.Animating())
+	}
+}
+
+func TestTransitionKeepsLeavingBranch(t *testing.T) {
+	show := true
+	tmpl := Build(VBox(
+		If(&show).Then(Text("going")).Transition(Fade, time.Hour, nil),
+		Text("end"),
+	))
+	buf := NewBuffer(10, 3)
+	frame(tmpl, buf)
+
+	show = false
+	if got := frame(tmpl, buf); got != "going\nend" {
+		t.Errorf("leaving = %q", got)
+	}
+}
+
+func TestTransitionSwitchCrossFade(t *testing.T) {
+	tab := "a"
+	tmpl := Build(VBox(
+		Switch(&tab).Case("a", Text("first").FG(Red)).Case("b", Text("second").FG(Red)).End().
+			Transition(Fade, time.Hour, nil),
+	))
+	buf := NewBuffer(10, 2)
+	frame(tmpl, buf)
+
+	// the case leaving is fully in and the one arriving not yet drawn
+	tab = "b"
+	if got := frame(tmpl, buf); got != "first" {
+		t.Errorf("switching = %q", got)
+	}
+	if fg := buf.Get(0, 0).Style.FG; fg != Red {
+		t.Errorf("leaving case faded early: FG = %+v", fg)
+	}
+}
+
+func TestTransitionSlideLeft(t *testing.T) {
+	show := false
+	tmpl := Build(VBox(If(&show).Then(Text("ab")).Transition(SlideLeft, time.Hour, nil)))
+	buf := NewBuffer(4, 1)
+	frame(tmpl, buf)
+
+	// arriving from the right edge, so nothing is in view yet
+	show = true
+	if got := frame(tmpl, buf); got != "" {
+		t.Errorf("first frame in = %q", got)
+	}
+}
+
+func TestForEachTransition(t *testing.T) {
+	items := []string{"a", "b", "c"}
+	tmpl := Build(VBox(
+		ForEach(&items, func(s *string) any { return Text(s) }).Transition(Expand, time.Hour, nil),
+		Text("end"),
+	))
+	buf := NewBuffer(10, 6)
+	if got := frame(tmpl, buf); got != "a\nb\nc\nend" {
+		t.Fatalf("first frame = %q", got)
+	}
+
+	// a removed item keeps its row, drawn from a copy, while it leaves
+	items = []string{"a", "c"}
+	if got := frame(tmpl, buf); got != "a\nb\nc\nend" || !tmpl.Animating() {
+		t.Errorf("removing = %q, animating %v", got, tmpl.Animating())
+	}
+
+	// an added item starts with no height
+	items = append(items, "d")
+	if got := frame(tmpl, buf); got != "a\nb\nc\nend" {
+		t.Errorf("adding = %q", got)
+	}
+}
+
+func TestForEachTransitionSettles(t *testing.T) {
+	items := []string{"a", "b"}
+	tmpl := Build(VBox(ForEach(&items, func(s *string) any { return Text(s) }).Transition(SlideDown, 20*time.Millisecond, EaseOutCubic)))
+	buf := NewBuffer(10, 4)
+	frame(tmpl, buf)
+
+	items = []string{"b", "c", "d"}
+	frame(tmpl, buf)
+	time.Sleep(40 * time.Millisecond)
+	if got := frame(tmpl, buf); got != "b\nc\nd" || tmpl.Animating() {
+		t.Errorf("settled = %q, animating %v", got, tmpl.Animating())
+	}
+}
+
+func TestTransitionIgnoredInForEachItems(t *testing.T) {
+	type row struct {
+		name string
+		on   bool
+	}
+	rows := []row{{"x", true}, {"y", false}}
+	tmpl := Build(VBox(ForEach(&rows, func(r *row) any {
+		return If(&r.on).Then(Text("on")).Else(Text("off")).Transition(Fade, time.Hour, nil)
+	})))
+	iter := tmpl.ops[1].Ext.(*opForEach).iterTmpl
+	if kind := iter.ops[0].Kind; kind != OpIf {
+		t.Errorf("If in a ForEach item compiled to %s, want If", opKindName(kind))
+	}
+	buf := NewBuffer(10, 3)
+	if got := frame(tmpl, buf); got != "on\noff" {
+		t.Errorf("rows = %q", got)
+	}
+}
+
+func TestForEachTransitionFade(t *testing.T) {
+	now := time.Unix(0, 0)
+	items := []string{"a", "b"}
+	tmpl := Build(VBox(ForEach(&items, func(s *string) any { return Text(s).FG(RGB(200, 0, 0)) }).
+		Transition(Fade, 100*time.Millisecond, nil)))
+	tmpl.SetClock(func() time.Time { return now })
+	buf := NewBuffer(4, 2)
+	frame(tmpl, buf)
+
+	// the row leaving fades through its Opacity, toward black with no fill
+	items = items[:1]
+	frame(tmpl, buf)
+	now = now.Add(50 * time.Millisecond)
+	if got := frame(tmpl, buf); got != "a\nb" {
+		t.Fatalf("fading = %q", got)
+	}
+	if fg := buf.Get(0, 1).Style.FG; fg != RGB(100, 0, 0) {
+		t.Errorf("halfway FG = %+v", fg)
+	}
+	if fg := buf.Get(0, 0).Style.FG; fg != RGB(200, 0, 0) {
+		t.Errorf("row staying FG = %+v", fg)
+	}
+
+	now = now.Add(50 * time.Millisecond)
+	if got := frame(tmpl, buf); got != "a" || tmpl.Animating() {
+		t.Errorf("gone = %q, animating %v", got, tmpl.Animating())
+	}
+}
+
+func TestForEachTransitionReusesTweens(t *testing.T) {
+	now := time.Unix(0, 0)
+	items := []string{"a"}
+	tmpl := Build(VBox(ForEach(&items, func(s *string) any { return Text(s) }).
+		Transition(Expand, 10*time.Millisecond, nil)))
+	tmpl.SetClock(func() time.Time { return now })
+	buf := NewBuffer(4, 3)
+	frame(tmpl, buf)
+
+	cycle := func() {
+		items = append(items, "b")
+		frame(tmpl, buf)
+		now = now.Add(20 * time.Millisecond)
+		frame(tmpl, buf)
+		items = items[:1]
+		frame(tmpl, buf)
+		now = now.Add(20 * time.Millisecond)
+		frame(tmpl, buf)
+	}
+	cycle()
+	evals := len(tmpl.evals)
+	for range 5 {
+		cycle()
+	}
+	if len(tmpl.evals) != evals {
+		t.Errorf("evals grew from %d to %d", evals, len(tmpl.evals))
+	}
+	if got := frame(tmpl, buf); got != "a" {
+		t.Errorf("after cycles = %q", got)
+	}
+}
@@ -98,7 +98,7 @@ func Hex(hex uint32) Color {
 }
 
 // standard ANSI basic-16 RGB values
-var basic16RGB = [16][3]uint8{
+var ansi16RGB = [16][3]uint8{
 	{0, 0, 0},       // 0  black
 	{170, 0, 0},     // 1  red
 	{0, 170, 0},     // 2  green
@@ -117,6 +117,10 @@ var basic16RGB = [16][3]uint8{
 	{255, 255, 255}, // 15 bright white
 }
 
+// basic16RGB is the process terminal's palette: ansi16RGB until OSC 4
+// detection replaces it.
+var basic16RGB = ansi16RGB
+
 func rgbToBasic16(r, g, b uint8) (uint8, bool) {
 	for i, c := range basic16RGB {
 		if c[0] == r && c[1] == g && c[2] == b {
@@ -746,11 +750,12 @@ func isWithinRange(ptr, base unsafe.Pointer, size uintptr) bool {
 // ThemeEx provides a set of styles for consistent UI appearance.
 // Use CascadeStyle on containers to apply theme styles to children.
 type ThemeEx struct {
-	Base   Style // default text style
-	Muted  Style // de-emphasized text
-	Accent Style // highlighted/important text
-	Error  Style // error messages
-	Border Style // border/divider style
+	Base   Style    // default text style
+	Muted  Style    // de-emphasized text
+	Accent Style    // highlighted/important text
+	Error  Style    // error messages
+	Border Style    // border/divider style
+	Series [8]Color // chart series colours, given out in turn; unset ones are skipped
 }
 
 // Pre-defined themes
@@ -762,6 +767,7 @@ var ThemeDark = ThemeEx{
 	Accent: Style{FG: BrightCyan},
 	Error:  Style{FG: BrightRed},
 	Border: Style{FG: BrightBlack},
+	Series: [8]Color{BrightCyan, BrightMagenta, BrightYellow, BrightGreen, BrightBlue, BrightRed},
 }
 
 // ThemeLight is a light theme with dark text on light background.
@@ -771,6 +777,7 @@ var ThemeLight = ThemeEx{
 	Accent: Style{FG: Blue},
 	Error:  Style{FG: Red},
 	Border: Style{FG: White},
+	Series: [8]Color{Blue, Magenta, Red, Green, Cyan, Yellow},
 }
 
 // ThemeMonochrome is a minimal theme using only attributes.
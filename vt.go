@@ -0,0 +1,683 @@
+package glyph
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// ============================================================================
+// VT100/xterm screen emulation
+// ============================================================================
+
+// vtState is the escape sequence parser state.
+type vtState uint8
+
+const (
+	vtGround  vtState = iota
+	vtEscape          // after ESC
+	vtCSI             // ESC [ collecting parameters
+	vtOSC             // ESC ] collecting until BEL or ST
+	vtString          // DCS/SOS/PM/APC, discarded until ST
+	vtCharset         // ESC ( ) * + awaiting the charset designator
+	vtSkip            // ESC # awaiting one byte
+)
+
+// vtCursor is the state saved by DECSC and restored by DECRC.
+type vtCursor struct {
+	x, y  int
+	style Style
+	g0    byte
+}
+
+// vtScreen models a terminal screen fed with a child process's output.
+// It keeps a main and an alternate grid, a cursor, SGR state and a scroll
+// region, and understands the subset of VT100/xterm sequences that shells,
+// pagers and full-screen tools rely on.
+type vtScreen struct {
+	cols, rows int
+	main, alt  *Buffer
+	grid       *Buffer // main or alt, whichever is active
+	altActive  bool
+
+	cx, cy   int
+	wrapNext bool // a glyph was written in the last column; the next one wraps
+	style    Style
+	top, bot int // scroll region, inclusive
+	saved    vtCursor
+	g0       byte // '0' selects DEC special graphics (line drawing)
+	shifted  bool // SO active: G1 is in use, which is always line drawing here
+	last     rune // the last character written, for REP
+
+	autowrap       bool
+	cursorVisible  bool
+	cursorShape    CursorShape
+	appCursor      bool // DECCKM: cursor keys send SS3 sequences
+	bracketedPaste bool
+	title          string
+
+	// replies holds answers to device status and attribute queries until
+	// the owner writes them back, outside any lock it holds around Write
+	replies []byte
+
+	state  vtState
+	params []byte
+	inter  byte
+	osc    []byte
+	utf    [utf8.UTFMax]byte
+	utfN   int
+
+	dirty bool // grid changed since the last copy out
+}
+
+func newVTScreen(cols, rows int) *vtScreen {
+	s := &vtScreen{
+		cols:          cols,
+		rows:          rows,
+		main:          NewBuffer(cols, rows),
+		alt:           NewBuffer(cols, rows),
+		bot:           rows - 1,
+		autowrap:      true,
+		cursorVisible: true,
+		cursorShape:   CursorBlock,
+		dirty:         true,
+	}
+	s.grid = s.main
+	return s
+}
+
+// Emulator is a VT100/xterm screen that terminal output can be written to,
+// turning the bytes an app or a program wrote back into cells. The replay
+// package plays recordings with it.
+type Emulator struct {
+	vt *vtScreen
+}
+
+// NewEmulator returns a blank cols×rows screen.
+func NewEmulator(cols, rows int) *Emulator {
+	return &Emulator{vt: newVTScreen(cols, rows)}
+}
+
+// Write feeds terminal output into the screen. Sequences may be split
+// across writes.
+func (e *Emulator) Write(p []byte) (int, error) { return e.vt.Write(p) }
+
+// Resize changes the screen size, keeping content anchored top-left.
+func (e *Emulator) Resize(cols, rows int) { e.vt.resize(cols, rows) }
+
+// Size returns the screen size.
+func (e *Emulator) Size() (cols, rows int) { return e.vt.cols, e.vt.rows }
+
+// Buffer returns the screen's cells, the alternate screen while it is
+// active. It changes with the next Write.
+func (e *Emulator) Buffer() *Buffer { return e.vt.grid }
+
+// Cursor returns the cursor position and whether it is shown.
+func (e *Emulator) Cursor() (x, y int, visible bool) {
+	return e.vt.cx, e.vt.cy, e.vt.cursorVisible
+}
+
+// Title returns the window title last set with OSC 0 or 2.
+func (e *Emulator) Title() string { return e.vt.title }
+
+// Write feeds output from the child into the screen. Sequences may be split
+// across writes.
+func (s *vtScreen) Write(p []byte) (int, error) {
+	for _, b := range p {
+		s.feed(b)
+	}
+	s.dirty = true
+	return len(p), nil
+}
+
+func (s *vtScreen) feed(b byte) {
+	switch s.state {
+	case vtGround:
+		if s.utfN > 0 || b >= 0x80 {
+			s.feedUTF8(b)
+			return
+		}
+		if b < 0x20 || b == 0x7f {
+			s.control(b)
+			return
+		}
+		s.put(rune(b))
+
+	case vtEscape:
+		s.escape(b)
+
+	case vtCSI:
+		switch {
+		case b >= 0x40 && b <= 0x7e:
+			s.state = vtGround
+			s.csi(b)
+		case b >= 0x30 && b <= 0x3f:
+			s.params = append(s.params, b)
+		case b >= 0x20 && b <= 0x2f:
+			s.inter = b
+		default:
+			s.control(b)
+		}
+
+	case vtOSC:
+		switch b {
+		case 0x07:
+			s.state = vtGround
+			s.oscDone()
+		case 0x1b:
+			s.oscDone()
+			s.state = vtEscape // the following '\' is ST
+		default:
+			if len(s.osc) < 4096 {
+				s.osc = append(s.osc, b)
+			}
+		}
+
+	case vtString:
+		if b == 0x1b {
+			s.state = vtEscape
+		}
+
+	case vtCharset:
+		if s.inter == '(' {
+			s.g0 = b
+		}
+		s.state = vtGround
+
+	case vtSkip:
+		s.state = vtGround
+	}
+}
+
+// feedUTF8 accumulates a multi-byte rune.
+func (s *vtScreen) feedUTF8(b byte) {
+	if s.utfN > 0 && (b < 0x80 || b >= 0xc0) {
+		// truncated sequence: drop it and treat b afresh
+		s.utfN = 0
+		s.feed(b)
+		return
+	}
+	s.utf[s.utfN] = b
+	s.utfN++
+	if !utf8.FullRune(s.utf[:s.utfN]) {
+		if s.utfN == len(s.utf) {
+			s.utfN = 0
+		}
+		return
+	}
+	r, _ := utf8.DecodeRune(s.utf[:s.utfN])
+	s.utfN = 0
+	s.put(r)
+}
+
+// control executes a C0 control character.
+func (s *vtScreen) control(b byte) {
+	switch b {
+	case 0x1b:
+		s.state = vtEscape
+		s.params = s.params[:0]
+		s.inter = 0
+	case '\r':
+		s.cx = 0
+		s.wrapNext = false
+	case '\n', '\v', '\f':
+		s.lineFeed()
+	case '\b':
+		if s.cx > 0 {
+			s.cx--
+		}
+		s.wrapNext = false
+	case '\t':
+		s.cx = min((s.cx/8+1)*8, s.cols-1)
+		s.wrapNext = false
+	case 0x0e: // SO
+		s.shifted = true
+	case 0x0f: // SI
+		s.shifted = false
+	case 0x18, 0x1a: // CAN, SUB abort a sequence
+		s.state = vtGround
+	}
+}
+
+func (s *vtScreen) escape(b byte) {
+	s.state = vtGround
+	switch b {
+	case '[':
+		s.state = vtCSI
+		s.params = s.params[:0]
+		s.inter = 0
+	case ']':
+		s.state = vtOSC
+		s.osc = s.osc[:0]
+	case 'P', 'X', '^', '_':
+		s.state = vtString
+	case '(', ')', '*', '+':
+		s.state = vtCharset
+		s.inter = b
+	case '#':
+		s.state = vtSkip
+	case '7':
+		s.saveCursor()
+	case '8':
+		s.restoreCursor()
+	case 'D':
+		s.lineFeed()
+	case 'E':
+		s.cx = 0
+		s.lineFeed()
+	case 'M':
+		s.reverseIndex()
+	case 'c':
+		s.reset()
+	default:
+		if b < 0x20 {
+			s.control(b)
+		}
+	}
+}
+
+// put writes a printable rune at the cursor.
+func (s *vtScreen) put(r rune) {
+	if (s.g0 == '0' || s.shifted) && r >= 0x5f && r <= 0x7e {
+		r = decGraphics[r-0x5f]
+	}
+	w := runewidth.RuneWidth(r)
+	if w == 0 {
+		return
+	}
+	if s.wrapNext {
+		s.wrapNext = false
+		if s.autowrap {
+			s.cx = 0
+			s.lineFeed()
+		}
+	}
+	if w == 2 && s.cx == s.cols-1 {
+		if !s.autowrap {
+			return
+		}
+		s.grid.SetFast(s.cx, s.cy, s.blank())
+		s.cx = 0
+		s.lineFeed()
+	}
+	s.last = r
+	s.grid.SetFast(s.cx, s.cy, Cell{Rune: r, Style: s.style})
+	if w == 2 {
+		s.grid.SetFast(s.cx+1, s.cy, Cell{Rune: 0, Style: s.style})
+	}
+	s.cx += w
+	if s.cx >= s.cols {
+		s.cx = s.cols - 1
+		s.wrapNext = true
+	}
+}
+
+// decGraphics maps 0x5f-0x7e to the DEC special graphics set.
+var decGraphics = [32]rune{
+	' ', '◆', '▒', '␉', '␌', '␍', '␊', '°', '±', '␤', '␋', '┘', '┐', '┌', '└', '┼',
+	'⎺', '⎻', '─', '⎼', '⎽', '├', '┤', '┴', '┬', '│', '≤', '≥', 'π', '≠', '£', '·',
+}
+
+// blank is an erased cell: a space carrying the current background.
+func (s *vtScreen) blank() Cell {
+	return Cell{Rune: ' ', Style: Style{BG: s.style.BG}}
+}
+
+func (s *vtScreen) lineFeed() {
+	s.wrapNext = false
+	if s.cy == s.bot {
+		s.scrollUp(1)
+	} else if s.cy < s.rows-1 {
+		s.cy++
+	}
+}
+
+func (s *vtScreen) reverseIndex() {
+	s.wrapNext = false
+	if s.cy == s.top {
+		s.scrollDown(1)
+	} else if s.cy > 0 {
+		s.cy--
+	}
+}
+
+// scrollUp moves the scroll region's rows up by n, blanking the bottom.
+func (s *vtScreen) scrollUp(n int) {
+	n = min(n, s.bot-s.top+1)
+	if n <= 0 {
+		return
+	}
+	c := s.grid.cells
+	w := s.cols
+	copy(c[s.top*w:(s.bot+1-n)*w], c[(s.top+n)*w:(s.bot+1)*w])
+	s.fill(c[(s.bot+1-n)*w : (s.bot+1)*w])
+}
+
+// scrollDown moves the scroll region's rows down by n, blanking the top.
+func (s *vtScreen) scrollDown(n int) {
+	n = min(n, s.bot-s.top+1)
+	if n <= 0 {
+		return
+	}
+	c := s.grid.cells
+	w := s.cols
+	copy(c[(s.top+n)*w:(s.bot+1)*w], c[s.top*w:(s.bot+1-n)*w])
+	s.fill(c[s.top*w : (s.top+n)*w])
+}
+
+func (s *vtScreen) fill(cells []Cell) {
+	blank := s.blank()
+	for i := range cells {
+		cells[i] = blank
+	}
+}
+
+// erase blanks columns [x0, x1) of row y.
+func (s *vtScreen) erase(y, x0, x1 int) {
+	x0, x1 = max(x0, 0), min(x1, s.cols)
+	if y < 0 || y >= s.rows || x0 >= x1 {
+		return
+	}
+	s.fill(s.grid.cells[y*s.cols+x0 : y*s.cols+x1])
+}
+
+// moveTo positions the cursor, clamped to the screen.
+func (s *vtScreen) moveTo(x, y int) {
+	s.cx = max(0, min(x, s.cols-1))
+	s.cy = max(0, min(y, s.rows-1))
+	s.wrapNext = false
+}
+
+func (s *vtScreen) saveCursor() {
+	s.saved = vtCursor{x: s.cx, y: s.cy, style: s.style, g0: s.g0}
+}
+
+func (s *vtScreen) restoreCursor() {
+	s.moveTo(s.saved.x, s.saved.y)
+	s.style = s.saved.style
+	s.g0 = s.saved.g0
+}
+
+// reset performs RIS: both grids cleared and all modes back to defaults.
+func (s *vtScreen) reset() {
+	*s = vtScreen{
+		cols:    s.cols,
+		rows:    s.rows,
+		main:    s.main,
+		alt:     s.alt,
+		title:   s.title,
+		replies: s.replies,
+	}
+	s.main.Clear()
+	s.alt.Clear()
+	s.grid = s.main
+	s.bot = s.rows - 1
+	s.autowrap = true
+	s.cursorVisible = true
+	s.cursorShape = CursorBlock
+}
+
+// setAlt switches between the main and alternate grids.
+func (s *vtScreen) setAlt(on bool) {
+	if on == s.altActive {
+		return
+	}
+	s.altActive = on
+	if on {
+		s.grid = s.alt
+		s.fill(s.alt.cells)
+	} else {
+		s.grid = s.main
+	}
+}
+
+func (s *vtScreen) oscDone() {
+	// OSC 0 and 2 set the window title
+	p := string(s.osc)
+	if i := strings.IndexByte(p, ';'); i > 0 {
+		if code := p[:i]; code == "0" || code == "2" {
+			s.title = p[i+1:]
+		}
+	}
+}
+
+// csi dispatches a complete control sequence.
+func (s *vtScreen) csi(final byte) {
+	params := string(s.params)
+	var priv byte
+	if params != "" && params[0] >= '<' && params[0] <= '?' {
+		priv, params = params[0], params[1:]
+	}
+	args := vtArgs(params)
+	arg := func(i, def int) int {
+		if i < len(args) && args[i] > 0 {
+			return args[i]
+		}
+		return def
+	}
+
+	if priv != 0 {
+		switch {
+		case priv == '?' && (final == 'h' || final == 'l'):
+			for _, m := range args {
+				s.setPrivateMode(m, final == 'h')
+			}
+		case priv == '>' && final == 'c':
+			s.send("\x1b[>0;0;0c")
+		}
+		return
+	}
+	if s.inter == ' ' && final == 'q' {
+		s.setCursorShape(arg(0, 0))
+		return
+	}
+	if s.inter != 0 {
+		return
+	}
+
+	switch final {
+	case 'A':
+		s.moveTo(s.cx, max(s.cy-arg(0, 1), s.topFor(s.cy)))
+	case 'B', 'e':
+		s.moveTo(s.cx, min(s.cy+arg(0, 1), s.botFor(s.cy)))
+	case 'C', 'a':
+		s.moveTo(s.cx+arg(0, 1), s.cy)
+	case 'D':
+		s.moveTo(s.cx-arg(0, 1), s.cy)
+	case 'E':
+		s.moveTo(0, min(s.cy+arg(0, 1), s.botFor(s.cy)))
+	case 'F':
+		s.moveTo(0, max(s.cy-arg(0, 1), s.topFor(s.cy)))
+	case 'G', '`':
+		s.moveTo(arg(0, 1)-1, s.cy)
+	case 'd':
+		s.moveTo(s.cx, arg(0, 1)-1)
+	case 'H', 'f':
+		s.moveTo(arg(1, 1)-1, arg(0, 1)-1)
+	case 'J':
+		switch arg(0, 0) {
+		case 0:
+			s.erase(s.cy, s.cx, s.cols)
+			s.fill(s.grid.cells[(s.cy+1)*s.cols:])
+		case 1:
+			s.fill(s.grid.cells[:s.cy*s.cols])
+			s.erase(s.cy, 0, s.cx+1)
+		case 2, 3:
+			s.fill(s.grid.cells)
+		}
+	case 'K':
+		switch arg(0, 0) {
+		case 0:
+			s.erase(s.cy, s.cx, s.cols)
+		case 1:
+			s.erase(s.cy, 0, s.cx+1)
+		case 2:
+			s.erase(s.cy, 0, s.cols)
+		}
+	case 'X':
+		s.erase(s.cy, s.cx, s.cx+arg(0, 1))
+	case 'b':
+		if s.last != 0 {
+			for range min(arg(0, 1), s.cols*s.rows) {
+				s.put(s.last)
+			}
+		}
+	case '@':
+		s.insertChars(arg(0, 1))
+	case 'P':
+		s.deleteChars(arg(0, 1))
+	case 'L', 'M':
+		if s.cy < s.top || s.cy > s.bot {
+			return
+		}
+		top := s.top
+		s.top = s.cy
+		if final == 'L' {
+			s.scrollDown(arg(0, 1))
+		} else {
+			s.scrollUp(arg(0, 1))
+		}
+		s.top = top
+		s.moveTo(0, s.cy)
+	case 'S':
+		s.scrollUp(arg(0, 1))
+	case 'T':
+		s.scrollDown(arg(0, 1))
+	case 'r':
+		top, bot := arg(0, 1)-1, arg(1, s.rows)-1
+		if top < bot && bot < s.rows {
+			s.top, s.bot = top, bot
+			s.moveTo(0, 0)
+		}
+	case 's':
+		s.saveCursor()
+	case 'u':
+		s.restoreCursor()
+	case 'm':
+		applySGR(&s.style, params)
+	case 'n':
+		switch arg(0, 0) {
+		case 5:
+			s.send("\x1b[0n")
+		case 6:
+			s.send("\x1b[" + strconv.Itoa(s.cy+1) + ";" + strconv.Itoa(s.cx+1) + "R")
+		}
+	case 'c':
+		if arg(0, 0) == 0 {
+			s.send("\x1b[?1;2c")
+		}
+	}
+}
+
+// topFor and botFor bound vertical cursor movement: inside the scroll
+// region the cursor stops at its margins, outside it at the screen edge.
+func (s *vtScreen) topFor(y int) int {
+	if y >= s.top {
+		return s.top
+	}
+	return 0
+}
+
+func (s *vtScreen) botFor(y int) int {
+	if y <= s.bot {
+		return s.bot
+	}
+	return s.rows - 1
+}
+
+func (s *vtScreen) insertChars(n int) {
+	row := s.grid.cells[s.cy*s.cols : (s.cy+1)*s.cols]
+	n = min(n, s.cols-s.cx)
+	copy(row[s.cx+n:], row[s.cx:])
+	s.fill(row[s.cx : s.cx+n])
+	s.wrapNext = false
+}
+
+func (s *vtScreen) deleteChars(n int) {
+	row := s.grid.cells[s.cy*s.cols : (s.cy+1)*s.cols]
+	n = min(n, s.cols-s.cx)
+	copy(row[s.cx:], row[s.cx+n:])
+	s.fill(row[s.cols-n:])
+	s.wrapNext = false
+}
+
+func (s *vtScreen) setPrivateMode(m int, on bool) {
+	switch m {
+	case 1:
+		s.appCursor = on
+	case 7:
+		s.autowrap = on
+	case 25:
+		s.cursorVisible = on
+	case 47, 1047:
+		s.setAlt(on)
+	case 1049:
+		if on {
+			s.saveCursor()
+			s.setAlt(true)
+		} else {
+			s.setAlt(false)
+			s.restoreCursor()
+		}
+	case 2004:
+		s.bracketedPaste = on
+	}
+}
+
+// setCursorShape applies DECSCUSR, whose values match CursorShape.
+func (s *vtScreen) setCursorShape(n int) {
+	if n == 0 {
+		n = int(CursorBlockBlink)
+	}
+	if n <= int(CursorBar) {
+		s.cursorShape = CursorShape(n)
+	}
+}
+
+func (s *vtScreen) send(reply string) {
+	s.replies = append(s.replies, reply...)
+}
+
+// takeReplies returns the queued replies and clears the queue.
+func (s *vtScreen) takeReplies() []byte {
+	r := s.replies
+	s.replies = nil
+	return r
+}
+
+// resize changes the screen size, keeping content anchored top-left. When
+// the screen shrinks below the cursor, rows scroll off the top so the
+// cursor line stays visible, as xterm does.
+func (s *vtScreen) resize(cols, rows int) {
+	if cols == s.cols && rows == s.rows {
+		return
+	}
+	if s.cy >= rows {
+		n := s.cy - rows + 1
+		s.top, s.bot = 0, s.rows-1
+		s.scrollUp(n)
+		s.cy -= n
+	}
+	s.main.Resize(cols, rows)
+	s.alt.Resize(cols, rows)
+	s.cols, s.rows = cols, rows
+	s.top, s.bot = 0, rows-1
+	s.moveTo(s.cx, s.cy)
+	s.saved.x, s.saved.y = min(s.saved.x, cols-1), min(s.saved.y, rows-1)
+	s.dirty = true
+}
+
+// vtArgs parses semicolon separated numeric parameters. Missing values
+// are 0; colon sub-parameters are ignored.
+func vtArgs(params string) []int {
+	if params == "" {
+		return nil
+	}
+	var args []int
+	for f := range strings.SplitSeq(params, ";") {
+		args = append(args, ansiParam(f, 0))
+	}
+	return args
+}
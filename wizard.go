@@ -0,0 +1,449 @@
+package glyph
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WizardStep is one screen of a Wizard. See Step.
+type WizardStep struct {
+	title string
+	view  any
+	when  func() bool
+}
+
+// Step creates a wizard step showing view under title. A *FormC or
+// *AutoFormC view must validate before the wizard moves past it.
+func Step(title string, view any) WizardStep {
+	return WizardStep{title: title, view: view}
+}
+
+// When shows the step only while fn returns true, so a step can depend on
+// answers given in earlier ones. Checked each time the wizard moves.
+func (s WizardStep) When(fn func() bool) WizardStep {
+	s.when = fn
+	return s
+}
+
+// WizardC walks through a sequence of steps. See Wizard.
+type WizardC struct {
+	steps    []WizardStep
+	summary  any
+	tabStyle TabsStyle
+
+	nextKey   string
+	backKey   string
+	cancelKey string
+
+	onFinish func()
+	onCancel func()
+	onChange func(index int)
+
+	cur         int      // index into steps; len(steps) is the summary
+	pos         int      // position of cur among visible steps, for the header
+	titles      []string // titles of visible steps
+	summaryRows []string // generated summary lines
+	wired       bool     // step forms are connected to app input
+	view        any
+}
+
+// Wizard creates a multi-step flow with a step indicator, Next/Back/Cancel
+// keys and per-step validation. Form steps are validated with ValidateAll
+// before advancing and steps with When are skipped while their condition is
+// false. Works in fullscreen and inline apps.
+//
+//	Wizard(
+//	    Step("Account", Form(Field("User", Input(&user)))),
+//	    Step("Plan", Form(Field("Plan", Radio(&plan, "free", "pro")))),
+//	    Step("Billing", billingForm).When(func() bool { return plan == 1 }),
+//	).Summary().OnFinish(install)
+//
+// Enter moves to the next step, Ctrl-B back and Escape cancels; Escape
+// first leaves the focused field on form steps. Enter in a field submits its
+// form first; an OnSubmitAsync form moves on once its submit succeeds.
+func Wizard(steps ...WizardStep) *WizardC {
+	w := &WizardC{
+		steps:     steps,
+		nextKey:   "<Enter>",
+		backKey:   "<C-b>",
+		cancelKey: "<Escape>",
+	}
+	// Enter on a focused field advances too, once the form has submitted
+	for _, s := range steps {
+		switch v := s.view.(type) {
+		case *AutoFormC:
+			// the form's own submit has validated and applied the values
+			prev := v.onSubmit
+			v.onSubmit = func() {
+				if prev != nil {
+					prev()
+				}
+				w.advance()
+			}
+		case *FormC:
+			if v.onSubmitAsync != nil {
+				// runs on the render pass; Back may have left the step since
+				v.onSubmitted = func() {
+					if w.cur < len(w.steps) && w.steps[w.cur].view == v {
+						w.advance()
+					}
+				}
+				continue
+			}
+			if prev := v.onSubmit; prev != nil {
+				v.onSubmit = func() { prev(); w.Next() }
+			} else {
+				v.onSubmit = func() { w.Next() }
+			}
+		}
+	}
+	if next := w.following(-1, 1); next >= 0 {
+		w.cur = next
+	}
+	w.refresh()
+	return w
+}
+
+// Kind sets the step indicator style.
+func (w *WizardC) Kind(s TabsStyle) *WizardC {
+	w.tabStyle = s
+	return w
+}
+
+// Summary adds a final step before finishing. With no view, it lists the
+// answers given on each visited form step.
+func (w *WizardC) Summary(view ...any) *WizardC {
+	if len(view) > 0 {
+		w.summary = view[0]
+	} else {
+		w.summary = ForEach(&w.summaryRows, func(row *string) any { return Text(row) })
+	}
+	w.refresh()
+	return w
+}
+
+// NextKey sets the key that advances (default: Enter).
+func (w *WizardC) NextKey(key string) *WizardC {
+	w.nextKey = key
+	return w
+}
+
+// BackKey sets the key that returns to the previous step (default: Ctrl-B).
+func (w *WizardC) BackKey(key string) *WizardC {
+	w.backKey = key
+	return w
+}
+
+// CancelKey sets the key that cancels the wizard (default: Escape).
+func (w *WizardC) CancelKey(key string) *WizardC {
+	w.cancelKey = key
+	return w
+}
+
+// OnFinish sets a callback for Next on the last step.
+func (w *WizardC) OnFinish(fn func()) *WizardC {
+	w.onFinish = fn
+	return w
+}
+
+// OnCancel sets a callback for the cancel key.
+func (w *WizardC) OnCancel(fn func()) *WizardC {
+	w.onCancel = fn
+	return w
+}
+
+// OnChange sets a callback that fires when the step changes, with the index
+// of the new step. The summary's index is the number of steps.
+func (w *WizardC) OnChange(fn func(index int)) *WizardC {
+	w.onChange = fn
+	return w
+}
+
+// Current returns the index of the current step.
+func (w *WizardC) Current() int {
+	return w.cur
+}
+
+// Title returns the title of the current step.
+func (w *WizardC) Title() string {
+	if w.cur < len(w.steps) {
+		return w.steps[w.cur].title
+	}
+	return "Summary"
+}
+
+// Next validates the current step and moves to the next visible one, or
+// finishes on the last. Returns false if the step is invalid. An
+// OnSubmitAsync form step starts its submit instead and returns false; the
+// wizard moves on on the render after the submit succeeds.
+func (w *WizardC) Next() bool {
+	if w.cur < len(w.steps) {
+		if f, ok := w.steps[w.cur].view.(*FormC); ok && f.onSubmitAsync != nil {
+			f.submitAsync()
+			return false
+		}
+	}
+	if w.cur < len(w.steps) && !stepValid(w.steps[w.cur].view) {
+		return false
+	}
+	w.advance()
+	return true
+}
+
+// advance moves to the next visible step, or finishes on the last.
+func (w *WizardC) advance() {
+	next := w.following(w.cur, 1)
+	if next < 0 {
+		if w.onFinish != nil {
+			w.onFinish()
+		}
+		return
+	}
+	w.show(next)
+}
+
+// Back moves to the previous visible step without validating.
+func (w *WizardC) Back() {
+	if prev := w.following(w.cur, -1); prev >= 0 {
+		w.show(prev)
+	}
+}
+
+// Cancel leaves the current step and calls OnCancel.
+func (w *WizardC) Cancel() {
+	if fm := w.focus(w.cur); fm != nil {
+		fm.BlurCurrent()
+	}
+	if w.onCancel != nil {
+		w.onCancel()
+	}
+}
+
+// visible reports whether step i is shown.
+func (w *WizardC) visible(i int) bool {
+	if i == len(w.steps) {
+		return w.summary != nil
+	}
+	return w.steps[i].when == nil || w.steps[i].when()
+}
+
+// following returns the next visible step from i in direction dir, or -1.
+func (w *WizardC) following(i, dir int) int {
+	for j := i + dir; j >= 0 && j <= len(w.steps); j += dir {
+		if w.visible(j) {
+			return j
+		}
+	}
+	return -1
+}
+
+// show switches to step i, moving input focus to its form.
+func (w *WizardC) show(i int) {
+	if fm := w.focus(w.cur); fm != nil {
+		fm.BlurCurrent()
+	}
+	w.cur = i
+	w.refresh()
+	if i == len(w.steps) {
+		w.summarize()
+	}
+	if fm := w.focus(i); fm != nil && w.wired {
+		fm.resume()
+	}
+	if w.onChange != nil {
+		w.onChange(i)
+	}
+}
+
+// refresh rebuilds the header titles for the visible steps.
+func (w *WizardC) refresh() {
+	w.titles = w.titles[:0]
+	w.pos = 0
+	for i := range len(w.steps) + 1 {
+		if !w.visible(i) {
+			continue
+		}
+		if i == w.cur {
+			w.pos = len(w.titles)
+		}
+		if i < len(w.steps) {
+			w.titles = append(w.titles, w.steps[i].title)
+		} else {
+			w.titles = append(w.titles, "Summary")
+		}
+	}
+}
+
+// summarize lists the answers on visited form steps.
+func (w *WizardC) summarize() {
+	w.summaryRows = w.summaryRows[:0]
+	for i, s := range w.steps {
+		f := stepForm(s.view)
+		if f == nil || !w.visible(i) {
+			continue
+		}
+		w.summaryRows = append(w.summaryRows, s.title)
+		width := 0
+		for _, ff := range f.fields {
+			width = max(width, len(ff.label))
+		}
+		for _, ff := range f.fields {
+			if v, ok := summaryValue(ff.control); ok {
+				w.summaryRows = append(w.summaryRows, fmt.Sprintf("  %*s: %s", width, ff.label, v))
+			}
+		}
+	}
+}
+
+// focus returns the focus manager of step i's form, or nil.
+func (w *WizardC) focus(i int) *FocusManager {
+	if i >= len(w.steps) {
+		return nil
+	}
+	if f := stepForm(w.steps[i].view); f != nil {
+		return f.fm
+	}
+	return nil
+}
+
+// focusManagers returns the focus managers of all form steps.
+func (w *WizardC) focusManagers() []*FocusManager {
+	var fms []*FocusManager
+	for i := range w.steps {
+		if fm := w.focus(i); fm != nil {
+			fms = append(fms, fm)
+		}
+	}
+	return fms
+}
+
+// start pushes the current step's input once the app has wired the forms.
+func (w *WizardC) start() {
+	w.wired = true
+	if fm := w.focus(w.cur); fm != nil {
+		fm.resume()
+	}
+}
+
+func (w *WizardC) bindings() []binding {
+	binds := []binding{
+		{pattern: w.nextKey, handler: func() { w.Next() }, name: "wizard.next", desc: "next step", group: "Wizard"},
+		{pattern: w.backKey, handler: w.Back, name: "wizard.back", desc: "previous step", group: "Wizard"},
+		{pattern: w.cancelKey, handler: w.Cancel, name: "wizard.cancel", desc: "cancel", group: "Wizard"},
+		// refocus the form after Escape left its field
+		{pattern: "<Tab>", handler: func() { w.refocus(1) }, name: "focus.next", desc: "next field", group: "Focus"},
+		{pattern: "<S-Tab>", handler: func() { w.refocus(-1) }, name: "focus.prev", desc: "previous field", group: "Focus"},
+	}
+	// form steps see the keys on their field routers too; Escape stays
+	// with the field so it can be left first
+	for _, fm := range w.focusManagers() {
+		fm.subBindings = append(fm.subBindings, binds[1])
+	}
+	return binds
+}
+
+func (w *WizardC) refocus(dir int) {
+	fm := w.focus(w.cur)
+	switch {
+	case fm == nil:
+	case !fm.pushed:
+		fm.resume()
+	case dir > 0:
+		fm.Next()
+	default:
+		fm.Prev()
+	}
+}
+
+func (w *WizardC) toTemplate() any {
+	if w.view != nil {
+		return w.view
+	}
+	tabs := Tabs(nil, &w.pos).Kind(w.tabStyle)
+	tabs.labelsPtr = &w.titles
+
+	body := Switch(&w.cur)
+	for i, s := range w.steps {
+		body.Case(i, s.view)
+	}
+	if w.summary != nil {
+		body.Case(len(w.steps), w.summary)
+	}
+
+	hint := fmt.Sprintf("%s next · %s back · %s cancel", keyName(w.nextKey), keyName(w.backKey), keyName(w.cancelKey))
+	w.view = VBox.Gap(1)(tabs, body.End(), Text(hint).Dim())
+	return w.view
+}
+
+// stepForm returns the form behind a step view, or nil.
+func stepForm(view any) *FormC {
+	switch v := view.(type) {
+	case *FormC:
+		return v
+	case *AutoFormC:
+		return v.build()
+	}
+	return nil
+}
+
+// stepValid validates a form step; other views always pass.
+func stepValid(view any) bool {
+	switch v := view.(type) {
+	case *FormC:
+		return v.ValidateAll()
+	case *AutoFormC:
+		return v.Submit()
+	}
+	return true
+}
+
+// summarizer is implemented by form controls that can show their value as text.
+type summarizer interface {
+	summary() string
+}
+
+// summaryValue returns a control's value as text for the wizard summary.
+func summaryValue(control any) (string, bool) {
+	switch c := control.(type) {
+	case *InputC:
+		if c.mask != 0 {
+			return strings.Repeat(string(c.mask), len([]rune(c.Value()))), true
+		}
+		return c.Value(), true
+	case *CheckboxC:
+		if *c.checked {
+			return "yes", true
+		}
+		return "no", true
+	case *RadioC:
+		return c.Selected(), true
+	case summarizer:
+		return c.summary(), true
+	}
+	return "", false
+}
+
+func (s *SelectC[T]) summary() string {
+	if item := s.Selected(); item != nil {
+		return s.label(item)
+	}
+	return ""
+}
+
+func (n *NumberInputC[N]) summary() string { return n.field.Value }
+
+func (s *SliderC) summary() string {
+	if s.format != nil {
+		return s.format(*s.value)
+	}
+	return strconv.FormatFloat(*s.value, 'f', -1, 64)
+}
+
+func (d *DatePickerC) summary() string {
+	if d.value.IsZero() {
+		return ""
+	}
+	return d.value.Format(time.DateOnly)
+}
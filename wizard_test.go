@@ -0,0 +1,198 @@
+package glyph
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kungfusheep/riffkey"
+)
+
+// wizardApp wires a view to an App without a terminal and returns a
+// function that types keys into it.
+func wizardApp(t *testing.T, view any) func(keys string) {
+	t.Helper()
+	router := riffkey.NewRouter()
+	a := &App{router: router, input: riffkey.NewInput(router), renderChan: make(chan struct{}, 1)}
+	a.wireBindings(Build(view), router)
+	return func(keys string) {
+		for _, k := range riffkey.ParsePattern(keys) {
+			a.input.Dispatch(k)
+		}
+	}
+}
+
+func TestWizardStepsAndSkips(t *testing.T) {
+	name, plan := "", 0
+	var card string
+	finished := false
+
+	w := Wizard(
+		Step("Account", Form(Field("Name", Input(&name).Validate(VRequired)))),
+		Step("Plan", Form(Field("Plan", Radio(&plan, "free", "pro")))),
+		Step("Billing", Form(Field("Card", Input(&card).Mask('*')))).When(func() bool { return plan == 1 }),
+		Step("Done", Text("ready")),
+	).Summary().OnFinish(func() { finished = true })
+
+	buf := NewBuffer(40, 10)
+	tmpl := Build(w)
+	tmpl.Execute(buf, 40, 10)
+	if got := buf.GetLine(0); !strings.Contains(got, "Account") || strings.Contains(got, "Billing") {
+		t.Errorf("header = %q", got)
+	}
+
+	if w.Next() || w.Current() != 0 {
+		t.Fatalf("advanced past invalid step to %d", w.Current())
+	}
+	w.steps[0].view.(*FormC).fields[0].control.(*InputC).SetValue("ada")
+	if !w.Next() || w.Title() != "Plan" {
+		t.Fatalf("step = %q", w.Title())
+	}
+
+	// free plan skips billing
+	w.Next()
+	if w.Title() != "Done" {
+		t.Errorf("step = %q, want Done", w.Title())
+	}
+	w.Back()
+	plan = 1
+	w.Next()
+	if w.Title() != "Billing" {
+		t.Errorf("step = %q, want Billing", w.Title())
+	}
+	buf.Clear()
+	tmpl.Execute(buf, 40, 10)
+	if got := buf.GetLine(0); !strings.Contains(got, "Billing") {
+		t.Errorf("header = %q", got)
+	}
+
+	w.steps[2].view.(*FormC).fields[0].control.(*InputC).SetValue("4242")
+	w.Next()
+	w.Next()
+	if w.Title() != "Summary" || finished {
+		t.Fatalf("step = %q finished = %v", w.Title(), finished)
+	}
+	summary := strings.Join(w.summaryRows, "\n")
+	for _, want := range []string{"Account", "Name: ada", "Plan: pro", "Card: ****"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("summary missing %q:\n%s", want, summary)
+		}
+	}
+	w.Next()
+	if !finished {
+		t.Error("OnFinish not called")
+	}
+}
+
+func TestWizardKeys(t *testing.T) {
+	host, port := "", ""
+	cancelled := false
+	w := Wizard(
+		Step("Host", Form(Field("Host", Input(&host).Validate(VRequired)))),
+		Step("Port", Form(Field("Port", Input(&port)))),
+	).OnCancel(func() { cancelled = true })
+	press := wizardApp(t, w)
+
+	// typing reaches the first step's input; Enter advances from the field
+	press("<Enter>")
+	if w.Current() != 0 {
+		t.Fatal("advanced with empty host")
+	}
+	press("db<Enter>")
+	if host != "db" || w.Current() != 1 {
+		t.Fatalf("host = %q step = %d", host, w.Current())
+	}
+
+	// the second step's input now has the keys
+	press("5432")
+	if port != "5432" {
+		t.Errorf("port = %q", port)
+	}
+	press("<C-b>")
+	if w.Current() != 0 {
+		t.Errorf("back: step = %d", w.Current())
+	}
+	press("x")
+	if host != "dbx" {
+		t.Errorf("host = %q after back", host)
+	}
+
+	// Escape leaves the field, then cancels
+	press("<Escape>")
+	if cancelled {
+		t.Fatal("first Escape should only leave the field")
+	}
+	press("<Escape>")
+	if !cancelled {
+		t.Error("OnCancel not called")
+	}
+}
+
+// appliedText counts the times an AutoForm writes it back. The validator
+// parses into a zero copy, which has no counter.
+type appliedText struct {
+	s       string
+	applies *int
+}
+
+func (a appliedText) MarshalText() ([]byte, error) { return []byte(a.s), nil }
+
+func (a *appliedText) UnmarshalText(b []byte) error {
+	a.s = string(b)
+	if a.applies != nil {
+		*a.applies++
+	}
+	return nil
+}
+
+func TestWizardSubmitsOnce(t *testing.T) {
+	var srv struct{ Host appliedText }
+	submits := 0
+	srv.Host.applies = &submits
+	note := ""
+	w := Wizard(
+		Step("Server", AutoForm(&srv)),
+		Step("Note", Form.OnSubmitAsync(func(ctx context.Context) error { return nil })(
+			Field("Note", Input(&note)),
+		)),
+		Step("Done", Text("ready")),
+	)
+	router := riffkey.NewRouter()
+	a := &App{router: router, input: riffkey.NewInput(router), renderChan: make(chan struct{}, 1)}
+	tmpl := Build(w)
+	a.wireBindings(tmpl, router)
+	press := func(keys string) {
+		for _, k := range riffkey.ParsePattern(keys) {
+			a.input.Dispatch(k)
+		}
+	}
+
+	// Enter in the AutoForm's field submits it once and advances
+	press("db<Enter>")
+	if submits != 1 || srv.Host.s != "db" || w.Current() != 1 {
+		t.Fatalf("submits = %d host = %q step = %d", submits, srv.Host.s, w.Current())
+	}
+
+	// an async step advances on the render after its submit succeeds
+	f := w.steps[1].view.(*FormC)
+	press("hi<Enter>")
+	waitFor(t, "submitted", func() bool { return f.State() == Submitted })
+	if w.Current() != 1 {
+		t.Fatal("advanced before the render pass")
+	}
+	tmpl.Execute(NewBuffer(40, 10), 40, 10)
+	if w.Current() != 2 || note != "hi" {
+		t.Errorf("step = %d note = %q", w.Current(), note)
+	}
+
+	// Next on the async step submits it rather than only validating
+	w.Back()
+	if w.Next() {
+		t.Fatal("Next moved on before the submit finished")
+	}
+	waitFor(t, "resubmitted", func() bool { return f.State() == Submitted })
+	tmpl.Execute(NewBuffer(40, 10), 40, 10)
+	if w.Current() != 2 {
+		t.Errorf("step = %d after Next's submit", w.Current())
+	}
+}